@@ -0,0 +1,82 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+)
+
+const FolderPath string = "/security/folder"
+
+// A folder domains can be grouped into
+type Folder struct {
+	ID   int    `json:"value"`
+	Name string `json:"label"`
+}
+
+// What can be learned about the account a client is talking to
+//
+// NOTE: the V2.0 API has no account endpoint, so there is no account
+// name or number; this is assembled from the responses of other calls
+type AccountInfo struct {
+	BaseURL     BaseURL
+	Environment Environment
+
+	// The API key with all but its first characters masked, for
+	// labelling output without leaking credentials
+	APIKey string
+
+	// The request quota per RateLimitWindow and what is left of it
+	RequestLimit      int
+	RequestsRemaining int
+
+	DomainCount int
+	Folders     []Folder
+}
+
+// Returns the folders defined in the account
+func (c *Client) Folders(ctx context.Context) ([]Folder, error) {
+	var folders []Folder
+	_, err := checkRespForError(c.newRequest(ctx).
+		SetResult(&folders).
+		Get(FolderPath))
+	if err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// Gathers account level metadata, costing two requests
+func (c *Client) AccountInfo(ctx context.Context) (AccountInfo, error) {
+	info := AccountInfo{
+		BaseURL:     c.BaseURL,
+		Environment: c.BaseURL.Environment(),
+		APIKey:      maskSecret(c.APIKey),
+	}
+	if c.credentials != nil {
+		creds, err := c.credentials.Credentials(ctx)
+		if err != nil {
+			return AccountInfo{}, err
+		}
+		info.APIKey = maskSecret(creds.APIKey)
+	}
+
+	var respDomains DomainsResp
+	_, err := checkRespForError(c.newRequest(ctx).
+		SetResult(&respDomains).
+		SetQueryParam("rows", "1").
+		Get(DNSManagedPath))
+	if err != nil {
+		return AccountInfo{}, err
+	}
+	info.DomainCount = respDomains.TotalRecords
+
+	folders, err := c.Folders(ctx)
+	if err != nil {
+		return AccountInfo{}, fmt.Errorf("listing folders: %w", err)
+	}
+	info.Folders = folders
+
+	rl := c.RateLimit()
+	info.RequestLimit, info.RequestsRemaining = rl.Limit, rl.Remaining
+	return info, nil
+}
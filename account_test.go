@@ -0,0 +1,27 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountInfo(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.limit, fake.remaining = 150, 150
+	fake.folders = []Folder{{1, "Default"}, {2, "Customers"}}
+	fake.addDomain("a.example")
+	fake.addDomain("b.example")
+
+	client = GetClient("0123456789abcdef", "secret", client.BaseURL)
+	info, err := client.AccountInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, client.BaseURL, info.BaseURL)
+	assert.Equal(t, "0123..."+redacted, info.APIKey)
+	assert.Equal(t, 2, info.DomainCount)
+	assert.Equal(t, []Folder{{1, "Default"}, {2, "Customers"}}, info.Folders)
+	assert.Equal(t, 150, info.RequestLimit)
+	assert.Equal(t, 148, info.RequestsRemaining)
+}
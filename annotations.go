@@ -0,0 +1,301 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Companion TXT records are named AnnotationLabel.<record name>
+	AnnotationLabel = "_dme-meta"
+
+	// Leads the value of every companion TXT record
+	annotationTag = "dme-meta1"
+
+	// Leads the keys of labels in companion TXT records
+	labelPrefix = "label."
+)
+
+// Governance metadata for the records of one name and type
+type Annotation struct {
+	Owner  string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Team   string `json:"team,omitempty" yaml:"team,omitempty"`
+	Ticket string `json:"ticket,omitempty" yaml:"ticket,omitempty"`
+
+	// Why the records exist, in free text
+	Note string `json:"note,omitempty" yaml:"note,omitempty"`
+
+	// For selecting records in bulk; see ParseSelector
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// Reports whether the annotation is empty
+func (a Annotation) IsZero() bool {
+	return a.Owner == "" && a.Team == "" && a.Ticket == "" && a.Note == "" && len(a.Labels) == 0
+}
+
+// An annotation and the records it describes
+type RecordAnnotation struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Annotation
+}
+
+// Where annotations are kept. Setting an empty Annotation removes it.
+type AnnotationStore interface {
+	Get(ctx context.Context, domainID int, name, recordType string) (Annotation, bool, error)
+	Set(ctx context.Context, domainID int, a RecordAnnotation) error
+	List(ctx context.Context, domainID int) ([]RecordAnnotation, error)
+}
+
+// Returns the annotation of the supplied record, answering who owns it
+func OwnerOf(ctx context.Context, store AnnotationStore, domainID int, record Record) (Annotation, bool, error) {
+	return store.Get(ctx, domainID, record.Name, record.Type)
+}
+
+// Returns the annotations of a domain whose owner or team is owner
+func OwnedBy(ctx context.Context, store AnnotationStore, domainID int, owner string) ([]RecordAnnotation, error) {
+	all, err := store.List(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	var owned []RecordAnnotation
+	for _, a := range all {
+		if strings.EqualFold(a.Owner, owner) || strings.EqualFold(a.Team, owner) {
+			owned = append(owned, a)
+		}
+	}
+	return owned, nil
+}
+
+// Keeps annotations in the zone itself, as a TXT record at
+// AnnotationLabel.<name> for each annotated name and type, so they are
+// visible to everyone sharing the zone
+type TXTAnnotations struct {
+	Client *Client
+
+	// TTL of created TXT records; 1800 if zero
+	Ttl int
+}
+
+func (s TXTAnnotations) Get(ctx context.Context, domainID int, name, recordType string) (Annotation, bool, error) {
+	all, err := s.List(ctx, domainID)
+	if err != nil {
+		return Annotation{}, false, err
+	}
+	for _, a := range all {
+		if a.Name == name && strings.EqualFold(a.Type, recordType) {
+			return a.Annotation, true, nil
+		}
+	}
+	return Annotation{}, false, nil
+}
+
+func (s TXTAnnotations) Set(ctx context.Context, domainID int, a RecordAnnotation) error {
+	records, err := s.Client.Records(domainID).cached(ctx)
+	if err != nil {
+		return err
+	}
+	companion := AnnotationName(a.Name)
+	var existing *Record
+	for idx, record := range records {
+		if record.Type != "TXT" || record.Name != companion {
+			continue
+		}
+		if parsed, ok := ParseAnnotation(record.Value); ok && parsed.Name == a.Name && strings.EqualFold(parsed.Type, a.Type) {
+			existing = &records[idx]
+			break
+		}
+	}
+
+	if a.Annotation.IsZero() {
+		if existing == nil {
+			return nil
+		}
+		return s.Client.Records(domainID).Delete(ctx, existing.ID)
+	}
+	value := a.txt()
+	if existing != nil {
+		if existing.Value == value {
+			return nil
+		}
+		updated := *existing
+		updated.Value = value
+		return s.Client.Records(domainID).Update(ctx, updated)
+	}
+	ttl := s.Ttl
+	if ttl == 0 {
+		ttl = 1800
+	}
+	_, err = s.Client.Records(domainID).Create(ctx, Record{
+		Name: companion, Type: "TXT", Value: value, Ttl: ttl, GtdLocation: GtdDefault,
+	})
+	return err
+}
+
+func (s TXTAnnotations) List(ctx context.Context, domainID int) ([]RecordAnnotation, error) {
+	records, err := s.Client.Records(domainID).cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var all []RecordAnnotation
+	for _, record := range records {
+		if record.Type != "TXT" {
+			continue
+		}
+		if a, ok := ParseAnnotation(record.Value); ok && AnnotationName(a.Name) == record.Name {
+			all = append(all, a)
+		}
+	}
+	sortAnnotations(all)
+	return all, nil
+}
+
+// Returns the name of the companion TXT record for records named name
+func AnnotationName(name string) string {
+	if name == "" {
+		return AnnotationLabel
+	}
+	return AnnotationLabel + "." + name
+}
+
+// Parses the value of a companion TXT record
+func ParseAnnotation(txt string) (RecordAnnotation, bool) {
+	fields := strings.Fields(strings.Trim(txt, `"`))
+	if len(fields) == 0 || fields[0] != annotationTag {
+		return RecordAnnotation{}, false
+	}
+	var a RecordAnnotation
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		value, err := url.QueryUnescape(value)
+		if err != nil {
+			return RecordAnnotation{}, false
+		}
+		switch key {
+		case "name":
+			a.Name = value
+		case "type":
+			a.Type = value
+		case "owner":
+			a.Owner = value
+		case "team":
+			a.Team = value
+		case "ticket":
+			a.Ticket = value
+		case "note":
+			a.Note = value
+		default:
+			if label, ok := strings.CutPrefix(key, labelPrefix); ok {
+				if label, err = url.QueryUnescape(label); err != nil {
+					return RecordAnnotation{}, false
+				}
+				if a.Labels == nil {
+					a.Labels = map[string]string{}
+				}
+				a.Labels[label] = value
+			}
+		}
+	}
+	return a, a.Type != ""
+}
+
+// Renders the annotation as a companion TXT value
+func (a RecordAnnotation) txt() string {
+	fields := []string{annotationTag, "name=" + url.QueryEscape(a.Name), "type=" + url.QueryEscape(a.Type)}
+	for _, kv := range [][2]string{{"owner", a.Owner}, {"team", a.Team}, {"ticket", a.Ticket}, {"note", a.Note}} {
+		if kv[1] != "" {
+			fields = append(fields, kv[0]+"="+url.QueryEscape(kv[1]))
+		}
+	}
+	for _, key := range sortedKeys(a.Labels) {
+		fields = append(fields, labelPrefix+url.QueryEscape(key)+"="+url.QueryEscape(a.Labels[key]))
+	}
+	return strconv.Quote(strings.Join(fields, " "))
+}
+
+// Keeps annotations in a local JSON file, for zones where extra TXT
+// records are unwelcome. Safe for concurrent use within a process.
+type FileAnnotations struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileAnnotations) Get(ctx context.Context, domainID int, name, recordType string) (Annotation, bool, error) {
+	all, err := s.List(ctx, domainID)
+	if err != nil {
+		return Annotation{}, false, err
+	}
+	for _, a := range all {
+		if a.Name == name && strings.EqualFold(a.Type, recordType) {
+			return a.Annotation, true, nil
+		}
+	}
+	return Annotation{}, false, nil
+}
+
+func (s *FileAnnotations) Set(ctx context.Context, domainID int, a RecordAnnotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.load()
+	if err != nil {
+		return err
+	}
+	key := strconv.Itoa(domainID)
+	kept := []RecordAnnotation{}
+	for _, existing := range stored[key] {
+		if existing.Name != a.Name || !strings.EqualFold(existing.Type, a.Type) {
+			kept = append(kept, existing)
+		}
+	}
+	if !a.Annotation.IsZero() {
+		kept = append(kept, a)
+	}
+	sortAnnotations(kept)
+	stored[key] = kept
+	return writeJSONFile(s.Path, stored)
+}
+
+func (s *FileAnnotations) List(ctx context.Context, domainID int) ([]RecordAnnotation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return stored[strconv.Itoa(domainID)], nil
+}
+
+// Reads the file, annotations keyed by domain ID. A missing file holds
+// no annotations.
+func (s *FileAnnotations) load() (map[string][]RecordAnnotation, error) {
+	stored := map[string][]RecordAnnotation{}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stored, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stored, json.Unmarshal(data, &stored)
+}
+
+func sortAnnotations(all []RecordAnnotation) {
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Name != all[j].Name {
+			return all[i].Name < all[j].Name
+		}
+		return all[i].Type < all[j].Type
+	})
+}
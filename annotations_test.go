@@ -0,0 +1,75 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAnnotation(t *testing.T) {
+	a := RecordAnnotation{Name: "www", Type: "A", Annotation: Annotation{Owner: "Jane Doe", Team: "web", Ticket: "OPS-12"}}
+	txt := a.txt()
+	assert.Equal(t, `"dme-meta1 name=www type=A owner=Jane+Doe team=web ticket=OPS-12"`, txt)
+	parsed, ok := ParseAnnotation(txt)
+	require.True(t, ok)
+	assert.Equal(t, a, parsed)
+
+	_, ok = ParseAnnotation(`"v=spf1 -all"`)
+	assert.False(t, ok)
+}
+
+func testAnnotationStore(t *testing.T, store AnnotationStore, domainID int) {
+	ctx := context.Background()
+	www := Record{Name: "www", Type: "A"}
+
+	_, ok, err := OwnerOf(ctx, store, domainID, www)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(ctx, domainID, RecordAnnotation{"www", "A", Annotation{Owner: "alice", Team: "web"}}))
+	require.NoError(t, store.Set(ctx, domainID, RecordAnnotation{"www", "AAAA", Annotation{Owner: "bob"}}))
+	require.NoError(t, store.Set(ctx, domainID, RecordAnnotation{"", "MX", Annotation{Team: "mail", Ticket: "OPS-1"}}))
+	require.NoError(t, store.Set(ctx, domainID, RecordAnnotation{"www", "A", Annotation{Owner: "carol", Team: "web"}}))
+
+	owner, ok, err := OwnerOf(ctx, store, domainID, www)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Annotation{Owner: "carol", Team: "web"}, owner)
+
+	owned, err := OwnedBy(ctx, store, domainID, "WEB")
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	assert.Equal(t, "A", owned[0].Type)
+
+	require.NoError(t, store.Set(ctx, domainID, RecordAnnotation{Name: "www", Type: "AAAA"}))
+	all, err := store.List(ctx, domainID)
+	require.NoError(t, err)
+	assert.Equal(t, []RecordAnnotation{
+		{"", "MX", Annotation{Team: "mail", Ticket: "OPS-1"}},
+		{"www", "A", Annotation{Owner: "carol", Team: "web"}},
+	}, all)
+}
+
+func TestTXTAnnotations(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	testAnnotationStore(t, TXTAnnotations{Client: client}, domain.ID)
+
+	var names []string
+	for _, record := range fake.recordList(domain.ID) {
+		names = append(names, record.Type+" "+record.Name)
+	}
+	assert.ElementsMatch(t, []string{"A www", "TXT _dme-meta", "TXT _dme-meta.www"}, names)
+}
+
+func TestFileAnnotations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "annotations.json")
+	testAnnotationStore(t, &FileAnnotations{Path: path}, 7)
+
+	all, err := (&FileAnnotations{Path: path}).List(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -0,0 +1,83 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Rejects records that can't exist at the zone apex (the record named
+// ""). CNAMEs there would conflict with the SOA and NS records; DNS Made
+// Easy's ANAME record is the way to alias the apex instead.
+func ValidateApex(record Record) error {
+	if record.Name != "" {
+		return nil
+	}
+	if strings.EqualFold(record.Type, "CNAME") {
+		return errors.New("CNAME records aren't allowed at the zone apex, use an ANAME record instead")
+	}
+	return nil
+}
+
+// Points the apex of the domain at target with an ANAME record, creating
+// it, updating an existing one, or replacing an apex CNAME. A ttl of 0
+// keeps the TTL of any record being replaced, defaulting to 1800.
+func (c *Client) SetApexAlias(ctx context.Context, domainID int, target string, ttl int) (Record, error) {
+	if target == "" {
+		return Record{}, errors.New("an ANAME target is required")
+	}
+	if !strings.HasSuffix(target, ".") {
+		target += "."
+	}
+
+	records, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return Record{}, err
+	}
+	var aname, cname *Record
+	for idx := range records {
+		if records[idx].Name != "" {
+			continue
+		}
+		switch strings.ToUpper(records[idx].Type) {
+		case "ANAME":
+			aname = &records[idx]
+		case "CNAME":
+			cname = &records[idx]
+		}
+	}
+
+	if aname != nil {
+		record := *aname
+		record.Value = target
+		if ttl > 0 {
+			record.Ttl = ttl
+		}
+		if err := c.Records(domainID).Update(ctx, record); err != nil {
+			return Record{}, err
+		}
+		return record, nil
+	}
+
+	record := Record{Name: "", Type: "ANAME", Value: target, Ttl: ttl, GtdLocation: "DEFAULT"}
+	if cname != nil {
+		if record.Ttl == 0 {
+			record.Ttl = cname.Ttl
+		}
+		record.GtdLocation = cname.GtdLocation
+		// the CNAME would conflict with the new record, so it goes first
+		if err := c.Records(domainID).Delete(ctx, cname.ID); err != nil {
+			return Record{}, fmt.Errorf("removing apex CNAME: %w", err)
+		}
+	}
+	if record.Ttl == 0 {
+		record.Ttl = 1800
+	}
+
+	created, err := c.Records(domainID).Create(ctx, record)
+	if err != nil && cname != nil {
+		return Record{}, fmt.Errorf("apex CNAME %s was removed but the ANAME could not be created: %w", cname.Value, err)
+	}
+	return created, err
+}
@@ -0,0 +1,47 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateApex(t *testing.T) {
+	assert.NoError(t, ValidateApex(Record{Name: "www", Type: "CNAME"}))
+	assert.NoError(t, ValidateApex(Record{Name: "", Type: "ANAME"}))
+	assert.Error(t, ValidateApex(Record{Name: "", Type: "cname"}))
+}
+
+func TestSetApexAlias(t *testing.T) {
+	fake, client := newFakeDME(t)
+	ctx := context.Background()
+
+	domain := fake.addDomain("example.com")
+	created, err := client.SetApexAlias(ctx, domain.ID, "lb.example.net", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "ANAME", created.Type)
+	assert.Equal(t, "lb.example.net.", created.Value)
+	assert.Equal(t, 1800, created.Ttl)
+
+	updated, err := client.SetApexAlias(ctx, domain.ID, "other.example.net.", 300)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, updated.ID)
+	records := fake.recordList(domain.ID)
+	require.Len(t, records, 1)
+	assert.Equal(t, "other.example.net.", records[0].Value)
+	assert.Equal(t, 300, records[0].Ttl)
+
+	converted := fake.addDomain("example.org",
+		Record{Name: "", Type: "CNAME", Value: "lb.example.net.", Ttl: 600, GtdLocation: "DEFAULT"},
+		Record{Name: "www", Type: "CNAME", Value: "lb.example.net.", Ttl: 600, GtdLocation: "DEFAULT"},
+	)
+	_, err = client.SetApexAlias(ctx, converted.ID, "lb.example.net", 0)
+	require.NoError(t, err)
+	records = fake.recordList(converted.ID)
+	require.Len(t, records, 2)
+	assert.Equal(t, "www", records[0].Name)
+	assert.Equal(t, "ANAME", records[1].Type)
+	assert.Equal(t, 600, records[1].Ttl)
+}
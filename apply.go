@@ -0,0 +1,211 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type OperationType int
+
+const (
+	OpCreate OperationType = iota
+	OpUpdate
+	OpDelete
+)
+
+func (t OperationType) String() string {
+	switch t {
+	case OpCreate:
+		return "create"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	}
+	return fmt.Sprintf("OperationType(%d)", int(t))
+}
+
+// A single change to a record within a domain
+type Operation struct {
+	Type     OperationType
+	DomainID int
+
+	// For creates, the record to create. For updates, the desired state
+	// of the record identified by Record.ID. For deletes, only Record.ID
+	// is required.
+	Record Record
+}
+
+func (op Operation) String() string {
+	return fmt.Sprintf("%s %s %s (domain %d, record %d)",
+		op.Type, op.Record.Type, op.Record.Name, op.DomainID, op.Record.ID)
+}
+
+type ApplyState int
+
+const (
+	// Every operation was applied
+	ApplyCommitted ApplyState = iota
+	// An operation failed and every applied operation was undone
+	ApplyRolledBack
+	// An operation failed and at least one applied operation could not
+	// be undone; see ApplyResult.Applied for what remains in the zone
+	ApplyPartial
+)
+
+func (s ApplyState) String() string {
+	switch s {
+	case ApplyCommitted:
+		return "committed"
+	case ApplyRolledBack:
+		return "rolled back"
+	case ApplyPartial:
+		return "partial"
+	}
+	return fmt.Sprintf("ApplyState(%d)", int(s))
+}
+
+type ApplyResult struct {
+	State ApplyState
+
+	// Operations that are in effect in the zone once Apply returns. Creates
+	// carry the ID assigned by DNS Made Easy.
+	Applied []Operation
+
+	// Operations that were applied and then successfully undone
+	RolledBack []Operation
+
+	// The operation that caused the rollback, if any
+	Failed *Operation
+
+	// Errors encountered while undoing applied operations
+	RollbackErrors []error
+}
+
+// Executes a set of record operations in order. If an operation fails,
+// the operations applied so far are undone in reverse order and the
+// result describes the state the zone(s) ended in.
+//
+// NOTE: the DNS Made Easy API has no transactions; a rolled back delete
+// recreates the record, which gives it a new ID
+func (c *Client) Apply(ops []Operation) (ApplyResult, error) {
+	return c.ApplyContext(context.Background(), ops)
+}
+
+// Executes a set of record operations as Apply does, once any Approver
+// configured with WithApprover has approved them. Undoing applied
+// operations carries on even if ctx is done.
+func (c *Client) ApplyContext(ctx context.Context, ops []Operation) (ApplyResult, error) {
+	var result ApplyResult
+	if c.approver != nil && len(ops) > 0 {
+		if err := c.approver.Approve(ctx, ApprovalRequest{Plan: ops, Digest: PlanDigest(ops)}); err != nil {
+			return result, err
+		}
+	}
+
+	// snapshot the current state of every record we're about to
+	// modify so updates and deletes can be undone
+	originals := map[int]map[int]Record{}
+	for _, op := range ops {
+		if op.Type == OpCreate {
+			continue
+		}
+		if _, ok := originals[op.DomainID]; ok {
+			continue
+		}
+		records, err := c.Records(op.DomainID).List(ctx)
+		if err != nil {
+			return result, err
+		}
+		byId := map[int]Record{}
+		for _, record := range records {
+			byId[record.ID] = record
+		}
+		originals[op.DomainID] = byId
+	}
+
+	// undo records the inverse of each applied operation
+	var undo []Operation
+
+	var applyErr error
+	for idx := range ops {
+		op := ops[idx]
+		switch op.Type {
+		case OpCreate:
+			var created Record
+			created, applyErr = c.Records(op.DomainID).Create(ctx, op.Record)
+			if applyErr == nil {
+				op.Record = created
+				undo = append(undo, Operation{OpDelete, op.DomainID, created})
+			}
+		case OpUpdate:
+			original, ok := originals[op.DomainID][op.Record.ID]
+			if !ok {
+				applyErr = fmt.Errorf("record %d in domain %d: %w", op.Record.ID, op.DomainID, ErrNotFound)
+				break
+			}
+			applyErr = c.Records(op.DomainID).Update(ctx, op.Record)
+			if applyErr == nil {
+				undo = append(undo, Operation{OpUpdate, op.DomainID, original})
+			}
+		case OpDelete:
+			original, ok := originals[op.DomainID][op.Record.ID]
+			if !ok {
+				applyErr = fmt.Errorf("record %d in domain %d: %w", op.Record.ID, op.DomainID, ErrNotFound)
+				break
+			}
+			applyErr = c.Records(op.DomainID).Delete(ctx, op.Record.ID)
+			if applyErr == nil {
+				undo = append(undo, Operation{OpCreate, op.DomainID, original})
+			}
+		default:
+			applyErr = fmt.Errorf("unknown operation type %s", op.Type)
+		}
+
+		if applyErr != nil {
+			result.Failed = &ops[idx]
+			break
+		}
+		result.Applied = append(result.Applied, op)
+	}
+
+	if applyErr == nil {
+		result.State = ApplyCommitted
+		return result, nil
+	}
+
+	// walk backwards undoing everything that was applied; anything we
+	// fail to undo stays in Applied
+	ctx = context.WithoutCancel(ctx)
+	var remaining []Operation
+	for idx := len(undo) - 1; idx >= 0; idx-- {
+		inverse := undo[idx]
+		var err error
+		switch inverse.Type {
+		case OpCreate:
+			inverse.Record.ID = 0
+			_, err = c.Records(inverse.DomainID).Create(ctx, inverse.Record)
+		case OpUpdate:
+			err = c.Records(inverse.DomainID).Update(ctx, inverse.Record)
+		case OpDelete:
+			err = c.Records(inverse.DomainID).Delete(ctx, inverse.Record.ID)
+		}
+		if err != nil {
+			result.RollbackErrors = append(result.RollbackErrors,
+				fmt.Errorf("undoing %s: %w", result.Applied[idx], err))
+			remaining = append([]Operation{result.Applied[idx]}, remaining...)
+		} else {
+			result.RolledBack = append(result.RolledBack, result.Applied[idx])
+		}
+	}
+	result.Applied = remaining
+
+	if len(result.RollbackErrors) > 0 {
+		result.State = ApplyPartial
+		return result, fmt.Errorf("%s failed: %w (rollback incomplete: %w)",
+			result.Failed, applyErr, errors.Join(result.RollbackErrors...))
+	}
+	result.State = ApplyRolledBack
+	return result, fmt.Errorf("%s failed: %w", result.Failed, applyErr)
+}
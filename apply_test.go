@@ -0,0 +1,93 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	www := Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"}
+	mail := Record{Name: "mail", Type: "A", Value: "192.0.2.2", Ttl: 1800, GtdLocation: "DEFAULT"}
+
+	t.Run("commits every operation", func(t *testing.T) {
+		fake, client := newFakeDME(t)
+		domain := fake.addDomain("example.com", www, mail)
+		existing := fake.recordList(domain.ID)
+
+		updated := existing[0]
+		updated.Value = "192.0.2.10"
+		result, err := client.Apply([]Operation{
+			{OpCreate, domain.ID, Record{Name: "api", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: "DEFAULT"}},
+			{OpUpdate, domain.ID, updated},
+			{OpDelete, domain.ID, existing[1]},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ApplyCommitted, result.State)
+		assert.Len(t, result.Applied, 3)
+		assert.NotZero(t, result.Applied[0].Record.ID)
+
+		records := fake.recordList(domain.ID)
+		require.Len(t, records, 2)
+		assert.Equal(t, "192.0.2.10", records[0].Value)
+		assert.Equal(t, "api", records[1].Name)
+	})
+
+	t.Run("reports missing records as not found", func(t *testing.T) {
+		fake, client := newFakeDME(t)
+		domain := fake.addDomain("example.com", www)
+
+		_, err := client.Apply([]Operation{{OpDelete, domain.ID, Record{ID: 1, Name: "gone", Type: "A"}}})
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		fake, client := newFakeDME(t)
+		domain := fake.addDomain("example.com", www, mail)
+		existing := fake.recordList(domain.ID)
+
+		updated := existing[0]
+		updated.Value = "192.0.2.10"
+		result, err := client.Apply([]Operation{
+			{OpCreate, domain.ID, Record{Name: "api", Type: "A", Value: "192.0.2.3"}},
+			{OpUpdate, domain.ID, updated},
+			{OpDelete, domain.ID, existing[1]},
+			{OpCreate, domain.ID, Record{Name: "invalid", Type: "A", Value: "192.0.2.4"}},
+		})
+		require.Error(t, err)
+		assert.Equal(t, ApplyRolledBack, result.State)
+		assert.Empty(t, result.Applied)
+		assert.Len(t, result.RolledBack, 3)
+		require.NotNil(t, result.Failed)
+		assert.Equal(t, "invalid", result.Failed.Record.Name)
+
+		records := fake.recordList(domain.ID)
+		require.Len(t, records, 2)
+		assert.Equal(t, "192.0.2.1", records[0].Value)
+		assert.Equal(t, "mail", records[1].Name)
+	})
+
+	t.Run("reports partial state when rollback fails", func(t *testing.T) {
+		fake, client := newFakeDME(t)
+		domain := fake.addDomain("example.com", www)
+
+		fake.fail = func(r *http.Request) bool {
+			// the create succeeds, then every later request fails
+			return fake.calls["POST /dns/managed/"+fmt.Sprint(domain.ID)+"/records"] > 1 ||
+				r.Method == http.MethodDelete
+		}
+		result, err := client.Apply([]Operation{
+			{OpCreate, domain.ID, Record{Name: "api", Type: "A", Value: "192.0.2.3"}},
+			{OpCreate, domain.ID, Record{Name: "app", Type: "A", Value: "192.0.2.4"}},
+		})
+		require.Error(t, err)
+		assert.Equal(t, ApplyPartial, result.State)
+		require.Len(t, result.Applied, 1)
+		assert.Equal(t, "api", result.Applied[0].Record.Name)
+		assert.Len(t, result.RollbackErrors, 1)
+		assert.Len(t, fake.recordList(domain.ID), 2)
+	})
+}
@@ -0,0 +1,96 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// A plan of record changes awaiting approval
+type ApprovalRequest struct {
+	Plan []Operation
+
+	// PlanDigest of Plan, which approvals should be bound to so one
+	// given for a plan can't be used for another
+	Digest string
+}
+
+// Decides whether a plan may be executed, for four-eyes control over
+// changes. ApplyContext, and so the reconciler, consult the Approver
+// configured with WithApprover.
+type Approver interface {
+	// Returns nil once the plan is approved, or an error wrapping
+	// ErrNotApproved if it is refused. May block, such as while waiting
+	// for a second person, until ctx is done.
+	Approve(ctx context.Context, req ApprovalRequest) error
+}
+
+// Adapts a function to the Approver interface
+type ApproverFunc func(ctx context.Context, req ApprovalRequest) error
+
+func (f ApproverFunc) Approve(ctx context.Context, req ApprovalRequest) error {
+	return f(ctx, req)
+}
+
+// Has every plan passed to ApplyContext approved before it is executed
+func WithApprover(approver Approver) Option {
+	return func(c *Client) {
+		c.approver = approver
+	}
+}
+
+// Returns a hex SHA-256 digest identifying the operations of a plan
+func PlanDigest(ops []Operation) string {
+	data, _ := json.Marshal(ops)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns a token approving the plan with digest on behalf of approver,
+// signed with a key shared with a TokenApprover
+func SignApproval(key []byte, approver string, digest string) string {
+	return approver + "." + approvalMAC(key, approver, digest)
+}
+
+func approvalMAC(key []byte, approver string, digest string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(approver + "\x00" + digest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Approves plans given a token from SignApproval by someone other than
+// Requester, so no one can approve their own changes
+type TokenApprover struct {
+	Key []byte
+
+	// Who is making the changes
+	Requester string
+
+	// Fetches the token for a plan, such as by posting its digest for
+	// review and waiting for a reply
+	Token func(ctx context.Context, req ApprovalRequest) (string, error)
+}
+
+func (a TokenApprover) Approve(ctx context.Context, req ApprovalRequest) error {
+	token, err := a.Token(ctx, req)
+	if err != nil {
+		return fmt.Errorf("fetching approval: %w", err)
+	}
+	// approver names may contain dots, signatures don't
+	dot := strings.LastIndex(token, ".")
+	if dot < 0 {
+		return fmt.Errorf("%w: invalid approval token", ErrNotApproved)
+	}
+	approver, mac := token[:dot], token[dot+1:]
+	if !hmac.Equal([]byte(mac), []byte(approvalMAC(a.Key, approver, req.Digest))) {
+		return fmt.Errorf("%w: invalid approval token", ErrNotApproved)
+	}
+	if strings.EqualFold(approver, a.Requester) {
+		return fmt.Errorf("%w: %s can't approve their own changes", ErrNotApproved, approver)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenApprover(t *testing.T) {
+	key := []byte("shared secret")
+	ops := []Operation{{Type: OpCreate, DomainID: 7, Record: Record{Name: "www", Type: "A", Value: "192.0.2.1"}}}
+	req := ApprovalRequest{Plan: ops, Digest: PlanDigest(ops)}
+	approve := func(token string) error {
+		return TokenApprover{Key: key, Requester: "alice", Token: func(ctx context.Context, r ApprovalRequest) (string, error) {
+			return token, nil
+		}}.Approve(context.Background(), req)
+	}
+
+	assert.NoError(t, approve(SignApproval(key, "bob.smith", req.Digest)))
+	assert.ErrorIs(t, approve(SignApproval(key, "Alice", req.Digest)), ErrNotApproved)
+	assert.ErrorIs(t, approve(SignApproval([]byte("wrong"), "bob", req.Digest)), ErrNotApproved)
+	assert.ErrorIs(t, approve("bob"), ErrNotApproved)
+
+	// approvals are bound to the plan
+	other := []Operation{{Type: OpDelete, DomainID: 7, Record: Record{ID: 1}}}
+	assert.NotEqual(t, req.Digest, PlanDigest(other))
+	assert.ErrorIs(t, approve(SignApproval(key, "bob", PlanDigest(other))), ErrNotApproved)
+}
+
+func TestApplyConsultsApprover(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	var requests []ApprovalRequest
+	refuse := true
+	client = GetClient("key", "secret", client.BaseURL, WithApprover(ApproverFunc(func(ctx context.Context, req ApprovalRequest) error {
+		requests = append(requests, req)
+		if refuse {
+			return errors.Join(ErrNotApproved, errors.New("waiting for review"))
+		}
+		return nil
+	})))
+	ops := []Operation{{Type: OpCreate, DomainID: domain.ID, Record: Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault}}}
+
+	_, err := client.ApplyContext(context.Background(), ops)
+	assert.ErrorIs(t, err, ErrNotApproved)
+	assert.Empty(t, fake.recordList(domain.ID))
+
+	refuse = false
+	result, err := client.Apply(ops)
+	require.NoError(t, err)
+	assert.Equal(t, ApplyCommitted, result.State)
+	assert.Len(t, fake.recordList(domain.ID), 1)
+	require.Len(t, requests, 2)
+	assert.Equal(t, PlanDigest(ops), requests[1].Digest)
+
+	// empty plans need no approval
+	_, err = client.Apply(nil)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+}
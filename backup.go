@@ -0,0 +1,302 @@
+package dnsmadeeasy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// How often BackupSchedule takes a full backup when FullInterval is zero
+const DefaultFullBackupInterval = 7 * 24 * time.Hour
+
+// A backup of one zone: either every record, or the changes since the
+// zone's previous full backup
+type BackupEntry struct {
+	DomainID int       `json:"domainId"`
+	Domain   string    `json:"domain"`
+	Time     time.Time `json:"time"`
+	Full     bool      `json:"full"`
+
+	// Every record, for full backups
+	Records []Record `json:"records,omitempty"`
+
+	// The changes since the previous full backup, for differential ones
+	Changes []ChangeEvent `json:"changes,omitempty"`
+}
+
+// Persists backup entries
+type BackupStore interface {
+	// Returns the domain's entries, oldest first
+	LoadBackups(domainID int) ([]BackupEntry, error)
+	AppendBackup(BackupEntry) error
+
+	// Removes the domain's entries that aren't needed to restore it to
+	// before or any later time
+	PruneBackups(domainID int, before time.Time) error
+}
+
+// Keeps each domain's backups in a JSON lines file in Dir
+type FileBackups struct {
+	Dir string
+}
+
+func (f FileBackups) path(domainID int) string {
+	return filepath.Join(f.Dir, fmt.Sprintf("backup-%d.jsonl", domainID))
+}
+
+// Returns the IDs of the domains with backups in Dir
+func (f FileBackups) Domains() ([]int, error) {
+	paths, err := filepath.Glob(filepath.Join(f.Dir, "backup-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, path := range paths {
+		var id int
+		if _, err := fmt.Sscanf(filepath.Base(path), "backup-%d.jsonl", &id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (f FileBackups) LoadBackups(domainID int) ([]BackupEntry, error) {
+	file, err := os.Open(f.path(domainID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []BackupEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var entry BackupEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", f.path(domainID), line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func (f FileBackups) AppendBackup(entry BackupEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path(entry.DomainID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f FileBackups) PruneBackups(domainID int, before time.Time) error {
+	entries, err := f.LoadBackups(domainID)
+	if err != nil {
+		return err
+	}
+	first := firstNeeded(entries, before)
+	if first == 0 {
+		return nil
+	}
+	var data []byte
+	for _, entry := range entries[first:] {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return writeFileAtomic(f.path(domainID), data)
+}
+
+// Returns the index of the full backup a restore to before would start
+// from; the entries ahead of it are no longer needed
+func firstNeeded(entries []BackupEntry, before time.Time) int {
+	first := 0
+	for idx, entry := range entries {
+		if entry.Time.After(before) {
+			break
+		}
+		if entry.Full {
+			first = idx
+		}
+	}
+	return first
+}
+
+// Returns a zone's records as of t from its backup entries, and when the
+// entry they come from was taken. Reports false if no entry was taken at
+// or before t.
+func BackupState(entries []BackupEntry, t time.Time) ([]Record, time.Time, bool) {
+	var full, latest *BackupEntry
+	for idx := range entries {
+		entry := &entries[idx]
+		if entry.Time.After(t) {
+			break
+		}
+		if entry.Full {
+			full = entry
+		}
+		latest = entry
+	}
+	if full == nil {
+		return nil, time.Time{}, false
+	}
+	return applyBackup(*full, *latest), latest.Time, true
+}
+
+// Returns the records of a full backup with a later differential's
+// changes applied
+func applyBackup(full, diff BackupEntry) []Record {
+	byID := make(map[int]Record, len(full.Records))
+	for _, record := range full.Records {
+		byID[record.ID] = record
+	}
+	if !diff.Full {
+		for _, change := range diff.Changes {
+			if change.Type == ChangeRemoved {
+				delete(byID, change.Record.ID)
+			} else {
+				byID[change.Record.ID] = change.Record
+			}
+		}
+	}
+	records := make([]Record, 0, len(byID))
+	for _, record := range byID {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+// Backs zones up every Interval, storing a full copy every FullInterval
+// and otherwise only the changes since the last full copy, so long
+// histories stay small. Zones unchanged since their last backup aren't
+// stored again. BackupState recovers a zone as of any time since its
+// first backup.
+type BackupSchedule struct {
+	Client *Client
+	Store  BackupStore
+
+	// Every domain of the account if empty
+	DomainIDs []int
+
+	Interval time.Duration
+
+	// DefaultFullBackupInterval if zero
+	FullInterval time.Duration
+
+	// How far back zones can be restored; entries no longer needed for
+	// that are pruned. Zero keeps everything.
+	Retention time.Duration
+
+	// Receives errors from backing up; they don't stop the schedule.
+	// Ignored if nil.
+	OnError func(error)
+}
+
+// Backs up the zones, then again every Interval until ctx is done
+func (b *BackupSchedule) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+	for {
+		if err := b.Backup(ctx); err != nil && b.OnError != nil {
+			b.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backs up each zone once
+func (b *BackupSchedule) Backup(ctx context.Context) error {
+	domains, err := b.Client.domainsOrAll(ctx, b.DomainIDs)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, domain := range domains {
+		if err := b.backup(ctx, domain, time.Now()); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *BackupSchedule) backup(ctx context.Context, domain Domain, now time.Time) error {
+	records, err := b.Client.Records(domain.ID).list(ctx)
+	if err != nil {
+		return err
+	}
+	entries, err := b.Store.LoadBackups(domain.ID)
+	if err != nil {
+		return fmt.Errorf("loading backups: %w", err)
+	}
+	entry, changed := b.entry(domain, entries, records, now)
+	if changed {
+		if err := b.Store.AppendBackup(entry); err != nil {
+			return fmt.Errorf("saving backup: %w", err)
+		}
+	}
+	if b.Retention > 0 {
+		if err := b.Store.PruneBackups(domain.ID, now.Add(-b.Retention)); err != nil {
+			return fmt.Errorf("pruning backups: %w", err)
+		}
+	}
+	return nil
+}
+
+// Returns the entry to store for records, and false if the zone is
+// unchanged since its last entry and no full backup is due
+func (b *BackupSchedule) entry(domain Domain, entries []BackupEntry, records []Record, now time.Time) (BackupEntry, bool) {
+	entry := BackupEntry{DomainID: domain.ID, Domain: domain.Name, Time: now, Full: true, Records: records}
+	fullInterval := b.FullInterval
+	if fullInterval == 0 {
+		fullInterval = DefaultFullBackupInterval
+	}
+	var full *BackupEntry
+	for idx := range entries {
+		if entries[idx].Full {
+			full = &entries[idx]
+		}
+	}
+	if full == nil || now.Sub(full.Time) >= fullInterval {
+		return entry, true
+	}
+
+	latest, _, _ := BackupState(entries, now)
+	if created, updated, deleted := diffByID(latest, records); len(created)+len(updated)+len(deleted) == 0 {
+		return BackupEntry{}, false
+	}
+
+	changes := changeEvents(domain.ID, domain.Name, now, full.Records, records)
+	// a differential larger than the zone is better stored in full
+	if len(changes) >= len(records) {
+		return entry, true
+	}
+	return BackupEntry{DomainID: domain.ID, Domain: domain.Name, Time: now, Changes: changes}, true
+}
@@ -0,0 +1,94 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordValues(records []Record) []string {
+	var vals []string
+	for _, record := range records {
+		vals = append(vals, record.Name+"="+record.Value)
+	}
+	return vals
+}
+
+func TestBackupSchedule(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "ftp", Type: "A", Value: "192.0.2.4", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "vpn", Type: "A", Value: "192.0.2.5", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	store := FileBackups{Dir: t.TempDir()}
+	b := &BackupSchedule{Client: client, Store: store, FullInterval: 24 * time.Hour}
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return start.Add(time.Duration(hours) * time.Hour) }
+
+	require.NoError(t, b.backup(ctx, domain, at(0)))
+	require.NoError(t, b.backup(ctx, domain, at(1)))
+	entries, err := store.LoadBackups(domain.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "unchanged zones aren't backed up again")
+	assert.True(t, entries[0].Full)
+
+	records, err := client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	www := records[0]
+	www.Value = "192.0.2.10"
+	require.NoError(t, client.Records(domain.ID).Update(ctx, www))
+	require.NoError(t, b.backup(ctx, domain, at(2)))
+	require.NoError(t, client.Records(domain.ID).Delete(ctx, records[1].ID))
+	require.NoError(t, b.backup(ctx, domain, at(3)))
+
+	entries, err = store.LoadBackups(domain.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.False(t, entries[2].Full)
+	assert.Len(t, entries[2].Changes, 2, "differentials hold every change since the full backup")
+
+	state, taken, ok := BackupState(entries, at(2).Add(30*time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, at(2), taken)
+	assert.Equal(t, []string{"www=192.0.2.10", "api=192.0.2.2", "mail=192.0.2.3", "ftp=192.0.2.4", "vpn=192.0.2.5"}, recordValues(state))
+	state, _, _ = BackupState(entries, at(3))
+	assert.Equal(t, []string{"www=192.0.2.10", "mail=192.0.2.3", "ftp=192.0.2.4", "vpn=192.0.2.5"}, recordValues(state))
+	state, _, _ = BackupState(entries, at(0))
+	assert.Equal(t, []string{"www=192.0.2.1", "api=192.0.2.2", "mail=192.0.2.3", "ftp=192.0.2.4", "vpn=192.0.2.5"}, recordValues(state))
+	_, _, ok = BackupState(entries, at(-1))
+	assert.False(t, ok)
+
+	// a full backup is taken once FullInterval passes; the chain before
+	// it is pruned once restores no longer reach back that far
+	b.Retention = time.Hour
+	require.NoError(t, b.backup(ctx, domain, at(30)))
+	entries, err = store.LoadBackups(domain.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.True(t, entries[3].Full)
+	require.NoError(t, b.backup(ctx, domain, at(60)))
+	entries, err = store.LoadBackups(domain.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, at(30), entries[0].Time)
+}
+
+func TestFirstNeeded(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []BackupEntry{
+		{Time: start, Full: true},
+		{Time: start.Add(time.Hour)},
+		{Time: start.Add(2 * time.Hour), Full: true},
+		{Time: start.Add(3 * time.Hour)},
+	}
+	assert.Equal(t, 0, firstNeeded(entries, start.Add(90*time.Minute)))
+	assert.Equal(t, 2, firstNeeded(entries, start.Add(2*time.Hour)))
+	assert.Equal(t, 2, firstNeeded(entries, start.Add(5*time.Hour)))
+}
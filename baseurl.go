@@ -0,0 +1,60 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type Environment string
+
+const (
+	EnvironmentProd    Environment = "prod"
+	EnvironmentSandbox Environment = "sandbox"
+
+	// A gateway, proxy or mock whose backing environment is unknown
+	EnvironmentCustom Environment = "custom"
+)
+
+// Validates a base URL for an API-compatible endpoint such as an internal
+// gateway or a mock server, normalizing it to end in a slash
+func ParseBaseURL(s string) (BaseURL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL %q: %w", s, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https", s)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", s)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("invalid base URL %q: must not contain credentials, a query or a fragment", s)
+	}
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+	return BaseURL(s), nil
+}
+
+// Reports which DNS Made Easy environment the URL points at, judged by
+// its host name
+func (b BaseURL) Environment() Environment {
+	u, err := url.Parse(string(b))
+	if err != nil {
+		return EnvironmentCustom
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "api.dnsmadeeasy.com":
+		return EnvironmentProd
+	case "api.sandbox.dnsmadeeasy.com":
+		return EnvironmentSandbox
+	}
+	return EnvironmentCustom
+}
+
+// Reports whether the URL points at the DNS Made Easy sandbox
+func (b BaseURL) IsSandbox() bool {
+	return b.Environment() == EnvironmentSandbox
+}
@@ -0,0 +1,125 @@
+package dnsmadeeasy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Splits CreateMulti, UpdateMulti and DeleteMulti into requests of at
+// most size items. Each request is still transactional on its own, but
+// a failing request no longer stops the others; the failures are
+// reported in a *BatchError.
+//
+// NOTE: a size of 0 (the default) sends every item in one request
+func WithBatchSize(size int) Option {
+	return func(c *Client) {
+		c.batchSize = size
+	}
+}
+
+// Makes CreateMulti fall back to creating records individually when
+// DNS Made Easy rejects a createMulti request, so a few bad records
+// don't block the rest. The records that still fail are reported in a
+// *BatchError.
+//
+// NOTE: records are only retried when the API rejected the request, not
+// after a network failure where it may already have been applied
+func WithCreateFallback() Option {
+	return func(c *Client) {
+		c.createFallback = true
+	}
+}
+
+// The failure of one item of a batch operation
+type BatchItemError struct {
+	// The position of the item in the slice passed to the operation
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// Returned by batch operations when some of their items failed. Items
+// sent in the same request share that request's error.
+type BatchError struct {
+	// The positions of the items that were applied
+	Succeeded []int
+
+	// The items that weren't, in order
+	Failed []*BatchItemError
+}
+
+func (e *BatchError) Error() string {
+	// items of a failed chunk share its error; only report it once
+	var errs []error
+	seen := map[error]bool{}
+	for _, item := range e.Failed {
+		if !seen[item.Err] {
+			seen[item.Err] = true
+			errs = append(errs, item.Err)
+		}
+	}
+	total := len(e.Succeeded) + len(e.Failed)
+	return fmt.Sprintf("%d of %d items failed: %v", len(e.Failed), total, errors.Join(errs...))
+}
+
+// Lets errors.Is and errors.As see each item's error
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for idx, item := range e.Failed {
+		errs[idx] = item
+	}
+	return errs
+}
+
+// Calls fn with consecutive chunks of n items, continuing past chunks
+// that fail. Returns a *BatchError if any did. Without WithBatchSize,
+// fn is called once with every item and its error returned as is.
+func (c *Client) chunked(n int, fn func(start, end int) error) error {
+	if c.batchSize <= 0 {
+		return fn(0, n)
+	}
+	return chunkedBy(c.batchSize, n, fn)
+}
+
+// Reports every one of n items as failed with the error of the single
+// request that carried them
+func failedBatch(n int, err error) *BatchError {
+	batchErr := &BatchError{}
+	for idx := range n {
+		batchErr.Failed = append(batchErr.Failed, &BatchItemError{Index: idx, Err: err})
+	}
+	return batchErr
+}
+
+// Calls fn with consecutive chunks of at most size of n items, or a
+// single chunk when size is 0
+func chunkedBy(size, n int, fn func(start, end int) error) error {
+	if size <= 0 {
+		size = n
+	}
+
+	batchErr := &BatchError{}
+	for start := 0; start < n; start += size {
+		end := min(start+size, n)
+		err := fn(start, end)
+		for idx := start; idx < end; idx++ {
+			if err != nil {
+				batchErr.Failed = append(batchErr.Failed, &BatchItemError{Index: idx, Err: err})
+			} else {
+				batchErr.Succeeded = append(batchErr.Succeeded, idx)
+			}
+		}
+	}
+
+	if len(batchErr.Failed) > 0 {
+		return batchErr
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package dnsmadeeasy
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchCreate(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client = GetClient("key", "secret", client.BaseURL, WithBatchSize(2))
+
+	records := []Record{
+		{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		{Name: "b", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		{Name: "invalid", Type: "A", Value: "192.0.2.3", Ttl: 300},
+		{Name: "d", Type: "A", Value: "192.0.2.4", Ttl: 300},
+		{Name: "e", Type: "A", Value: "192.0.2.5", Ttl: 300},
+	}
+	created, err := client.CreateRecords(domain.ID, records)
+	require.Error(t, err)
+	assert.Len(t, created, 3)
+	assert.Equal(t, 3, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records/createMulti"])
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{0, 1, 4}, batchErr.Succeeded)
+	require.Len(t, batchErr.Failed, 2)
+	assert.Equal(t, 2, batchErr.Failed[0].Index)
+	assert.Equal(t, 3, batchErr.Failed[1].Index)
+	assert.EqualError(t, err, "2 of 5 items failed: Record name invalid is not allowed")
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+}
+
+func TestBatchUpdateAndDelete(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "b", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		Record{Name: "c", Type: "A", Value: "192.0.2.3", Ttl: 300},
+	)
+	client = GetClient("key", "secret", client.BaseURL, WithBatchSize(2))
+
+	records, err := client.EnumerateRecords(domain.ID)
+	require.NoError(t, err)
+	for idx := range records {
+		records[idx].Ttl = 600
+	}
+	_, err = client.UpdateRecords(domain.ID, records)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records/updateMulti"])
+
+	ids := []int{records[0].ID, records[1].ID, records[2].ID}
+	fake.fail = func(r *http.Request) bool {
+		return r.Method == http.MethodDelete && r.URL.Query().Get("ids") == itoa(records[2].ID)
+	}
+	deleted, err := client.DeleteRecords(domain.ID, ids)
+	assert.Equal(t, ids[:2], deleted)
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{0, 1}, batchErr.Succeeded)
+	assert.Equal(t, 2, batchErr.Failed[0].Index)
+
+	fake.fail = nil
+	deleted, err = client.DeleteRecords(domain.ID, ids[2:])
+	require.NoError(t, err)
+	assert.Equal(t, ids[2:], deleted)
+	remaining, _ := client.EnumerateRecords(domain.ID)
+	assert.Empty(t, remaining)
+}
+
+func TestBatchUnchunked(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+
+	_, err := client.CreateRecords(domain.ID, []Record{
+		{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		{Name: "invalid", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	})
+	// without WithBatchSize the request's error is returned as is
+	var batchErr *BatchError
+	assert.False(t, errors.As(err, &batchErr))
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.EqualError(t, err, "Record name invalid is not allowed")
+	assert.Equal(t, 1, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records/createMulti"])
+
+	// the fallback still reports the records left out
+	client = GetClient("key", "secret", client.BaseURL, WithCreateFallback())
+	created, err := client.CreateRecords(domain.ID, []Record{
+		{Name: "b", Type: "A", Value: "192.0.2.3", Ttl: 300},
+		{Name: "invalid", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	})
+	assert.Len(t, created, 1)
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{0}, batchErr.Succeeded)
+	require.Len(t, batchErr.Failed, 1)
+	assert.Equal(t, 1, batchErr.Failed[0].Index)
+}
+
+func TestCreateFallback(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client = GetClient("key", "secret", client.BaseURL, WithBatchSize(2), WithCreateFallback())
+
+	records := []Record{
+		{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		{Name: "b", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		{Name: "invalid", Type: "A", Value: "192.0.2.3", Ttl: 300},
+		{Name: "d", Type: "A", Value: "192.0.2.4", Ttl: 300},
+	}
+	created, err := client.CreateRecords(domain.ID, records)
+	assert.Len(t, created, 3)
+	assert.Equal(t, 2, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records"])
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{0, 1, 3}, batchErr.Succeeded)
+	require.Len(t, batchErr.Failed, 1)
+	assert.Equal(t, 2, batchErr.Failed[0].Index)
+	assert.Len(t, fake.recordList(domain.ID), 3)
+
+	// nothing to fall back to when every chunk succeeds
+	created, err = client.CreateRecords(domain.ID, records[:2])
+	require.NoError(t, err)
+	assert.Len(t, created, 2)
+}
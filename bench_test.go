@@ -0,0 +1,150 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+// Record counts the bulk benchmarks run at. 100k is skipped with -short.
+var benchScales = []int{1_000, 10_000, 100_000}
+
+// Set DME_BENCH_PROFILE to a directory to write a CPU and heap profile
+// for each benchmark, named after it; -cpuprofile would mix them all
+// together.
+//
+//	DME_BENCH_PROFILE=prof go test -run x -bench Enumerate -benchmem
+func profile(b *testing.B) {
+	dir := os.Getenv("DME_BENCH_PROFILE")
+	if dir == "" {
+		return
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		b.Fatal(err)
+	}
+	name := filepath.Join(dir, strings.NewReplacer("/", "_", "=", "_").Replace(b.Name()))
+	cpu, err := os.Create(name + ".cpu.pprof")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		pprof.StopCPUProfile()
+		cpu.Close()
+		heap, err := os.Create(name + ".heap.pprof")
+		if err != nil {
+			b.Error(err)
+			return
+		}
+		defer heap.Close()
+		pprof.WriteHeapProfile(heap)
+	})
+}
+
+func benchRecords(n int) []Record {
+	records := make([]Record, n)
+	for idx := range records {
+		records[idx] = Record{
+			Name: fmt.Sprint("host-", idx), Type: "A", Value: "192.0.2.1",
+			Ttl: 1800, GtdLocation: "DEFAULT",
+		}
+	}
+	return records
+}
+
+func benchScale(b *testing.B, fn func(b *testing.B, n int)) {
+	for _, n := range benchScales {
+		b.Run(fmt.Sprint("records=", n), func(b *testing.B) {
+			if testing.Short() && n > 10_000 {
+				b.Skip("skipping large scale in short mode")
+			}
+			fn(b, n)
+		})
+	}
+}
+
+func BenchmarkEnumerateRecords(b *testing.B) {
+	benchScale(b, func(b *testing.B, n int) {
+		fake, client := newFakeDME(b)
+		domain := fake.addDomain("example.com", benchRecords(n)...)
+		profile(b)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			records, err := client.Records(domain.ID).List(context.Background())
+			if err != nil || len(records) != n {
+				b.Fatalf("got %d records: %v", len(records), err)
+			}
+		}
+		b.ReportMetric(float64(n)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+	})
+}
+
+func BenchmarkEnumerateRecordsPaged(b *testing.B) {
+	benchScale(b, func(b *testing.B, n int) {
+		fake, client := newFakeDME(b)
+		client = GetClient("key", "secret", client.BaseURL, WithListPageSize(1000))
+		domain := fake.addDomain("example.com", benchRecords(n)...)
+		profile(b)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for range b.N {
+			records, err := client.Records(domain.ID).List(context.Background())
+			if err != nil || len(records) != n {
+				b.Fatalf("got %d records: %v", len(records), err)
+			}
+		}
+		b.ReportMetric(float64(n)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+	})
+}
+
+func BenchmarkCreateRecords(b *testing.B) {
+	benchScale(b, func(b *testing.B, n int) {
+		fake, client := newFakeDME(b)
+		client = GetClient("key", "secret", client.BaseURL, WithBatchSize(1000))
+		records := benchRecords(n)
+		profile(b)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for idx := range b.N {
+			b.StopTimer()
+			domain := fake.addDomain(fmt.Sprint("example-", idx, ".com"))
+			b.StartTimer()
+			created, err := client.Records(domain.ID).CreateMulti(context.Background(), records)
+			if err != nil || len(created) != n {
+				b.Fatalf("created %d records: %v", len(created), err)
+			}
+		}
+		b.ReportMetric(float64(n)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+	})
+}
+
+func BenchmarkDeleteRecords(b *testing.B) {
+	benchScale(b, func(b *testing.B, n int) {
+		fake, client := newFakeDME(b)
+		client = GetClient("key", "secret", client.BaseURL, WithBatchSize(1000))
+		profile(b)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for idx := range b.N {
+			b.StopTimer()
+			domain := fake.addDomain(fmt.Sprint("example-", idx, ".com"), benchRecords(n)...)
+			ids := make([]int, 0, n)
+			for _, record := range fake.recordList(domain.ID) {
+				ids = append(ids, record.ID)
+			}
+			b.StartTimer()
+			deleted, err := client.Records(domain.ID).DeleteMulti(context.Background(), ids)
+			if err != nil || len(deleted) != n {
+				b.Fatalf("deleted %d records: %v", len(deleted), err)
+			}
+		}
+		b.ReportMetric(float64(n)*float64(b.N)/b.Elapsed().Seconds(), "records/s")
+	})
+}
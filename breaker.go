@@ -0,0 +1,127 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Returned without contacting the API while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open: DNS Made Easy calls are failing")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	// The cool-down has passed and a single trial call is allowed through
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// Stops calling the API for cooldown after threshold consecutive calls
+// fail with a network error, timeout, 5xx or authentication failure.
+// While open, calls fail immediately with ErrCircuitOpen. Once the
+// cool-down passes one trial call is let through; its success closes
+// the circuit and its failure opens it again.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		cb := &circuitBreaker{threshold: threshold, cooldown: cooldown}
+		c.breaker = cb
+		c.resty.
+			OnBeforeRequest(cb.allow).
+			OnSuccess(cb.observeResponse).
+			OnError(cb.observeError)
+	}
+}
+
+// Returns the state of the circuit breaker, CircuitClosed if none is
+// configured
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state()
+}
+
+func (cb *circuitBreaker) state() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch {
+	case cb.failures < cb.threshold:
+		return CircuitClosed
+	case cb.trial || !time.Now().Before(cb.openUntil):
+		return CircuitHalfOpen
+	}
+	return CircuitOpen
+}
+
+func (cb *circuitBreaker) allow(_ *resty.Client, req *resty.Request) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.failures < cb.threshold {
+		return nil
+	}
+	// retries of the trial call are part of the trial
+	if cb.trial && req.Attempt > 1 {
+		return nil
+	}
+	if cb.trial || time.Now().Before(cb.openUntil) {
+		return ErrCircuitOpen
+	}
+	cb.trial = true
+	return nil
+}
+
+func (cb *circuitBreaker) record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.trial = false
+	if !failed {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.failures >= cb.threshold {
+		cb.openUntil = time.Now().Add(cb.cooldown)
+	}
+}
+
+func (cb *circuitBreaker) observeResponse(_ *resty.Client, resp *resty.Response) {
+	status := resp.StatusCode()
+	cb.record(status >= 500 || status == http.StatusUnauthorized || status == http.StatusForbidden)
+}
+
+func (cb *circuitBreaker) observeError(_ *resty.Request, err error) {
+	// neither a fast failure nor the caller giving up says anything
+	// about the API's health
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) {
+		return
+	}
+	cb.record(true)
+}
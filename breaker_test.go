@@ -0,0 +1,51 @@
+package dnsmadeeasy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	fake, client := newFakeDME(t)
+	client = GetClient("key", "secret", client.BaseURL, WithCircuitBreaker(2, 20*time.Millisecond))
+
+	// client errors don't count
+	_, err := client.GetDomain(1)
+	require.Error(t, err)
+	_, err = client.GetDomain(1)
+	require.Error(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	fake.failStatus = http.StatusBadGateway
+	fake.fail = func(r *http.Request) bool { return true }
+	for range 2 {
+		_, err = client.EnumerateDomains()
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	served := fake.served
+	_, err = client.EnumerateDomains()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, served, fake.served)
+
+	// a failed trial reopens the circuit
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, client.CircuitState())
+	_, err = client.EnumerateDomains()
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = client.EnumerateDomains()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// a successful one closes it
+	time.Sleep(25 * time.Millisecond)
+	fake.fail = nil
+	_, err = client.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
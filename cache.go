@@ -0,0 +1,98 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// A read-through cache of each domain's records. Concurrent misses for
+// the same domain share a single request.
+type recordCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[int]recordCacheEntry
+
+	// bumped on every invalidation so a fetch that was in flight when
+	// the domain changed doesn't repopulate the cache with stale data
+	generations map[int]uint64
+
+	group singleflight.Group
+}
+
+type recordCacheEntry struct {
+	records []Record
+	expires time.Time
+}
+
+func newRecordCache(ttl time.Duration) *recordCache {
+	return &recordCache{
+		ttl:         ttl,
+		entries:     map[int]recordCacheEntry{},
+		generations: map[int]uint64{},
+	}
+}
+
+func (rc *recordCache) get(domainId int, fetch func() ([]Record, error)) ([]Record, error) {
+	rc.mu.Lock()
+	entry, ok := rc.entries[domainId]
+	rc.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return copyRecords(entry.records), nil
+	}
+
+	v, err, _ := rc.group.Do(fmt.Sprint(domainId), func() (interface{}, error) {
+		rc.mu.Lock()
+		generation := rc.generations[domainId]
+		rc.mu.Unlock()
+
+		records, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		rc.mu.Lock()
+		if rc.generations[domainId] == generation {
+			rc.entries[domainId] = recordCacheEntry{records, time.Now().Add(rc.ttl)}
+		}
+		rc.mu.Unlock()
+		return records, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyRecords(v.([]Record)), nil
+}
+
+func (rc *recordCache) invalidate(domainId int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, domainId)
+	rc.generations[domainId]++
+}
+
+func copyRecords(records []Record) []Record {
+	if records == nil {
+		return nil
+	}
+	return append([]Record(nil), records...)
+}
+
+// Caches the result of EnumerateRecords per domain for ttl. Writes made
+// through the client invalidate the cached records of that domain.
+func WithRecordCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.recordCache = newRecordCache(ttl)
+	}
+}
+
+// Discards any cached records for the supplied domain
+func (c *Client) InvalidateRecords(domainId int) {
+	c.forgetRead(recordsReadKey(domainId))
+	if c.recordCache != nil {
+		c.recordCache.invalidate(domainId)
+	}
+}
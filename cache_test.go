@@ -0,0 +1,46 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCache(t *testing.T) {
+	fake, client := newFakeDME(t)
+	client = GetClient(client.APIKey, client.SecretKey, client.BaseURL, WithRecordCache(time.Minute))
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	listPath := "GET /dns/managed/" + fmt.Sprint(domain.ID) + "/records"
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := client.EnumerateRecords(domain.ID)
+			assert.NoError(t, err)
+			assert.Len(t, records, 1)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, fake.calls[listPath], 2)
+	fetched := fake.calls[listPath]
+
+	// served from the cache
+	records, err := client.EnumerateRecords(domain.ID)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, fetched, fake.calls[listPath])
+
+	// a write invalidates the domain
+	_, err = client.CreateRecord(domain.ID, Record{Name: "mail", Type: "A", Value: "192.0.2.2"})
+	require.NoError(t, err)
+	records, err = client.EnumerateRecords(domain.ID)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, fetched+1, fake.calls[listPath])
+}
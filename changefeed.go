@@ -0,0 +1,164 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// How a record changed between two snapshots of its zone
+type ChangeType int
+
+const (
+	ChangeAdded ChangeType = iota
+	ChangeModified
+	ChangeRemoved
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeModified:
+		return "modified"
+	case ChangeRemoved:
+		return "removed"
+	}
+	return fmt.Sprintf("ChangeType(%d)", int(t))
+}
+
+func (t ChangeType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *ChangeType) UnmarshalText(text []byte) error {
+	for _, candidate := range []ChangeType{ChangeAdded, ChangeModified, ChangeRemoved} {
+		if candidate.String() == string(text) {
+			*t = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown change type %q", text)
+}
+
+// A single record change seen by a ChangeFeed
+type ChangeEvent struct {
+	Type     ChangeType `json:"type"`
+	Time     time.Time  `json:"time"`
+	DomainID int        `json:"domainId"`
+	Domain   string     `json:"domain"`
+
+	// The record as it is now; as it was, for removals
+	Record Record `json:"record"`
+
+	// The record before a modification
+	Previous *Record `json:"previous,omitempty"`
+}
+
+// Snapshots zones periodically and emits an event for each record
+// added, modified or removed between snapshots, giving downstream
+// systems a change stream DNS Made Easy doesn't offer itself.
+// Records are matched across snapshots by ID.
+type ChangeFeed struct {
+	Client    *Client
+	DomainIDs []int
+	Interval  time.Duration
+
+	// Receives errors from polling; they don't stop the feed. Ignored
+	// if nil.
+	OnError func(error)
+
+	last  map[int][]Record
+	names map[int]string
+}
+
+// Takes a baseline snapshot before returning, then polls every
+// Interval until ctx is done, sending events on the returned channel.
+// The channel is closed when the feed stops.
+func (f *ChangeFeed) Start(ctx context.Context) <-chan ChangeEvent {
+	events := make(chan ChangeEvent)
+	if _, err := f.poll(ctx); err != nil && f.OnError != nil {
+		f.OnError(err)
+	}
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(f.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			changes, err := f.poll(ctx)
+			if err != nil && f.OnError != nil {
+				f.OnError(err)
+			}
+			for _, change := range changes {
+				select {
+				case events <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events
+}
+
+// Snapshots every zone, returning the changes since the last poll.
+// Zones seen for the first time become the baseline.
+func (f *ChangeFeed) poll(ctx context.Context) ([]ChangeEvent, error) {
+	if f.last == nil {
+		f.last = map[int][]Record{}
+		f.names = map[int]string{}
+	}
+	var changes []ChangeEvent
+	var errs []error
+	for _, domainID := range f.DomainIDs {
+		records, err := f.Client.Records(domainID).list(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainID, err))
+			continue
+		}
+		before, seen := f.last[domainID]
+		f.last[domainID] = records
+		if !seen {
+			if domain, err := f.Client.Domains().Get(ctx, domainID); err == nil {
+				f.names[domainID] = domain.Name
+			}
+			continue
+		}
+
+		changes = append(changes, changeEvents(domainID, f.names[domainID], time.Now(), before, records)...)
+	}
+	return changes, errors.Join(errs...)
+}
+
+// Returns an event for each record added, modified or removed between
+// two snapshots of a zone, matching records by ID
+func changeEvents(domainID int, domain string, t time.Time, before, after []Record) []ChangeEvent {
+	event := func(typ ChangeType, record Record) ChangeEvent {
+		return ChangeEvent{Type: typ, Time: t, DomainID: domainID, Domain: domain, Record: record}
+	}
+	previous := make(map[int]Record, len(before))
+	for _, record := range before {
+		previous[record.ID] = record
+	}
+	var changes []ChangeEvent
+	created, updated, deleted := diffByID(before, after)
+	for _, record := range created {
+		changes = append(changes, event(ChangeAdded, record))
+	}
+	for _, record := range updated {
+		change := event(ChangeModified, record)
+		prev := previous[record.ID]
+		change.Previous = &prev
+		changes = append(changes, change)
+	}
+	for _, record := range deleted {
+		changes = append(changes, event(ChangeRemoved, record))
+	}
+	return changes
+}
@@ -0,0 +1,75 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeFeedPoll(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "old", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	)
+	records := fake.recordList(domain.ID)
+	feed := &ChangeFeed{Client: client, DomainIDs: []int{domain.ID}}
+	ctx := context.Background()
+
+	changes, err := feed.poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	fake.mu.Lock()
+	modified := records[0]
+	modified.Ttl = 60
+	fake.records[domain.ID][modified.ID] = modified
+	delete(fake.records[domain.ID], records[1].ID)
+	fake.mu.Unlock()
+	added, err := client.Records(domain.ID).Create(ctx, Record{Name: "new", Type: "A", Value: "192.0.2.3", Ttl: 300})
+	require.NoError(t, err)
+
+	changes, err = feed.poll(ctx)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, ChangeAdded, changes[0].Type)
+	assert.Equal(t, added, changes[0].Record)
+	assert.Equal(t, "example.com", changes[0].Domain)
+	assert.Equal(t, ChangeModified, changes[1].Type)
+	assert.Equal(t, modified, changes[1].Record)
+	assert.Equal(t, &records[0], changes[1].Previous)
+	assert.Equal(t, ChangeRemoved, changes[2].Type)
+	assert.Equal(t, records[1], changes[2].Record)
+
+	b, err := json.Marshal(changes[2])
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"type":"removed"`)
+}
+
+func TestChangeFeedStart(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed := &ChangeFeed{Client: client, DomainIDs: []int{domain.ID}, Interval: 10 * time.Millisecond}
+	events := feed.Start(ctx)
+	_, err := client.Records(domain.ID).Create(ctx, Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, ChangeAdded, event.Type)
+		assert.Equal(t, "www", event.Record.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+	}
+
+	cancel()
+	for range events {
+	}
+}
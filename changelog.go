@@ -0,0 +1,114 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Writes change events to an audit trail, one message per event
+type ChangeWriter interface {
+	WriteChange(event ChangeEvent) error
+}
+
+// Writes each event as a line of JSON. Every event is a single Write,
+// so w may be a *syslog.Writer as well as a file.
+func NewJSONLinesWriter(w io.Writer) ChangeWriter {
+	return &lineWriter{w: w, format: func(e ChangeEvent) (string, error) {
+		b, err := json.Marshal(e)
+		return string(b), err
+	}}
+}
+
+// Writes each event in ArcSight Common Event Format, as most SIEMs
+// ingest. Every event is a single Write, so w may be a *syslog.Writer
+// as well as a file.
+func NewCEFWriter(w io.Writer) ChangeWriter {
+	return &lineWriter{w: w, format: func(e ChangeEvent) (string, error) {
+		return FormatCEF(e), nil
+	}}
+}
+
+type lineWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format func(ChangeEvent) (string, error)
+}
+
+func (l *lineWriter) WriteChange(event ChangeEvent) error {
+	line, err := l.format(event)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = io.WriteString(l.w, line+"\n")
+	return err
+}
+
+// CEF severities by change type; removals are the likeliest sign of
+// tampering
+var cefSeverity = map[ChangeType]int{
+	ChangeAdded:    3,
+	ChangeModified: 5,
+	ChangeRemoved:  7,
+}
+
+// Renders a change event as a CEF message. DNS Made Easy doesn't
+// report who made a change, so events carry what changed and when.
+func FormatCEF(e ChangeEvent) string {
+	ext := []string{
+		"rt=" + fmt.Sprint(e.Time.UnixMilli()),
+		"dhost=" + cefValue(strings.TrimSuffix(AbsoluteName(e.Record.Name, e.Domain), ".")),
+		"externalId=" + fmt.Sprint(e.Record.ID),
+		"cs1Label=domain", "cs1=" + cefValue(e.Domain),
+		"cs2Label=recordType", "cs2=" + cefValue(e.Record.Type),
+		"cs3Label=value", "cs3=" + cefValue(e.Record.Value),
+		"cn1Label=domainId", "cn1=" + fmt.Sprint(e.DomainID),
+		"cn2Label=ttl", "cn2=" + fmt.Sprint(e.Record.Ttl),
+	}
+	if e.Previous != nil {
+		ext = append(ext, "cs4Label=previousValue", "cs4="+cefValue(e.Previous.Value),
+			"cn3Label=previousTtl", "cn3="+fmt.Sprint(e.Previous.Ttl))
+	}
+	return strings.Join([]string{
+		"CEF:0",
+		"DNS Made Easy",
+		cefHeader(UserAgent),
+		"1",
+		"record." + e.Type.String(),
+		"DNS record " + e.Type.String(),
+		fmt.Sprint(cefSeverity[e.Type]),
+		strings.Join(ext, " "),
+	}, "|")
+}
+
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`)
+	cefValueEscaper  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+func cefHeader(s string) string { return cefHeaderEscaper.Replace(s) }
+func cefValue(s string) string  { return cefValueEscaper.Replace(s) }
+
+// Writes every event from events until the channel closes or ctx is
+// done, for connecting a ChangeFeed to an audit trail. Stops at the
+// first write error.
+func ExportChanges(ctx context.Context, events <-chan ChangeEvent, w ChangeWriter) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := w.WriteChange(event); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChange = ChangeEvent{
+	Type:     ChangeModified,
+	Time:     time.UnixMilli(1700000000123).UTC(),
+	DomainID: 7,
+	Domain:   "example.com",
+	Record:   Record{ID: 42, Name: "www", Type: "TXT", Value: `"a=b|c"`, Ttl: 300},
+	Previous: &Record{ID: 42, Name: "www", Type: "TXT", Value: `"old"`, Ttl: 600},
+}
+
+func TestFormatCEF(t *testing.T) {
+	assert.Equal(t, `CEF:0|DNS Made Easy|dnsmadeeasy-go|1|record.modified|DNS record modified|5|`+
+		`rt=1700000000123 dhost=www.example.com externalId=42 cs1Label=domain cs1=example.com cs2Label=recordType cs2=TXT `+
+		`cs3Label=value cs3="a\=b|c" cn1Label=domainId cn1=7 cn2Label=ttl cn2=300 cs4Label=previousValue cs4="old" cn3Label=previousTtl cn3=600`,
+		FormatCEF(testChange))
+}
+
+func TestJSONLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLinesWriter(&buf)
+	require.NoError(t, w.WriteChange(testChange))
+	require.NoError(t, w.WriteChange(ChangeEvent{Type: ChangeAdded}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var got ChangeEvent
+	require.NoError(t, json.Unmarshal(lines[0], &got))
+	assert.Equal(t, testChange.Record, got.Record)
+	assert.Contains(t, string(lines[0]), `"type":"modified"`)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestExportChanges(t *testing.T) {
+	events := make(chan ChangeEvent, 2)
+	events <- testChange
+	events <- testChange
+	close(events)
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportChanges(context.Background(), events, NewCEFWriter(&buf)))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	events = make(chan ChangeEvent, 1)
+	events <- testChange
+	assert.EqualError(t, ExportChanges(context.Background(), events, NewCEFWriter(failingWriter{})), "disk full")
+}
@@ -1,6 +1,7 @@
 package dnsmadeeasy
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/hex"
@@ -8,9 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -27,364 +30,215 @@ const (
 )
 
 type Client struct {
-	APIKey      string
-	SecretKey   string
-	BaseURL     BaseURL
-	resty       *resty.Client
-	zoneIdCache map[string]int
-}
+	APIKey       string
+	SecretKey    string
+	BaseURL      BaseURL
+	resty        *resty.Client
+	zoneIdCache  map[string]int
+	domainsMu    sync.Mutex
+	rateLimit    rateLimitTracker
+	recordCache  *recordCache
+	reads        *singleflight.Group
+	transport    *http.Transport
+	interceptors []Interceptor
+	validators   validatorStore
+	meta         metaRecorder
+	credentials  CredentialsProvider
+
+	version          APIVersion
+	endpointVersions map[string]APIVersion
+
+	breaker      *circuitBreaker
+	retryBudget  time.Duration
+	pollInterval time.Duration
+	pollDeadline time.Duration
+
+	idempotentDeletes  bool
+	idempotentCreates  bool
+	nameservers        []string
+	domainStore        DomainCacheStore
+	domainStoreTTL     time.Duration
+	negativeTTL        time.Duration
+	misses             map[string]time.Time
+	pendingDeleteRetry bool
+	batchSize          int
+	listPageSize       int
+	recordDefaults     RecordDefaults
+	notes              AnnotationStore
+	valueIndex         *ValueIndex
+	ttlState           TTLStateStore
+	approver           Approver
+	snapshots          SnapshotStore
+	snapshotMaxAge     time.Duration
+	createFallback     bool
+	strictDecoding     bool
+	unknownFields      func(target string, fields []string)
+}
+
+// Configures optional client behaviour
+type Option func(*Client)
 
 // Construct a client using the supplied values
-func GetClient(APIKey string, SecretKey string, url BaseURL) *Client {
-	r := resty.New().SetBaseURL(string(url))
-	return &Client{APIKey, SecretKey, url, r, nil}
-}
-
-// Convenience function to determine the error status of a response
-// from DNS Made Easy
-func checkRespForError(resp *resty.Response, err error) (*resty.Response, error) {
-	// first bubble up any error passed to us
-	if err != nil {
-		return resp, err
+func GetClient(APIKey string, SecretKey string, url BaseURL, opts ...Option) *Client {
+	c := &Client{
+		APIKey:       APIKey,
+		SecretKey:    SecretKey,
+		BaseURL:      url,
+		pollInterval: DefaultPollInterval,
+		pollDeadline: DefaultPollDeadline,
 	}
-
-	var data map[string]interface{}
-
-	// next check for json-formatted errors in the response body
-	err = json.Unmarshal(resp.Body(), &data)
-	// no error indicates that we were able to de-serialize some json
-	if err == nil {
-		if data["error"] != nil {
-			// translate the array of strings that is DME's error json element
-			// ie { "error": [ "", "" ] }
-			resp_errors := data["error"].([]interface{})
-			if len(resp_errors) > 0 {
-				var error string
-				if len(resp_errors) == 1 {
-					error = resp_errors[0].(string)
-				} else {
-					for idx, err := range resp_errors {
-						error += fmt.Sprintf("%d: %s\n", idx, err.(string))
-					}
-				}
-				return resp, errors.New(error)
-			}
-		}
+	root, version := splitBaseURL(url)
+	if version == "" {
+		version = DefaultAPIVersion
 	}
-
-	// lastly, check for an HTTP error code
-	status := resp.StatusCode()
-	if status < 200 || status >= 300 {
-		return resp, fmt.Errorf("request returned http error code %d", status)
+	c.version = version
+	c.transport = newTransport()
+	c.resty = resty.New().
+		SetTransport(c.transport).
+		SetBaseURL(root).
+		SetHeader("User-Agent", UserAgent).
+		SetHeader("Accept-Encoding", "gzip").
+		SetTimeout(DefaultRequestTimeout).
+		OnBeforeRequest(markFirstAttempt).
+		OnBeforeRequest(c.applyVersion).
+		OnBeforeRequest(c.addAuthHeaders).
+		OnRequestLog(redactRequestLog).
+		OnAfterResponse(c.rateLimit.observe).
+		OnAfterResponse(c.meta.observe).
+		AddRetryHook(closeRetriedBody)
+	for _, opt := range opts {
+		opt(c)
 	}
-
-	// if we got here, there are no errors
-	return resp, nil
+	return c
 }
 
-// Convenience function to calculate the authentication headers
-// expected by DNS Made Easy
-func (c *Client) addAuthHeaders(req *resty.Request) {
-	requestDate := time.Now().UTC().Format(http.TimeFormat)
-
-	// Calculate the hexadecimal HMAC SHA1 of requestDate using APIKey
-	key := []byte(c.SecretKey)
-	h := hmac.New(sha1.New, key)
-	h.Write([]byte(requestDate))
-	hmacString := hex.EncodeToString(h.Sum(nil))
+// An error reported by DNS Made Easy, either in the body of a response
+// or by its HTTP status
+type APIError struct {
+	StatusCode int
 
-	req.Header.Add("X-Dnsme-Apikey", c.APIKey)
-	req.Header.Add("X-Dnsme-Requestdate", requestDate)
-	req.Header.Add("X-Dnsme-Hmac", hmacString)
-}
+	// The messages from the response's error array, if any
+	Messages []string
 
-// Convenience function to construct a request with common headers
-func (c *Client) newRequest() *resty.Request {
-	req := c.resty.R().ExpectContentType("application/json").
-		SetHeader("Content-Type", "application/json")
-	c.addAuthHeaders(req)
-	return req
+	// Quote this when contacting DNS Made Easy support
+	RequestID string
 }
 
-type Domain struct {
-	ID                 int      `json:"id"`
-	Name               string   `json:"name"`
-	CreatedAt          int      `json:"created"`
-	UpdatedAt          int      `json:"updated"`
-	FolderID           int      `json:"folderId"`
-	ProcessMulti       bool     `json:"processMulti"`
-	ActiveThirdParties []string `json:"activeThirdParties"`
-	GtdEnabled         bool     `json:"gtdEnabled"`
-
-	// Identifies which action is currently pending
-	// Values:
-	//  0 - nothing pending
-	//  1 - pending creation (usable but not fully created throughout the system)
-	//  3 - pending deletion
-	PendingActionID int `json:"pendingActionId"`
+func (e *APIError) Error() string {
+	switch len(e.Messages) {
+	case 0:
+		return fmt.Sprintf("request returned http error code %d", e.StatusCode)
+	case 1:
+		return e.Messages[0]
+	}
+	var msg string
+	for idx, m := range e.Messages {
+		msg += fmt.Sprintf("%d: %s\n", idx, m)
+	}
+	return msg
 }
 
-type DomainsResp struct {
-	TotalRecords int      `json:"totalRecords"`
-	TotalPages   int      `json:"totalPages"`
-	Domains      []Domain `json:"data"`
-	CurrentPage  int      `json:"page"`
+// Lets errors.Is match ErrNotFound against 404 responses
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
 }
 
-// Creates a new domain
-func (c *Client) CreateDomain(domainName string) (Domain, error) {
-	var newDomain Domain
-
-	createDomainBody := fmt.Sprintf(`{"name":"%s"}`, domainName)
-	req := c.newRequest().
-		SetResult(&newDomain).
-		SetBody(createDomainBody)
+// Matches errors for domains, records and other resources that don't
+// exist
+var ErrNotFound = errors.New("not found")
 
-	_, err := checkRespForError(req.Post(DNSManagedPath))
+// Convenience function to determine the error status of a response
+// from DNS Made Easy
+func checkRespForError(resp *resty.Response, err error) (*resty.Response, error) {
+	// first bubble up any error passed to us
 	if err != nil {
-		return Domain{}, err
+		return resp, err
 	}
 
-	return newDomain, nil
-}
-
-// Removes a domain and all associated records
-func (c *Client) DeleteDomain(domainID int) error {
-	_, err := checkRespForError(c.newRequest().
-		Delete(fmt.Sprint(DNSManagedPath, domainID)))
-	return err
-}
-
-// Returns the domain record for a given domain ID
-func (c *Client) GetDomain(domainID int) (Domain, error) {
-	var domain Domain
-	_, err := checkRespForError(c.newRequest().
-		SetResult(&domain).
-		Get(DNSManagedPath + fmt.Sprint(domainID)))
-	if err != nil {
-		return Domain{}, err
+	if err := apiError(resp.StatusCode(), resp.Header().Get(RequestIDHeader), resp.Body()); err != nil {
+		return resp, err
 	}
-	return domain, nil
+	return resp, nil
 }
 
-// Returns a map of Name:ID for all domains managed by the
-// given account
-func (c *Client) EnumerateDomains() (map[string]int, error) {
-	domains := map[string]int{}
-
-	var respDomains DomainsResp
-	_, err := checkRespForError(c.newRequest().
-		SetResult(&respDomains).
-		Get(DNSManagedPath))
-	if err != nil {
-		return nil, err
+// Returns the error reported by a response with the supplied status and
+// body, or nil if it succeeded. Successful responses are judged by
+// status alone, so their bodies are decoded only once, into the
+// caller's result.
+func apiError(status int, requestID string, body []byte) *APIError {
+	if status >= 200 && status < 300 {
+		return nil
 	}
 
-	for _, domain := range respDomains.Domains {
-		domains[domain.Name] = domain.ID
+	apiErr := &APIError{
+		StatusCode: status,
+		RequestID:  requestID,
 	}
 
-	return domains, nil
-}
-
-// Finds the numerical ID for a given domain name
-func (c *Client) IdForDomain(domain string) (int, error) {
-	justPopulated := false
-	if c.zoneIdCache == nil {
-		domainMap, err := c.EnumerateDomains()
-		if err != nil {
-			return 0, err
-		}
-		c.zoneIdCache = domainMap
-		justPopulated = true
+	// DME's error json element is an array of strings,
+	// ie { "error": [ "", "" ] }, though a bare string is tolerated
+	var shape struct {
+		Error json.RawMessage `json:"error"`
 	}
-
-	zoneId, ok := c.zoneIdCache[domain]
-	if ok {
-		return zoneId, nil
-	} else {
-		// if we didn't just populate the cache, refresh it in case
-		// our domain exists now
-		if !justPopulated {
-			domainMap, err := c.EnumerateDomains()
-			if err != nil {
-				return 0, err
-			}
-			c.zoneIdCache = domainMap
-			justPopulated = true
-		}
-		zoneId, ok := c.zoneIdCache[domain]
-		if ok {
-			return zoneId, nil
-		}
+	if json.Unmarshal(body, &shape) != nil || len(shape.Error) == 0 {
+		return apiErr
 	}
-
-	return 0, errors.New("Domain not found")
-}
-
-type Record struct {
-	// A unique name per record Type
-	Name string `json:"name"`
-
-	// A unique identifier for this record
-	ID int `json:"id,omitempty"`
-
-	// Can be one of: A, AAAA, ANAME, CNAME, HTTPRED, MX
-	//                NS, PTR, SRV, TXT, SPF, or SOA
-	Type string `json:"type"`
-
-	// Differs per record type
-	Value string `json:"value"`
-
-	// 1 if the record is the record is domain specific
-	// 0 if the record is part of a template
-	Source int `json:"source,omitempty"`
-
-	// The time to live of the record
-	Ttl int `json:"ttl"`
-
-	// Global Traffic Director location.
-	// Values: DEFAULT, US_EAST, US_WEST, EUROPE,
-	//         ASIA_PAC, OCREANIA, SOUTH_AMERICA
-	GtdLocation string `json:"gtdLocation"`
-
-	// The domain ID of this record
-	SourceId int `json:"sourceId,omitempty"`
-
-	// Indicates if DNS Failover is enabled for an A record
-	Failover bool `json:"failover,omitempty"`
-
-	// Indicates if System Monitoring is enabled for an A record
-	Monitor bool `json:"monitor,omitempty"`
-
-	// For HTTP Redirection Records
-	HardLink bool `json:"hardLink,omitempty"`
-
-	// Indicates if the record has dynamic DNS enabled
-	DynamicDns bool `json:"dynamicDns,omitempty"`
-
-	// Indicates if an A record is in failed status
-	Failed bool `json:"failed,omitempty"`
-
-	// The priority for an MX record
-	MxLevel int `json:"mxLevel,omitempty"`
-
-	// The priority for an SRV record
-	Priority int `json:"priority,omitempty"`
-
-	// The weight for an SRV record
-	Weight int `json:"weight,omitempty"`
-
-	// The port for an SRV record
-	Port int `json:"port,omitempty"`
-}
-
-type RecordsResp struct {
-	TotalRecords int      `json:"totalRecords"`
-	TotalPages   int      `json:"totalPages"`
-	Records      []Record `json:"data"`
-	CurrentPage  int      `json:"page"`
-}
-
-func (c *Client) EnumerateRecords(domainId int) ([]Record, error) {
-	var respRecords RecordsResp
-	req := c.newRequest().
-		SetResult(&respRecords).
-		SetPathParam("domainId", fmt.Sprint(domainId))
-
-	_, err := checkRespForError(req.Get(DNSManagedPath + DNSRecordsPath))
-	if err != nil {
-		return nil, err
+	var single string
+	if json.Unmarshal(shape.Error, &apiErr.Messages) != nil && json.Unmarshal(shape.Error, &single) == nil {
+		apiErr.Messages = []string{single}
 	}
-
-	return respRecords.Records, nil
-}
-
-// Deletes all records for the supplied domain
-func (c *Client) DeleteAllRecords(domainID int) error {
-	_, err := checkRespForError(c.newRequest().
-		SetPathParam("domainId", fmt.Sprint(domainID)).
-		Delete(DNSManagedPath + DNSRecordsPath))
-	return err
+	return apiErr
 }
 
-// Deletes records with numerical IDs for the supplied domain
-//
-// NOTE: will silently continue if a recordId that doesn't belong to the
-// given domainId is passed
-func (c *Client) DeleteRecords(domainId int, recordIds []int) ([]int, error) {
-	var queryString string
-
-	// build query string of ids=X&ids=Y&ids=Z
-	// we can't use other convenience methods since they use
-	// map[string] and only the last id would made it
-	for idx, id := range recordIds {
-		if idx > 0 {
-			queryString += "&"
+// Convenience function to calculate the authentication headers
+// expected by DNS Made Easy
+func (c *Client) addAuthHeaders(_ *resty.Client, req *resty.Request) error {
+	creds := Credentials{c.APIKey, c.SecretKey}
+	if c.credentials != nil {
+		var err error
+		creds, err = c.credentials.Credentials(req.Context())
+		if err != nil {
+			return fmt.Errorf("fetching credentials: %w", err)
 		}
-		queryString += fmt.Sprintf("ids=%d", id)
 	}
 
-	req := c.newRequest().
-		SetPathParam("domainId", fmt.Sprint(domainId)).
-		SetPathParam("recordId", "").
-		SetQueryString(queryString)
-
-	_, err := checkRespForError(req.Delete(DNSManagedPath + DNSRecordPath))
-	if err != nil {
-		return nil, err
-	}
-	return recordIds, nil
-}
-
-// Creates a single record in the supplied domain
-func (c *Client) CreateRecord(domainId int, record Record) (Record, error) {
-	var newRecord Record
+	requestDate := time.Now().UTC().Format(http.TimeFormat)
 
-	req := c.newRequest().
-		SetResult(&newRecord).
-		SetBody(&record).
-		SetPathParam("domainId", fmt.Sprint(domainId))
+	// Calculate the hexadecimal HMAC SHA1 of requestDate using APIKey
+	key := []byte(creds.SecretKey)
+	h := hmac.New(sha1.New, key)
+	h.Write([]byte(requestDate))
+	hmacString := hex.EncodeToString(h.Sum(nil))
 
-	_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath))
-	if err != nil {
-		return Record{}, err
-	}
+	req.Header.Set("X-Dnsme-Apikey", creds.APIKey)
+	req.Header.Set("X-Dnsme-Requestdate", requestDate)
+	req.Header.Set("X-Dnsme-Hmac", hmacString)
+	return nil
+}
 
-	return newRecord, nil
+// Convenience function to construct a request with common headers
+func (c *Client) newRequest(ctx context.Context) *resty.Request {
+	req := c.resty.R().SetContext(ctx).
+		ExpectContentType("application/json").
+		SetHeader("Content-Type", "application/json")
+	return req
 }
 
-// Create many records at once in the supplied domain
+// Sends an authenticated request to an arbitrary API path, relative to
+// the client's BaseURL, for endpoints not yet covered by this package.
+// body, if non-nil, is sent as JSON and a successful response is decoded
+// into result, if non-nil.
 //
-// NOTE: is transactional; an error in creating any record causes none to be created
-func (c *Client) CreateRecords(domainId int, records []Record) ([]Record, error) {
-	var newRecords []Record
-
-	req := c.newRequest().
-		SetResult(&newRecords).
-		SetBody(&records).
-		SetPathParam("domainId", fmt.Sprint(domainId))
-
-	_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath + "/createMulti"))
-	if err != nil {
-		return []Record{}, err
+// NOTE: writes made this way do not invalidate the record cache
+func (c *Client) Do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	req := c.newRequest(ctx)
+	if body != nil {
+		req.SetBody(body)
 	}
-
-	return newRecords, nil
-}
-
-func (c *Client) UpdateRecords(domainId int, records []Record) ([]Record, error) {
-	var updatedRecords []Record
-
-	req := c.newRequest().
-		SetResult(&updatedRecords).
-		SetBody(&records).
-		SetPathParam("domainId", fmt.Sprint(domainId))
-
-	_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath + "/updateMulti"))
-	if err != nil {
-		return []Record{}, err
+	if result != nil {
+		req.SetResult(result)
 	}
 
-	return updatedRecords, nil
+	_, err := checkRespForError(req.Execute(method, path))
+	return err
 }
@@ -0,0 +1,132 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+
+	var got Domain
+	err := client.Do(context.Background(), http.MethodGet, DNSManagedPath+itoa(domain.ID), nil, &got)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Name, got.Name)
+
+	var created Record
+	err = client.Do(context.Background(), http.MethodPost, DNSManagedPath+itoa(domain.ID)+"/records",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1"}, &created)
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	err = client.Do(context.Background(), http.MethodGet, DNSManagedPath+"1", nil, nil)
+	assert.EqualError(t, err, "Domain not found")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.Do(ctx, http.MethodGet, DNSManagedPath, nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestResponseMeta(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.limit, fake.remaining = 150, 150
+	domain := fake.addDomain("example.com")
+
+	ctx, meta := CaptureResponseMeta(context.Background())
+	err := client.Do(ctx, http.MethodGet, DNSManagedPath+itoa(domain.ID), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", meta.RequestID)
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, 149, meta.RateLimit.Remaining)
+
+	_, err = client.GetDomain(domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "req-2", client.LastResponseMeta().RequestID)
+	assert.Equal(t, "req-1", meta.RequestID)
+}
+
+// Requests made in parallel with one capture context all record their
+// metadata in it; run with -race
+func TestResponseMetaConcurrent(t *testing.T) {
+	fake, client := newFakeDME(t)
+	var ids []int
+	for idx := range 8 {
+		ids = append(ids, fake.addDomain("example"+itoa(idx)+".com").ID)
+	}
+
+	ctx, meta := CaptureResponseMeta(context.Background())
+	_, err := client.FetchAllRecordsContext(ctx, ids, 4)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.NotEmpty(t, meta.RequestID)
+}
+
+func TestServices(t *testing.T) {
+	fake, client := newFakeDME(t)
+	ctx := context.Background()
+
+	domain, err := client.Domains().Create(ctx, "example.com")
+	require.NoError(t, err)
+	id, err := client.Domains().IdFor(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ID, id)
+
+	records := client.Records(domain.ID)
+	www, err := records.Create(ctx, Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	require.NoError(t, err)
+
+	monitor := Monitor{Monitor: true, Failover: true, ProtocolID: ProtocolHTTP, Sensitivity: SensitivityMedium, IP1: "192.0.2.1", IP2: "192.0.2.2"}
+	require.NoError(t, client.Monitors().Update(ctx, www.ID, monitor))
+	got, err := client.Monitors().Get(ctx, www.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.1", got.IP1)
+
+	ctx, meta := CaptureResponseMeta(ctx)
+	list, err := records.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].Failover)
+	assert.NotEmpty(t, meta.RequestID)
+
+	require.NoError(t, records.Delete(ctx, www.ID))
+	assert.Empty(t, fake.recordList(domain.ID))
+}
+
+func TestCheckRespForError(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		body   string
+		err    string
+	}{
+		{http.StatusOK, `{"data":[]}`, ""},
+		{http.StatusCreated, `{"error":["ignored on success"]}`, ""},
+		{http.StatusBadRequest, `{"error":["Record name invalid","TTL too low"]}`, "0: Record name invalid\n1: TTL too low\n"},
+		{http.StatusBadRequest, `{"error":"Domain already exists"}`, "Domain already exists"},
+		{http.StatusBadGateway, `<html>Bad Gateway</html>`, "request returned http error code 502"},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(tc.status)
+			fmt.Fprint(w, tc.body)
+		}))
+		client := GetClient("key", "secret", BaseURL(server.URL+"/V2.0/"))
+		_, err := checkRespForError(client.resty.R().Get(server.URL))
+		server.Close()
+		if tc.err == "" {
+			assert.NoError(t, err, tc.body)
+			continue
+		}
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr, tc.body)
+		assert.Equal(t, tc.status, apiErr.StatusCode)
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
@@ -0,0 +1,241 @@
+// Package clientconfig builds DNS Made Easy clients from environment
+// variables and profile-based config files, so programs don't each need
+// their own credential loading boilerplate.
+//
+// The config file, ~/.dme/config by default, is INI formatted with one
+// section per profile:
+//
+//	[default]
+//	api_key = ...
+//	secret_key = ...
+//	environment = prod
+//
+//	[testing]
+//	api_key = ...
+//	secret_key = ...
+//	environment = sandbox
+//
+// environment may be "prod" or "sandbox"; base_url can be set instead to
+// point at any API-compatible endpoint.
+package clientconfig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/john-k/dnsmadeeasy"
+	"github.com/joho/godotenv"
+)
+
+const (
+	EnvAPIKey      = "DME_API_TOKEN"
+	EnvSecretKey   = "DME_API_SECRET"
+	EnvEnvironment = "DME_ENVIRONMENT"
+	EnvBaseURL     = "DME_BASE_URL"
+	EnvProfile     = "DME_PROFILE"
+	EnvConfigFile  = "DME_CONFIG_FILE"
+
+	DefaultProfile = "default"
+)
+
+// Everything needed to construct a client
+type Config struct {
+	Profile   string
+	APIKey    string
+	SecretKey string
+	BaseURL   dnsmadeeasy.BaseURL
+}
+
+// Constructs a client from the config
+func (c Config) Client(opts ...dnsmadeeasy.Option) *dnsmadeeasy.Client {
+	return dnsmadeeasy.GetClient(c.APIKey, c.SecretKey, c.BaseURL, opts...)
+}
+
+// Returns the path of the config file: $DME_CONFIG_FILE if set, or
+// ~/.dme/config
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".dme", "config")
+}
+
+// Translates an environment name into its base URL
+func BaseURLFor(environment string) (dnsmadeeasy.BaseURL, error) {
+	switch strings.ToLower(environment) {
+	case "", "prod", "production":
+		return dnsmadeeasy.Prod, nil
+	case "sandbox":
+		return dnsmadeeasy.Sandbox, nil
+	}
+	return "", fmt.Errorf("unknown environment %q, expected prod or sandbox", environment)
+}
+
+// Reads credentials from the DME_* environment variables
+func FromEnv() (Config, error) {
+	cfg := Config{
+		APIKey:    os.Getenv(EnvAPIKey),
+		SecretKey: os.Getenv(EnvSecretKey),
+	}
+	var err error
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		cfg.BaseURL, err = dnsmadeeasy.ParseBaseURL(baseURL)
+	} else {
+		cfg.BaseURL, err = BaseURLFor(os.Getenv(EnvEnvironment))
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	if cfg.APIKey == "" || cfg.SecretKey == "" {
+		return Config{}, fmt.Errorf("%s and %s must be set", EnvAPIKey, EnvSecretKey)
+	}
+	return cfg, nil
+}
+
+// Reads a single profile from a config file
+func FromFile(path string, profile string) (Config, error) {
+	profiles, err := readProfiles(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, ok := profiles[profile]
+	if !ok {
+		return Config{}, fmt.Errorf("%s: no profile %q", path, profile)
+	}
+	return cfg, nil
+}
+
+// Resolves a config the way command line tools should: a .env file in
+// the working directory is loaded if present, then complete credentials
+// in the environment win, otherwise the profile named by profile,
+// $DME_PROFILE or "default" is read from the config file
+func Load(profile string) (Config, error) {
+	// a missing .env is fine; variables already set take precedence
+	_ = godotenv.Load()
+
+	if profile == "" {
+		if cfg, err := FromEnv(); err == nil {
+			return cfg, nil
+		}
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	path := DefaultPath()
+	cfg, err := FromFile(path, profile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, fmt.Errorf("no credentials: set %s and %s or create %s", EnvAPIKey, EnvSecretKey, path)
+	}
+	return cfg, err
+}
+
+// Loads a config with Load and constructs a client from it
+func NewClient(profile string, opts ...dnsmadeeasy.Option) (*dnsmadeeasy.Client, error) {
+	cfg, err := Load(profile)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Client(opts...), nil
+}
+
+// Returns a Manager holding every profile in the config file
+func NewManager(path string, opts ...dnsmadeeasy.Option) (*dnsmadeeasy.Manager, error) {
+	profiles, err := readProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+	m := dnsmadeeasy.NewManager(opts...)
+	for name, cfg := range profiles {
+		m.Add(dnsmadeeasy.Account{
+			Name:      name,
+			APIKey:    cfg.APIKey,
+			SecretKey: cfg.SecretKey,
+			BaseURL:   cfg.BaseURL,
+		})
+	}
+	return m, nil
+}
+
+// Parses every profile in an INI formatted config file
+func readProfiles(path string) (map[string]Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles := map[string]Config{}
+	environments := map[string]string{}
+	profile := DefaultProfile
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			profile = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, lineNo)
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		cfg := profiles[profile]
+		cfg.Profile = profile
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "api_key":
+			cfg.APIKey = value
+		case "secret_key":
+			cfg.SecretKey = value
+		case "base_url":
+			cfg.BaseURL, err = dnsmadeeasy.ParseBaseURL(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+			}
+		case "environment":
+			environments[profile] = value
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown setting %q", path, lineNo, key)
+		}
+		profiles[profile] = cfg
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg := profiles[name]
+		if cfg.BaseURL == "" {
+			cfg.BaseURL, err = BaseURLFor(environments[name])
+			if err != nil {
+				return nil, fmt.Errorf("%s: profile %s: %w", path, name, err)
+			}
+		}
+		if cfg.APIKey == "" || cfg.SecretKey == "" {
+			return nil, fmt.Errorf("%s: profile %s: api_key and secret_key are required", path, name)
+		}
+		profiles[name] = cfg
+	}
+	return profiles, nil
+}
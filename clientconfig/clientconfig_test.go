@@ -0,0 +1,78 @@
+package clientconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+# shared by the ops team
+[default]
+api_key = prod-key
+secret_key = prod-secret
+
+[profile testing]
+api_key = "sandbox-key"
+secret_key = sandbox-secret
+environment = sandbox
+
+[gateway]
+api_key = gw-key
+secret_key = gw-secret
+base_url = https://dme.internal.example/V2.0/
+`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0600))
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv(EnvSecretKey, "")
+
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, Config{"default", "prod-key", "prod-secret", dnsmadeeasy.Prod}, cfg)
+
+	t.Setenv(EnvProfile, "testing")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, Config{"testing", "sandbox-key", "sandbox-secret", dnsmadeeasy.Sandbox}, cfg)
+
+	cfg, err = Load("gateway")
+	require.NoError(t, err)
+	assert.Equal(t, dnsmadeeasy.BaseURL("https://dme.internal.example/V2.0/"), cfg.BaseURL)
+
+	_, err = Load("missing")
+	assert.ErrorContains(t, err, `no profile "missing"`)
+
+	// credentials in the environment win over the file
+	t.Setenv(EnvAPIKey, "env-key")
+	t.Setenv(EnvSecretKey, "env-secret")
+	t.Setenv(EnvEnvironment, "sandbox")
+	cfg, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, Config{"", "env-key", "env-secret", dnsmadeeasy.Sandbox}, cfg)
+
+	m, err := NewManager(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "gateway", "testing"}, m.Names())
+}
+
+func TestReadProfilesErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown setting":     "[default]\napi_keys = x\n",
+		"missing secret":      "[default]\napi_key = x\n",
+		"unknown environment": "[default]\napi_key = x\nsecret_key = y\nenvironment = staging\n",
+		"invalid base url":    "[default]\napi_key = x\nsecret_key = y\nbase_url = gw.internal\n",
+	} {
+		path := filepath.Join(t.TempDir(), "config")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		_, err := readProfiles(path)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Reads the account periodically and serves the last reading
+type collector struct {
+	client *dme.Client
+	usage  bool
+
+	// stubbed out in tests
+	now func() time.Time
+
+	mu   sync.Mutex
+	last snapshot
+}
+
+// One reading of the account
+type snapshot struct {
+	time     time.Time
+	duration time.Duration
+	err      error
+
+	domains  []dme.Domain
+	records  map[int]int
+	failover []dme.FailoverStatus
+	queries  map[int]int64
+	quota    dme.RateLimit
+}
+
+// Collects every interval until ctx is done
+func (c *collector) run(ctx context.Context, interval time.Duration) {
+	for {
+		s := c.collect(ctx)
+		if s.err != nil {
+			log.Printf("collecting: %v", s.err)
+		}
+		c.mu.Lock()
+		c.last = s
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *collector) collect(ctx context.Context) snapshot {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	start := now()
+	s := snapshot{time: start, records: map[int]int{}, queries: map[int]int64{}}
+	var errs []error
+
+	domains, err := c.client.Domains().List(ctx)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	s.domains = domains
+	ids := make([]int, len(domains))
+	for idx, domain := range domains {
+		ids[idx] = domain.ID
+	}
+
+	zones, err := c.client.FetchAllRecords(ids, 4)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for domainID, records := range zones {
+		s.records[domainID] = len(records)
+	}
+
+	s.failover, err = c.client.FailoverStatuses(ctx, ids)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.usage {
+		usage, err := c.client.Usage().Month(ctx, start.Year(), start.Month())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, u := range usage {
+			if domainID := u.DomainID(); domainID != 0 {
+				s.queries[domainID] += u.Total
+			}
+		}
+	}
+
+	s.quota = c.client.RateLimit()
+	s.duration = now().Sub(start)
+	s.err = errors.Join(errs...)
+	return s
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	s := c.last
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.write(w)
+}
+
+// Writes the snapshot in the Prometheus text exposition format
+func (s snapshot) write(w io.Writer) {
+	p := promWriter{w: w}
+
+	names := map[int]string{}
+	for _, domain := range s.domains {
+		names[domain.ID] = domain.Name
+	}
+
+	p.header("dme_up", "gauge", "Whether the last reading of the account succeeded completely.")
+	p.sample("dme_up", nil, boolValue(!s.time.IsZero() && s.err == nil))
+	if s.time.IsZero() {
+		return
+	}
+	p.header("dme_last_collect_timestamp_seconds", "gauge", "When the account was last read.")
+	p.sample("dme_last_collect_timestamp_seconds", nil, float64(s.time.Unix()))
+	p.header("dme_collect_duration_seconds", "gauge", "How long the last reading took.")
+	p.sample("dme_collect_duration_seconds", nil, s.duration.Seconds())
+
+	p.header("dme_domains", "gauge", "Domains in the account.")
+	p.sample("dme_domains", nil, float64(len(s.domains)))
+
+	domainIDs := make([]int, 0, len(s.records))
+	for id := range s.records {
+		domainIDs = append(domainIDs, id)
+	}
+	sort.Ints(domainIDs)
+	p.header("dme_domain_records", "gauge", "Records in each domain.")
+	for _, id := range domainIDs {
+		p.sample("dme_domain_records", []string{"domain", names[id], "domain_id", fmt.Sprint(id)}, float64(s.records[id]))
+	}
+
+	p.header("dme_record_failed_over", "gauge", "Whether a monitored record is serving a failover address.")
+	for _, status := range s.failover {
+		name := dme.AbsoluteName(status.Record.Name, names[status.DomainID])
+		p.sample("dme_record_failed_over", []string{"domain", names[status.DomainID], "record", strings.TrimSuffix(name, "."), "record_id", fmt.Sprint(status.Record.ID)}, boolValue(status.FailedOver))
+	}
+
+	if s.quota.Limit > 0 {
+		p.header("dme_rate_limit_requests", "gauge", "API requests allowed per window.")
+		p.sample("dme_rate_limit_requests", nil, float64(s.quota.Limit))
+		p.header("dme_rate_limit_remaining", "gauge", "API requests remaining in the current window.")
+		p.sample("dme_rate_limit_remaining", nil, float64(s.quota.Remaining))
+	}
+
+	queried := make([]int, 0, len(s.queries))
+	for id := range s.queries {
+		queried = append(queried, id)
+	}
+	sort.Ints(queried)
+	if len(queried) > 0 {
+		p.header("dme_domain_queries_month", "gauge", "DNS queries answered for each domain this month.")
+		for _, id := range queried {
+			p.sample("dme_domain_queries_month", []string{"domain", names[id], "domain_id", fmt.Sprint(id)}, float64(s.queries[id]))
+		}
+	}
+}
+
+// Formats samples, keeping the first write error
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) header(name, kind, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// Writes a sample; labels alternate names and values
+func (p *promWriter) sample(name string, labels []string, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for idx := 0; idx+1 < len(labels); idx += 2 {
+			if idx > 0 {
+				b.WriteByte(',')
+			}
+			// %q escapes backslashes, quotes and newlines as Prometheus expects
+			fmt.Fprintf(&b, "%s=%q", labels[idx], labels[idx+1])
+		}
+		b.WriteByte('}')
+	}
+	p.printf("%s %g\n", b.String(), value)
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	mux := http.NewServeMux()
+	respond := func(v interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(dme.RequestLimitHeader, "150")
+			w.Header().Set(dme.RequestsRemainingHeader, "120")
+			json.NewEncoder(w).Encode(v)
+		}
+	}
+	mux.HandleFunc("GET /V2.0/dns/managed/{$}", respond(dme.DomainsResp{Domains: []dme.Domain{{ID: 1, Name: "example.com"}, {ID: 2, Name: "example.org"}}}))
+	mux.HandleFunc("GET /V2.0/dns/managed/1/records", respond(dme.RecordsResp{Records: []dme.Record{
+		{ID: 10, Name: "www", Type: "A", Value: "192.0.2.1", Monitor: true, Failover: true, Failed: true},
+		{ID: 11, Name: "", Type: "A", Value: "192.0.2.2"},
+	}}))
+	mux.HandleFunc("GET /V2.0/dns/managed/2/records", respond(dme.RecordsResp{}))
+	mux.HandleFunc("GET /V2.0/monitor/10", respond(dme.Monitor{RecordID: 10, Monitor: true, Failover: true}))
+	mux.HandleFunc("GET /V2.0/usageApi/queriesApi/2024/3", respond(dme.QueryUsageResp{Usage: []dme.QueryUsage{
+		{Year: 2024, Month: 3, Total: 1234, SecondaryEntity: "Domain", SecondaryEntityID: 1},
+	}}))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &collector{
+		client: dme.GetClient("key", "secret", dme.BaseURL(server.URL+"/V2.0/")),
+		usage:  true,
+		now:    func() time.Time { return time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC) },
+	}
+
+	// nothing collected yet
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "# HELP dme_up Whether the last reading of the account succeeded completely.\n# TYPE dme_up gauge\ndme_up 0\n", rec.Body.String())
+
+	c.last = c.collect(context.Background())
+	assert.NoError(t, c.last.err)
+	rec = httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		"dme_up 1",
+		"dme_domains 2",
+		`dme_domain_records{domain="example.com",domain_id="1"} 2`,
+		`dme_domain_records{domain="example.org",domain_id="2"} 0`,
+		`dme_record_failed_over{domain="example.com",record="www.example.com",record_id="10"} 1`,
+		"dme_rate_limit_remaining 120",
+		`dme_domain_queries_month{domain="example.com",domain_id="1"} 1234`,
+	} {
+		assert.Contains(t, strings.Split(body, "\n"), line)
+	}
+}
@@ -0,0 +1,60 @@
+// Command dme-exporter exposes the state of a DNS Made Easy account as
+// Prometheus metrics: record counts per domain, the failover state of
+// monitored records, the API request quota and this month's query
+// volume per domain.
+//
+// Credentials are loaded as by the clientconfig package, from the
+// environment or a profile in ~/.dme/config.
+//
+//	dme-exporter [-profile name] [-listen :9154] [-interval 5m] [-usage=false]
+//
+// The account is read every interval rather than on each scrape, so
+// scraping frequently doesn't use up the request quota.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/john-k/dnsmadeeasy/clientconfig"
+)
+
+func main() {
+	profile := flag.String("profile", "", "config file profile to use")
+	listen := flag.String("listen", ":9154", "address to serve /metrics on")
+	interval := flag.Duration("interval", 5*time.Minute, "how often to read the account")
+	usage := flag.Bool("usage", true, "export query volumes from the usage API")
+	flag.Parse()
+
+	client, err := clientconfig.NewClient(*profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-exporter:", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := &collector{client: client, usage: *usage}
+	go c.run(ctx, *interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", c)
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Printf("serving metrics on %s", *listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, "dme-exporter:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+// Command dme-grpc serves the dmegrpc API over a DNS Made Easy account,
+// for internal services that aren't written in Go. It has no
+// authentication of its own, so listen only where trusted callers can
+// reach it.
+//
+// Credentials are loaded as by the clientconfig package, from the
+// environment or a profile in ~/.dme/config.
+//
+//	dme-grpc [-profile name] [-listen :9090]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/john-k/dnsmadeeasy/clientconfig"
+	"github.com/john-k/dnsmadeeasy/dmegrpc"
+	"github.com/john-k/dnsmadeeasy/dmegrpc/dmepb"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	profile := flag.String("profile", "", "config file profile to use")
+	listen := flag.String("listen", ":9090", "address to serve the API on")
+	flag.Parse()
+
+	client, err := clientconfig.NewClient(*profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-grpc:", err)
+		os.Exit(1)
+	}
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-grpc:", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := grpc.NewServer()
+	dmepb.RegisterDNSServer(server, dmegrpc.NewServer(client))
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	log.Printf("serving on %s", *listen)
+	if err := server.Serve(listener); err != nil {
+		fmt.Fprintln(os.Stderr, "dme-grpc:", err)
+		os.Exit(1)
+	}
+}
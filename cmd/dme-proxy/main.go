@@ -0,0 +1,71 @@
+// Command dme-proxy serves a small REST API over a DNS Made Easy
+// account, so teams can manage their own records without being given
+// the account's credentials. Each team authenticates with a bearer token
+// and may only use the domains the policy file grants it.
+//
+// Credentials are loaded as by the clientconfig package, from the
+// environment or a profile in ~/.dme/config.
+//
+//	dme-proxy -policy policy.yaml [-profile name] [-listen :8080]
+//
+// The API:
+//
+//	GET    /v1/domains                               domains the team may use
+//	GET    /v1/records?name=&type=&value=            search those domains' records
+//	GET    /v1/domains/{domain}/records?name=&type=&value=
+//	PUT    /v1/domains/{domain}/records/{name}/{type}  {"values": [...], "ttl": 300}
+//	DELETE /v1/domains/{domain}/records/{name}/{type}
+//
+// The apex is named "@", and name and value filters may be glob
+// patterns. Changes are logged with the team that made them.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/john-k/dnsmadeeasy/clientconfig"
+)
+
+func main() {
+	profile := flag.String("profile", "", "config file profile to use")
+	listen := flag.String("listen", ":8080", "address to serve the API on")
+	policyFile := flag.String("policy", "", "YAML file of teams and the domains they may use")
+	flag.Parse()
+
+	if *policyFile == "" {
+		fmt.Fprintln(os.Stderr, "dme-proxy: -policy is required")
+		os.Exit(2)
+	}
+	policy, err := loadPolicy(*policyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-proxy:", err)
+		os.Exit(1)
+	}
+	client, err := clientconfig.NewClient(*profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-proxy:", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := &server{client: client, policy: policy, logger: log.Default()}
+	server := &http.Server{Addr: *listen, Handler: s.routes()}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Printf("serving on %s", *listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, "dme-proxy:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Who may use the proxy, and for which domains:
+//
+//	teams:
+//	  - name: web
+//	    tokenSha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    domains: [example.com, "*.example.org"]
+//	  - name: auditors
+//	    tokenSha256: ...
+//	    domains: ["*"]
+//	    readOnly: true
+type Policy struct {
+	Teams []Team `yaml:"teams"`
+}
+
+type Team struct {
+	Name string `yaml:"name"`
+
+	// The hex SHA-256 of the bearer token the team authenticates with,
+	// so the policy file holds no usable secrets
+	TokenSHA256 string `yaml:"tokenSha256"`
+
+	// Domain names, or path.Match patterns of them, the team may use
+	Domains []string `yaml:"domains"`
+
+	// Allow reads only
+	ReadOnly bool `yaml:"readOnly"`
+}
+
+// Reads a YAML policy file
+func loadPolicy(file string) (Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Policy{}, err
+	}
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fmt.Errorf("%s: %w", file, err)
+	}
+	for idx, team := range policy.Teams {
+		if team.Name == "" || len(team.TokenSHA256) != sha256.Size*2 {
+			return Policy{}, fmt.Errorf("%s: team %d needs a name and a tokenSha256", file, idx)
+		}
+		for _, pattern := range team.Domains {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return Policy{}, fmt.Errorf("%s: team %s: bad domain pattern %q", file, team.Name, pattern)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// Returns the team a bearer token belongs to
+func (p Policy) team(token string) (Team, bool) {
+	sum := sha256.Sum256([]byte(token))
+	digest := hex.EncodeToString(sum[:])
+	for _, team := range p.Teams {
+		if subtle.ConstantTimeCompare([]byte(digest), []byte(strings.ToLower(team.TokenSHA256))) == 1 {
+			return team, true
+		}
+	}
+	return Team{}, false
+}
+
+// Reports whether the team may use the domain
+func (t Team) allowed(domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, pattern := range t.Domains {
+		if ok, _ := path.Match(strings.ToLower(pattern), domain); ok {
+			return true
+		}
+	}
+	return false
+}
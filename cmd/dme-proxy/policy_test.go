@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`teams:
+  - name: web
+    tokenSha256: `+tokenHash("web-token")+`
+    domains: [example.com, "*.example.org"]
+`), 0o600))
+	policy, err := loadPolicy(path)
+	require.NoError(t, err)
+
+	team, ok := policy.team("web-token")
+	require.True(t, ok)
+	assert.True(t, team.allowed("Example.COM."))
+	assert.True(t, team.allowed("dev.example.org"))
+	assert.False(t, team.allowed("example.org"))
+	_, ok = policy.team("other")
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(path, []byte("teams:\n  - name: web\n    tokenSha256: plaintext\n"), 0o600))
+	_, err = loadPolicy(path)
+	assert.ErrorContains(t, err, "needs a name and a tokenSha256")
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// The TTL of records created without one
+const defaultTTL = 1800
+
+type server struct {
+	client *dme.Client
+	policy Policy
+
+	// Changes are logged here with the team that made them
+	logger *log.Logger
+}
+
+// The body of an upsert
+type recordSet struct {
+	Values []string `json:"values"`
+
+	// Kept from the existing records, or defaultTTL, if zero
+	Ttl int `json:"ttl"`
+}
+
+type teamKey struct{}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/domains", s.listDomains)
+	mux.HandleFunc("GET /v1/records", s.search)
+	mux.HandleFunc("GET /v1/domains/{domain}/records", s.listRecords)
+	mux.HandleFunc("PUT /v1/domains/{domain}/records/{name}/{type}", s.upsert)
+	mux.HandleFunc("DELETE /v1/domains/{domain}/records/{name}/{type}", s.delete)
+	return s.authenticate(mux)
+}
+
+// Identifies the team from the request's bearer token
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		team, known := s.policy.team(token)
+		if !ok || !known {
+			writeError(w, http.StatusUnauthorized, "missing or unknown bearer token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), teamKey{}, team)))
+	})
+}
+
+func teamOf(r *http.Request) Team {
+	return r.Context().Value(teamKey{}).(Team)
+}
+
+// Lists the domains the team may use
+func (s *server) listDomains(w http.ResponseWriter, r *http.Request) {
+	domains, err := s.permittedDomains(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, domains)
+}
+
+func (s *server) permittedDomains(r *http.Request) ([]dme.Domain, error) {
+	all, err := s.client.Domains().List(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	team := teamOf(r)
+	domains := []dme.Domain{}
+	for _, domain := range all {
+		if team.allowed(domain.Name) {
+			domains = append(domains, domain)
+		}
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Name < domains[j].Name })
+	return domains, nil
+}
+
+// Lists a domain's records, filtered by the name, type and value query
+// parameters; name and value may be glob patterns
+func (s *server) listRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.domainID(w, r, false)
+	if !ok {
+		return
+	}
+	records, err := s.client.Records(id).List(r.Context())
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, filterRecords(records, r))
+}
+
+// Searches the records of every domain the team may use, with the same
+// filters as listRecords
+func (s *server) search(w http.ResponseWriter, r *http.Request) {
+	domains, err := s.permittedDomains(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	type match struct {
+		Domain string `json:"domain"`
+		dme.Record
+	}
+	matches := []match{}
+	for _, domain := range domains {
+		records, err := s.client.Records(domain.ID).List(r.Context())
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		for _, record := range filterRecords(records, r) {
+			matches = append(matches, match{domain.Name, record})
+		}
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+// Sets the records of a name and type to the supplied values, keeping
+// records whose values are unchanged
+func (s *server) upsert(w http.ResponseWriter, r *http.Request) {
+	var body recordSet
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Values) == 0 {
+		writeError(w, http.StatusBadRequest, "expected a JSON body with values")
+		return
+	}
+	id, ok := s.domainID(w, r, true)
+	if !ok {
+		return
+	}
+	name, recordType := recordName(r), strings.ToUpper(r.PathValue("type"))
+	records := s.client.Records(id)
+	set, err := records.GetSet(r.Context(), name, recordType)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	ttl := body.Ttl
+	existing := map[string]dme.Record{}
+	for _, record := range set.Records {
+		existing[record.Value] = record
+		if ttl == 0 {
+			ttl = record.Ttl
+		}
+	}
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	desired := make([]dme.Record, 0, len(body.Values))
+	for _, value := range body.Values {
+		record, ok := existing[value]
+		if !ok {
+			record = dme.Record{Name: name, Type: recordType, Value: value, GtdLocation: dme.GtdDefault}
+		}
+		record.Ttl = ttl
+		desired = append(desired, record)
+	}
+	if _, err := records.ReplaceSet(r.Context(), set, desired); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	s.logger.Printf("%s: set %s %s in %s to %v", teamOf(r).Name, recordType, displayName(name), r.PathValue("domain"), body.Values)
+
+	set, err = records.GetSet(r.Context(), name, recordType)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, set.Records)
+}
+
+// Deletes every record of a name and type
+func (s *server) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.domainID(w, r, true)
+	if !ok {
+		return
+	}
+	name, recordType := recordName(r), strings.ToUpper(r.PathValue("type"))
+	records := s.client.Records(id)
+	set, err := records.GetSet(r.Context(), name, recordType)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if len(set.Records) == 0 {
+		writeError(w, http.StatusNotFound, "no such records")
+		return
+	}
+	if _, err := records.ReplaceSet(r.Context(), set, nil); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	s.logger.Printf("%s: deleted %s %s in %s", teamOf(r).Name, recordType, displayName(name), r.PathValue("domain"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Resolves the request's domain, checking the team may use it, and for
+// writes, change it
+func (s *server) domainID(w http.ResponseWriter, r *http.Request, write bool) (int, bool) {
+	domain := r.PathValue("domain")
+	team := teamOf(r)
+	if !team.allowed(domain) {
+		writeError(w, http.StatusForbidden, "team "+team.Name+" may not use "+domain)
+		return 0, false
+	}
+	if write && team.ReadOnly {
+		writeError(w, http.StatusForbidden, "team "+team.Name+" is read only")
+		return 0, false
+	}
+	id, err := s.client.Domains().IdFor(r.Context(), domain)
+	if err != nil {
+		writeAPIError(w, err)
+		return 0, false
+	}
+	return id, true
+}
+
+// The apex is written as "@" in paths
+func recordName(r *http.Request) string {
+	if name := r.PathValue("name"); name != "@" {
+		return name
+	}
+	return ""
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
+}
+
+func filterRecords(records []dme.Record, r *http.Request) []dme.Record {
+	query := r.URL.Query()
+	name, recordType, value := query.Get("name"), query.Get("type"), query.Get("value")
+	if name == "@" {
+		name = ""
+	}
+	matches := func(pattern, s string) bool {
+		ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(s))
+		return ok
+	}
+	filtered := []dme.Record{}
+	for _, record := range records {
+		if query.Has("name") && !matches(name, record.Name) ||
+			recordType != "" && !strings.EqualFold(recordType, record.Type) ||
+			value != "" && !matches(value, record.Value) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// Reports a failure from the client, hiding DNS Made Easy's details
+// behind a 502 unless the caller can act on them
+func writeAPIError(w http.ResponseWriter, err error) {
+	var apiErr *dme.APIError
+	switch {
+	case errors.Is(err, dme.ErrDomainNotFound), errors.Is(err, dme.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, dme.ErrConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, "DNS Made Easy request failed")
+	}
+}
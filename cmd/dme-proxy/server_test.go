@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a fake account holding example.com, example.org and
+// dev.example.org
+func newFakeAPI(t *testing.T) (*dmetest.FakeAccount, *dme.Client) {
+	f, client := dmetest.NewFakeAccount(t)
+	f.PutDomain(dme.Domain{ID: 1, Name: "example.com"},
+		dme.Record{ID: 10, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+	)
+	f.PutDomain(dme.Domain{ID: 2, Name: "example.org"})
+	f.PutDomain(dme.Domain{ID: 3, Name: "dev.example.org"},
+		dme.Record{ID: 30, Name: "api", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: "DEFAULT"},
+	)
+	return f, client
+}
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newProxy(t *testing.T, opts ...dme.Option) (*dmetest.FakeAccount, *httptest.Server, *bytes.Buffer) {
+	f, client := newFakeAPI(t)
+	for _, opt := range opts {
+		opt(client)
+	}
+	var audit bytes.Buffer
+	s := &server{client: client, logger: log.New(&audit, "", 0), policy: Policy{Teams: []Team{
+		{Name: "web", TokenSHA256: tokenHash("web-token"), Domains: []string{"example.com", "*.example.org"}},
+		{Name: "audit", TokenSHA256: tokenHash("audit-token"), Domains: []string{"*"}, ReadOnly: true},
+	}}}
+	proxy := httptest.NewServer(s.routes())
+	t.Cleanup(proxy.Close)
+	return f, proxy, &audit
+}
+
+func call(t *testing.T, proxy *httptest.Server, token, method, path, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, proxy.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestProxyReads(t *testing.T) {
+	_, proxy, _ := newProxy(t)
+
+	status, _ := call(t, proxy, "", "GET", "/v1/domains", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = call(t, proxy, "wrong", "GET", "/v1/domains", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body := call(t, proxy, "web-token", "GET", "/v1/domains", "")
+	require.Equal(t, http.StatusOK, status)
+	var domains []dme.Domain
+	require.NoError(t, json.Unmarshal([]byte(body), &domains))
+	require.Len(t, domains, 2)
+	assert.Equal(t, "dev.example.org", domains[0].Name)
+	assert.Equal(t, "example.com", domains[1].Name)
+
+	status, body = call(t, proxy, "web-token", "GET", "/v1/domains/example.com/records?type=a&name=w*", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"value":"192.0.2.1"`)
+
+	status, _ = call(t, proxy, "web-token", "GET", "/v1/domains/example.org/records", "")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = call(t, proxy, "audit-token", "GET", "/v1/records?value=192.0.2.*", "")
+	require.Equal(t, http.StatusOK, status)
+	var matches []struct {
+		Domain string `json:"domain"`
+		Name   string `json:"name"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &matches))
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "dev.example.org", matches[0].Domain)
+}
+
+func TestProxyWrites(t *testing.T) {
+	f, proxy, audit := newProxy(t)
+
+	status, body := call(t, proxy, "web-token", "PUT", "/v1/domains/example.com/records/www/A", `{"values": ["192.0.2.1", "192.0.2.2"]}`)
+	require.Equal(t, http.StatusOK, status, body)
+	records := f.Records(1)
+	require.Len(t, records, 2)
+	assert.Equal(t, 10, records[0].ID)
+	assert.Equal(t, dme.Record{ID: records[1].ID, SourceId: 1, Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "DEFAULT"}, records[1])
+
+	status, _ = call(t, proxy, "web-token", "PUT", "/v1/domains/example.com/records/@/mx", `{"values": ["mail"], "ttl": 600}`)
+	require.Equal(t, http.StatusOK, status)
+	records = f.Records(1)
+	require.Len(t, records, 3)
+	assert.Equal(t, dme.Record{ID: records[2].ID, SourceId: 1, Name: "", Type: "MX", Value: "mail", Ttl: 600, GtdLocation: "DEFAULT"}, records[2])
+
+	status, _ = call(t, proxy, "web-token", "DELETE", "/v1/domains/example.com/records/www/A", "")
+	require.Equal(t, http.StatusNoContent, status)
+	assert.Len(t, f.Records(1), 1)
+	status, _ = call(t, proxy, "web-token", "DELETE", "/v1/domains/example.com/records/www/A", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	assert.Equal(t, "web: set A www in example.com to [192.0.2.1 192.0.2.2]\n"+
+		"web: set MX @ in example.com to [mail]\n"+
+		"web: deleted A www in example.com\n", audit.String())
+
+	status, _ = call(t, proxy, "audit-token", "DELETE", "/v1/domains/example.com/records/@/MX", "")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = call(t, proxy, "web-token", "PUT", "/v1/domains/example.com/records/www/A", `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = call(t, proxy, "web-token", "PUT", "/v1/domains/missing.example.org/records/www/A", `{"values": ["192.0.2.1"]}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestProxyHidesAPIErrors(t *testing.T) {
+	faults := &dmetest.FaultInjector{}
+	_, proxy, _ := newProxy(t, faults.Option())
+	faults.Add(dmetest.Fault{Status: http.StatusForbidden})
+
+	status, body := call(t, proxy, "web-token", "GET", "/v1/domains/example.com/records", "")
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.JSONEq(t, `{"error": "DNS Made Easy request failed"}`, body)
+}
+
+// Handlers share one client; run with -race
+func TestProxyConcurrentRequests(t *testing.T) {
+	_, proxy, _ := newProxy(t)
+
+	var wg sync.WaitGroup
+	for idx := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			domain := []string{"example.com", "dev.example.org"}[idx%2]
+			req, err := http.NewRequest("GET", proxy.URL+"/v1/domains/"+domain+"/records", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			req.Header.Set("Authorization", "Bearer web-token")
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode, domain)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+var formFields = []string{"name", "type", "value", "ttl"}
+
+// A record being created or edited
+type form struct {
+	original dme.Record
+	editing  bool
+	values   []string
+	focus    int
+	err      error
+}
+
+func newForm(record dme.Record, editing bool) form {
+	return form{
+		original: record,
+		editing:  editing,
+		values:   []string{record.Name, record.Type, record.Value, strconv.Itoa(record.Ttl)},
+	}
+}
+
+func (f *form) key(msg tea.KeyMsg) {
+	f.err = nil
+	switch msg.Type {
+	case tea.KeyTab, tea.KeyDown:
+		f.focus = (f.focus + 1) % len(f.values)
+	case tea.KeyShiftTab, tea.KeyUp:
+		f.focus = (f.focus + len(f.values) - 1) % len(f.values)
+	case tea.KeyBackspace:
+		if value := f.values[f.focus]; value != "" {
+			runes := []rune(value)
+			f.values[f.focus] = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		f.values[f.focus] += string(msg.Runes)
+	}
+}
+
+// Returns the record the form describes, starting from the original so
+// fields the form doesn't show are kept
+func (f form) record() (dme.Record, error) {
+	record := f.original
+	record.Name = strings.TrimSpace(f.values[0])
+	if record.Name == "@" {
+		record.Name = ""
+	}
+	record.Type = strings.ToUpper(strings.TrimSpace(f.values[1]))
+	record.Value = strings.TrimSpace(f.values[2])
+	ttl, err := strconv.Atoi(strings.TrimSpace(f.values[3]))
+	if err != nil || ttl <= 0 {
+		return dme.Record{}, fmt.Errorf("ttl must be a positive number")
+	}
+	record.Ttl = ttl
+
+	if record.Type == "" || record.Value == "" {
+		return dme.Record{}, fmt.Errorf("type and value are required")
+	}
+	if err := dme.ValidateWildcard(record); err != nil {
+		return dme.Record{}, err
+	}
+	if err := dme.ValidateApex(record); err != nil {
+		return dme.Record{}, err
+	}
+	return record, nil
+}
+
+func (f form) view() string {
+	var b strings.Builder
+	for idx, field := range formFields {
+		value := f.values[idx]
+		if idx == f.focus {
+			value += "_"
+		}
+		fmt.Fprintf(&b, "%s %-6s %s\n", cursor(idx == f.focus), field, value)
+	}
+	if f.err != nil {
+		fmt.Fprintf(&b, "\n%v\n", f.err)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+// Command dme-tui is a terminal UI for browsing and editing DNS Made
+// Easy zones.
+//
+// Credentials are loaded as by the clientconfig package, from the
+// environment or a profile in ~/.dme/config.
+//
+//	dme-tui [-profile name]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/john-k/dnsmadeeasy/clientconfig"
+)
+
+func main() {
+	profile := flag.String("profile", "", "config file profile to use")
+	flag.Parse()
+
+	client, err := clientconfig.NewClient(*profile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dme-tui:", err)
+		os.Exit(1)
+	}
+
+	if _, err := tea.NewProgram(newModel(client), tea.WithAltScreen()).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "dme-tui:", err)
+		os.Exit(1)
+	}
+}
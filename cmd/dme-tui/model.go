@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Records shown per page
+const pageSize = 20
+
+type view int
+
+const (
+	viewDomains view = iota
+	viewRecords
+	viewForm
+	viewConfirm
+)
+
+type model struct {
+	client *dme.Client
+	view   view
+	status string
+	err    error
+
+	domains      []dme.Domain
+	domainCursor int
+
+	domain       dme.Domain
+	records      []dme.Record
+	page, pages  int
+	total        int
+	recordCursor int
+
+	form form
+
+	// the change awaiting confirmation
+	pending dme.Operation
+	before  dme.Record
+}
+
+func newModel(client *dme.Client) model {
+	return model{client: client, status: "loading domains..."}
+}
+
+type domainsMsg struct {
+	domains []dme.Domain
+	err     error
+}
+
+type recordsMsg struct {
+	resp dme.RecordsResp
+	err  error
+}
+
+type appliedMsg struct {
+	op  dme.Operation
+	err error
+}
+
+func (m model) loadDomains() tea.Msg {
+	domains, err := m.client.Domains().List(context.Background())
+	return domainsMsg{domains, err}
+}
+
+func (m model) loadRecords(page int) tea.Cmd {
+	client, domainID := m.client, m.domain.ID
+	return func() tea.Msg {
+		resp, err := client.Records(domainID).ListPage(context.Background(), dme.ListOptions{
+			Rows: pageSize, Page: page, Sort: "name", Direction: dme.Ascending,
+		})
+		return recordsMsg{resp, err}
+	}
+}
+
+func (m model) apply(op dme.Operation) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		_, err := client.Apply([]dme.Operation{op})
+		return appliedMsg{op, err}
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return m.loadDomains
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case domainsMsg:
+		m.err = msg.err
+		m.domains = msg.domains
+		m.status = fmt.Sprintf("%d domains", len(msg.domains))
+		return m, nil
+	case recordsMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.records = msg.resp.Records
+			m.page = max(msg.resp.CurrentPage, 1)
+			m.pages = max(msg.resp.TotalPages, 1)
+			m.total = msg.resp.TotalRecords
+			m.recordCursor = min(m.recordCursor, max(len(m.records)-1, 0))
+		}
+		m.status = fmt.Sprintf("%d records", m.total)
+		return m, nil
+	case appliedMsg:
+		m.err = msg.err
+		m.view = viewRecords
+		if msg.err == nil {
+			m.status = fmt.Sprintf("%sd %s %s", msg.op.Type, msg.op.Record.Name, msg.op.Record.Type)
+		}
+		return m, m.loadRecords(m.page)
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.view {
+		case viewDomains:
+			return m.updateDomains(msg)
+		case viewRecords:
+			return m.updateRecords(msg)
+		case viewForm:
+			return m.updateForm(msg)
+		case viewConfirm:
+			return m.updateConfirm(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateDomains(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.domainCursor = max(m.domainCursor-1, 0)
+	case "down", "j":
+		m.domainCursor = min(m.domainCursor+1, max(len(m.domains)-1, 0))
+	case "r":
+		m.status = "loading domains..."
+		return m, m.loadDomains
+	case "enter":
+		if len(m.domains) == 0 {
+			return m, nil
+		}
+		m.domain = m.domains[m.domainCursor]
+		m.view = viewRecords
+		m.records, m.recordCursor, m.page = nil, 0, 1
+		m.status = "loading records..."
+		return m, m.loadRecords(1)
+	}
+	return m, nil
+}
+
+func (m model) updateRecords(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.view = viewDomains
+		m.err = nil
+	case "up", "k":
+		m.recordCursor = max(m.recordCursor-1, 0)
+	case "down", "j":
+		m.recordCursor = min(m.recordCursor+1, max(len(m.records)-1, 0))
+	case "right", "l", "pgdown":
+		if m.page < m.pages {
+			m.recordCursor = 0
+			return m, m.loadRecords(m.page + 1)
+		}
+	case "left", "h", "pgup":
+		if m.page > 1 {
+			m.recordCursor = 0
+			return m, m.loadRecords(m.page - 1)
+		}
+	case "r":
+		return m, m.loadRecords(m.page)
+	case "n":
+		m.form = newForm(dme.Record{Type: "A", Ttl: 1800, GtdLocation: dme.GtdDefault}, false)
+		m.view = viewForm
+	case "e", "enter":
+		if record, ok := m.selected(); ok {
+			m.form = newForm(record, true)
+			m.view = viewForm
+		}
+	case "d":
+		if record, ok := m.selected(); ok {
+			m.pending = dme.Operation{Type: dme.OpDelete, DomainID: m.domain.ID, Record: record}
+			m.before = record
+			m.view = viewConfirm
+		}
+	}
+	return m, nil
+}
+
+func (m model) selected() (dme.Record, bool) {
+	if m.recordCursor < len(m.records) {
+		return m.records[m.recordCursor], true
+	}
+	return dme.Record{}, false
+}
+
+func (m model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewRecords
+	case "enter":
+		record, err := m.form.record()
+		if err != nil {
+			m.form.err = err
+			return m, nil
+		}
+		op := dme.OpCreate
+		if m.form.editing {
+			op = dme.OpUpdate
+		}
+		m.pending = dme.Operation{Type: op, DomainID: m.domain.ID, Record: record}
+		m.before = m.form.original
+		m.view = viewConfirm
+	default:
+		m.form.key(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.status = "applying..."
+		return m, m.apply(m.pending)
+	case "n", "esc":
+		if m.pending.Type == dme.OpDelete {
+			m.view = viewRecords
+		} else {
+			m.view = viewForm
+		}
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	var b strings.Builder
+	switch m.view {
+	case viewDomains:
+		b.WriteString("Domains\n\n")
+		for idx, domain := range m.domains {
+			fmt.Fprintf(&b, "%s %s\n", cursor(idx == m.domainCursor), domain.Name)
+		}
+		b.WriteString("\nenter: open  r: reload  q: quit\n")
+	case viewRecords:
+		fmt.Fprintf(&b, "%s  (page %d of %d)\n\n", m.domain.Name, m.page, m.pages)
+		for idx, record := range m.records {
+			fmt.Fprintf(&b, "%s %-30s %-6s %-6d %s\n", cursor(idx == m.recordCursor),
+				displayName(record.Name), record.Type, record.Ttl, record.Value)
+		}
+		b.WriteString("\nn: new  e: edit  d: delete  ←/→: page  esc: domains  q: quit\n")
+	case viewForm:
+		title := "New record"
+		if m.form.editing {
+			title = "Edit record"
+		}
+		fmt.Fprintf(&b, "%s in %s\n\n%s", title, m.domain.Name, m.form.view())
+		b.WriteString("\ntab: next field  enter: preview  esc: cancel\n")
+	case viewConfirm:
+		fmt.Fprintf(&b, "Plan for %s\n\n", m.domain.Name)
+		for _, line := range describe(m.pending, m.before) {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("\napply? y/n\n")
+	}
+	if m.err != nil {
+		fmt.Fprintf(&b, "\nerror: %v\n", m.err)
+	} else if m.status != "" {
+		fmt.Fprintf(&b, "\n%s\n", m.status)
+	}
+	return b.String()
+}
+
+// Describes an operation as a plan preview, showing what changes
+func describe(op dme.Operation, before dme.Record) []string {
+	after := op.Record
+	switch op.Type {
+	case dme.OpCreate:
+		return []string{fmt.Sprintf("+ create %s %s %s ttl %d", displayName(after.Name), after.Type, after.Value, after.Ttl)}
+	case dme.OpDelete:
+		return []string{fmt.Sprintf("- delete %s %s %s ttl %d", displayName(before.Name), before.Type, before.Value, before.Ttl)}
+	}
+
+	lines := []string{fmt.Sprintf("~ update %s %s", displayName(before.Name), before.Type)}
+	change := func(field, from, to string) {
+		if from != to {
+			lines = append(lines, fmt.Sprintf("    %s: %s → %s", field, from, to))
+		}
+	}
+	change("name", displayName(before.Name), displayName(after.Name))
+	change("type", before.Type, after.Type)
+	change("value", before.Value, after.Value)
+	change("ttl", strconv.Itoa(before.Ttl), strconv.Itoa(after.Ttl))
+	if len(lines) == 1 {
+		lines = append(lines, "    (no changes)")
+	}
+	return lines
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
+}
+
+func cursor(selected bool) string {
+	if selected {
+		return ">"
+	}
+	return " "
+}
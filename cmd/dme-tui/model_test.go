@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm(t *testing.T) {
+	f := newForm(dme.Record{Name: "www", Type: "MX", Value: "mail", Ttl: 300, MxLevel: 10}, true)
+	f.focus = 2
+	f.key(tea.KeyMsg{Type: tea.KeyBackspace})
+	f.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	f.key(tea.KeyMsg{Type: tea.KeyTab})
+	f.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+
+	record, err := f.record()
+	require.NoError(t, err)
+	assert.Equal(t, "mai2", record.Value)
+	assert.Equal(t, 3000, record.Ttl)
+	assert.Equal(t, 10, record.MxLevel)
+
+	f = newForm(dme.Record{Name: "@", Type: "cname", Value: "other", Ttl: 300}, false)
+	_, err = f.record()
+	assert.Error(t, err)
+}
+
+func TestDescribe(t *testing.T) {
+	before := dme.Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300}
+	after := before
+	after.Value = "192.0.2.2"
+
+	assert.Equal(t, []string{"~ update www A", "    value: 192.0.2.1 → 192.0.2.2"},
+		describe(dme.Operation{Type: dme.OpUpdate, Record: after}, before))
+	assert.Equal(t, []string{"+ create @ A 192.0.2.1 ttl 300"},
+		describe(dme.Operation{Type: dme.OpCreate, Record: dme.Record{Type: "A", Value: "192.0.2.1", Ttl: 300}}, dme.Record{}))
+	assert.Equal(t, []string{"- delete www A 192.0.2.1 ttl 300"},
+		describe(dme.Operation{Type: dme.OpDelete, Record: before}, before))
+}
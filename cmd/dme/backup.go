@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Backs up the given domains, or the whole account, into -dir: a full
+// copy every -full-interval and only the changes since in between.
+// Runs every -interval until interrupted, or once with -once.
+func backup(e *env, args []string) error {
+	fs := e.flagSet("backup")
+	dir := fs.String("dir", "", "directory to keep backups in")
+	interval := fs.Duration("interval", time.Hour, "how often to back up")
+	fullInterval := fs.Duration("full-interval", dme.DefaultFullBackupInterval, "how often to take a full backup")
+	retention := fs.Duration("retention", 0, "how far back zones can be restored; 0 keeps everything")
+	once := fs.Bool("once", false, "back up once and exit")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *dir == "" {
+		return usagef("expected -dir")
+	}
+	if *interval <= 0 || *fullInterval <= 0 || *retention < 0 {
+		return usagef("intervals must be positive")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	var domainIDs []int
+	if len(args) > 0 {
+		if domainIDs, err = resolveDomains(client, args); err != nil {
+			return err
+		}
+	}
+
+	b := &dme.BackupSchedule{
+		Client:       client,
+		Store:        dme.FileBackups{Dir: *dir},
+		DomainIDs:    domainIDs,
+		Interval:     *interval,
+		FullInterval: *fullInterval,
+		Retention:    *retention,
+		OnError:      func(err error) { e.errorf("%v", err) },
+	}
+	if *once {
+		return b.Backup(context.Background())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err = b.Run(ctx)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Streams record changes in the given domains, or the whole account,
+// as CEF or JSON lines to stdout, a file or syslog, for audit trails
+func changesWatch(e *env, args []string) error {
+	fs := e.flagSet("changes watch")
+	format := fs.String("format", "json", "json (one object per line) or cef")
+	path := fs.String("file", "", "file to append to instead of stdout")
+	syslogAddr := fs.String("syslog", "", "send to syslog: \"local\" or host:port (UDP)")
+	interval := fs.Duration("interval", time.Minute, "how often to snapshot the zones")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format != "json" && *format != "cef" {
+		return usagef("unknown format %q, expected json or cef", *format)
+	}
+	if *path != "" && *syslogAddr != "" {
+		return usagef("-file and -syslog are exclusive")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainIDs, err := resolveDomains(client, args)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = e.stdout
+	switch {
+	case *path != "":
+		f, err := os.OpenFile(*path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	case *syslogAddr != "":
+		w, err := dialSyslog(*syslogAddr)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+		out = w
+	}
+	writer := dme.NewJSONLinesWriter(out)
+	if *format == "cef" {
+		writer = dme.NewCEFWriter(out)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	feed := &dme.ChangeFeed{
+		Client:    client,
+		DomainIDs: domainIDs,
+		Interval:  *interval,
+		OnError:   func(err error) { e.errorf("%v", err) },
+	}
+	err = dme.ExportChanges(ctx, feed.Start(ctx), writer)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
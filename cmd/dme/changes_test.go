@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangesWatchUsage(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	code, _, stderr := runDME(client, "changes", "watch", "-format", "leef")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown format "leef"`)
+
+	code, _, stderr = runDME(client, "changes", "watch", "-file", "audit.log", "-syslog", "local")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "exclusive")
+
+	code, _, stderr = runDME(client, "changes", "watch", "missing.example")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "Domain not found")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+func domainsList(e *env, args []string) error {
+	fs := e.flagSet("domains list")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usagef("unexpected arguments")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+
+	domains, err := client.Domains().List(context.Background())
+	if err != nil {
+		return err
+	}
+	t := table{headers: []string{"ID", "NAME", "FOLDER", "GTD", "PENDING"}}
+	for _, domain := range domains {
+		t.add(domain.ID, domain.ID, domain.Name, domain.FolderID, domain.GtdEnabled, domain.PendingActionID)
+	}
+	return e.out.print(domains, t)
+}
+
+func domainsGet(e *env, args []string) error {
+	fs := e.flagSet("domains get")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	domain, err := client.Domains().Get(context.Background(), domainID)
+	if err != nil {
+		return err
+	}
+	t := table{headers: []string{"ID", "NAME", "FOLDER", "GTD", "PENDING"}}
+	t.add(domain.ID, domain.ID, domain.Name, domain.FolderID, domain.GtdEnabled, domain.PendingActionID)
+	return e.out.print(domain, t)
+}
+
+// Accepts a domain as a name or a numerical ID
+func resolveDomain(client *dme.Client, domain string) (int, error) {
+	if id, err := strconv.Atoi(domain); err == nil {
+		return id, nil
+	}
+	return client.Domains().IdFor(context.Background(), domain)
+}
+
+// Resolves domain arguments to IDs; no arguments means every domain
+func resolveDomains(client *dme.Client, args []string) ([]int, error) {
+	var domainIDs []int
+	for _, arg := range args {
+		domainID, err := resolveDomain(client, arg)
+		if err != nil {
+			return nil, err
+		}
+		domainIDs = append(domainIDs, domainID)
+	}
+	if len(args) > 0 {
+		return domainIDs, nil
+	}
+	domains, err := client.Domains().List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, domain := range domains {
+		domainIDs = append(domainIDs, domain.ID)
+	}
+	return domainIDs, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Shows the changes to a domain recorded by dme backup in -dir and dme
+// changes watch in -journal, oldest first
+func history(e *env, args []string) error {
+	fs := e.flagSet("history")
+	dir := fs.String("dir", "", "directory dme backup keeps backups in")
+	journal := fs.String("journal", "", "file dme changes watch -format json appends to")
+	since := fs.Duration("since", 0, "only show changes this recent; 0 shows everything")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	if *dir == "" && *journal == "" {
+		return usagef("expected -dir, -journal or both")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	changes, err := loadHistory(*dir, *journal, []int{domainID})
+	if err != nil {
+		return err
+	}
+
+	var shown []dme.HistoryEntry
+	t := table{headers: []string{"ID", "TIME", "CHANGE", "NAME", "TYPE", "VALUE", "TTL"}}
+	for _, change := range changes {
+		if *since > 0 && time.Since(change.Time) > *since {
+			continue
+		}
+		shown = append(shown, change)
+		value, ttl := change.Record.Value, fmt.Sprint(change.Record.Ttl)
+		if prev := change.Previous; prev != nil {
+			if prev.Value != value {
+				value = prev.Value + " -> " + value
+			}
+			if prev.Ttl != change.Record.Ttl {
+				ttl = fmt.Sprint(prev.Ttl) + " -> " + ttl
+			}
+		}
+		name := change.Record.Name
+		if name == "" {
+			name = "@"
+		}
+		t.add(change.ID, change.ID, change.Time.Format(time.RFC3339), change.Type, name, change.Record.Type, value, ttl)
+	}
+	return e.out.print(shown, t)
+}
+
+// Undoes one change listed by dme history, after confirmation. The
+// change ID may be abbreviated as long as it stays unique.
+func rollback(e *env, args []string) error {
+	fs := e.flagSet("rollback")
+	dir := fs.String("dir", "", "directory dme backup keeps backups in")
+	journal := fs.String("journal", "", "file dme changes watch -format json appends to")
+	force := fs.Bool("force", false, "roll back without asking for confirmation")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a change ID")
+	}
+	if *dir == "" && *journal == "" {
+		return usagef("expected -dir, -journal or both")
+	}
+	changes, err := loadHistory(*dir, *journal, nil)
+	if err != nil {
+		return err
+	}
+	change, err := dme.FindChange(changes, args[0])
+	if err != nil {
+		return err
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+
+	if !*force {
+		fmt.Fprintf(e.stderr, "undo %s of %s %s in %s at %s? [y/N] ", change.Type, change.Record.Type,
+			change.Record.Name, change.Domain, change.Time.Format(time.RFC3339))
+		answer, _ := bufio.NewReader(e.stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errors.New("not confirmed, nothing changed")
+		}
+	}
+	result, err := client.RevertChange(context.Background(), change.ChangeEvent)
+	if err != nil {
+		return err
+	}
+	for _, op := range result.Applied {
+		fmt.Fprintln(e.stderr, op)
+	}
+	return nil
+}
+
+// Returns the changes to the domains, or every domain if domainIDs is
+// empty, found in the backup directory and the journal
+func loadHistory(dir, journal string, domainIDs []int) ([]dme.HistoryEntry, error) {
+	wanted := map[int]bool{}
+	for _, id := range domainIDs {
+		wanted[id] = true
+	}
+	var sources [][]dme.ChangeEvent
+	if dir != "" {
+		store := dme.FileBackups{Dir: dir}
+		ids := domainIDs
+		if len(ids) == 0 {
+			var err error
+			if ids, err = store.Domains(); err != nil {
+				return nil, err
+			}
+		}
+		for _, id := range ids {
+			entries, err := store.LoadBackups(id)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, dme.BackupChanges(entries))
+		}
+	}
+	if journal != "" {
+		f, err := os.Open(journal)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		events, err := dme.ReadChangeJournal(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", journal, err)
+		}
+		var kept []dme.ChangeEvent
+		for _, event := range events {
+			if len(wanted) == 0 || wanted[event.DomainID] {
+				kept = append(kept, event)
+			}
+		}
+		sources = append(sources, kept)
+	}
+	return dme.History(sources...), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryAndRollback(t *testing.T) {
+	api, client := newFakeAPI(t)
+	journal := filepath.Join(t.TempDir(), "changes.jsonl")
+	f, err := os.Create(journal)
+	require.NoError(t, err)
+	w := dme.NewJSONLinesWriter(f)
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	removed := dme.Record{ID: 12, Name: "old", Type: "A", Value: "192.0.2.9", Ttl: 300, GtdLocation: "DEFAULT"}
+	require.NoError(t, w.WriteChange(dme.ChangeEvent{Type: dme.ChangeRemoved, Time: at, DomainID: 1, Domain: "example.com", Record: removed}))
+	require.NoError(t, w.WriteChange(dme.ChangeEvent{Type: dme.ChangeAdded, Time: at, DomainID: 2, Domain: "example.org", Record: dme.Record{ID: 20}}))
+	require.NoError(t, f.Close())
+
+	code, stdout, stderr := runDME(client, "history", "-journal", journal, "example.com")
+	require.Equal(t, exitOK, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2)
+	fields := strings.Fields(lines[1])
+	assert.Equal(t, []string{"2024-03-01T12:00:00Z", "removed", "old", "A", "192.0.2.9", "300"}, fields[1:])
+
+	code, _, stderr = runDME(client, "rollback", "-journal", journal, "-force", fields[0])
+	require.Equal(t, exitOK, code, stderr)
+	records := api.Records(1)
+	require.Len(t, records, 3)
+	assert.Equal(t, "old", records[2].Name)
+	assert.NotEqual(t, 12, records[2].ID)
+
+	code, _, stderr = runDME(client, "rollback", "-journal", journal, "-force", fields[0])
+	assert.NotEqual(t, exitOK, code)
+	assert.Contains(t, stderr, "records changed since they were read")
+}
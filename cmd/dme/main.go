@@ -0,0 +1,255 @@
+// Command dme manages DNS Made Easy domains and records from the
+// command line.
+//
+//	dme [flags] <command> [subcommand] [flags] [args]
+//
+// Credentials are loaded as by the clientconfig package, from the
+// environment or a profile in ~/.dme/config. Every command accepts
+// -output table|json|yaml and -quiet, which prints only IDs, so output
+// can be piped into jq or other commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/clientconfig"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+
+	// returned by "record ensure"; unchanged is exitOK
+	exitCreated = 3
+	exitUpdated = 4
+)
+
+// A subcommand; run receives the subcommand's own arguments
+type command struct {
+	usage string
+	run   func(env *env, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"backup": {
+		"": {"dme backup -dir dir [-once] [-interval d] [-full-interval d] [-retention d] [domain...]", backup},
+	},
+	"changes": {
+		"watch": {"dme changes watch [-format json|cef] [-file path | -syslog addr] [-interval d] [domain...]", changesWatch},
+	},
+	"domains": {
+		"list": {"dme domains list", domainsList},
+		"get":  {"dme domains get <domain>", domainsGet},
+	},
+	"records": {
+		"list":   {"dme records list [-type t] [-name n] [-l selector] [-labels-file path] <domain>", recordsList},
+		"ensure": {"dme record ensure [-ttl n] [-gtd location] [-mx-level n] <domain> <name> <type> <value>", recordEnsure},
+		"reap":   {"dme records reap [-force] [-reverse] [-snapshot-dir dir] [-batch n] [domain...]", recordsReap},
+		"note":   {"dme records note [-file path] [-clear] <domain> <name> <type> [note]", recordsNote},
+		"label":  {"dme records label [-file path] <domain> <name> <type> [key=value | key-]...", recordsLabel},
+		"query":  {"dme records query [-searches file] [-save name] <domain> <query | @name> | -list", recordsQuery},
+	},
+	"history": {
+		"": {"dme history [-dir dir] [-journal file] [-since d] <domain>", history},
+	},
+	"impact": {
+		"": {"dme impact [-domains a,b] [-concurrency n] [-direct] <address | host>", impact},
+	},
+	"mirror": {
+		"": {"dme mirror [-listen addr] [-interval d] [-nameserver host] [-transfer-from prefix,...] [domain...]", mirror},
+	},
+	"monitor": {
+		"show":    {"dme monitor show <domain> <record>", monitorShow},
+		"list":    {"dme monitor list [-failed] [domain...]", monitorList},
+		"set":     {"dme monitor set [-failover] [-protocol p] [-port n] [-sensitivity s] [-ips ip,...] <domain> <record>", monitorSet},
+		"disable": {"dme monitor disable <domain> <record>", monitorDisable},
+	},
+	"rollback": {
+		"": {"dme rollback [-dir dir] [-journal file] [-force] <change-id>", rollback},
+	},
+	"search": {
+		"": {"dme search [-domains a,b] [-concurrency n] <query>", search},
+	},
+	"serve": {
+		"": {"dme serve -config file [-once [-diff format] | -watch] [-dry-run]", serve},
+	},
+	"usage": {
+		"": {"dme usage [-since yyyy-mm] [-until yyyy-mm] [-total] [-format table|csv|json|yaml]", usageReport},
+	},
+	"zone": {
+		"export":    {"dme zone export [-f file] [-format bind|csv|json|octodns] <domain>", zoneExport},
+		"import":    {"dme zone import [-f file] [-format bind|csv|json|octodns] <domain>", zoneImport},
+		"restore":   {"dme zone restore -dir dir -to time [-force] <domain>", zoneRestore},
+		"terraform": {"dme zone terraform [-f file] [-imports-only] <domain...>", zoneTerraform},
+	},
+}
+
+// Alternative spellings of command names
+var aliases = map[string]string{
+	"monitors": "monitor",
+	"domain":   "domains",
+	"record":   "records",
+}
+
+// What commands run with
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	out            *output
+	profile        string
+	client         *dme.Client
+}
+
+// Reports a problem that doesn't stop the command
+func (e *env) errorf(format string, args ...interface{}) {
+	fmt.Fprintf(e.stderr, "dme: "+format+"\n", args...)
+}
+
+// Returns the client, connecting on first use
+func (e *env) dme() (*dme.Client, error) {
+	if e.client == nil {
+		client, err := clientconfig.NewClient(e.profile)
+		if err != nil {
+			return nil, err
+		}
+		e.client = client
+	}
+	return e.client, nil
+}
+
+// An error from bad arguments, reported with the command's usage
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+// An error that exits with a specific status
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e statusError) Error() string {
+	if e.err == nil {
+		return ""
+	}
+	return e.err.Error()
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, nil))
+}
+
+// Runs the command line in args, returning the exit code. client, when
+// set, is used instead of loading configuration.
+func run(args []string, stdout, stderr io.Writer, client *dme.Client) int {
+	e := &env{stdin: os.Stdin, stdout: stdout, stderr: stderr, client: client, out: &output{w: stdout}}
+
+	global := flag.NewFlagSet("dme", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.StringVar(&e.profile, "profile", "", "config file profile to use")
+	e.out.flags(global)
+	global.Usage = func() { printUsage(stderr) }
+	if err := global.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	rest := global.Args()
+	if len(rest) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+	group := rest[0]
+	if alias, ok := aliases[group]; ok {
+		group = alias
+	}
+	// commands without subcommands are registered under ""
+	cmd, ok := commands[group][""]
+	rest = rest[1:]
+	if !ok && len(rest) > 0 {
+		cmd, ok = commands[group][rest[0]]
+		rest = rest[1:]
+	}
+	if !ok {
+		fmt.Fprintf(stderr, "dme: unknown command %q\n", strings.Join(global.Args()[:len(global.Args())-len(rest)], " "))
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	err := cmd.run(e, rest)
+	var usageErr usageError
+	var exitErr statusError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "dme: %v\nusage: %s\n", err, cmd.usage)
+		return exitUsage
+	case errors.Is(err, flag.ErrHelp):
+		return exitUsage
+	case errors.As(err, &exitErr):
+		if exitErr.err != nil {
+			fmt.Fprintln(stderr, "dme:", exitErr.err)
+		}
+		return exitErr.code
+	}
+	fmt.Fprintln(stderr, "dme:", err)
+	return exitError
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: dme [-profile name] [-output table|json|yaml] [-quiet] <command> [subcommand] [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	var usages []string
+	for _, subcommands := range commands {
+		for _, cmd := range subcommands {
+			usages = append(usages, cmd.usage)
+		}
+	}
+	sort.Strings(usages)
+	for _, usage := range usages {
+		fmt.Fprintln(w, "  "+usage)
+	}
+}
+
+// Creates the flag set for a subcommand, with the output flags every
+// command accepts
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	e.out.flags(fs)
+	return fs
+}
+
+// Parses flags wherever they appear among the positional arguments,
+// so "dme records list example.com -output json" works
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns a fake account holding example.com, with an A and an MX
+// record, and example.org, for driving the command
+func newFakeAPI(t *testing.T) (*dmetest.FakeAccount, *dme.Client) {
+	f, client := dmetest.NewFakeAccount(t)
+	f.PutDomain(dme.Domain{ID: 1, Name: "example.com"},
+		dme.Record{ID: 10, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		dme.Record{ID: 11, Name: "", Type: "MX", Value: "mail", Ttl: 1800, MxLevel: 10, GtdLocation: "DEFAULT"},
+	)
+	f.PutDomain(dme.Domain{ID: 2, Name: "example.org", GtdEnabled: true})
+	return f, client
+}
+
+// Runs the command against client, returning the exit code and output
+func runDME(client *dme.Client, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr, client)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestOutputFormats(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	code, stdout, stderr := runDME(client, "domains", "list")
+	require.Equal(t, exitOK, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"ID", "NAME", "FOLDER", "GTD", "PENDING"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"2", "example.org", "0", "true", "0"}, strings.Fields(lines[2]))
+
+	code, stdout, stderr = runDME(client, "-o", "json", "domains", "list")
+	require.Equal(t, exitOK, code, stderr)
+	var domains []dme.Domain
+	require.NoError(t, json.Unmarshal([]byte(stdout), &domains))
+	assert.Len(t, domains, 2)
+
+	code, stdout, stderr = runDME(client, "records", "list", "example.com", "-output", "yaml")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "  id: 10\n")
+	assert.Contains(t, stdout, "  mxLevel: 10\n")
+
+	code, stdout, stderr = runDME(client, "records", "list", "-q", "-type", "A", "1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+	code, stdout, stderr = runDME(client, "records", "list", "-q", "-type", "mx", "1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "11\n", stdout)
+
+	code, stdout, _ = runDME(client, "-quiet", "domains", "get", "example.org")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "2\n", stdout)
+}
+
+func TestErrors(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	code, _, stderr := runDME(client, "domains", "list", "-o", "xml")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown output format "xml"`)
+
+	code, _, _ = runDME(client, "domains", "frobnicate")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr = runDME(client, "domains", "get", "missing.example")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "Domain not found")
+}
+
+func TestRecordEnsure(t *testing.T) {
+	f, client := newFakeAPI(t)
+
+	code, stdout, stderr := runDME(client, "record", "ensure", "example.com", "api", "a", "203.0.113.7", "-ttl", "300", "-q")
+	require.Equal(t, exitCreated, code, stderr)
+	records := f.Records(1)
+	require.Len(t, records, 3)
+	assert.Equal(t, strconv.Itoa(records[2].ID)+"\n", stdout)
+	assert.Equal(t, dme.Record{ID: records[2].ID, SourceId: 1, Name: "api", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: "DEFAULT"}, records[2])
+
+	code, _, stderr = runDME(client, "record", "ensure", "example.com", "api", "A", "203.0.113.7", "-ttl", "300")
+	assert.Equal(t, exitOK, code, stderr)
+
+	code, _, stderr = runDME(client, "record", "ensure", "-ttl", "300", "example.com", "www", "A", "203.0.113.8")
+	assert.Equal(t, exitUpdated, code, stderr)
+	assert.Equal(t, "203.0.113.8", f.Records(1)[0].Value)
+	assert.Len(t, f.Records(1), 3)
+
+	f.PutRecords(1, dme.Record{ID: 50, Name: "www", Type: "A", Value: "203.0.113.9", Ttl: 300})
+	code, _, stderr = runDME(client, "record", "ensure", "example.com", "www", "A", "203.0.113.10")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "refusing to choose")
+
+	code, _, _ = runDME(client, "record", "ensure", "example.com", "www", "A")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRecordsNote(t *testing.T) {
+	f, client := newFakeAPI(t)
+
+	code, _, stderr := runDME(client, "records", "note", "example.com", "www", "a", "fronts the 2019 landing pages")
+	require.Equal(t, exitOK, code, stderr)
+	require.Len(t, f.Records(1), 3)
+	assert.Equal(t, dme.AnnotationName("www"), f.Records(1)[2].Name)
+
+	code, stdout, stderr := runDME(client, "records", "note", "example.com", "www", "A")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "fronts the 2019 landing pages\n", stdout)
+
+	code, _, stderr = runDME(client, "records", "note", "-clear", "example.com", "www", "A")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Len(t, f.Records(1), 2)
+
+	path := filepath.Join(t.TempDir(), "notes.json")
+	code, _, stderr = runDME(client, "records", "note", "-file", path, "example.com", "@", "MX", "relay for the old CRM")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Len(t, f.Records(1), 2)
+	code, stdout, _ = runDME(client, "records", "note", "-file", path, "example.com", "@", "mx")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "relay for the old CRM\n", stdout)
+}
+
+func TestRecordsLabel(t *testing.T) {
+	f, client := newFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "labels.json")
+
+	code, _, stderr := runDME(client, "records", "label", "-file", path, "example.com", "www", "a", "env=prod", "team=web")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Len(t, f.Records(1), 2, "labels kept in a file don't touch the zone")
+
+	code, stdout, stderr := runDME(client, "records", "list", "-l", "env=prod", "-labels-file", path, "-q", "example.com")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	code, _, stderr = runDME(client, "records", "label", "-file", path, "example.com", "www", "A", "team-")
+	require.Equal(t, exitOK, code, stderr)
+	code, stdout, stderr = runDME(client, "-o", "json", "records", "label", "-file", path, "example.com", "www", "A")
+	require.Equal(t, exitOK, code, stderr)
+	assert.JSONEq(t, `{"env": "prod"}`, stdout)
+
+	code, stdout, _ = runDME(client, "records", "list", "-l", "team=web", "-labels-file", path, "-q", "example.com")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+
+	code, _, _ = runDME(client, "records", "list", "-l", "env=", "example.com")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRecordsQuery(t *testing.T) {
+	_, client := newFakeAPI(t)
+	path := filepath.Join(t.TempDir(), "searches.json")
+
+	code, stdout, stderr := runDME(client, "records", "query", "-searches", path, "-save", "web", "-q", "example.com", "type=A", "ttl<=300")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	code, stdout, stderr = runDME(client, "records", "query", "-searches", path, "-q", "example.com", "@web")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	code, stdout, stderr = runDME(client, "records", "query", "-searches", path, "-list")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "type=A ttl<=300")
+
+	code, stdout, stderr = runDME(client, "records", "query", "-searches", path, "-q", "example.com", "name=@")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "11\n", stdout)
+
+	code, _, _ = runDME(client, "records", "query", "example.com", "colour=red")
+	assert.Equal(t, exitUsage, code)
+	code, _, _ = runDME(client, "records", "query", "-searches", path, "example.com", "@missing")
+	assert.Equal(t, exitError, code)
+}
+
+func TestSearch(t *testing.T) {
+	f, client := newFakeAPI(t)
+	f.PutRecords(2, dme.Record{ID: 20, Name: "old", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"})
+
+	code, stdout, stderr := runDME(client, "search", "-q", "value=192.0.2.1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n20\n", stdout)
+
+	code, stdout, stderr = runDME(client, "-o", "json", "search", "-domains", "example.org", "type=A")
+	require.Equal(t, exitOK, code, stderr)
+	var matches []dme.SearchMatch
+	require.NoError(t, json.Unmarshal([]byte(stdout), &matches))
+	require.Len(t, matches, 1)
+	assert.Equal(t, "old.example.org.", matches[0].FQDN)
+
+	code, _, _ = runDME(client, "search")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestImpact(t *testing.T) {
+	f, client := newFakeAPI(t)
+	f.PutRecords(2, dme.Record{ID: 20, Name: "legacy", Type: "CNAME", Value: "www.example.com.", Ttl: 300, GtdLocation: "DEFAULT"})
+
+	code, stdout, stderr := runDME(client, "impact", "-q", "192.0.2.1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n20\n", stdout)
+
+	code, stdout, stderr = runDME(client, "impact", "-q", "-direct", "192.0.2.1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	code, _, _ = runDME(client, "impact")
+	assert.Equal(t, exitUsage, code)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/miekg/dns"
+)
+
+// Serves the given domains, or the whole account, over DNS from copies
+// refreshed every -interval, as a read-only mirror internal resolvers
+// can fall back to during outages
+func mirror(e *env, args []string) error {
+	fs := e.flagSet("mirror")
+	listen := fs.String("listen", ":5353", "address to answer queries on, over UDP and TCP")
+	interval := fs.Duration("interval", 5*time.Minute, "how often to refresh the zones")
+	nameserver := fs.String("nameserver", "", "primary nameserver to name in SOA records")
+	transferFrom := fs.String("transfer-from", "", "comma separated prefixes allowed zone transfers")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return usagef("-interval must be positive")
+	}
+	var prefixes []netip.Prefix
+	if *transferFrom != "" {
+		for _, field := range strings.Split(*transferFrom, ",") {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(field))
+			if err != nil {
+				return usagef("invalid -transfer-from prefix %q", field)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	var domainIDs []int
+	if len(args) > 0 {
+		// otherwise the mirror lists the account's domains on each
+		// refresh, picking up new ones
+		if domainIDs, err = resolveDomains(client, args); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := log.New(e.stderr, "", log.LstdFlags)
+	m := &dme.ZoneMirror{
+		Client:       client,
+		DomainIDs:    domainIDs,
+		Interval:     *interval,
+		Nameserver:   *nameserver,
+		TransferFrom: prefixes,
+		OnError:      func(err error) { logger.Printf("refreshing: %v", err) },
+	}
+
+	udp, err := net.ListenPacket("udp", *listen)
+	if err != nil {
+		return err
+	}
+	tcp, err := net.Listen("tcp", *listen)
+	if err != nil {
+		udp.Close()
+		return err
+	}
+	for _, server := range []*dns.Server{{PacketConn: udp, Handler: m}, {Listener: tcp, Handler: m}} {
+		go server.ActivateAndServe()
+		defer server.Shutdown()
+	}
+	logger.Printf("serving zones on %s", *listen)
+
+	err = m.Run(ctx)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// A monitored record, as shown by the monitor commands
+type monitorRow struct {
+	DomainID int         `json:"domainId"`
+	Record   dme.Record  `json:"record"`
+	Monitor  dme.Monitor `json:"monitor"`
+	Failed   bool        `json:"failedOver"`
+}
+
+func monitorTable(rows []monitorRow) table {
+	t := table{headers: []string{"RECORD ID", "DOMAIN ID", "NAME", "VALUE", "MONITOR", "FAILOVER", "PROTOCOL", "PORT", "IPS", "FAILED OVER"}}
+	for _, row := range rows {
+		name := row.Record.Name
+		if name == "" {
+			name = "@"
+		}
+		t.add(row.Record.ID, row.Record.ID, row.DomainID, name, row.Record.Value, row.Monitor.Monitor, row.Monitor.Failover,
+			row.Monitor.ProtocolID, row.Monitor.Port, strings.Join(row.Monitor.FailoverIPs(), ","), row.Failed)
+	}
+	return t
+}
+
+// Finds the A records a command refers to, by name or record ID
+func findARecords(client *dme.Client, domainID int, ref string) ([]dme.Record, error) {
+	if ref == "@" {
+		ref = ""
+	}
+	records, err := client.Records(domainID).List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	recordID, byID := strconv.Atoi(ref)
+	var matches []dme.Record
+	for _, record := range records {
+		if record.Type != "A" {
+			continue
+		}
+		if (byID == nil && record.ID == recordID) || record.Name == ref {
+			matches = append(matches, record)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no A record %q in domain %d", ref, domainID)
+	}
+	return matches, nil
+}
+
+// Shows the monitor configuration of the A records with the name or ID
+func monitorShow(e *env, args []string) error {
+	fs := e.flagSet("monitor show")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usagef("expected a domain and a record")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	records, err := findARecords(client, domainID, args[1])
+	if err != nil {
+		return err
+	}
+
+	var rows []monitorRow
+	for _, record := range records {
+		monitor, err := client.Monitors().Get(context.Background(), record.ID)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, monitorRow{domainID, record, monitor, record.Failed})
+	}
+	return e.out.print(rows, monitorTable(rows))
+}
+
+// Lists monitored records in the given domains, or the whole account
+func monitorList(e *env, args []string) error {
+	fs := e.flagSet("monitor list")
+	failed := fs.Bool("failed", false, "only list records that have failed over")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := e.out.validate(); err != nil {
+		return err
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+
+	domainIDs, err := resolveDomains(client, args)
+	if err != nil {
+		return err
+	}
+
+	statuses, statusErr := client.FailoverStatuses(context.Background(), domainIDs)
+	rows := []monitorRow{}
+	for _, status := range statuses {
+		if *failed && !status.FailedOver {
+			continue
+		}
+		rows = append(rows, monitorRow{status.DomainID, status.Record, status.Monitor, status.FailedOver})
+	}
+	if err := e.out.print(rows, monitorTable(rows)); err != nil {
+		return err
+	}
+	return statusErr
+}
+
+// Changes the monitor settings given as flags, keeping the rest
+func monitorSet(e *env, args []string) error {
+	fs := e.flagSet("monitor set")
+	enable := fs.Bool("monitor", true, "enable system monitoring")
+	failover := fs.Bool("failover", false, "enable DNS failover")
+	autoFailover := fs.Bool("auto-failover", false, "return to the primary IP once it recovers")
+	protocol := fs.String("protocol", "", "tcp, udp, http, dns, smtp or https")
+	port := fs.Int("port", 0, "port to check, the protocol's default if unset")
+	sensitivity := fs.String("sensitivity", "", "high, medium or low")
+	ips := fs.String("ips", "", "comma separated failover IPs, the record's value first")
+	httpFqdn := fs.String("http-fqdn", "", "host name for HTTP(S) checks")
+	httpFile := fs.String("http-file", "", "path for HTTP(S) checks")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usagef("expected a domain and a record")
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	client, domainID, record, err := singleARecord(e, args)
+	if err != nil {
+		return err
+	}
+	monitor, err := client.Monitors().Get(context.Background(), record.ID)
+	if err != nil {
+		return err
+	}
+
+	if set["monitor"] || !monitor.Monitor {
+		monitor.Monitor = *enable
+	}
+	if set["failover"] {
+		monitor.Failover = *failover
+	}
+	if set["auto-failover"] {
+		monitor.AutoFailover = *autoFailover
+	}
+	if set["protocol"] {
+		if monitor.ProtocolID, err = parseProtocol(*protocol); err != nil {
+			return err
+		}
+		if !set["port"] {
+			monitor.Port = 0
+		}
+	}
+	if set["port"] {
+		monitor.Port = *port
+	}
+	if set["sensitivity"] {
+		if monitor.Sensitivity, err = parseSensitivity(*sensitivity); err != nil {
+			return err
+		}
+	}
+	if set["ips"] {
+		if err := monitor.SetFailoverIPs(strings.Split(*ips, ",")); err != nil {
+			return err
+		}
+	}
+	if set["http-fqdn"] {
+		monitor.HttpFqdn = *httpFqdn
+	}
+	if set["http-file"] {
+		monitor.HttpFile = *httpFile
+	}
+
+	if err := client.Monitors().Update(context.Background(), record.ID, monitor); err != nil {
+		return err
+	}
+	monitor, err = client.Monitors().Get(context.Background(), record.ID)
+	if err != nil {
+		return err
+	}
+	rows := []monitorRow{{domainID, record, monitor, record.Failed}}
+	return e.out.print(rows, monitorTable(rows))
+}
+
+// Turns off monitoring and failover for a record
+func monitorDisable(e *env, args []string) error {
+	fs := e.flagSet("monitor disable")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usagef("expected a domain and a record")
+	}
+	client, domainID, record, err := singleARecord(e, args)
+	if err != nil {
+		return err
+	}
+	monitor, err := client.Monitors().Get(context.Background(), record.ID)
+	if err != nil {
+		return err
+	}
+	monitor.Monitor, monitor.Failover = false, false
+	if err := client.Monitors().Update(context.Background(), record.ID, monitor); err != nil {
+		return err
+	}
+	rows := []monitorRow{{domainID, record, monitor, false}}
+	return e.out.print(rows, monitorTable(rows))
+}
+
+// Resolves a domain and record argument pair that must identify exactly
+// one A record
+func singleARecord(e *env, args []string) (*dme.Client, int, dme.Record, error) {
+	client, err := e.dme()
+	if err != nil {
+		return nil, 0, dme.Record{}, err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return nil, 0, dme.Record{}, err
+	}
+	records, err := findARecords(client, domainID, args[1])
+	if err != nil {
+		return nil, 0, dme.Record{}, err
+	}
+	if len(records) > 1 {
+		return nil, 0, dme.Record{}, fmt.Errorf("%d A records named %q; give a record ID instead", len(records), args[1])
+	}
+	return client, domainID, records[0], nil
+}
+
+func parseProtocol(s string) (dme.Protocol, error) {
+	for p := dme.ProtocolTCP; p <= dme.ProtocolHTTPS; p++ {
+		if strings.EqualFold(p.String(), s) {
+			return p, nil
+		}
+	}
+	return 0, usagef("unknown protocol %q", s)
+}
+
+func parseSensitivity(s string) (dme.Sensitivity, error) {
+	for _, sensitivity := range []dme.Sensitivity{dme.SensitivityHigh, dme.SensitivityMedium, dme.SensitivityLow} {
+		if strings.EqualFold(sensitivity.String(), s) {
+			return sensitivity, nil
+		}
+	}
+	return 0, usagef("unknown sensitivity %q", s)
+}
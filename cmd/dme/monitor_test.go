@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorCommands(t *testing.T) {
+	f, client := newFakeAPI(t)
+
+	code, _, stderr := runDME(client, "monitor", "set", "example.com", "www", "-protocol", "http", "-sensitivity", "low", "-http-fqdn", "www.example.com")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, dme.Monitor{RecordID: 10, Monitor: true, ProtocolID: dme.ProtocolHTTP, Port: 80, Sensitivity: dme.SensitivityLow, HttpFqdn: "www.example.com"}, f.Monitor(10))
+
+	code, _, stderr = runDME(client, "monitor", "set", "example.com", "10", "-failover", "-ips", "192.0.2.1,192.0.2.9")
+	require.Equal(t, exitOK, code, stderr)
+	assert.True(t, f.Monitor(10).Failover)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.9"}, f.Monitor(10).FailoverIPs())
+	assert.Equal(t, dme.ProtocolHTTP, f.Monitor(10).ProtocolID)
+
+	code, stdout, stderr := runDME(client, "monitor", "show", "example.com", "www", "-q")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	// only failed over records are listed with -failed
+	www := f.Records(1)[0]
+	www.Monitor, www.Failover = true, true
+	f.PutRecords(1, www)
+	code, stdout, stderr = runDME(client, "monitor", "list", "-failed", "-q")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "", stdout)
+	www.Failed = true
+	f.PutRecords(1, www)
+	code, stdout, stderr = runDME(client, "monitor", "list", "-failed", "-q")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "10\n", stdout)
+
+	code, _, stderr = runDME(client, "monitor", "disable", "example.com", "www")
+	require.Equal(t, exitOK, code, stderr)
+	assert.False(t, f.Monitor(10).Monitor)
+	assert.False(t, f.Monitor(10).Failover)
+
+	code, _, _ = runDME(client, "monitor", "set", "example.com", "www", "-protocol", "gopher")
+	assert.Equal(t, exitUsage, code)
+	code, _, stderr = runDME(client, "monitor", "show", "example.com", "mail")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, `no A record "mail"`)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// How results are written
+type output struct {
+	w      io.Writer
+	format string
+	quiet  bool
+}
+
+// Registers -output and -quiet, defaulting to anything already set by
+// the global flags
+func (o *output) flags(fs *flag.FlagSet) {
+	if o.format == "" {
+		o.format = formatTable
+	}
+	fs.StringVar(&o.format, "output", o.format, "output format: table, json or yaml")
+	fs.StringVar(&o.format, "o", o.format, "shorthand for -output")
+	fs.BoolVar(&o.quiet, "quiet", o.quiet, "print only IDs")
+	fs.BoolVar(&o.quiet, "q", o.quiet, "shorthand for -quiet")
+}
+
+func (o *output) validate() error {
+	switch o.format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	}
+	return usagef("unknown output format %q", o.format)
+}
+
+// A result as rows for table output
+type table struct {
+	headers []string
+	rows    [][]string
+
+	// the ID of each row, for -quiet
+	ids []string
+}
+
+func (t *table) add(id interface{}, cells ...interface{}) {
+	row := make([]string, len(cells))
+	for idx, cell := range cells {
+		row[idx] = fmt.Sprint(cell)
+	}
+	t.rows = append(t.rows, row)
+	t.ids = append(t.ids, fmt.Sprint(id))
+}
+
+// Prints the ID of each row of t, one per line, as -quiet does
+func (o *output) printIDs(t table) {
+	for _, id := range t.ids {
+		fmt.Fprintln(o.w, id)
+	}
+}
+
+// Writes v in the selected format, using t for tables and IDs
+func (o *output) print(v interface{}, t table) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if o.quiet {
+		o.printIDs(t)
+		return nil
+	}
+
+	switch o.format {
+	case formatJSON:
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case formatYAML:
+		// go through JSON so keys match the API's field names
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(o.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Resolves only the names it is given
+type stubResolver map[string][]string
+
+func (r stubResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r stubResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func TestRecordsReap(t *testing.T) {
+	api, client := newFakeAPI(t)
+	api.PutRecords(1,
+		dme.Record{ID: 12, Name: "old", Type: "CNAME", Value: "gone.example.net."},
+		dme.Record{ID: 13, Name: "blog", Type: "CNAME", Value: "blog.example.net."},
+	)
+	resolver = stubResolver{"blog.example.net.": {"192.0.2.7"}, "mail.example.com.": {"192.0.2.8"}}
+	t.Cleanup(func() { resolver = nil })
+
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	code, stdout, stderr := runDME(client, "records", "reap", "-force", "-snapshot-dir", dir, "-q", "example.com")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "12\n", stdout)
+	assert.Contains(t, stderr, "deleted 1 records")
+
+	var ids []int
+	for _, record := range api.Records(1) {
+		ids = append(ids, record.ID)
+	}
+	assert.Equal(t, []int{10, 11, 13}, ids)
+	matches, _ := filepath.Glob(filepath.Join(dir, "example.com-*.json"))
+	assert.Len(t, matches, 1)
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+func recordsList(e *env, args []string) error {
+	fs := e.flagSet("records list")
+	recordType := fs.String("type", "", "only list records of this type")
+	name := fs.String("name", "", "only list records with this name")
+	selector := fs.String("l", "", "only list records whose labels match, e.g. env=prod,team=web")
+	labelsFile := fs.String("labels-file", "", "JSON file labels are kept in instead of the zone")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	sel, err := dme.ParseSelector(*selector)
+	if err != nil {
+		return usagef("%v", err)
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	var records []dme.Record
+	if len(sel) > 0 {
+		records, err = client.SelectRecords(context.Background(), annotationStore(client, *labelsFile), domainID, sel)
+	} else {
+		records, err = client.Records(domainID).List(context.Background())
+	}
+	if err != nil {
+		return err
+	}
+	wantType := strings.ToUpper(*recordType)
+	filtered := []dme.Record{}
+	for _, record := range records {
+		if (wantType == "" || record.Type == wantType) && (*name == "" || record.Name == *name) {
+			filtered = append(filtered, record)
+		}
+	}
+	return e.out.print(filtered, recordTable(filtered))
+}
+
+// Lists the records of a domain matching a query such as "type=A
+// ttl<300 name=web*", or a saved query given as @name. -save keeps the
+// query under a name for later; -list shows the saved queries.
+func recordsQuery(e *env, args []string) error {
+	fs := e.flagSet("records query")
+	path := fs.String("searches", "dme-searches.json", "file saved queries are kept in")
+	save := fs.String("save", "", "save the query under this name")
+	list := fs.Bool("list", false, "list the saved queries")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	searches := &dme.SavedSearches{Path: *path}
+	if *list {
+		all, err := searches.List()
+		if err != nil {
+			return err
+		}
+		names, err := searches.Names()
+		if err != nil {
+			return err
+		}
+		t := table{headers: []string{"NAME", "QUERY"}}
+		for _, name := range names {
+			t.add(name, name, all[name].String())
+		}
+		return e.out.print(all, t)
+	}
+	if len(args) < 2 {
+		return usagef("expected a domain and a query")
+	}
+
+	var q dme.Query
+	text := strings.Join(args[1:], " ")
+	if name, ok := strings.CutPrefix(text, "@"); ok {
+		if q, err = searches.Get(name); err != nil {
+			return err
+		}
+	} else if q, err = dme.ParseQuery(text); err != nil {
+		return usagef("%v", err)
+	}
+	if *save != "" {
+		if err := searches.Save(*save, q); err != nil {
+			return err
+		}
+	}
+
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	records, err := client.QueryRecords(context.Background(), domainID, q)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		records = []dme.Record{}
+	}
+	return e.out.print(records, recordTable(records))
+}
+
+func recordTable(records []dme.Record) table {
+	t := table{headers: []string{"ID", "NAME", "TYPE", "VALUE", "TTL", "GTD"}}
+	for _, record := range records {
+		name := record.Name
+		if name == "" {
+			name = "@"
+		}
+		t.add(record.ID, record.ID, name, record.Type, record.Value, record.Ttl, record.GtdLocation)
+	}
+	return t
+}
+
+// Shows or sets the note explaining why the records of a name and type
+// exist. Notes are kept in a companion TXT record in the zone, or in
+// -file for zones where extra records are unwelcome.
+func recordsNote(e *env, args []string) error {
+	fs := e.flagSet("records note")
+	path := fs.String("file", "", "JSON file to keep notes in instead of the zone")
+	clearNote := fs.Bool("clear", false, "remove the note")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 3 && len(args) != 4 {
+		return usagef("expected a domain, name, type and optionally a note")
+	}
+	if *clearNote && len(args) == 4 {
+		return usagef("-clear doesn't take a note")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	name, recordType := args[1], strings.ToUpper(args[2])
+	if name == "@" {
+		name = ""
+	}
+
+	store := annotationStore(client, *path)
+	ctx := context.Background()
+	switch {
+	case *clearNote:
+		return dme.SetNote(ctx, store, domainID, name, recordType, "")
+	case len(args) == 4:
+		return dme.SetNote(ctx, store, domainID, name, recordType, args[3])
+	}
+	a, _, err := store.Get(ctx, domainID, name, recordType)
+	if err != nil {
+		return err
+	}
+	if a.Note != "" {
+		fmt.Fprintln(e.stdout, a.Note)
+	}
+	return nil
+}
+
+// Shows or changes the labels of the records of a name and type, for
+// selecting them with records list -l. Labels are given as key=value to
+// set and key- to remove.
+func recordsLabel(e *env, args []string) error {
+	fs := e.flagSet("records label")
+	path := fs.String("file", "", "JSON file to keep labels in instead of the zone")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 3 {
+		return usagef("expected a domain, name, type and optionally labels")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	name, recordType := args[1], strings.ToUpper(args[2])
+	if name == "@" {
+		name = ""
+	}
+
+	store := annotationStore(client, *path)
+	ctx := context.Background()
+	a, _, err := store.Get(ctx, domainID, name, recordType)
+	if err != nil {
+		return err
+	}
+	if len(args) == 3 {
+		t := table{headers: []string{"KEY", "VALUE"}}
+		keys := make([]string, 0, len(a.Labels))
+		for key := range a.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			t.add(key, key, a.Labels[key])
+		}
+		return e.out.print(a.Labels, t)
+	}
+
+	labels := map[string]string{}
+	for key, value := range a.Labels {
+		labels[key] = value
+	}
+	for _, arg := range args[3:] {
+		if key, ok := strings.CutSuffix(arg, "-"); ok && !strings.Contains(arg, "=") {
+			delete(labels, key)
+			continue
+		}
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return usagef("expected key=value or key-, got %q", arg)
+		}
+		labels[key] = value
+	}
+	return dme.SetLabels(ctx, store, domainID, name, recordType, labels)
+}
+
+// Returns the annotation store kept in path, or in the zone itself
+func annotationStore(client *dme.Client, path string) dme.AnnotationStore {
+	if path == "" {
+		return dme.TXTAnnotations{Client: client}
+	}
+	return &dme.FileAnnotations{Path: path}
+}
+
+// Creates or updates a record so the zone holds name/type with value,
+// for idempotent use in deploy scripts. Exits 0 when the record was
+// already as requested, exitCreated or exitUpdated otherwise.
+//
+// An existing record with the same value is updated if its settings
+// differ; failing that, the single record of the same name and type is
+// changed to the new value. Several records of that name and type are
+// ambiguous and left alone.
+func recordEnsure(e *env, args []string) error {
+	fs := e.flagSet("record ensure")
+	ttl := fs.Int("ttl", 1800, "record TTL in seconds")
+	gtd := fs.String("gtd", "DEFAULT", "GTD location")
+	mxLevel := fs.Int("mx-level", 0, "MX preference")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 4 {
+		return usagef("expected a domain, name, type and value")
+	}
+	if err := e.out.validate(); err != nil {
+		return err
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	want := dme.Record{
+		Name:        args[1],
+		Type:        strings.ToUpper(args[2]),
+		Value:       args[3],
+		Ttl:         *ttl,
+		GtdLocation: *gtd,
+		MxLevel:     *mxLevel,
+	}
+	if want.Name == "@" {
+		want.Name = ""
+	}
+
+	ctx := context.Background()
+	records, err := client.Records(domainID).List(ctx)
+	if err != nil {
+		return err
+	}
+	var candidates []dme.Record
+	for _, record := range records {
+		if record.Name != want.Name || record.Type != want.Type {
+			continue
+		}
+		if record.Value == want.Value {
+			candidates = []dme.Record{record}
+			break
+		}
+		candidates = append(candidates, record)
+	}
+
+	var code int
+	switch len(candidates) {
+	case 0:
+		want, err = client.Records(domainID).Create(ctx, want)
+		if err != nil {
+			return err
+		}
+		code = exitCreated
+	case 1:
+		current := candidates[0]
+		updated := current
+		updated.Value, updated.Ttl, updated.GtdLocation, updated.MxLevel = want.Value, want.Ttl, want.GtdLocation, want.MxLevel
+		if updated == current {
+			want, code = current, exitOK
+			break
+		}
+		if err := client.Records(domainID).Update(ctx, updated); err != nil {
+			return err
+		}
+		want, code = updated, exitUpdated
+	default:
+		return fmt.Errorf("%d %s records named %q; refusing to choose one to change", len(candidates), want.Type, args[1])
+	}
+
+	if err := e.out.print(want, recordTable([]dme.Record{want})); err != nil {
+		return err
+	}
+	if code == exitOK {
+		return nil
+	}
+	return statusError{code: code}
+}
+
+// Used for dangling record lookups; the system resolver if nil.
+// Stubbed out in tests.
+var resolver dme.HostResolver
+
+// Deletes records whose targets no longer resolve, after showing the
+// plan and asking for confirmation on stdin unless -force is given.
+// Each affected zone is snapshotted to -snapshot-dir first.
+func recordsReap(e *env, args []string) error {
+	fs := e.flagSet("records reap")
+	force := fs.Bool("force", false, "delete without asking for confirmation")
+	reverse := fs.Bool("reverse", false, "also reap A and AAAA records failing reverse DNS checks")
+	snapshotDir := fs.String("snapshot-dir", "dme-snapshots", "directory to snapshot zones to before deleting")
+	batch := fs.Int("batch", 50, "records deleted per request")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainIDs, err := resolveDomains(client, args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	plan, err := client.PlanReap(ctx, domainIDs, dme.DanglingOptions{Resolver: resolver, CheckReverse: *reverse})
+	if err != nil {
+		// a failed lookup only leaves its records out of the plan
+		e.errorf("%v", err)
+	}
+	if len(plan.Records) == 0 {
+		fmt.Fprintln(e.stderr, "no dangling records found")
+		return nil
+	}
+	t := table{headers: []string{"ID", "DOMAIN", "NAME", "TYPE", "VALUE", "REASON"}}
+	for _, d := range plan.Records {
+		name := d.Record.Name
+		if name == "" {
+			name = "@"
+		}
+		t.add(d.Record.ID, d.Record.ID, d.Domain, name, d.Record.Type, d.Record.Value, d.Reason)
+	}
+	if err := e.out.print(plan.Records, t); err != nil {
+		return err
+	}
+
+	if !*force {
+		fmt.Fprintf(e.stderr, "delete %d records? [y/N] ", len(plan.Records))
+		answer, _ := bufio.NewReader(e.stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errors.New("not confirmed, nothing deleted")
+		}
+	}
+	plan.Approve()
+
+	result, err := client.Reap(ctx, plan, dme.ReapOptions{SnapshotDir: *snapshotDir, BatchSize: *batch})
+	for _, d := range result.Skipped {
+		e.errorf("skipped %s %s in %s: changed since it was planned", d.Record.Type, d.Record.Name, d.Domain)
+	}
+	fmt.Fprintf(e.stderr, "deleted %d records\n", len(result.Deleted))
+	return err
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Finds the records matching a query, such as value=203.0.113.7, in
+// every domain of the account or those given with -domains. Domains
+// that can't be read are reported after the matches found elsewhere.
+func search(e *env, args []string) error {
+	fs := e.flagSet("search")
+	domains := fs.String("domains", "", "comma separated domains to search instead of the whole account")
+	concurrency := fs.Int("concurrency", 8, "domains searched at once")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usagef("expected a query")
+	}
+	q, err := dme.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return usagef("%v", err)
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	opts := dme.SearchOptions{Concurrency: *concurrency}
+	if *domains != "" {
+		if opts.DomainIDs, err = resolveDomains(client, strings.Split(*domains, ",")); err != nil {
+			return err
+		}
+	}
+
+	matches, searchErr := client.SearchAllZones(context.Background(), q, opts)
+	if matches == nil {
+		matches = []dme.SearchMatch{}
+	}
+	t := table{headers: []string{"ID", "DOMAIN", "FQDN", "TYPE", "VALUE", "TTL"}}
+	for _, match := range matches {
+		t.add(match.Record.ID, match.Record.ID, match.Domain, match.FQDN, match.Record.Type, match.Record.Value, match.Record.Ttl)
+	}
+	if err := e.out.print(matches, t); err != nil {
+		return err
+	}
+	return searchErr
+}
+
+// Lists the records that depend on an address or host about to be
+// decommissioned: those referencing it and, unless -direct is given,
+// the aliases and records pointing at their names in turn.
+func impact(e *env, args []string) error {
+	fs := e.flagSet("impact")
+	domains := fs.String("domains", "", "comma separated domains to check instead of the whole account")
+	concurrency := fs.Int("concurrency", 8, "domains read at once")
+	direct := fs.Bool("direct", false, "only records referencing the value itself")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected an address or host")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	opts := dme.SearchOptions{Concurrency: *concurrency}
+	if *domains != "" {
+		if opts.DomainIDs, err = resolveDomains(client, strings.Split(*domains, ",")); err != nil {
+			return err
+		}
+	}
+
+	index := dme.NewValueIndex()
+	indexErr := client.IndexValues(context.Background(), index, opts)
+	matches := index.Dependents(args[0])
+	if *direct {
+		matches = index.Lookup(args[0])
+	}
+	if matches == nil {
+		matches = []dme.SearchMatch{}
+	}
+	t := table{headers: []string{"ID", "DOMAIN", "FQDN", "TYPE", "VALUE", "TTL"}}
+	for _, match := range matches {
+		t.add(match.Record.ID, match.Record.ID, match.Domain, match.FQDN, match.Record.Type, match.Record.Value, match.Record.Ttl)
+	}
+	if err := e.out.print(matches, t); err != nil {
+		return err
+	}
+	return indexErr
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/john-k/dnsmadeeasy/reconcile"
+)
+
+// Runs the reconciler described by a config file until interrupted,
+// serving metrics if the config sets listen. -once reconciles a single
+// time and reports what was planned, as a diff with -diff; -watch syncs
+// zones whenever their spec files change instead of on an interval.
+func serve(e *env, args []string) error {
+	fs := e.flagSet("serve")
+	config := fs.String("config", "", "reconciler config file")
+	once := fs.Bool("once", false, "reconcile once and exit")
+	dryRun := fs.Bool("dry-run", false, "plan changes without applying them")
+	watch := fs.Bool("watch", false, "sync zones as soon as their spec files change")
+	diff := fs.String("diff", "", "with -once, print the plan as a `format` diff: text, color or markdown")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 || *config == "" {
+		return usagef("expected -config")
+	}
+	switch *diff {
+	case "", "text", "color", "markdown":
+	default:
+		return usagef("unknown diff format %q", *diff)
+	}
+	if *diff != "" && !*once {
+		return usagef("-diff needs -once")
+	}
+	if err := e.out.validate(); err != nil {
+		return err
+	}
+	cfg, err := reconcile.LoadConfig(*config)
+	if err != nil {
+		return err
+	}
+	if *watch && cfg.Dir == "" {
+		return usagef("-watch needs a config with dir set")
+	}
+	if *dryRun {
+		cfg.Policy.DryRun = true
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	r := cfg.Reconciler(client)
+	r.Logger = log.New(e.stderr, "", log.LstdFlags)
+
+	if *once {
+		results, err := r.Once(context.Background())
+		switch *diff {
+		case "text", "color":
+			if renderErr := reconcile.RenderText(e.stdout, results, *diff == "color"); renderErr != nil {
+				return renderErr
+			}
+			return err
+		case "markdown":
+			if renderErr := reconcile.RenderMarkdown(e.stdout, results); renderErr != nil {
+				return renderErr
+			}
+			return err
+		}
+		t := table{headers: []string{"DOMAIN", "ID", "CHANGES", "APPLIED", "ERROR"}}
+		for _, result := range results {
+			errText := ""
+			if result.Err != nil {
+				errText = result.Err.Error()
+			}
+			t.add(result.DomainID, result.Domain, result.DomainID, len(result.Plan), result.Applied, errText)
+		}
+		if printErr := e.out.print(results, t); printErr != nil {
+			return printErr
+		}
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.Listen != "" {
+		listener, err := net.Listen("tcp", cfg.Listen)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{Handler: metricsHandler(r)}
+		go server.Serve(listener)
+		defer server.Close()
+		r.Logger.Printf("serving metrics on %s", listener.Addr())
+	}
+
+	if *watch {
+		w := &reconcile.Watcher{Reconciler: r, Dir: cfg.Dir, InitialSync: true}
+		err = w.Run(ctx)
+	} else {
+		err = r.Run(ctx)
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func metricsHandler(r *reconcile.Reconciler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.Metrics().WritePrometheus(w)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
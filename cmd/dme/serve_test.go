@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/john-k/dnsmadeeasy/reconcile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeOnce(t *testing.T) {
+	f, client := newFakeAPI(t)
+	dir := t.TempDir()
+	config := filepath.Join(dir, "dme.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("dir: zones.yaml\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "zones.yaml"), []byte(`
+domains:
+  - name: example.com
+    records:
+      - {name: www, type: A, value: 192.0.2.1, ttl: 300}
+      - {name: "", type: MX, value: mail, mxLevel: 10}
+      - {name: api, type: A, value: 203.0.113.7}
+`), 0o644))
+
+	code, stdout, stderr := runDME(client, "serve", "-config", config, "-once", "-dry-run", "-q")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "1\n", stdout)
+	assert.Len(t, f.Records(1), 2)
+
+	code, stdout, stderr = runDME(client, "serve", "-config", config, "-once", "-dry-run", "-diff", "markdown")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "| + | api | A |  | `203.0.113.7 ttl=1800` |\n")
+
+	code, _, _ = runDME(client, "serve", "-config", config, "-diff", "markdown")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, stderr = runDME(client, "serve", "-config", config, "-once")
+	require.Equal(t, exitOK, code, stderr)
+	records := f.Records(1)
+	require.Len(t, records, 3)
+	assert.Equal(t, "api", records[2].Name)
+
+	code, _, _ = runDME(client, "serve")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	metricsHandler(&reconcile.Reconciler{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), "dme_reconcile_runs_total 0\n")
+}
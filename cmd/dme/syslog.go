@@ -0,0 +1,16 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// Connects to the local syslog daemon, or a remote one over UDP
+func dialSyslog(addr string) (io.WriteCloser, error) {
+	if addr == "local" {
+		return syslog.New(syslog.LOG_NOTICE|syslog.LOG_AUTH, "dme")
+	}
+	return syslog.Dial("udp", addr, syslog.LOG_NOTICE|syslog.LOG_AUTH, "dme")
+}
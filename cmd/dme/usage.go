@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// One line of the usage report
+type usageRow struct {
+	Month    string `json:"month,omitempty"`
+	DomainID int    `json:"domainId"`
+	Domain   string `json:"domain"`
+	Queries  int64  `json:"queries"`
+}
+
+// Reports queries per domain and month, for reconciling bills. -total
+// sums each domain over the whole period instead.
+func usageReport(e *env, args []string) error {
+	fs := e.flagSet("usage")
+	now := time.Now().UTC()
+	since := fs.String("since", now.Format("2006-01"), "first month to report, yyyy-mm")
+	until := fs.String("until", now.Format("2006-01"), "last month to report, yyyy-mm")
+	total := fs.Bool("total", false, "sum each domain over the period")
+	fs.StringVar(&e.out.format, "format", e.out.format, "table, csv, json or yaml")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usagef("unexpected arguments")
+	}
+	from, err := time.Parse("2006-01", *since)
+	if err != nil {
+		return usagef("invalid -since %q, expected yyyy-mm", *since)
+	}
+	to, err := time.Parse("2006-01", *until)
+	if err != nil {
+		return usagef("invalid -until %q, expected yyyy-mm", *until)
+	}
+	if to.Before(from) {
+		return usagef("-until is before -since")
+	}
+	if e.out.format != "csv" {
+		if err := e.out.validate(); err != nil {
+			return err
+		}
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	usage, err := client.Usage().Range(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	list, err := client.Domains().List(ctx)
+	if err != nil {
+		return err
+	}
+	domains := map[int]string{}
+	for _, domain := range list {
+		domains[domain.ID] = domain.Name
+	}
+
+	var rows []usageRow
+	totals := map[int]int{}
+	for _, u := range usage {
+		domainID := u.DomainID()
+		if domainID == 0 {
+			continue
+		}
+		row := usageRow{Month: fmt.Sprintf("%04d-%02d", u.Year, u.Month), DomainID: domainID, Domain: domains[domainID], Queries: u.Total}
+		if *total {
+			row.Month = ""
+			if idx, ok := totals[domainID]; ok {
+				rows[idx].Queries += u.Total
+				continue
+			}
+			totals[domainID] = len(rows)
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Month != rows[j].Month {
+			return rows[i].Month < rows[j].Month
+		}
+		return rows[i].Domain < rows[j].Domain
+	})
+	if rows == nil {
+		rows = []usageRow{}
+	}
+
+	t := table{headers: []string{"MONTH", "DOMAIN ID", "DOMAIN", "QUERIES"}}
+	if *total {
+		t.headers = t.headers[1:]
+	}
+	for _, row := range rows {
+		cells := []interface{}{row.Month, row.DomainID, row.Domain, row.Queries}
+		if *total {
+			cells = cells[1:]
+		}
+		t.add(row.DomainID, cells...)
+	}
+	if e.out.format != "csv" {
+		return e.out.print(rows, t)
+	}
+	if e.out.quiet {
+		e.out.printIDs(t)
+		return nil
+	}
+
+	w := csv.NewWriter(e.stdout)
+	header := []string{"month", "domain_id", "domain", "queries"}
+	if *total {
+		header = header[1:]
+	}
+	w.Write(header)
+	for _, row := range rows {
+		record := []string{row.Month, strconv.Itoa(row.DomainID), row.Domain, strconv.FormatInt(row.Queries, 10)}
+		if *total {
+			record = record[1:]
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageReport(t *testing.T) {
+	f, client := newFakeAPI(t)
+	domainUsage := func(year, month, domainID int, total int64) dme.QueryUsage {
+		return dme.QueryUsage{Year: year, Month: month, Total: total, PrimaryEntity: "Account", SecondaryEntity: "Domain", SecondaryEntityID: domainID}
+	}
+	f.SetUsage(2024, time.January, domainUsage(2024, 1, 2, 500), domainUsage(2024, 1, 1, 1000))
+	f.SetUsage(2024, time.February, domainUsage(2024, 2, 1, 1500))
+
+	code, stdout, stderr := runDME(client, "usage", "-since", "2024-01", "-until", "2024-02", "-format", "csv")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "month,domain_id,domain,queries\n"+
+		"2024-01,1,example.com,1000\n"+
+		"2024-01,2,example.org,500\n"+
+		"2024-02,1,example.com,1500\n", stdout)
+
+	code, stdout, stderr = runDME(client, "usage", "-since", "2024-01", "-until", "2024-02", "-format", "csv", "-quiet")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "1\n2\n1\n", stdout)
+
+	code, stdout, stderr = runDME(client, "-o", "json", "usage", "-since", "2024-01", "-until", "2024-02", "-total")
+	require.Equal(t, exitOK, code, stderr)
+	assert.JSONEq(t, `[{"domainId":1,"domain":"example.com","queries":2500},{"domainId":2,"domain":"example.org","queries":500}]`, stdout)
+
+	code, _, _ = runDME(client, "usage", "-since", "January")
+	assert.Equal(t, exitUsage, code)
+}
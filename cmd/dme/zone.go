@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Writes a domain's records to stdout or a file, in the format given or
+// implied by the file's extension, BIND otherwise
+func zoneExport(e *env, args []string) error {
+	fs := e.flagSet("zone export")
+	path := fs.String("f", "", "file to write instead of stdout")
+	format := fs.String("format", "", "bind, csv, json or octodns")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	if *format == "" {
+		*format = formatForPath(*path)
+	}
+	if *format == "" {
+		*format = zoneBIND
+	}
+	if err := checkZoneFormat(*format); err != nil {
+		return err
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domain, err := lookupDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	records, err := client.Records(domain.ID).List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return writeZone(e.stdout, *format, domain, records)
+	}
+	f, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := writeZone(f, *format, domain, records); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Writes Terraform import blocks and resource stubs for domains, to
+// stdout or a file
+func zoneTerraform(e *env, args []string) error {
+	fs := e.flagSet("zone terraform")
+	path := fs.String("f", "", "file to write instead of stdout")
+	importsOnly := fs.Bool("imports-only", false, "write only import blocks")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usagef("expected at least one domain")
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for _, arg := range args {
+		domain, err := lookupDomain(client, arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, domain.ID)
+	}
+
+	opts := dme.TerraformOptions{ImportsOnly: *importsOnly}
+	if *path == "" {
+		return client.ExportTerraform(context.Background(), e.stdout, ids, opts)
+	}
+	f, err := os.Create(*path)
+	if err != nil {
+		return err
+	}
+	if err := client.ExportTerraform(context.Background(), f, ids, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Creates the records in a zone file that the domain doesn't have yet,
+// detecting the format from the file's extension or content. Like
+// ImportZone, it can be re-run safely.
+func zoneImport(e *env, args []string) error {
+	fs := e.flagSet("zone import")
+	path := fs.String("f", "-", "file to read, - for stdin")
+	format := fs.String("format", "", "bind, csv, json or octodns; detected if unset")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	if *format != "" {
+		if err := checkZoneFormat(*format); err != nil {
+			return err
+		}
+	}
+	if err := e.out.validate(); err != nil {
+		return err
+	}
+
+	var data []byte
+	if *path == "-" {
+		data, err = io.ReadAll(e.stdin)
+	} else {
+		data, err = os.ReadFile(*path)
+	}
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = formatForPath(*path)
+	}
+	if *format == "" {
+		*format = sniffFormat(data)
+	}
+
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domain, err := lookupDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+	records, err := readZone(data, *format, domain.Name)
+	if err != nil {
+		return err
+	}
+
+	result, importErr := client.ImportZone(context.Background(), domain.ID, records)
+	t := table{headers: []string{"CREATED", "SKIPPED", "FAILED"}}
+	t.rows = [][]string{{strconv.Itoa(len(result.Created)), strconv.Itoa(len(result.Skipped)), strconv.Itoa(len(result.Failed))}}
+	for _, record := range result.Created {
+		t.ids = append(t.ids, strconv.Itoa(record.ID))
+	}
+	if err := e.out.print(result, t); err != nil {
+		return err
+	}
+	return importErr
+}
+
+// Returns a domain to its state at -to from backups taken by dme backup,
+// showing the changes and asking for confirmation first
+func zoneRestore(e *env, args []string) error {
+	fs := e.flagSet("zone restore")
+	dir := fs.String("dir", "", "directory the backups are kept in")
+	to := fs.String("to", "", "time to restore to, in RFC 3339 format")
+	force := fs.Bool("force", false, "restore without asking for confirmation")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usagef("expected a domain")
+	}
+	if *dir == "" || *to == "" {
+		return usagef("expected -dir and -to")
+	}
+	at, err := time.Parse(time.RFC3339, *to)
+	if err != nil {
+		return usagef("invalid -to: %v", err)
+	}
+	client, err := e.dme()
+	if err != nil {
+		return err
+	}
+	domainID, err := resolveDomain(client, args[0])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	plan, err := client.PlanRestore(ctx, dme.FileBackups{Dir: *dir}, domainID, at)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "restoring from the backup taken %s\n", plan.BackupAt.Format(time.RFC3339))
+	if len(plan.Operations) == 0 {
+		fmt.Fprintln(e.stderr, "nothing to change")
+		return nil
+	}
+	t := table{headers: []string{"ACTION", "ID", "NAME", "TYPE", "VALUE", "TTL"}}
+	for _, op := range plan.Operations {
+		name := op.Record.Name
+		if name == "" {
+			name = "@"
+		}
+		t.add(op.Record.ID, op.Type, op.Record.ID, name, op.Record.Type, op.Record.Value, op.Record.Ttl)
+	}
+	if err := e.out.print(plan.Operations, t); err != nil {
+		return err
+	}
+
+	if !*force {
+		fmt.Fprintf(e.stderr, "apply %d changes? [y/N] ", len(plan.Operations))
+		answer, _ := bufio.NewReader(e.stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return errors.New("not confirmed, nothing changed")
+		}
+	}
+	plan.Approve()
+
+	result, err := client.RestoreZoneTo(ctx, plan)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "applied %d changes\n", len(result.Applied))
+	return nil
+}
+
+// Accepts a domain as a name or a numerical ID, returning its details
+func lookupDomain(client *dme.Client, domain string) (dme.Domain, error) {
+	domainID, err := resolveDomain(client, domain)
+	if err != nil {
+		return dme.Domain{}, err
+	}
+	return client.Domains().Get(context.Background(), domainID)
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"gopkg.in/yaml.v3"
+)
+
+// Zone file formats
+const (
+	zoneBIND    = "bind"
+	zoneCSV     = "csv"
+	zoneJSON    = "json"
+	zoneOctoDNS = "octodns"
+)
+
+var csvHeader = []string{"name", "type", "value", "ttl", "gtd", "mxLevel", "priority", "weight", "port"}
+
+// Picks a format from a file's extension, returning "" if it doesn't say
+func formatForPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".zone", ".bind", ".txt":
+		return zoneBIND
+	case ".csv":
+		return zoneCSV
+	case ".json":
+		return zoneJSON
+	case ".yaml", ".yml":
+		return zoneOctoDNS
+	}
+	return ""
+}
+
+// Guesses the format of zone data from its content
+func sniffFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return zoneJSON
+	}
+	firstLine, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	if strings.HasPrefix(strings.ToLower(string(firstLine)), strings.Join(csvHeader[:3], ",")) {
+		return zoneCSV
+	}
+	// octoDNS maps each name to a record set or a list of them
+	var octo map[string]yaml.Node
+	if err := yaml.Unmarshal(trimmed, &octo); err != nil || len(octo) == 0 {
+		return zoneBIND
+	}
+	for _, node := range octo {
+		if node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode {
+			return zoneBIND
+		}
+	}
+	return zoneOctoDNS
+}
+
+func checkZoneFormat(format string) error {
+	switch format {
+	case zoneBIND, zoneCSV, zoneJSON, zoneOctoDNS:
+		return nil
+	}
+	return usagef("unknown zone format %q, expected bind, csv, json or octodns", format)
+}
+
+// Writes records for zone in format
+func writeZone(w io.Writer, format string, domain dme.Domain, records []dme.Record) error {
+	switch format {
+	case zoneBIND:
+		_, err := io.WriteString(w, dme.ZoneFile(domain.Name, records))
+		return err
+	case zoneCSV:
+		return writeCSV(w, records)
+	case zoneJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dme.ZoneExport{Domain: domain, Records: records})
+	case zoneOctoDNS:
+		return writeOctoDNS(w, domain.Name, records)
+	}
+	return checkZoneFormat(format)
+}
+
+// Reads records for zone in format
+func readZone(data []byte, format string, zone string) ([]dme.Record, error) {
+	switch format {
+	case zoneBIND:
+		return dme.ParseZoneFile(bytes.NewReader(data), zone)
+	case zoneCSV:
+		return readCSV(data)
+	case zoneJSON:
+		// either a bare list of records or an export with its domain
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			var records []dme.Record
+			err := json.Unmarshal(trimmed, &records)
+			return records, err
+		}
+		var export dme.ZoneExport
+		err := json.Unmarshal(data, &export)
+		return export.Records, err
+	case zoneOctoDNS:
+		return readOctoDNS(data, zone)
+	}
+	return nil, checkZoneFormat(format)
+}
+
+func writeCSV(w io.Writer, records []dme.Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{r.Name, r.Type, r.Value, strconv.Itoa(r.Ttl), r.GtdLocation,
+			strconv.Itoa(r.MxLevel), strconv.Itoa(r.Priority), strconv.Itoa(r.Weight), strconv.Itoa(r.Port)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Reads CSV with a header row naming columns from csvHeader. Only name,
+// type and value are required; missing numbers are zero and the TTL
+// defaults to 1800.
+func readCSV(data []byte) ([]dme.Record, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := map[string]int{}
+	for idx, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = idx
+	}
+	for _, required := range csvHeader[:3] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv: missing %q column", required)
+		}
+	}
+
+	var records []dme.Record
+	for lineNo, row := range rows[1:] {
+		field := func(name string) string {
+			if idx, ok := columns[name]; ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+			return ""
+		}
+		number := func(name string, def int) int {
+			if err != nil || field(name) == "" {
+				return def
+			}
+			var n int
+			n, err = strconv.Atoi(field(name))
+			if err != nil {
+				err = fmt.Errorf("csv line %d: %s: %w", lineNo+2, name, err)
+			}
+			return n
+		}
+		record := dme.Record{
+			Name:        field("name"),
+			Type:        strings.ToUpper(field("type")),
+			Value:       field("value"),
+			Ttl:         number("ttl", 1800),
+			GtdLocation: field("gtd"),
+			MxLevel:     number("mxLevel", 0),
+			Priority:    number("priority", 0),
+			Weight:      number("weight", 0),
+			Port:        number("port", 0),
+		}
+		if err != nil {
+			return nil, err
+		}
+		if record.GtdLocation == "" {
+			record.GtdLocation = "DEFAULT"
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// One record set in octoDNS's YAML layout
+type octoRecord struct {
+	Type   string        `yaml:"type"`
+	TTL    int           `yaml:"ttl,omitempty"`
+	Value  interface{}   `yaml:"value,omitempty"`
+	Values []interface{} `yaml:"values,omitempty"`
+}
+
+type octoMX struct {
+	Exchange   string `yaml:"exchange"`
+	Preference int    `yaml:"preference"`
+}
+
+type octoSRV struct {
+	Priority int    `yaml:"priority"`
+	Weight   int    `yaml:"weight"`
+	Port     int    `yaml:"port"`
+	Target   string `yaml:"target"`
+}
+
+// Writes records in octoDNS's YAML layout, grouping them by name and
+// type. octoDNS wants fully qualified targets and escaped semicolons in
+// TXT values.
+func writeOctoDNS(w io.Writer, zone string, records []dme.Record) error {
+	type set struct {
+		name, recordType string
+	}
+	sets := map[set]*octoRecord{}
+	var order []set
+	for _, r := range records {
+		key := set{r.Name, r.Type}
+		rs, ok := sets[key]
+		if !ok {
+			rs = &octoRecord{Type: r.Type, TTL: r.Ttl}
+			sets[key] = rs
+			order = append(order, key)
+		}
+		var value interface{}
+		switch r.Type {
+		case "CNAME", "NS", "PTR", "ANAME":
+			value = octoTarget(r.Value, zone)
+		case "MX":
+			value = octoMX{Exchange: octoTarget(r.Value, zone), Preference: r.MxLevel}
+		case "SRV":
+			value = octoSRV{Priority: r.Priority, Weight: r.Weight, Port: r.Port, Target: octoTarget(r.Value, zone)}
+		case "TXT", "SPF":
+			value = strings.ReplaceAll(strings.Trim(r.Value, `"`), ";", `\;`)
+		default:
+			value = r.Value
+		}
+		rs.Values = append(rs.Values, value)
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].name < order[j].name })
+
+	byName := map[string][]*octoRecord{}
+	var names []string
+	for _, key := range order {
+		if _, ok := byName[key.name]; !ok {
+			names = append(names, key.name)
+		}
+		rs := sets[key]
+		if len(rs.Values) == 1 {
+			rs.Value, rs.Values = rs.Values[0], nil
+		}
+		byName[key.name] = append(byName[key.name], rs)
+	}
+
+	doc := yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range names {
+		var value yaml.Node
+		var err error
+		if len(byName[name]) == 1 {
+			err = value.Encode(byName[name][0])
+		} else {
+			err = value.Encode(byName[name])
+		}
+		if err != nil {
+			return err
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name, Style: yaml.SingleQuotedStyle}, &value)
+	}
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func octoTarget(target, zone string) string {
+	if strings.HasSuffix(target, ".") {
+		return target
+	}
+	return dme.AbsoluteName(target, zone)
+}
+
+// Reads octoDNS YAML, where each name maps to a record set or a list of
+// them
+func readOctoDNS(data []byte, zone string) ([]dme.Record, error) {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(doc))
+	for name := range doc {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var records []dme.Record
+	for _, name := range names {
+		node := doc[name]
+		var sets []octoRecord
+		if node.Kind == yaml.SequenceNode {
+			if err := node.Decode(&sets); err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+		} else {
+			var rs octoRecord
+			if err := node.Decode(&rs); err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+			sets = []octoRecord{rs}
+		}
+		for _, rs := range sets {
+			set, err := octoRecords(name, rs)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+			records = append(records, set...)
+		}
+	}
+	return records, nil
+}
+
+func octoRecords(name string, rs octoRecord) ([]dme.Record, error) {
+	values := rs.Values
+	if rs.Value != nil {
+		values = append([]interface{}{rs.Value}, values...)
+	}
+	ttl := rs.TTL
+	if ttl == 0 {
+		// octoDNS's default
+		ttl = 3600
+	}
+	recordType := strings.ToUpper(rs.Type)
+	if recordType == "ALIAS" {
+		recordType = "ANAME"
+	}
+
+	var records []dme.Record
+	for _, value := range values {
+		record := dme.Record{Name: name, Type: recordType, Ttl: ttl, GtdLocation: "DEFAULT"}
+		switch recordType {
+		case "MX":
+			var mx octoMX
+			if err := remarshal(value, &mx); err != nil {
+				return nil, err
+			}
+			record.Value, record.MxLevel = mx.Exchange, mx.Preference
+		case "SRV":
+			var srv octoSRV
+			if err := remarshal(value, &srv); err != nil {
+				return nil, err
+			}
+			record.Value, record.Priority, record.Weight, record.Port = srv.Target, srv.Priority, srv.Weight, srv.Port
+		case "TXT", "SPF":
+			record.Value = strconv.Quote(strings.ReplaceAll(fmt.Sprint(value), `\;`, ";"))
+		default:
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s value %v", recordType, value)
+			}
+			record.Value = s
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Converts a decoded YAML value into a struct
+func remarshal(value interface{}, v interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var zoneRecords = []dme.Record{
+	{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+	{Name: "", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "DEFAULT"},
+	{Name: "", Type: "MX", Value: "mail.example.com.", MxLevel: 10, Ttl: 1800, GtdLocation: "DEFAULT"},
+	{Name: "_sip._tcp", Type: "SRV", Value: "sip.example.com.", Priority: 1, Weight: 2, Port: 5060, Ttl: 300, GtdLocation: "DEFAULT"},
+	{Name: "txt", Type: "TXT", Value: `"v=spf1 a; -all"`, Ttl: 600, GtdLocation: "DEFAULT"},
+	{Name: "www", Type: "CNAME", Value: "example.com.", Ttl: 60, GtdLocation: "DEFAULT"},
+}
+
+func TestZoneFormatsRoundTrip(t *testing.T) {
+	domain := dme.Domain{ID: 1, Name: "example.com"}
+	for _, format := range []string{zoneBIND, zoneCSV, zoneJSON, zoneOctoDNS} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeZone(&buf, format, domain, zoneRecords))
+			assert.Equal(t, format, sniffFormat(buf.Bytes()))
+
+			records, err := readZone(buf.Bytes(), format, domain.Name)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, zoneRecords, records)
+		})
+	}
+}
+
+func TestOctoDNSLayout(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeOctoDNS(&buf, "example.com", zoneRecords[:3]))
+	assert.Equal(t, `---
+'':
+  - type: A
+    ttl: 300
+    values:
+      - 192.0.2.1
+      - 192.0.2.2
+  - type: MX
+    ttl: 1800
+    value:
+      exchange: mail.example.com.
+      preference: 10
+`, buf.String())
+}
+
+func TestFormatForPath(t *testing.T) {
+	assert.Equal(t, zoneBIND, formatForPath("zone.db"))
+	assert.Equal(t, zoneOctoDNS, formatForPath("example.com.yaml"))
+	assert.Equal(t, "", formatForPath("zone"))
+}
+
+func TestZoneExportImport(t *testing.T) {
+	f, client := newFakeAPI(t)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "zone.db")
+	code, _, stderr := runDME(client, "zone", "export", "example.com", "-f", path)
+	require.Equal(t, exitOK, code, stderr)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "www.example.com.\t300\tIN\tA\t192.0.2.1")
+
+	code, stdout, stderr := runDME(client, "zone", "export", "-format", "csv", "1")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "www,A,192.0.2.1,300,DEFAULT")
+
+	// no extension, so the format is detected from the content
+	path = filepath.Join(dir, "zone")
+	require.NoError(t, os.WriteFile(path, []byte("---\napi:\n  type: A\n  value: 203.0.113.7\n"), 0o644))
+	code, stdout, stderr = runDME(client, "zone", "import", "example.org", "-f", path, "-q")
+	require.Equal(t, exitOK, code, stderr)
+	records := f.Records(2)
+	require.Len(t, records, 1)
+	assert.Equal(t, strconv.Itoa(records[0].ID)+"\n", stdout)
+	assert.Equal(t, dme.Record{ID: records[0].ID, SourceId: 2, Name: "api", Type: "A", Value: "203.0.113.7", Ttl: 3600, GtdLocation: "DEFAULT"}, records[0])
+
+	code, stdout, stderr = runDME(client, "zone", "import", "example.org", "-f", path)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "0        1        0")
+}
+
+func TestZoneTerraform(t *testing.T) {
+	_, client := newFakeAPI(t)
+
+	code, stdout, stderr := runDME(client, "zone", "terraform", "example.com", "example.org")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "import {\n  to = dme_domain.example_org\n  id = \"2\"\n}")
+	assert.Contains(t, stdout, "resource \"dme_dns_record\" \"example_com_www_a\" {")
+
+	path := filepath.Join(t.TempDir(), "imports.tf")
+	code, _, stderr = runDME(client, "zone", "terraform", "-imports-only", "-f", path, "1")
+	require.Equal(t, exitOK, code, stderr)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "to = dme_dns_record.example_com_apex_mx\n  id = \"1:11\"")
+	assert.NotContains(t, string(data), "resource")
+
+	code, _, _ = runDME(client, "zone", "terraform")
+	assert.Equal(t, exitUsage, code)
+}
+
+func FuzzReadZone(f *testing.F) {
+	for _, format := range []string{zoneBIND, zoneCSV, zoneJSON, zoneOctoDNS} {
+		var buf bytes.Buffer
+		require.NoError(f, writeZone(&buf, format, dme.Domain{Name: "example.com"}, zoneRecords))
+		f.Add(format, buf.Bytes())
+	}
+	f.Add(zoneCSV, []byte("name,type,value\n\"unterminated,A\n"))
+	f.Add(zoneOctoDNS, []byte("'':\n  type: TXT\n  value: a\\;b\\\n"))
+	f.Add(zoneJSON, []byte(`[{"name":"www","type":"A","surprise":true}]`))
+	f.Fuzz(func(t *testing.T, format string, data []byte) {
+		records, err := readZone(data, format, "example.com")
+		if err != nil || format == zoneBIND {
+			return
+		}
+		// whatever reads writes out in the same format and reads back
+		var buf bytes.Buffer
+		if err := writeZone(&buf, format, dme.Domain{Name: "example.com"}, records); err != nil {
+			return
+		}
+		again, err := readZone(buf.Bytes(), format, "example.com")
+		if err != nil {
+			t.Fatalf("rereading %s: %v\n%s", format, err, buf.String())
+		}
+		if len(again) != len(records) {
+			t.Fatalf("%s round trip read %d records, then %d", format, len(records), len(again))
+		}
+	})
+}
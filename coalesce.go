@@ -0,0 +1,52 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Makes concurrent identical reads of a domain's records or of the
+// domain list share a single API call, for callers such as webhook
+// providers that see bursts of the same query. A read started after a
+// change made through the client never joins one that began before it.
+func WithReadCoalescing() Option {
+	return func(c *Client) {
+		c.reads = &singleflight.Group{}
+	}
+}
+
+const domainsReadKey = "domains"
+
+func recordsReadKey(domainID int) string {
+	return fmt.Sprint("records/", domainID)
+}
+
+// Runs fetch, sharing the call with concurrent callers of the same key
+// when reads are coalesced. The shared call doesn't stop when one
+// caller's ctx is done, but each caller stops waiting for it.
+func (c *Client) coalesce(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if c.reads == nil {
+		return fetch(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ch := c.reads.DoChan(key, func() (interface{}, error) {
+		return fetch(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Keeps later reads of key from joining a call already in flight
+func (c *Client) forgetRead(key string) {
+	if c.reads != nil {
+		c.reads.Forget(key)
+	}
+}
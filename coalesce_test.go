@@ -0,0 +1,74 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCoalescing(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[{"id":1,"name":"www","type":"A","value":"192.0.2.1"}]}`)
+	}))
+	defer server.Close()
+	client := GetClient("key", "secret", BaseURL(server.URL+"/V2.0/"), WithReadCoalescing())
+
+	var wg sync.WaitGroup
+	results := make([][]Record, 10)
+	for idx := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := client.Records(1).List(context.Background())
+			assert.NoError(t, err)
+			results[idx] = records
+		}()
+	}
+	// let the readers pile up behind the first request
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, records := range results {
+		require.Len(t, records, 1)
+	}
+	// callers get their own copies
+	results[0][0].Name = "changed"
+	assert.Equal(t, "www", results[1][0].Name)
+
+	// a reader that gives up doesn't fail the shared call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Records(1).List(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReadCoalescingForgetsAfterWrites(t *testing.T) {
+	fake, client := newFakeDME(t)
+	client = GetClient("key", "secret", client.BaseURL, WithReadCoalescing())
+	domain := fake.addDomain("example.com")
+
+	records, err := client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, records)
+	_, err = client.Records(domain.ID).Create(context.Background(), Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	require.NoError(t, err)
+	records, err = client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
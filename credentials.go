@@ -0,0 +1,171 @@
+package dnsmadeeasy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An API key and secret pair
+type Credentials struct {
+	APIKey    string `json:"apiKey"`
+	SecretKey string `json:"secretKey"`
+}
+
+// Supplies the credentials used to sign each request. Providers are
+// consulted for every request, so rotated secrets take effect without
+// recreating the client.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// Signs requests with credentials from the supplied provider instead of
+// the client's APIKey and SecretKey
+func WithCredentialsProvider(p CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = p
+	}
+}
+
+// A fixed key and secret
+type StaticCredentials Credentials
+
+func (s StaticCredentials) Credentials(context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// Reads credentials from environment variables, DME_API_TOKEN and
+// DME_API_SECRET unless overridden
+type EnvCredentials struct {
+	KeyVar    string
+	SecretVar string
+}
+
+func (e EnvCredentials) Credentials(context.Context) (Credentials, error) {
+	keyVar, secretVar := e.KeyVar, e.SecretVar
+	if keyVar == "" {
+		keyVar = "DME_API_TOKEN"
+	}
+	if secretVar == "" {
+		secretVar = "DME_API_SECRET"
+	}
+	creds := Credentials{os.Getenv(keyVar), os.Getenv(secretVar)}
+	if creds.APIKey == "" || creds.SecretKey == "" {
+		return Credentials{}, fmt.Errorf("%s and %s must be set", keyVar, secretVar)
+	}
+	return creds, nil
+}
+
+// Reads credentials from a file on every request. JSON files hold
+// {"apiKey": "...", "secretKey": "..."}; anything else is parsed as
+// INI-style "api_key = ..." and "secret_key = ..." lines.
+type FileCredentials struct {
+	Path string
+}
+
+func (f FileCredentials) Credentials(context.Context) (Credentials, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	creds, err := parseCredentials(data)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", f.Path, err)
+	}
+	return creds, nil
+}
+
+// Runs a command and parses its output as a credentials file, for
+// fetching secrets from Vault, SOPS, a password manager and the like
+type ExecCredentials struct {
+	Command []string
+}
+
+func (e ExecCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	if len(e.Command) == 0 {
+		return Credentials{}, errors.New("no credentials command configured")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Command[0], e.Command[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("credentials command %s: %w: %s",
+			e.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	creds, err := parseCredentials(out)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("credentials command %s: %w", e.Command[0], err)
+	}
+	return creds, nil
+}
+
+func parseCredentials(data []byte) (Credentials, error) {
+	var creds Credentials
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, &creds); err != nil {
+			return Credentials{}, err
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line[0] == '#' || line[0] == ';' || line[0] == '[' {
+				continue
+			}
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "api_key", "apikey":
+				creds.APIKey = value
+			case "secret_key", "secretkey":
+				creds.SecretKey = value
+			}
+		}
+	}
+	if creds.APIKey == "" || creds.SecretKey == "" {
+		return Credentials{}, errors.New("missing api key or secret key")
+	}
+	return creds, nil
+}
+
+// Wraps a provider so it is only consulted once per ttl, for sources
+// too slow to query on every request
+func CachedCredentials(p CredentialsProvider, ttl time.Duration) CredentialsProvider {
+	return &cachedCredentials{provider: p, ttl: ttl}
+}
+
+type cachedCredentials struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	creds   Credentials
+	expires time.Time
+}
+
+func (c *cachedCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Now().Before(c.expires) {
+		return c.creds, nil
+	}
+	creds, err := c.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.creds, c.expires = creds, time.Now().Add(c.ttl)
+	return creds, nil
+}
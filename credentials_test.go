@@ -0,0 +1,66 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCredentials(t *testing.T) {
+	creds, err := parseCredentials([]byte(`{"apiKey": "key", "secretKey": "secret"}`))
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{"key", "secret"}, creds)
+
+	creds, err = parseCredentials([]byte("[default]\n# comment\napi_key = key\nsecret_key = \"secret\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{"key", "secret"}, creds)
+
+	_, err = parseCredentials([]byte("api_key = key\n"))
+	assert.Error(t, err)
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com")
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	require.NoError(t, os.WriteFile(path, []byte("api_key=first\nsecret_key=s\n"), 0600))
+	client = GetClient("", "", client.BaseURL, WithCredentialsProvider(FileCredentials{path}))
+
+	_, err := client.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, "first", fake.apiKey)
+
+	// rotated credentials are picked up by the next request
+	require.NoError(t, os.WriteFile(path, []byte("api_key=second\nsecret_key=s\n"), 0600))
+	_, err = client.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, "second", fake.apiKey)
+
+	require.NoError(t, os.Remove(path))
+	_, err = client.EnumerateDomains()
+	assert.ErrorContains(t, err, "fetching credentials")
+}
+
+func TestExecCredentials(t *testing.T) {
+	creds, err := ExecCredentials{[]string{"sh", "-c", `echo '{"apiKey":"key","secretKey":"secret"}'`}}.
+		Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{"key", "secret"}, creds)
+
+	_, err = ExecCredentials{[]string{"sh", "-c", "echo denied >&2; exit 1"}}.
+		Credentials(context.Background())
+	assert.ErrorContains(t, err, "denied")
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("DME_API_TOKEN", "key")
+	t.Setenv("DME_API_SECRET", "secret")
+	creds, err := EnvCredentials{}.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{"key", "secret"}, creds)
+}
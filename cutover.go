@@ -0,0 +1,288 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Returned, wrapped along with the cause, when a cutover fails after
+// switching and the name is switched back
+var ErrRolledBack = errors.New("cutover rolled back")
+
+// Switches a name from one set of values to another, such as from a
+// blue deployment's addresses to a green one's, or flips a CNAME
+type Cutover struct {
+	DomainID int
+	Name     string
+
+	// A, AAAA or CNAME
+	Type string
+
+	// The values the name serves now, and those it should serve
+	// afterwards. A CNAME has one of each.
+	From []string
+	To   []string
+
+	// If non-zero and below the records' TTL, the TTL is lowered to this
+	// ahead of the switch, and the cutover waits out the old TTL so
+	// resolvers pick up the switch quickly. The original TTL is restored
+	// once the cutover succeeds.
+	LowerTTL int
+
+	// If non-zero, From and To are first served together, splitting
+	// clients between them, for this long before To is served alone.
+	// Not possible for CNAMEs.
+	Canary time.Duration
+
+	// Waits until the account's nameservers serve each change before
+	// checking health
+	VerifyPropagation bool
+
+	// Called after each change; an error switches the name back to From.
+	// Optional.
+	HealthCheck func(ctx context.Context) error
+}
+
+// The steps a cutover took
+type CutoverResult struct {
+	// The records' TTL before the cutover, restored afterwards
+	OriginalTTL int
+
+	// Whether the TTL was lowered ahead of the switch
+	Lowered bool
+
+	// Whether the name was switched back to From
+	RolledBack bool
+}
+
+// Waits, unless ctx is done first; replaced in tests
+var pause = func(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// Runs a cutover. The name must serve exactly the From values to begin
+// with. If the health check or propagation fails once the name serves
+// To values, it is switched back to From with its original TTL and the
+// error wraps ErrRolledBack.
+func (c *Client) Cutover(ctx context.Context, co Cutover) (CutoverResult, error) {
+	var result CutoverResult
+	co.Type = strings.ToUpper(co.Type)
+	if len(co.From) == 0 || len(co.To) == 0 {
+		return result, fmt.Errorf("cutover needs values to switch from and to")
+	}
+	if co.Type == "CNAME" && (len(co.From) != 1 || len(co.To) != 1 || co.Canary > 0) {
+		return result, fmt.Errorf("a CNAME cutover switches one target for another, without a canary")
+	}
+
+	records := c.Records(co.DomainID)
+	set, err := records.GetSet(ctx, co.Name, co.Type)
+	if err != nil {
+		return result, err
+	}
+	if !sameValues(set.Records, co.From) {
+		return result, fmt.Errorf("%s %s serves %v, not the values to switch from", co.Type, co.Name, values(set.Records))
+	}
+	template := set.Records[0]
+	result.OriginalTTL = template.Ttl
+
+	zone := ""
+	if co.VerifyPropagation {
+		domain, err := c.Domains().Get(ctx, co.DomainID)
+		if err != nil {
+			return result, err
+		}
+		zone = dns.Fqdn(domain.Name)
+	}
+
+	// writes the values and waits for them to be served
+	ttl := template.Ttl
+	publish := func(ctx context.Context, vals []string) error {
+		desired := recordsWithValues(set.Records, template, vals, ttl)
+		if _, err := records.ReplaceSet(ctx, set, desired); err != nil {
+			return err
+		}
+		set, err = records.GetSet(ctx, co.Name, co.Type)
+		if err != nil {
+			return err
+		}
+		if co.VerifyPropagation {
+			return c.waitPublished(ctx, zone, set.Records)
+		}
+		return nil
+	}
+
+	if co.LowerTTL > 0 && co.LowerTTL < template.Ttl {
+		ttl = co.LowerTTL
+		if err := publish(ctx, co.From); err != nil {
+			return result, fmt.Errorf("lowering TTL: %w", err)
+		}
+		result.Lowered = true
+		// resolvers may hold the old TTL until it runs out
+		if err := pause(ctx, time.Duration(template.Ttl)*time.Second); err != nil {
+			return result, err
+		}
+	}
+
+	stages := [][]string{co.To}
+	if co.Canary > 0 {
+		stages = [][]string{unionValues(co.From, co.To), co.To}
+	}
+	for idx, vals := range stages {
+		err := publish(ctx, vals)
+		if err == nil && co.Canary > 0 && idx == 0 {
+			err = pause(ctx, co.Canary)
+		}
+		if err == nil && co.HealthCheck != nil {
+			err = co.HealthCheck(ctx)
+		}
+		if err != nil {
+			ttl = template.Ttl
+			// switch back even if the cutover's context ran out
+			if rollbackErr := publish(context.WithoutCancel(ctx), co.From); rollbackErr != nil {
+				return result, errors.Join(err, fmt.Errorf("rolling back: %w", rollbackErr))
+			}
+			result.RolledBack = true
+			return result, fmt.Errorf("%w: %w", ErrRolledBack, err)
+		}
+	}
+
+	if ttl != template.Ttl {
+		ttl = template.Ttl
+		if err := publish(ctx, co.To); err != nil {
+			return result, fmt.Errorf("restoring TTL: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// Polls the account's nameservers until each serves exactly the records,
+// or the polling deadline passes
+func (c *Client) waitPublished(ctx context.Context, zone string, records []Record) error {
+	ctx, cancel := context.WithTimeout(ctx, c.pollDeadline)
+	defer cancel()
+
+	var name string
+	var rrtype uint16
+	want := map[string]bool{}
+	for _, record := range records {
+		rr, err := record.RR(zone)
+		if err != nil {
+			return err
+		}
+		name, rrtype = rr.Header().Name, rr.Header().Rrtype
+		want[rdata(rr)] = true
+	}
+
+	nameservers := c.nameservers
+	if nameservers == nil {
+		nameservers = DefaultNameservers
+	}
+	pending := append([]string{}, nameservers...)
+	for {
+		var still []string
+		for _, ns := range pending {
+			addr := ns
+			if _, _, err := net.SplitHostPort(ns); err != nil {
+				addr = net.JoinHostPort(ns, "53")
+			}
+			answer, err := queryAuthoritative(ctx, addr, name, rrtype)
+			if err != nil || !sameAnswer(answer, want) {
+				still = append(still, ns)
+			}
+		}
+		if len(still) == 0 {
+			return nil
+		}
+		pending = still
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not yet served by %s: %w", name, strings.Join(pending, ", "), ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+func sameAnswer(answer []dns.RR, want map[string]bool) bool {
+	got := map[string]bool{}
+	for _, rr := range answer {
+		got[rdata(rr)] = true
+	}
+	if len(got) != len(want) {
+		return false
+	}
+	for data := range want {
+		if !got[data] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns records serving vals with ttl, reusing the IDs of current
+// records that already hold a value
+func recordsWithValues(current []Record, template Record, vals []string, ttl int) []Record {
+	byValue := map[string]Record{}
+	for _, record := range current {
+		byValue[canonicalValue(record.Value)] = record
+	}
+	desired := make([]Record, 0, len(vals))
+	for _, value := range vals {
+		record, ok := byValue[canonicalValue(value)]
+		if !ok {
+			record = template
+			record.ID = 0
+			record.Value = value
+		}
+		record.Ttl = ttl
+		desired = append(desired, record)
+	}
+	return desired
+}
+
+func sameValues(records []Record, vals []string) bool {
+	have := values(records)
+	want := make([]string, len(vals))
+	for idx, value := range vals {
+		want[idx] = canonicalValue(value)
+	}
+	sort.Strings(want)
+	return strings.Join(have, "\x00") == strings.Join(want, "\x00")
+}
+
+// Returns the canonical values of records, sorted
+func values(records []Record) []string {
+	vals := make([]string, len(records))
+	for idx, record := range records {
+		vals[idx] = canonicalValue(record.Value)
+	}
+	sort.Strings(vals)
+	return vals
+}
+
+func unionValues(a, b []string) []string {
+	seen := map[string]bool{}
+	var union []string
+	for _, value := range append(append([]string{}, a...), b...) {
+		if !seen[canonicalValue(value)] {
+			seen[canonicalValue(value)] = true
+			union = append(union, value)
+		}
+	}
+	return union
+}
+
+func canonicalValue(value string) string {
+	return strings.ToLower(strings.TrimSuffix(value, "."))
+}
@@ -0,0 +1,132 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the fake's current records for a domain over UDP, returning
+// the address
+func serveFakeZone(t *testing.T, fake *fakeDME, domainID int, zone string) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		q := req.Question[0]
+		for _, record := range fake.recordList(domainID) {
+			rr, err := record.RR(zone)
+			if err == nil && dns.CanonicalName(rr.Header().Name) == dns.CanonicalName(q.Name) && rr.Header().Rrtype == q.Qtype {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+		w.WriteMsg(resp)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+// Records the waits a test makes instead of sleeping
+func stubPause(t *testing.T) *[]time.Duration {
+	var waits []time.Duration
+	original := pause
+	pause = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { pause = original })
+	return &waits
+}
+
+func liveValues(fake *fakeDME, domainID int, name string) (vals []string, ttls []int) {
+	for _, record := range fake.recordList(domainID) {
+		if record.Name == name {
+			vals = append(vals, record.Value)
+			ttls = append(ttls, record.Ttl)
+		}
+	}
+	sort.Strings(vals)
+	return vals, ttls
+}
+
+func TestCutover(t *testing.T) {
+	waits := stubPause(t)
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "app", Type: "A", Value: "192.0.2.1", Ttl: 3600, GtdLocation: GtdDefault},
+		Record{Name: "app", Type: "A", Value: "192.0.2.2", Ttl: 3600, GtdLocation: GtdDefault},
+	)
+	client = GetClient("key", "secret", client.BaseURL,
+		WithNameservers(serveFakeZone(t, fake, domain.ID, "example.com.")),
+		WithPolling(10*time.Millisecond, time.Second))
+
+	var served [][]string
+	result, err := client.Cutover(context.Background(), Cutover{
+		DomainID:          domain.ID,
+		Name:              "app",
+		Type:              "a",
+		From:              []string{"192.0.2.1", "192.0.2.2"},
+		To:                []string{"198.51.100.1"},
+		LowerTTL:          60,
+		Canary:            time.Minute,
+		VerifyPropagation: true,
+		HealthCheck: func(ctx context.Context) error {
+			vals, ttls := liveValues(fake, domain.ID, "app")
+			served = append(served, vals)
+			assert.Equal(t, 60, ttls[0])
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CutoverResult{OriginalTTL: 3600, Lowered: true}, result)
+	assert.Equal(t, []time.Duration{time.Hour, time.Minute}, *waits)
+	assert.Equal(t, [][]string{{"192.0.2.1", "192.0.2.2", "198.51.100.1"}, {"198.51.100.1"}}, served)
+
+	vals, ttls := liveValues(fake, domain.ID, "app")
+	assert.Equal(t, []string{"198.51.100.1"}, vals)
+	assert.Equal(t, []int{3600}, ttls)
+}
+
+func TestCutoverRollsBack(t *testing.T) {
+	stubPause(t)
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "app", Type: "CNAME", Value: "blue.example.net.", Ttl: 300, GtdLocation: GtdDefault},
+	)
+
+	unhealthy := errors.New("green is unhealthy")
+	result, err := client.Cutover(context.Background(), Cutover{
+		DomainID:    domain.ID,
+		Name:        "app",
+		Type:        "CNAME",
+		From:        []string{"blue.example.net."},
+		To:          []string{"green.example.net."},
+		HealthCheck: func(ctx context.Context) error { return unhealthy },
+	})
+	assert.ErrorIs(t, err, ErrRolledBack)
+	assert.ErrorIs(t, err, unhealthy)
+	assert.True(t, result.RolledBack)
+	vals, _ := liveValues(fake, domain.ID, "app")
+	assert.Equal(t, []string{"blue.example.net."}, vals)
+
+	_, err = client.Cutover(context.Background(), Cutover{
+		DomainID: domain.ID, Name: "app", Type: "CNAME",
+		From: []string{"green.example.net."}, To: []string{"blue.example.net."},
+	})
+	assert.ErrorContains(t, err, "not the values to switch from")
+
+	_, err = client.Cutover(context.Background(), Cutover{
+		DomainID: domain.ID, Name: "app", Type: "CNAME",
+		From: []string{"blue.example.net."}, To: []string{"green.example.net."}, Canary: time.Minute,
+	})
+	assert.ErrorContains(t, err, "without a canary")
+}
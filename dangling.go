@@ -0,0 +1,239 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Why a record was judged dangling
+type DanglingReason string
+
+const (
+	// A CNAME or ANAME target has no addresses (NXDOMAIN or no data)
+	DanglingUnresolvable DanglingReason = "target does not resolve"
+
+	// An A or AAAA address has no reverse DNS
+	DanglingNoPTR DanglingReason = "address has no reverse DNS"
+
+	// None of an address's PTR names resolve back to it
+	DanglingPTRMismatch DanglingReason = "reverse DNS does not resolve back to the address"
+)
+
+// The lookups dangling detection needs; *net.Resolver implements it
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// Settings for FindDangling
+type DanglingOptions struct {
+	// net.DefaultResolver if nil
+	Resolver HostResolver
+
+	// Concurrent lookups; 8 if zero
+	Concurrency int
+
+	// Also check A and AAAA records with forward-confirmed reverse DNS.
+	// Many valid addresses have no PTR record, so these findings need
+	// review before anything is removed.
+	CheckReverse bool
+}
+
+// A record that appears to point at nothing
+type DanglingRecord struct {
+	DomainID int
+	Domain   string
+	Record   Record
+	Reason   DanglingReason
+
+	// The name or address that was looked up
+	Target string
+}
+
+// Looks up the targets of the records of the supplied domains and
+// returns those that no longer lead anywhere, ordered by domain and
+// record name, so stale records can be reaped. Lookups that fail for
+// any reason other than the name not existing are returned as errors
+// rather than findings, so an unreachable resolver never condemns a
+// record.
+func (c *Client) FindDangling(ctx context.Context, domainIDs []int, opts DanglingOptions) ([]DanglingRecord, error) {
+	var (
+		zones []zoneRecords
+		errs  []error
+	)
+	for _, domainID := range domainIDs {
+		domain, err := c.Domains().Get(ctx, domainID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainID, err))
+			continue
+		}
+		records, err := c.Records(domainID).List(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainID, err))
+			continue
+		}
+		zones = append(zones, zoneRecords{domain, records})
+	}
+	dangling, err := findDangling(ctx, zones, opts)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return dangling, errors.Join(errs...)
+}
+
+type zoneRecords struct {
+	domain  Domain
+	records []Record
+}
+
+func findDangling(ctx context.Context, zones []zoneRecords, opts DanglingOptions) ([]DanglingRecord, error) {
+	resolver := opts.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+
+	type check struct {
+		zone   Domain
+		record Record
+		target string
+	}
+	var checks []check
+	for _, zone := range zones {
+		for _, record := range zone.records {
+			switch strings.ToUpper(record.Type) {
+			case "CNAME", "ANAME":
+				target := qualify(record.Value, AbsoluteName("", zone.domain.Name))
+				checks = append(checks, check{zone.domain, record, target})
+			case "A", "AAAA":
+				if opts.CheckReverse {
+					checks = append(checks, check{zone.domain, record, record.Value})
+				}
+			}
+		}
+	}
+
+	// each distinct target is looked up once, however many records
+	// share it
+	l := &lookups{resolver: resolver, results: map[string]*lookup{}}
+	var (
+		mu       sync.Mutex
+		dangling []DanglingRecord
+		errs     []error
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, concurrency)
+	)
+	for _, ch := range checks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ch check) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var reason DanglingReason
+			var err error
+			if net.ParseIP(ch.target) != nil {
+				reason, err = l.reverse(ctx, ch.target)
+			} else {
+				reason, err = l.forward(ctx, ch.target)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ch.target, err))
+				return
+			}
+			if reason != "" {
+				dangling = append(dangling, DanglingRecord{ch.zone.ID, ch.zone.Name, ch.record, reason, ch.target})
+			}
+		}(ch)
+	}
+	wg.Wait()
+
+	sort.Slice(dangling, func(i, j int) bool {
+		a, b := dangling[i], dangling[j]
+		if a.DomainID != b.DomainID {
+			return a.DomainID < b.DomainID
+		}
+		if a.Record.Name != b.Record.Name {
+			return a.Record.Name < b.Record.Name
+		}
+		return a.Record.ID < b.Record.ID
+	})
+	return dangling, errors.Join(errs...)
+}
+
+// Memoized lookups, safe for concurrent use
+type lookups struct {
+	resolver HostResolver
+	mu       sync.Mutex
+	results  map[string]*lookup
+}
+
+type lookup struct {
+	once   sync.Once
+	reason DanglingReason
+	err    error
+}
+
+func (l *lookups) do(key string, fn func() (DanglingReason, error)) (DanglingReason, error) {
+	l.mu.Lock()
+	result, ok := l.results[key]
+	if !ok {
+		result = &lookup{}
+		l.results[key] = result
+	}
+	l.mu.Unlock()
+	result.once.Do(func() { result.reason, result.err = fn() })
+	return result.reason, result.err
+}
+
+func (l *lookups) forward(ctx context.Context, host string) (DanglingReason, error) {
+	return l.do("host "+host, func() (DanglingReason, error) {
+		_, err := l.resolver.LookupHost(ctx, host)
+		if isNotFound(err) {
+			return DanglingUnresolvable, nil
+		}
+		return "", err
+	})
+}
+
+func (l *lookups) reverse(ctx context.Context, addr string) (DanglingReason, error) {
+	return l.do("addr "+addr, func() (DanglingReason, error) {
+		names, err := l.resolver.LookupAddr(ctx, addr)
+		if isNotFound(err) || (err == nil && len(names) == 0) {
+			return DanglingNoPTR, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		ip := net.ParseIP(addr)
+		for _, name := range names {
+			addrs, err := l.resolver.LookupHost(ctx, name)
+			if err != nil && !isNotFound(err) {
+				return "", err
+			}
+			for _, a := range addrs {
+				if net.ParseIP(a).Equal(ip) {
+					return "", nil
+				}
+			}
+		}
+		return DanglingPTRMismatch, nil
+	})
+}
+
+// Reports whether a lookup failed because the name doesn't exist
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,96 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Answers from fixed tables, counting lookups
+type fakeResolver struct {
+	mu    sync.Mutex
+	hosts map[string][]string
+	addrs map[string][]string
+	fail  map[string]bool
+	calls map[string]int
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[host]++
+	if r.fail[host] {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: host, IsTemporary: true}
+	}
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[addr]++
+	if names, ok := r.addrs[addr]; ok {
+		return names, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func TestFindDangling(t *testing.T) {
+	fake, client := newFakeDME(t)
+	a := fake.addDomain("example.com",
+		Record{Name: "www", Type: "CNAME", Value: "web"},
+		Record{Name: "web", Type: "A", Value: "192.0.2.1"},
+		Record{Name: "old", Type: "CNAME", Value: "gone.herokuapp.com."},
+		Record{Name: "old2", Type: "CNAME", Value: "gone.herokuapp.com."},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.2"},
+		Record{Name: "flaky", Type: "CNAME", Value: "flaky.example.net."},
+	)
+	b := fake.addDomain("example.org",
+		Record{Name: "", Type: "ANAME", Value: "lb.example.net."},
+		Record{Name: "v6", Type: "AAAA", Value: "2001:db8::1"},
+	)
+	resolver := &fakeResolver{
+		hosts: map[string][]string{
+			"web.example.com.":   {"192.0.2.1"},
+			"lb.example.net.":    {"192.0.2.9"},
+			"mail.example.com.":  {"192.0.2.2"},
+			"other.example.net.": {"192.0.2.50"},
+		},
+		addrs: map[string][]string{
+			"192.0.2.1": {"other.example.net."},
+			"192.0.2.2": {"mail.example.com."},
+		},
+		fail:  map[string]bool{"flaky.example.net.": true},
+		calls: map[string]int{},
+	}
+
+	dangling, err := client.FindDangling(context.Background(), []int{a.ID, b.ID}, DanglingOptions{Resolver: resolver})
+	var dnsErr *net.DNSError
+	assert.True(t, errors.As(err, &dnsErr))
+	require.Len(t, dangling, 2)
+	assert.Equal(t, "old", dangling[0].Record.Name)
+	assert.Equal(t, DanglingUnresolvable, dangling[0].Reason)
+	assert.Equal(t, "old2", dangling[1].Record.Name)
+	assert.Equal(t, 1, resolver.calls["gone.herokuapp.com."])
+	assert.Zero(t, resolver.calls["192.0.2.1"])
+
+	dangling, _ = client.FindDangling(context.Background(), []int{a.ID, b.ID}, DanglingOptions{Resolver: resolver, CheckReverse: true})
+	var reasons []string
+	for _, d := range dangling {
+		reasons = append(reasons, d.Domain+" "+d.Record.Name+": "+string(d.Reason))
+	}
+	assert.Equal(t, []string{
+		"example.com old: target does not resolve",
+		"example.com old2: target does not resolve",
+		"example.com web: reverse DNS does not resolve back to the address",
+		"example.org v6: address has no reverse DNS",
+	}, reasons)
+}
@@ -0,0 +1,71 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Values filled in for records that leave them unset
+type RecordDefaults struct {
+	Ttl         int
+	GtdLocation string
+
+	// Set in Annotations for each record created, unless the record
+	// already has an annotation. Needs Annotations.
+	Annotation  Annotation
+	Annotations AnnotationStore
+}
+
+// Fills in the TTL and GTD location of records created or updated
+// without them, and annotates newly created records
+func WithRecordDefaults(defaults RecordDefaults) Option {
+	return func(c *Client) {
+		c.recordDefaults = defaults
+	}
+}
+
+// Returns the records with defaults filled in, ready to send to the API
+func (c *Client) withDefaults(records ...Record) []Record {
+	d := c.recordDefaults
+	filled := make([]Record, len(records))
+	for idx, record := range records {
+		// notes are kept client side
+		record.Note = ""
+		if record.Ttl == 0 {
+			record.Ttl = d.Ttl
+		}
+		if record.GtdLocation == "" {
+			record.GtdLocation = d.GtdLocation
+		}
+		filled[idx] = record
+	}
+	return filled
+}
+
+// Records the default annotation for newly created records
+func (c *Client) annotateCreated(ctx context.Context, domainID int, created []Record) error {
+	d := c.recordDefaults
+	if d.Annotations == nil || d.Annotation.IsZero() {
+		return nil
+	}
+	var errs []error
+	seen := map[string]bool{}
+	for _, record := range created {
+		// companion records aren't annotated themselves
+		key := record.Name + "/" + record.Type
+		if seen[key] || strings.HasPrefix(record.Name, AnnotationLabel) {
+			continue
+		}
+		seen[key] = true
+		_, ok, err := d.Annotations.Get(ctx, domainID, record.Name, record.Type)
+		if err == nil && !ok {
+			err = d.Annotations.Set(ctx, domainID, RecordAnnotation{record.Name, record.Type, d.Annotation})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("annotating %s %s: %w", record.Type, record.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDefaults(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	store := &FileAnnotations{Path: filepath.Join(t.TempDir(), "annotations.json")}
+	client.recordDefaults = RecordDefaults{
+		Ttl:         300,
+		GtdLocation: GtdDefault,
+		Annotation:  Annotation{Owner: "alice", Team: "web"},
+		Annotations: store,
+	}
+	ctx := context.Background()
+	records := client.Records(domain.ID)
+
+	www, err := records.Create(ctx, Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, 300, www.Ttl)
+	assert.Equal(t, GtdDefault, www.GtdLocation)
+
+	// explicit values win, and existing annotations are kept
+	require.NoError(t, store.Set(ctx, domain.ID, RecordAnnotation{"api", "A", Annotation{Owner: "bob"}}))
+	created, err := records.CreateMulti(ctx, []Record{
+		{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 60},
+		{Name: "api", Type: "A", Value: "192.0.2.3", GtdLocation: "EUROPE"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 60, created[0].Ttl)
+	assert.Equal(t, 300, created[1].Ttl)
+	assert.Equal(t, "EUROPE", created[1].GtdLocation)
+
+	owner, _, err := OwnerOf(ctx, store, domain.ID, www)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", owner.Owner)
+	owner, _, err = OwnerOf(ctx, store, domain.ID, created[0])
+	require.NoError(t, err)
+	assert.Equal(t, "bob", owner.Owner)
+
+	www.Ttl = 0
+	require.NoError(t, records.Update(ctx, www))
+	assert.Equal(t, 300, fake.records[domain.ID][www.ID].Ttl)
+}
+
+func TestRecordDefaultsTXTAnnotations(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client.recordDefaults = RecordDefaults{
+		Ttl:         300,
+		Annotation:  Annotation{Team: "web"},
+		Annotations: TXTAnnotations{Client: client},
+	}
+
+	// the companion TXT records aren't annotated in turn
+	_, err := client.Records(domain.ID).Create(context.Background(), Record{Name: "www", Type: "A", Value: "192.0.2.1", GtdLocation: GtdDefault})
+	require.NoError(t, err)
+	var names []string
+	for _, record := range fake.recordList(domain.ID) {
+		names = append(names, record.Type+" "+record.Name)
+	}
+	assert.ElementsMatch(t, []string{"A www", "TXT _dme-meta.www"}, names)
+}
@@ -0,0 +1,245 @@
+// Package dmegrpc serves the client's domain, record and sync
+// operations over gRPC, so services not written in Go can manage DNS
+// Made Easy through a typed API. The service is defined in dme.proto;
+// clients in other languages generate their stubs from it.
+//
+// The server has no authentication of its own; serve it on an internal
+// network or behind an authenticating proxy.
+package dmegrpc
+
+//go:generate protoc --go_out=dmepb --go_opt=paths=source_relative --go-grpc_out=dmepb --go-grpc_opt=paths=source_relative dme.proto
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmegrpc/dmepb"
+	"github.com/john-k/dnsmadeeasy/reconcile"
+	"github.com/john-k/dnsmadeeasy/seed"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Implements dmepb.DNSServer over a client
+type Server struct {
+	dmepb.UnimplementedDNSServer
+
+	client *dme.Client
+
+	// Syncs are logged here; log.Default() if nil
+	Logger *log.Logger
+}
+
+func NewServer(client *dme.Client) *Server {
+	return &Server{client: client}
+}
+
+func (s *Server) ListDomains(ctx context.Context, req *dmepb.ListDomainsRequest) (*dmepb.ListDomainsResponse, error) {
+	domains, err := s.client.Domains().List(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &dmepb.ListDomainsResponse{}
+	for _, domain := range domains {
+		resp.Domains = append(resp.Domains, domainToProto(domain))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetDomain(ctx context.Context, req *dmepb.GetDomainRequest) (*dmepb.Domain, error) {
+	id := int(req.GetId())
+	if id == 0 {
+		if req.GetName() == "" {
+			return nil, status.Error(codes.InvalidArgument, "a domain id or name is required")
+		}
+		var err error
+		if id, err = s.client.Domains().IdFor(ctx, req.GetName()); err != nil {
+			return nil, toStatus(err)
+		}
+	}
+	domain, err := s.client.Domains().Get(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return domainToProto(domain), nil
+}
+
+func (s *Server) CreateDomain(ctx context.Context, req *dmepb.CreateDomainRequest) (*dmepb.Domain, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "a domain name is required")
+	}
+	domain, err := s.client.Domains().Create(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return domainToProto(domain), nil
+}
+
+func (s *Server) DeleteDomain(ctx context.Context, req *dmepb.DeleteDomainRequest) (*dmepb.DeleteDomainResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "a domain id is required")
+	}
+	if err := s.client.Domains().Delete(ctx, int(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &dmepb.DeleteDomainResponse{}, nil
+}
+
+func (s *Server) ListRecords(ctx context.Context, req *dmepb.ListRecordsRequest) (*dmepb.ListRecordsResponse, error) {
+	if req.GetDomainId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "a domain id is required")
+	}
+	if (req.GetName() == "") != (req.GetType() == "") {
+		return nil, status.Error(codes.InvalidArgument, "name and type must be set together")
+	}
+	records, err := s.client.Records(int(req.GetDomainId())).List(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &dmepb.ListRecordsResponse{}
+	for _, record := range records {
+		if req.GetType() != "" && (!strings.EqualFold(record.Name, req.GetName()) || !strings.EqualFold(record.Type, req.GetType())) {
+			continue
+		}
+		resp.Records = append(resp.Records, recordToProto(record))
+	}
+	return resp, nil
+}
+
+func (s *Server) CreateRecord(ctx context.Context, req *dmepb.CreateRecordRequest) (*dmepb.Record, error) {
+	if req.GetDomainId() == 0 || req.GetRecord() == nil {
+		return nil, status.Error(codes.InvalidArgument, "a domain id and record are required")
+	}
+	record, err := s.client.Records(int(req.GetDomainId())).Create(ctx, recordFromProto(req.GetRecord()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return recordToProto(record), nil
+}
+
+func (s *Server) UpdateRecord(ctx context.Context, req *dmepb.UpdateRecordRequest) (*dmepb.Record, error) {
+	if req.GetDomainId() == 0 || req.GetRecord().GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "a domain id and record with an id are required")
+	}
+	record := recordFromProto(req.GetRecord())
+	if err := s.client.Records(int(req.GetDomainId())).Update(ctx, record); err != nil {
+		return nil, toStatus(err)
+	}
+	return recordToProto(record), nil
+}
+
+func (s *Server) DeleteRecord(ctx context.Context, req *dmepb.DeleteRecordRequest) (*dmepb.DeleteRecordResponse, error) {
+	if req.GetDomainId() == 0 || req.GetRecordId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "a domain id and record id are required")
+	}
+	if err := s.client.Records(int(req.GetDomainId())).Delete(ctx, int(req.GetRecordId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &dmepb.DeleteRecordResponse{}, nil
+}
+
+func (s *Server) Sync(ctx context.Context, req *dmepb.SyncRequest) (*dmepb.SyncResponse, error) {
+	if req.GetDomain() == "" {
+		return nil, status.Error(codes.InvalidArgument, "a domain is required")
+	}
+	spec := seed.DomainSpec{Name: req.GetDomain()}
+	for _, record := range req.GetRecords() {
+		spec.Records = append(spec.Records, seed.RecordSpec{
+			Name:        record.GetName(),
+			Type:        record.GetType(),
+			Value:       record.GetValue(),
+			Ttl:         int(record.GetTtl()),
+			GtdLocation: record.GetGtdLocation(),
+			MxLevel:     int(record.GetMxLevel()),
+			Priority:    int(record.GetPriority()),
+			Weight:      int(record.GetWeight()),
+			Port:        int(record.GetPort()),
+		})
+	}
+	r := &reconcile.Reconciler{
+		Client: s.client,
+		Policy: reconcile.Policy{
+			MaxDeletes: int(req.GetMaxDeletes()),
+			Protected:  req.GetProtected(),
+			DryRun:     req.GetDryRun(),
+		},
+		Logger: s.Logger,
+	}
+	results, _ := r.Zones(ctx, []seed.DomainSpec{spec})
+	result := results[0]
+	if result.Err != nil {
+		return nil, toStatus(result.Err)
+	}
+	resp := &dmepb.SyncResponse{DomainId: int64(result.DomainID), Applied: result.Applied}
+	for _, op := range result.Plan {
+		resp.Plan = append(resp.Plan, &dmepb.Operation{Type: dmepb.Operation_Type(op.Type), Record: recordToProto(op.Record)})
+	}
+	return resp, nil
+}
+
+// Maps client errors to the status codes callers can act on
+func toStatus(err error) error {
+	var apiErr *dme.APIError
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, dme.ErrNotFound), errors.Is(err, dme.ErrDomainNotFound):
+		code = codes.NotFound
+	case errors.Is(err, dme.ErrConflict):
+		code = codes.Aborted
+	case errors.Is(err, reconcile.ErrPolicy), errors.Is(err, dme.ErrNotApproved), errors.Is(err, dme.ErrOutsideMaintenanceWindow):
+		code = codes.FailedPrecondition
+	case errors.Is(err, dme.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, dme.ErrCircuitOpen), errors.Is(err, dme.ErrNetwork):
+		code = codes.Unavailable
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest:
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+func domainToProto(domain dme.Domain) *dmepb.Domain {
+	return &dmepb.Domain{
+		Id:              int64(domain.ID),
+		Name:            domain.Name,
+		FolderId:        int64(domain.FolderID),
+		GtdEnabled:      domain.GtdEnabled,
+		PendingActionId: int32(domain.PendingActionID),
+	}
+}
+
+func recordToProto(record dme.Record) *dmepb.Record {
+	return &dmepb.Record{
+		Id:          int64(record.ID),
+		Name:        record.Name,
+		Type:        record.Type,
+		Value:       record.Value,
+		Ttl:         int32(record.Ttl),
+		GtdLocation: record.GtdLocation,
+		MxLevel:     int32(record.MxLevel),
+		Priority:    int32(record.Priority),
+		Weight:      int32(record.Weight),
+		Port:        int32(record.Port),
+	}
+}
+
+func recordFromProto(record *dmepb.Record) dme.Record {
+	return dme.Record{
+		ID:          int(record.GetId()),
+		Name:        record.GetName(),
+		Type:        record.GetType(),
+		Value:       record.GetValue(),
+		Ttl:         int(record.GetTtl()),
+		GtdLocation: record.GetGtdLocation(),
+		MxLevel:     int(record.GetMxLevel()),
+		Priority:    int(record.GetPriority()),
+		Weight:      int(record.GetWeight()),
+		Port:        int(record.GetPort()),
+	}
+}
@@ -0,0 +1,138 @@
+package dmegrpc
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmegrpc/dmepb"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Returns a fake account holding example.com with two A records
+func newFakeAPI(t *testing.T) (*dmetest.FakeAccount, *dme.Client) {
+	f, client := dmetest.NewFakeAccount(t)
+	f.PutDomain(dme.Domain{ID: 1, Name: "example.com"},
+		dme.Record{ID: 10, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		dme.Record{ID: 11, Name: "old", Type: "A", Value: "192.0.2.9", Ttl: 300, GtdLocation: "DEFAULT"},
+	)
+	return f, client
+}
+
+// Serves a Server over an in-memory connection and returns a client
+// for it
+func newDNSClient(t *testing.T) (*dmetest.FakeAccount, dmepb.DNSClient) {
+	f, client := newFakeAPI(t)
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s := NewServer(client)
+	s.Logger = log.New(io.Discard, "", 0)
+	dmepb.RegisterDNSServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return f, dmepb.NewDNSClient(conn)
+}
+
+func TestServerDomainsAndRecords(t *testing.T) {
+	f, c := newDNSClient(t)
+	ctx := context.Background()
+
+	domains, err := c.ListDomains(ctx, &dmepb.ListDomainsRequest{})
+	require.NoError(t, err)
+	require.Len(t, domains.Domains, 1)
+	assert.Equal(t, "example.com", domains.Domains[0].Name)
+
+	domain, err := c.GetDomain(ctx, &dmepb.GetDomainRequest{Name: "example.com"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, domain.Id)
+
+	_, err = c.GetDomain(ctx, &dmepb.GetDomainRequest{Name: "missing.com"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = c.GetDomain(ctx, &dmepb.GetDomainRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	created, err := c.CreateRecord(ctx, &dmepb.CreateRecordRequest{DomainId: 1,
+		Record: &dmepb.Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 60}})
+	require.NoError(t, err)
+	assert.NotZero(t, created.Id)
+
+	records, err := c.ListRecords(ctx, &dmepb.ListRecordsRequest{DomainId: 1, Name: "API", Type: "a"})
+	require.NoError(t, err)
+	require.Len(t, records.Records, 1)
+	assert.Equal(t, "192.0.2.2", records.Records[0].Value)
+
+	_, err = c.DeleteRecord(ctx, &dmepb.DeleteRecordRequest{DomainId: 1, RecordId: created.Id})
+	require.NoError(t, err)
+	assert.Len(t, f.Records(1), 2)
+}
+
+func TestServerSync(t *testing.T) {
+	f, c := newDNSClient(t)
+	ctx := context.Background()
+	desired := []*dmepb.Record{
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "new", Type: "A", Value: "192.0.2.5", Ttl: 300, GtdLocation: "DEFAULT"},
+	}
+
+	plan, err := c.Sync(ctx, &dmepb.SyncRequest{Domain: "example.com", Records: desired, DryRun: true})
+	require.NoError(t, err)
+	assert.False(t, plan.Applied)
+	require.Len(t, plan.Plan, 2)
+	assert.Equal(t, dmepb.Operation_CREATE, plan.Plan[0].Type)
+	assert.Equal(t, dmepb.Operation_DELETE, plan.Plan[1].Type)
+	assert.Len(t, f.Records(1), 2)
+
+	_, err = c.Sync(ctx, &dmepb.SyncRequest{Domain: "example.com", Records: desired, Protected: []string{"new"}})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	applied, err := c.Sync(ctx, &dmepb.SyncRequest{Domain: "example.com", Records: desired})
+	require.NoError(t, err)
+	assert.True(t, applied.Applied)
+	assert.EqualValues(t, 1, applied.DomainId)
+	var names []string
+	for _, record := range f.Records(1) {
+		names = append(names, record.Name)
+	}
+	assert.ElementsMatch(t, []string{"www", "new"}, names)
+}
+
+// Handlers share one client; run with -race
+func TestServerConcurrentLookups(t *testing.T) {
+	_, c := newDNSClient(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for idx := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := []string{"example.com", "missing.com"}[idx%2]
+			domain, err := c.GetDomain(ctx, &dmepb.GetDomainRequest{Name: name})
+			if name == "missing.com" {
+				assert.Equal(t, codes.NotFound, status.Code(err))
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.EqualValues(t, 1, domain.Id)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+// Package dmetest provides helpers for tests that run against a DNS
+// Made Easy account, usually the sandbox: throwaway domains with random
+// names that are removed when the test finishes. FaultInjector makes a
+// client see the failures the API is prone to, and FakeAccount stands
+// in for the API where no account is needed.
+package dmetest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// The top level domain of generated names, which never resolves
+const TestTLD = "testing"
+
+// Returns a random domain name under TestTLD
+func RandomDomainName() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "dme-" + hex.EncodeToString(b) + "." + TestTLD
+}
+
+// Creates a domain with a random name, deleting it again when the test
+// and its subtests complete
+func CreateDomain(t testing.TB, client *dme.Client) (dme.Domain, error) {
+	t.Helper()
+	name := RandomDomainName()
+	domain, err := client.CreateDomain(name)
+	if err != nil {
+		return dme.Domain{}, err
+	}
+	t.Logf("Created domain %s", name)
+	t.Cleanup(func() { DeleteDomain(t, client, domain.ID) })
+	return domain, nil
+}
+
+// Like CreateDomain, but stops the test if the domain can't be created
+func NewDomain(t testing.TB, client *dme.Client) dme.Domain {
+	t.Helper()
+	domain, err := CreateDomain(t, client)
+	if err != nil {
+		t.Fatalf("creating test domain: %v", err)
+	}
+	return domain
+}
+
+// Deletes a domain once any pending action, such as its creation, has
+// completed. Domains that are already gone are ignored.
+//
+// NOTE: DNS Made Easy can take several minutes to finish creating a
+// domain; the wait is bounded by the client's polling deadline (see
+// dnsmadeeasy.WithPolling)
+func DeleteDomain(t testing.TB, client *dme.Client, domainID int) {
+	t.Helper()
+	_, err := client.WaitForDomain(context.Background(), domainID)
+	if errors.Is(err, dme.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		t.Errorf("waiting to delete domain %d: %v", domainID, err)
+		return
+	}
+	if err := client.DeleteDomain(domainID); err != nil && !errors.Is(err, dme.ErrNotFound) {
+		t.Errorf("deleting domain %d: %v", domainID, err)
+	}
+}
@@ -0,0 +1,45 @@
+package dmetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDomain(t *testing.T) {
+	var created, deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			var domain dme.Domain
+			json.NewDecoder(r.Body).Decode(&domain)
+			created = append(created, domain.Name)
+			domain.ID = 42
+			json.NewEncoder(w).Encode(domain)
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(dme.Domain{ID: 42})
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	client := dme.GetClient("key", "secret", dme.BaseURL(server.URL+"/V2.0/"))
+
+	t.Run("subtest", func(t *testing.T) {
+		domain := NewDomain(t, client)
+		assert.Equal(t, 42, domain.ID)
+		assert.Empty(t, deleted)
+	})
+
+	require.Len(t, created, 1)
+	assert.True(t, strings.HasSuffix(created[0], "."+TestTLD))
+	assert.Equal(t, []string{"/V2.0/dns/managed/42"}, deleted)
+	assert.NotEqual(t, RandomDomainName(), RandomDomainName())
+}
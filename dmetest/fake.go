@@ -0,0 +1,369 @@
+package dmetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// An in-memory stand-in for the domain and record endpoints of the API,
+// for tests that don't need the sandbox. Domains and records get IDs
+// when created, and requests for missing ones fail with 404 as DNS Made
+// Easy's do.
+type FakeAccount struct {
+	mu      sync.Mutex
+	nextID  int
+	domains map[int]dme.Domain
+	records map[int]map[int]dme.Record
+
+	monitors map[int]dme.Monitor
+
+	// the query usage for each month, keyed by year/month
+	usage map[string][]dme.QueryUsage
+
+	// reported in the rate-limit headers when set
+	remaining int
+}
+
+// Serves a FakeAccount until the test completes and returns a client
+// for it
+func NewFakeAccount(t testing.TB) (*FakeAccount, *dme.Client) {
+	f := &FakeAccount{
+		nextID:   1000,
+		domains:  map[int]dme.Domain{},
+		records:  map[int]map[int]dme.Record{},
+		monitors: map[int]dme.Monitor{},
+		usage:    map[string][]dme.QueryUsage{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dns/managed/{$}", f.listDomains)
+	mux.HandleFunc("POST /dns/managed/{$}", f.createDomain)
+	mux.HandleFunc("GET /dns/managed/{domainId}", f.getDomain)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}", f.deleteDomain)
+	mux.HandleFunc("GET /dns/managed/{domainId}/records", f.listRecords)
+	mux.HandleFunc("POST /dns/managed/{domainId}/records", f.createRecord)
+	mux.HandleFunc("POST /dns/managed/{domainId}/records/createMulti", f.createRecords)
+	mux.HandleFunc("PUT /dns/managed/{domainId}/records/{recordId}", f.updateRecord)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records/{recordId}", f.deleteRecord)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records/{$}", f.deleteRecords)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records", f.deleteAllRecords)
+	mux.HandleFunc("GET /monitor/{recordId}", f.getMonitor)
+	mux.HandleFunc("PUT /monitor/{recordId}", f.updateMonitor)
+	mux.HandleFunc("GET /usageApi/queriesApi/{year}/{month}", f.monthUsage)
+
+	prefix := "/" + string(dme.DefaultAPIVersion)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.remaining > 0 {
+			w.Header().Set(dme.RequestLimitHeader, "150")
+			w.Header().Set(dme.RequestsRemainingHeader, strconv.Itoa(f.remaining))
+		}
+		http.StripPrefix(prefix, mux).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return f, dme.GetClient("key", "secret", dme.BaseURL(server.URL+prefix+"/"))
+}
+
+// Adds a domain and its records directly to the account
+func (f *FakeAccount) AddDomain(name string, records ...dme.Record) dme.Domain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	domain := f.addDomain(name)
+	for _, record := range records {
+		f.insert(domain.ID, record)
+	}
+	return domain
+}
+
+// Adds a domain with the ID it is given, or a new one when it has none,
+// replacing any domain with the same ID. Records are added as by
+// PutRecords.
+func (f *FakeAccount) PutDomain(domain dme.Domain, records ...dme.Record) dme.Domain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if domain.ID == 0 {
+		f.nextID++
+		domain.ID = f.nextID
+	}
+	f.nextID = max(f.nextID, domain.ID)
+	f.domains[domain.ID] = domain
+	if f.records[domain.ID] == nil {
+		f.records[domain.ID] = map[int]dme.Record{}
+	}
+	for _, record := range records {
+		f.put(domain.ID, record)
+	}
+	return domain
+}
+
+// Adds records to a domain, replacing those with the same ID. Records
+// without an ID are given a new one.
+func (f *FakeAccount) PutRecords(domainID int, records ...dme.Record) []dme.Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	put := []dme.Record{}
+	for _, record := range records {
+		put = append(put, f.put(domainID, record))
+	}
+	return put
+}
+
+// Returns the account's domains, ordered by ID
+func (f *FakeAccount) Domains() []dme.Domain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.domainList()
+}
+
+// Returns the records of a domain, ordered by ID
+func (f *FakeAccount) Records(domainID int) []dme.Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.recordList(domainID)
+}
+
+// Returns the monitor of a record, which is off unless set through the
+// API
+func (f *FakeAccount) Monitor(recordID int) dme.Monitor {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.monitor(recordID)
+}
+
+// Sets the query usage reported for a month
+func (f *FakeAccount) SetUsage(year int, month time.Month, usage ...dme.QueryUsage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.usage[usageKey(strconv.Itoa(year), strconv.Itoa(int(month)))] = usage
+}
+
+// Reports n requests left of a quota of 150 per window on every
+// response, as DNS Made Easy does. Zero, the default, leaves the headers out.
+func (f *FakeAccount) SetRemaining(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.remaining = n
+}
+
+func (f *FakeAccount) addDomain(name string) dme.Domain {
+	f.nextID++
+	domain := dme.Domain{ID: f.nextID, Name: name}
+	f.domains[domain.ID] = domain
+	f.records[domain.ID] = map[int]dme.Record{}
+	return domain
+}
+
+func (f *FakeAccount) insert(domainID int, record dme.Record) dme.Record {
+	f.nextID++
+	record.ID = f.nextID
+	record.SourceId = domainID
+	f.records[domainID][record.ID] = record
+	return record
+}
+
+func (f *FakeAccount) put(domainID int, record dme.Record) dme.Record {
+	if record.ID == 0 {
+		return f.insert(domainID, record)
+	}
+	f.nextID = max(f.nextID, record.ID)
+	record.SourceId = domainID
+	f.records[domainID][record.ID] = record
+	return record
+}
+
+func (f *FakeAccount) monitor(recordID int) dme.Monitor {
+	if monitor, ok := f.monitors[recordID]; ok {
+		return monitor
+	}
+	return dme.Monitor{RecordID: recordID}
+}
+
+func usageKey(year, month string) string {
+	return year + "/" + month
+}
+
+func (f *FakeAccount) domainList() []dme.Domain {
+	domains := []dme.Domain{}
+	for _, domain := range f.domains {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].ID < domains[j].ID })
+	return domains
+}
+
+func (f *FakeAccount) recordList(domainID int) []dme.Record {
+	records := []dme.Record{}
+	for _, record := range f.records[domainID] {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string][]string{"error": {msg}})
+}
+
+func (f *FakeAccount) domainFor(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, _ := strconv.Atoi(r.PathValue("domainId"))
+	if _, ok := f.domains[id]; !ok {
+		writeError(w, http.StatusNotFound, "Domain not found")
+		return 0, false
+	}
+	return id, true
+}
+
+func (f *FakeAccount) listDomains(w http.ResponseWriter, r *http.Request) {
+	domains := f.domainList()
+	writeJSON(w, http.StatusOK, dme.DomainsResp{TotalRecords: len(domains), TotalPages: 1, Domains: domains, CurrentPage: 1})
+}
+
+func (f *FakeAccount) createDomain(w http.ResponseWriter, r *http.Request) {
+	var domain dme.Domain
+	json.NewDecoder(r.Body).Decode(&domain)
+	for _, existing := range f.domains {
+		if existing.Name == domain.Name {
+			writeError(w, http.StatusBadRequest, "Domain already exists")
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, f.addDomain(domain.Name))
+}
+
+func (f *FakeAccount) getDomain(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		writeJSON(w, http.StatusOK, f.domains[id])
+	}
+}
+
+func (f *FakeAccount) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		delete(f.domains, id)
+		delete(f.records, id)
+	}
+}
+
+func (f *FakeAccount) listRecords(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		records := f.recordList(id)
+		writeJSON(w, http.StatusOK, dme.RecordsResp{TotalRecords: len(records), TotalPages: 1, Records: records, CurrentPage: 1})
+	}
+}
+
+func (f *FakeAccount) createRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	var record dme.Record
+	json.NewDecoder(r.Body).Decode(&record)
+	writeJSON(w, http.StatusCreated, f.insert(id, record))
+}
+
+func (f *FakeAccount) createRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	var records []dme.Record
+	json.NewDecoder(r.Body).Decode(&records)
+	created := []dme.Record{}
+	for _, record := range records {
+		created = append(created, f.insert(id, record))
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (f *FakeAccount) updateRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	recordID, _ := strconv.Atoi(r.PathValue("recordId"))
+	if _, ok := f.records[id][recordID]; !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	var record dme.Record
+	json.NewDecoder(r.Body).Decode(&record)
+	record.ID = recordID
+	record.SourceId = id
+	f.records[id][recordID] = record
+}
+
+func (f *FakeAccount) deleteRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	recordID, _ := strconv.Atoi(r.PathValue("recordId"))
+	if _, ok := f.records[id][recordID]; !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	delete(f.records[id], recordID)
+}
+
+func (f *FakeAccount) deleteRecords(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		for _, recordID := range r.URL.Query()["ids"] {
+			recordID, _ := strconv.Atoi(recordID)
+			delete(f.records[id], recordID)
+		}
+	}
+}
+
+func (f *FakeAccount) deleteAllRecords(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		f.records[id] = map[int]dme.Record{}
+	}
+}
+
+// Writes a 404 unless the record of the request exists in some domain
+func (f *FakeAccount) recordFor(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, _ := strconv.Atoi(r.PathValue("recordId"))
+	for _, records := range f.records {
+		if _, ok := records[id]; ok {
+			return id, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "Record not found")
+	return 0, false
+}
+
+func (f *FakeAccount) getMonitor(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.recordFor(w, r); ok {
+		writeJSON(w, http.StatusOK, f.monitor(id))
+	}
+}
+
+func (f *FakeAccount) updateMonitor(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.recordFor(w, r)
+	if !ok {
+		return
+	}
+	var monitor dme.Monitor
+	json.NewDecoder(r.Body).Decode(&monitor)
+	monitor.RecordID = id
+	f.monitors[id] = monitor
+}
+
+func (f *FakeAccount) monthUsage(w http.ResponseWriter, r *http.Request) {
+	usage := f.usage[usageKey(r.PathValue("year"), r.PathValue("month"))]
+	writeJSON(w, http.StatusOK, dme.QueryUsageResp{Usage: usage, TotalRecords: len(usage), TotalPages: 1, CurrentPage: 1})
+}
@@ -0,0 +1,98 @@
+package dmetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeAccount(t *testing.T) {
+	account, client := NewFakeAccount(t)
+	ctx := context.Background()
+	existing := account.AddDomain("example.com", dme.Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+
+	id, err := client.Domains().IdFor(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, id)
+	_, err = client.Domains().Create(ctx, "example.com")
+	assert.Error(t, err)
+
+	created, err := client.Records(id).CreateMulti(ctx, []dme.Record{{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300}})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	records, err := client.Records(id).List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	require.NoError(t, client.Records(id).Delete(ctx, created[0].ID))
+	assert.ErrorIs(t, client.Records(id).Delete(ctx, created[0].ID), dme.ErrNotFound)
+	require.NoError(t, client.DeleteDomain(id))
+	assert.Empty(t, account.Domains())
+	_, err = client.GetDomain(id)
+	assert.ErrorIs(t, err, dme.ErrNotFound)
+}
+
+func TestFakeAccountQuota(t *testing.T) {
+	account, client := NewFakeAccount(t)
+	ctx := context.Background()
+	_, err := client.Domains().List(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, client.RateLimit().Limit)
+
+	account.SetRemaining(5)
+	_, err = client.Domains().List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 150, client.RateLimit().Limit)
+	assert.Equal(t, 5, client.RateLimit().Remaining)
+}
+
+func TestFakeAccountPut(t *testing.T) {
+	account, client := NewFakeAccount(t)
+	ctx := context.Background()
+	domain := account.PutDomain(dme.Domain{ID: 1, Name: "example.com"},
+		dme.Record{ID: 10, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		dme.Record{ID: 11, Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	)
+	assert.Equal(t, 1, domain.ID)
+	put := account.PutRecords(domain.ID, dme.Record{ID: 10, Name: "www", Type: "A", Value: "192.0.2.9", Ttl: 300}, dme.Record{Name: "mail", Type: "A", Value: "192.0.2.3", Ttl: 300})
+	assert.Equal(t, 10, put[0].ID)
+	assert.Greater(t, put[1].ID, 11)
+
+	records, err := client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "192.0.2.9", records[0].Value)
+	_, err = client.Records(domain.ID).DeleteMulti(ctx, []int{10, 11})
+	require.NoError(t, err)
+	assert.Len(t, account.Records(domain.ID), 1)
+	require.NoError(t, client.Records(domain.ID).DeleteAll(ctx))
+	assert.Empty(t, account.Records(domain.ID))
+}
+
+func TestFakeAccountMonitorsAndUsage(t *testing.T) {
+	account, client := NewFakeAccount(t)
+	ctx := context.Background()
+	domain := account.AddDomain("example.com", dme.Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	record := account.Records(domain.ID)[0]
+
+	require.NoError(t, client.Monitors().Update(ctx, record.ID, dme.Monitor{Monitor: true, ProtocolID: dme.ProtocolHTTP, Port: 80, Sensitivity: dme.SensitivityLow}))
+	assert.True(t, account.Monitor(record.ID).Monitor)
+	monitor, err := client.Monitors().Get(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, account.Monitor(record.ID), monitor)
+	_, err = client.Monitors().Get(ctx, record.ID+1)
+	assert.ErrorIs(t, err, dme.ErrNotFound)
+
+	account.SetUsage(2024, time.February, dme.QueryUsage{Year: 2024, Month: 2, Total: 1500})
+	usage, err := client.Usage().Month(ctx, 2024, time.February)
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	assert.EqualValues(t, 1500, usage[0].Total)
+	usage, err = client.Usage().Month(ctx, 2024, time.March)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+}
@@ -0,0 +1,219 @@
+package dmetest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// A failure injected into the client's traffic. Only the fields that
+// are set take effect, so latency can be combined with any one way of
+// failing.
+type Fault struct {
+	// Requests the fault applies to; all if nil
+	Match func(req *http.Request) bool
+
+	// How many matching requests fail; every one if zero
+	Times int
+
+	// Delay before the request is sent
+	Latency time.Duration
+
+	// Answers with this status and an error body instead of calling the
+	// API
+	Status int
+
+	// Answers 429 with an exhausted request quota
+	RateLimit bool
+
+	// Cuts the real response body off half way
+	Malformed bool
+
+	// Drops the second half of the records of a listing, leaving its
+	// totals alone, as a page cut short
+	PartialPage bool
+
+	fired int
+}
+
+// Fault presets for common DNS Made Easy failure modes
+
+// Delays every request by d
+func Latency(d time.Duration) Fault {
+	return Fault{Latency: d}
+}
+
+// Fails the next n requests with a 5xx
+func ServerErrors(n int) Fault {
+	return Fault{Times: n, Status: http.StatusServiceUnavailable}
+}
+
+// Rejects the next n requests for exceeding the rate limit
+func RateLimited(n int) Fault {
+	return Fault{Times: n, RateLimit: true}
+}
+
+// Truncates the bodies of the next n responses
+func MalformedJSON(n int) Fault {
+	return Fault{Times: n, Malformed: true}
+}
+
+// Cuts the next n record listings short
+func PartialPages(n int) Fault {
+	return Fault{
+		Times:       n,
+		PartialPage: true,
+		Match: func(req *http.Request) bool {
+			return req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/records")
+		},
+	}
+}
+
+// Injects faults into a client's requests, for testing how code copes
+// with a misbehaving API. Faults are tried in the order added and the
+// first that matches a request applies.
+//
+//	faults := &dmetest.FaultInjector{}
+//	client := dme.GetClient(key, secret, dme.Sandbox, faults.Option())
+//	faults.Add(dmetest.ServerErrors(3))
+type FaultInjector struct {
+	mu     sync.Mutex
+	faults []*Fault
+}
+
+// Adds a fault
+func (f *FaultInjector) Add(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault)
+}
+
+// Removes every fault
+func (f *FaultInjector) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// Returns the client option installing the injector
+func (f *FaultInjector) Option() dme.Option {
+	return dme.WithInterceptors(f.Intercept)
+}
+
+// Applies the first matching fault to a request
+func (f *FaultInjector) Intercept(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	fault, ok := f.next(req)
+	if !ok {
+		return next.RoundTrip(req)
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	switch {
+	case fault.RateLimit:
+		resp := errorResponse(req, http.StatusTooManyRequests, "Rate limit exceeded")
+		resp.Header.Set(dme.RequestLimitHeader, "150")
+		resp.Header.Set(dme.RequestsRemainingHeader, "0")
+		return resp, nil
+	case fault.Status != 0:
+		return errorResponse(req, fault.Status, http.StatusText(fault.Status)), nil
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil || !(fault.Malformed || fault.PartialPage) {
+		return resp, err
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case fault.Malformed:
+		body = body[:len(body)/2]
+	case fault.PartialPage:
+		body = partialPage(body)
+	}
+	// the body is now plain JSON, whatever it was sent as
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Set("Content-Length", fmt.Sprint(len(body)))
+	resp.ContentLength = int64(len(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// Returns the fault to apply to req, using up one of its firings
+func (f *FaultInjector) next(req *http.Request) (Fault, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, fault := range f.faults {
+		if fault.Times > 0 && fault.fired >= fault.Times {
+			continue
+		}
+		if fault.Match != nil && !fault.Match(req) {
+			continue
+		}
+		fault.fired++
+		return *fault, true
+	}
+	return Fault{}, false
+}
+
+// Reads a response body, decompressing it if need be
+func readBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	var r io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		r = gz
+	}
+	return io.ReadAll(r)
+}
+
+func errorResponse(req *http.Request, status int, msg string) *http.Response {
+	body, _ := json.Marshal(map[string][]string{"error": {msg}})
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Drops the second half of a listing's data, keeping its other fields
+func partialPage(body []byte) []byte {
+	var listing map[string]json.RawMessage
+	if json.Unmarshal(body, &listing) != nil {
+		return body
+	}
+	var data []json.RawMessage
+	if json.Unmarshal(listing["data"], &data) != nil {
+		return body
+	}
+	listing["data"], _ = json.Marshal(data[:len(data)/2])
+	truncated, err := json.Marshal(listing)
+	if err != nil {
+		return body
+	}
+	return truncated
+}
@@ -0,0 +1,70 @@
+package dmetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjector(t *testing.T) {
+	records := []dme.Record{}
+	for idx := range 10 {
+		records = append(records, dme.Record{ID: idx, Name: fmt.Sprint("host-", idx), Type: "A"})
+	}
+	served := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dme.RecordsResp{TotalRecords: 10, TotalPages: 1, Records: records})
+	}))
+	defer server.Close()
+
+	faults := &FaultInjector{}
+	client := dme.GetClient("key", "secret", dme.BaseURL(server.URL+"/V2.0/"), faults.Option())
+	ctx := context.Background()
+	list := func() ([]dme.Record, error) {
+		return client.Records(1).List(ctx)
+	}
+
+	faults.Add(ServerErrors(2))
+	for range 2 {
+		_, err := list()
+		var apiErr *dme.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	}
+	got, err := list()
+	require.NoError(t, err)
+	assert.Len(t, got, 10)
+	assert.Equal(t, 1, served)
+
+	faults.Add(RateLimited(1))
+	_, err = list()
+	assert.EqualError(t, err, "Rate limit exceeded")
+	assert.Equal(t, 0, client.RateLimit().Remaining)
+
+	faults.Add(MalformedJSON(1))
+	_, err = list()
+	assert.Error(t, err)
+
+	faults.Add(PartialPages(1))
+	page, err := client.Records(1).ListPage(ctx, dme.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 5)
+	assert.Equal(t, 10, page.TotalRecords)
+
+	faults.Reset()
+	faults.Add(Latency(time.Second))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Records(1).List(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,132 @@
+package dnsmadeeasy
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A saved copy of the account's domain Name:ID map
+type DomainCache struct {
+	Domains map[string]int `json:"domains"`
+	Updated time.Time      `json:"updated"`
+}
+
+// Persists the domain Name:ID map between processes
+type DomainCacheStore interface {
+	// Returns the saved cache, or a zero DomainCache if there is none
+	LoadDomainCache() (DomainCache, error)
+	SaveDomainCache(DomainCache) error
+}
+
+// Keeps the domain cache in a JSON file
+type FileDomainCache struct {
+	Path string
+}
+
+func (f FileDomainCache) LoadDomainCache() (DomainCache, error) {
+	var cache DomainCache
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, err
+	}
+	err = json.Unmarshal(data, &cache)
+	return cache, err
+}
+
+func (f FileDomainCache) SaveDomainCache(cache DomainCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o700); err != nil {
+		return err
+	}
+	// write then rename, so concurrent processes never read half a file
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// Loads the domain Name:ID map used by IdForDomain from store when it
+// was saved less than ttl ago, and saves it whenever it is refreshed.
+// Short-lived processes then skip enumerating every domain on startup.
+//
+// NOTE: domains deleted through the client are dropped from the store,
+// but a stale entry for one deleted elsewhere is only noticed when it
+// is used, so ttl should be kept short where domains churn
+func WithDomainCacheStore(store DomainCacheStore, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.domainStore = store
+		c.domainStoreTTL = ttl
+	}
+}
+
+// Returns the domain Name:ID map, loading it from the store when the
+// client has none yet, or nil if neither has one. The map is replaced
+// rather than modified, so it can be read without holding the lock.
+func (c *Client) domainNames() map[string]int {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	if c.zoneIdCache == nil {
+		c.loadDomainNames()
+	}
+	return c.zoneIdCache
+}
+
+// Populates the domain map from the store, if it holds a fresh one.
+// c.domainsMu must be held.
+func (c *Client) loadDomainNames() {
+	if c.domainStore == nil {
+		return
+	}
+	cache, err := c.domainStore.LoadDomainCache()
+	if err != nil || cache.Domains == nil || time.Since(cache.Updated) > c.domainStoreTTL {
+		return
+	}
+	c.zoneIdCache = cache.Domains
+}
+
+// Replaces the domain map, saving it to the store
+func (c *Client) setDomainNames(names map[string]int) {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	c.putDomainNames(names)
+}
+
+// Replaces the domain map and saves it to the store. Failing to save
+// only costs a later enumeration, so errors are ignored. c.domainsMu
+// must be held.
+func (c *Client) putDomainNames(names map[string]int) {
+	c.zoneIdCache = names
+	if c.domainStore != nil {
+		c.domainStore.SaveDomainCache(DomainCache{Domains: names, Updated: time.Now()})
+	}
+}
+
+// Drops a deleted domain from the domain map and the store, so its name
+// no longer resolves to the old ID
+func (c *Client) forgetDomain(domainID int) {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	if c.zoneIdCache == nil {
+		c.loadDomainNames()
+	}
+	names := make(map[string]int, len(c.zoneIdCache))
+	for name, id := range c.zoneIdCache {
+		if id != domainID {
+			names[name] = id
+		}
+	}
+	if len(names) < len(c.zoneIdCache) {
+		c.putDomainNames(names)
+	}
+}
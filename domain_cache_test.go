@@ -0,0 +1,83 @@
+package dnsmadeeasy
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainCacheStore(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	store := FileDomainCache{Path: filepath.Join(t.TempDir(), "cache", "domains.json")}
+	listPath := "GET /dns/managed/"
+
+	first := GetClient("key", "secret", client.BaseURL, WithDomainCacheStore(store, time.Hour))
+	id, err := first.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ID, id)
+	assert.Equal(t, 1, fake.calls[listPath])
+
+	saved, err := store.LoadDomainCache()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"example.com": domain.ID}, saved.Domains)
+
+	// a second process starts from the saved map
+	second := GetClient("key", "secret", client.BaseURL, WithDomainCacheStore(store, time.Hour))
+	id, err = second.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ID, id)
+	assert.Equal(t, 1, fake.calls[listPath])
+
+	// misses still refresh
+	other := fake.addDomain("example.org")
+	id, err = second.IdForDomain("example.org")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, id)
+	assert.Equal(t, 2, fake.calls[listPath])
+
+	// and stale caches are ignored
+	saved.Updated = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, store.SaveDomainCache(saved))
+	third := GetClient("key", "secret", client.BaseURL, WithDomainCacheStore(store, time.Hour))
+	_, err = third.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.calls[listPath])
+
+	empty, err := FileDomainCache{Path: filepath.Join(t.TempDir(), "missing.json")}.LoadDomainCache()
+	require.NoError(t, err)
+	assert.Nil(t, empty.Domains)
+}
+
+func TestDomainCacheForgetsDeleted(t *testing.T) {
+	fake, client := newFakeDME(t)
+	old := fake.addDomain("example.com")
+	fake.addDomain("example.org")
+	store := FileDomainCache{Path: filepath.Join(t.TempDir(), "domains.json")}
+	client = GetClient("key", "secret", client.BaseURL, WithDomainCacheStore(store, time.Hour))
+
+	_, err := client.IdForDomain("example.com")
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteDomain(old.ID))
+	saved, err := store.LoadDomainCache()
+	require.NoError(t, err)
+	assert.NotContains(t, saved.Domains, "example.com")
+	assert.Contains(t, saved.Domains, "example.org")
+
+	// recreating the domain resolves to its new ID
+	recreated, err := client.CreateDomain("example.com")
+	require.NoError(t, err)
+	require.NotEqual(t, old.ID, recreated.ID)
+	id, err := client.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, recreated.ID, id)
+
+	// a second process doesn't see the deleted ID either
+	second := GetClient("key", "secret", client.BaseURL, WithDomainCacheStore(store, time.Hour))
+	id, err = second.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, recreated.ID, id)
+}
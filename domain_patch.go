@@ -0,0 +1,98 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+)
+
+// A change to a domain's settings. Only non-nil fields are changed, so
+// a setting can be cleared by patching it to 0.
+type DomainPatch struct {
+	FolderID      *int  `json:"folderId,omitempty"`
+	VanityID      *int  `json:"vanityId,omitempty"`
+	TemplateID    *int  `json:"templateId,omitempty"`
+	TransferAclID *int  `json:"transferAclId,omitempty"`
+	SoaID         *int  `json:"soaId,omitempty"`
+	GtdEnabled    *bool `json:"gtdEnabled,omitempty"`
+}
+
+// Returns a copy of domain with the patch's fields applied
+func (p DomainPatch) Apply(domain Domain) Domain {
+	if p.FolderID != nil {
+		domain.FolderID = *p.FolderID
+	}
+	if p.VanityID != nil {
+		domain.VanityID = *p.VanityID
+	}
+	if p.TemplateID != nil {
+		domain.TemplateID = *p.TemplateID
+	}
+	if p.TransferAclID != nil {
+		domain.TransferAclID = *p.TransferAclID
+	}
+	if p.SoaID != nil {
+		domain.SoaID = *p.SoaID
+	}
+	if p.GtdEnabled != nil {
+		domain.GtdEnabled = *p.GtdEnabled
+	}
+	return domain
+}
+
+// Changes the settings set in patch on the domain, leaving the others
+// as they are
+func (s *DomainsService) UpdateSettings(ctx context.Context, domainID int, patch DomainPatch) error {
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetBody(&patch).
+		Put(fmt.Sprint(DNSManagedPath, domainID)))
+	return err
+}
+
+// Changes the settings set in patch on the supplied domain
+//
+// Equivalent to c.Domains().UpdateSettings(context.Background(), domainId, patch)
+func (c *Client) UpdateDomainSettings(domainId int, patch DomainPatch) error {
+	return c.Domains().UpdateSettings(context.Background(), domainId, patch)
+}
+
+// The number of domains updated per request by UpdateSettingsMulti,
+// unless WithBatchSize sets another
+const DefaultDomainBatchSize = 50
+
+// Applies the same settings to many domains, in batches paced by the
+// account's request quota. Returns the IDs of the domains updated; a
+// *BatchError reports those that weren't.
+func (s *DomainsService) UpdateSettingsMulti(ctx context.Context, domainIds []int, patch DomainPatch) ([]int, error) {
+	size := s.client.batchSize
+	if size <= 0 {
+		size = DefaultDomainBatchSize
+	}
+
+	updated := []int{}
+	err := chunkedBy(size, len(domainIds), func(start, end int) error {
+		s.client.throttle(1)
+		ids := domainIds[start:end]
+		body := struct {
+			IDs []int `json:"ids"`
+			DomainPatch
+		}{ids, patch}
+
+		_, err := checkRespForError(s.client.newRequest(ctx).
+			SetBody(&body).
+			Put(DNSManagedPath))
+		if err != nil {
+			return err
+		}
+		updated = append(updated, ids...)
+		return nil
+	})
+	return updated, err
+}
+
+// Applies the same settings to many domains, such as moving them all
+// to one folder
+//
+// Equivalent to c.Domains().UpdateSettingsMulti(context.Background(), domainIds, patch)
+func (c *Client) UpdateDomainsSettings(domainIds []int, patch DomainPatch) ([]int, error) {
+	return c.Domains().UpdateSettingsMulti(context.Background(), domainIds, patch)
+}
@@ -0,0 +1,44 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateDomainSettings(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	fake.domains[domain.ID].FolderID = 7
+	fake.domains[domain.ID].TemplateID = 3
+
+	err := client.UpdateDomainSettings(domain.ID, DomainPatch{FolderID: Int(9), TemplateID: Int(0), GtdEnabled: Bool(true)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"folderId":9,"templateId":0,"gtdEnabled":true}`, string(fake.body))
+
+	updated, err := client.GetDomain(domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 9, updated.FolderID)
+	assert.Equal(t, 0, updated.TemplateID)
+	assert.True(t, updated.GtdEnabled)
+
+	assert.Equal(t, Domain{FolderID: 1, SoaID: 2}, DomainPatch{FolderID: Int(1), SoaID: Int(2)}.Apply(Domain{FolderID: 5}))
+
+	assert.ErrorIs(t, client.UpdateDomainSettings(1, DomainPatch{}), ErrNotFound)
+}
+
+func TestUpdateDomainsSettings(t *testing.T) {
+	fake, client := newFakeDME(t)
+	a := fake.addDomain("a.com")
+	b := fake.addDomain("b.com")
+	fake.domains[b.ID].TemplateID = 4
+
+	updated, err := client.UpdateDomainsSettings([]int{a.ID, b.ID}, DomainPatch{FolderID: Int(12)})
+	require.NoError(t, err)
+	assert.Equal(t, []int{a.ID, b.ID}, updated)
+	assert.JSONEq(t, `{"ids":[`+itoa(a.ID)+`,`+itoa(b.ID)+`],"folderId":12}`, string(fake.body))
+	assert.Equal(t, 12, fake.domains[a.ID].FolderID)
+	assert.Equal(t, 12, fake.domains[b.ID].FolderID)
+	assert.Equal(t, 4, fake.domains[b.ID].TemplateID)
+}
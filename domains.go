@@ -0,0 +1,236 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type Domain struct {
+	ID                 int      `json:"id"`
+	Name               string   `json:"name"`
+	CreatedAt          int      `json:"created"`
+	UpdatedAt          int      `json:"updated"`
+	FolderID           int      `json:"folderId"`
+	ProcessMulti       bool     `json:"processMulti"`
+	ActiveThirdParties []string `json:"activeThirdParties"`
+	GtdEnabled         bool     `json:"gtdEnabled"`
+	VanityID           int      `json:"vanityId"`
+	TemplateID         int      `json:"templateId"`
+	TransferAclID      int      `json:"transferAclId"`
+	SoaID              int      `json:"soaId"`
+
+	// Identifies which action is currently pending
+	// Values:
+	//  0 - nothing pending
+	//  1 - pending creation (usable but not fully created throughout the system)
+	//  3 - pending deletion
+	PendingActionID int `json:"pendingActionId"`
+}
+
+type DomainsResp struct {
+	TotalRecords int      `json:"totalRecords"`
+	TotalPages   int      `json:"totalPages"`
+	Domains      []Domain `json:"data"`
+	CurrentPage  int      `json:"page"`
+}
+
+// Operations on the managed domains of the account
+type DomainsService struct {
+	client *Client
+}
+
+// Returns the service for managing domains
+func (c *Client) Domains() *DomainsService {
+	return &DomainsService{c}
+}
+
+// Creates a new domain
+//
+// NOTE: with WithPendingDeleteRetry, waits for a recently deleted domain
+// of the same name to be removed first
+func (s *DomainsService) Create(ctx context.Context, domainName string) (Domain, error) {
+	domain, err := s.create(ctx, domainName)
+	if s.client.pendingDeleteRetry && s.pendingDelete(ctx, domainName, err) {
+		return s.createAfterDelete(ctx, domainName)
+	}
+	return domain, err
+}
+
+func (s *DomainsService) create(ctx context.Context, domainName string) (Domain, error) {
+	defer s.client.forgetRead(domainsReadKey)
+	var newDomain Domain
+
+	createDomainBody := fmt.Sprintf(`{"name":"%s"}`, domainName)
+	req := s.client.newRequest(ctx).
+		SetResult(&newDomain).
+		SetBody(createDomainBody)
+
+	_, err := checkRespForError(req.Post(DNSManagedPath))
+	if err != nil {
+		return Domain{}, err
+	}
+	s.client.forgetMiss(domainName)
+
+	return newDomain, nil
+}
+
+// Removes a domain and all associated records
+func (s *DomainsService) Delete(ctx context.Context, domainID int) error {
+	defer s.client.InvalidateRecords(domainID)
+	defer s.client.forgetRead(domainsReadKey)
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		Delete(fmt.Sprint(DNSManagedPath, domainID)))
+	if err == nil || errors.Is(err, ErrNotFound) {
+		s.client.forgetDomain(domainID)
+	}
+	return s.client.deleted(err)
+}
+
+// Returns the domain record for a given domain ID
+func (s *DomainsService) Get(ctx context.Context, domainID int) (Domain, error) {
+	var domain Domain
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetResult(&domain).
+		Get(DNSManagedPath + fmt.Sprint(domainID)))
+	if err != nil {
+		// served from the domains snapshot when the API is down
+		domains, _ := s.client.offlineDomains(ctx, err)
+		for _, domain := range domains {
+			if domain.ID == domainID {
+				return domain, nil
+			}
+		}
+		return Domain{}, err
+	}
+	return domain, nil
+}
+
+// Returns all domains managed by the account
+func (s *DomainsService) List(ctx context.Context) ([]Domain, error) {
+	v, err := s.client.coalesce(ctx, domainsReadKey, func(ctx context.Context) (interface{}, error) {
+		var respDomains DomainsResp
+		err := s.client.getStreamed(s.client.newRequest(ctx), DNSManagedPath, &respDomains)
+		if err != nil {
+			return nil, err
+		}
+		s.client.saveDomainsSnapshot(respDomains.Domains)
+		return respDomains.Domains, nil
+	})
+	if err != nil {
+		return s.client.offlineDomains(ctx, err)
+	}
+	domains := v.([]Domain)
+	if domains == nil {
+		return nil, nil
+	}
+	return append([]Domain{}, domains...), nil
+}
+
+// Returns the domains with the supplied IDs, or every domain if there
+// are none
+func (c *Client) domainsOrAll(ctx context.Context, domainIDs []int) ([]Domain, error) {
+	if len(domainIDs) == 0 {
+		return c.Domains().List(ctx)
+	}
+	domains := make([]Domain, 0, len(domainIDs))
+	for _, id := range domainIDs {
+		domain, err := c.Domains().Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, nil
+}
+
+// Finds the numerical ID for a given domain name
+func (s *DomainsService) IdFor(ctx context.Context, domain string) (int, error) {
+	c := s.client
+	if c.recentMiss(domain) {
+		return 0, ErrDomainNotFound
+	}
+	justPopulated := false
+	domainMap := c.domainNames()
+	if domainMap == nil {
+		var err error
+		if domainMap, err = s.names(ctx); err != nil {
+			return 0, err
+		}
+		c.setDomainNames(domainMap)
+		justPopulated = true
+	}
+
+	zoneId, ok := domainMap[domain]
+	if ok {
+		return zoneId, nil
+	} else {
+		// if we didn't just populate the cache, refresh it in case
+		// our domain exists now
+		if !justPopulated {
+			var err error
+			if domainMap, err = s.names(ctx); err != nil {
+				return 0, err
+			}
+			c.setDomainNames(domainMap)
+			justPopulated = true
+		}
+		zoneId, ok := domainMap[domain]
+		if ok {
+			return zoneId, nil
+		}
+	}
+
+	c.recordMiss(domain)
+	return 0, ErrDomainNotFound
+}
+
+// Returns a map of Name:ID for all domains
+func (s *DomainsService) names(ctx context.Context) (map[string]int, error) {
+	domains := map[string]int{}
+
+	respDomains, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range respDomains {
+		domains[domain.Name] = domain.ID
+	}
+
+	return domains, nil
+}
+
+// Creates a new domain
+//
+// Equivalent to c.Domains().Create(context.Background(), domainName)
+func (c *Client) CreateDomain(domainName string) (Domain, error) {
+	return c.Domains().Create(context.Background(), domainName)
+}
+
+// Removes a domain and all associated records
+//
+// Equivalent to c.Domains().Delete(context.Background(), domainID)
+func (c *Client) DeleteDomain(domainID int) error {
+	return c.Domains().Delete(context.Background(), domainID)
+}
+
+// Returns the domain record for a given domain ID
+//
+// Equivalent to c.Domains().Get(context.Background(), domainID)
+func (c *Client) GetDomain(domainID int) (Domain, error) {
+	return c.Domains().Get(context.Background(), domainID)
+}
+
+// Returns a map of Name:ID for all domains managed by the
+// given account
+func (c *Client) EnumerateDomains() (map[string]int, error) {
+	return c.Domains().names(context.Background())
+}
+
+// Finds the numerical ID for a given domain name
+//
+// Equivalent to c.Domains().IdFor(context.Background(), domain)
+func (c *Client) IdForDomain(domain string) (int, error) {
+	return c.Domains().IdFor(context.Background(), domain)
+}
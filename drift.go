@@ -0,0 +1,100 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Polls zones and reports any change to their records as an
+// EventDriftDetected, for catching edits made outside of automation
+// such as in the web console
+type DriftWatcher struct {
+	Client    *Client
+	DomainIDs []int
+	Interval  time.Duration
+	Notifier  Notifier
+
+	// Receives errors from polling and notifying; they don't stop the
+	// watcher. Ignored if nil.
+	OnError func(error)
+
+	last map[int][]Record
+}
+
+// Takes a snapshot of the zones, then polls them every Interval until
+// ctx is done, notifying of each difference from the previous poll
+func (w *DriftWatcher) Run(ctx context.Context) error {
+	if w.last == nil {
+		w.last = map[int][]Record{}
+		if err := w.poll(ctx); err != nil {
+			return err
+		}
+	}
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.poll(ctx); err != nil && w.OnError != nil {
+				w.OnError(err)
+			}
+		}
+	}
+}
+
+// Fetches every zone, notifying of those that changed since the last
+// poll. Zones seen for the first time become the baseline.
+func (w *DriftWatcher) poll(ctx context.Context) error {
+	var errs []error
+	for _, domainID := range w.DomainIDs {
+		records, err := w.Client.Records(domainID).list(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		before, seen := w.last[domainID]
+		w.last[domainID] = records
+		if !seen || RecordsFingerprint(before) == RecordsFingerprint(records) {
+			continue
+		}
+
+		event := Event{Kind: EventDriftDetected, Time: time.Now(), DomainID: domainID}
+		event.Created, event.Updated, event.Deleted = diffByID(before, records)
+		if domain, err := w.Client.Domains().Get(ctx, domainID); err == nil {
+			event.Domain = domain.Name
+		}
+		if err := w.Notifier.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Compares two snapshots of a zone by record ID, returning the records
+// only in after, those in both that differ (as in after), and those only
+// in before
+func diffByID(before, after []Record) (created, updated, deleted []Record) {
+	old := make(map[int]Record, len(before))
+	for _, record := range before {
+		old[record.ID] = record
+	}
+	for _, record := range after {
+		prev, ok := old[record.ID]
+		switch {
+		case !ok:
+			created = append(created, record)
+		case prev != record:
+			updated = append(updated, record)
+		}
+		delete(old, record.ID)
+	}
+	for _, record := range old {
+		deleted = append(deleted, record)
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].ID < deleted[j].ID })
+	return created, updated, deleted
+}
@@ -0,0 +1,55 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftWatcherPoll(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "old", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	)
+	records := fake.recordList(domain.ID)
+
+	var events []Event
+	w := &DriftWatcher{
+		Client:    client,
+		DomainIDs: []int{domain.ID},
+		Notifier: NotifierFunc(func(ctx context.Context, e Event) error {
+			events = append(events, e)
+			return nil
+		}),
+		last: map[int][]Record{},
+	}
+	ctx := context.Background()
+
+	// the first poll is the baseline
+	require.NoError(t, w.poll(ctx))
+	require.NoError(t, w.poll(ctx))
+	assert.Empty(t, events)
+
+	fake.mu.Lock()
+	changed := records[0]
+	changed.Value = "192.0.2.9"
+	fake.records[domain.ID][changed.ID] = changed
+	delete(fake.records[domain.ID], records[1].ID)
+	fake.mu.Unlock()
+	added, err := client.Records(domain.ID).Create(ctx, Record{Name: "new", Type: "A", Value: "192.0.2.3", Ttl: 300})
+	require.NoError(t, err)
+
+	require.NoError(t, w.poll(ctx))
+	require.Len(t, events, 1)
+	assert.Equal(t, EventDriftDetected, events[0].Kind)
+	assert.Equal(t, "example.com", events[0].Domain)
+	assert.Equal(t, []Record{added}, events[0].Created)
+	assert.Equal(t, []Record{changed}, events[0].Updated)
+	assert.Equal(t, []Record{records[1]}, events[0].Deleted)
+
+	require.NoError(t, w.poll(ctx))
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,223 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The file format zones are exported in
+type ExportFormat string
+
+const (
+	// A JSON document holding the domain and its records
+	ExportJSON ExportFormat = "json"
+
+	// An RFC 1035 zone file. Records with no standard equivalent, such
+	// as ANAME, are written as comments.
+	ExportZoneFile ExportFormat = "zone"
+)
+
+// The name of the checkpoint file an export keeps in its directory
+const ExportCheckpointFile = ".dme-export-checkpoint.json"
+
+type ExportOptions struct {
+	// The directory to write one file per domain into
+	Dir string
+
+	// Defaults to ExportJSON
+	Format ExportFormat
+
+	// Domains exported at once. Defaults to 4.
+	Concurrency int
+}
+
+// The outcome of an export
+type ExportResult struct {
+	// The domains written by this run, and those skipped because an
+	// interrupted earlier run had already written them
+	Exported []string
+	Skipped  []string
+
+	// The domains that could not be exported, by name
+	Failed map[string]error
+}
+
+// An exported JSON document
+type ZoneExport struct {
+	Domain  Domain   `json:"domain"`
+	Records []Record `json:"records"`
+}
+
+type exportCheckpoint struct {
+	Completed map[string]time.Time `json:"completed"`
+}
+
+// Writes every domain in the account to its own file in opts.Dir,
+// several at a time. Progress is checkpointed, so an export interrupted
+// by cancellation, failures or exhausting the request quota picks up
+// where it left off when run again with the same directory. The
+// checkpoint is removed once every domain has been written.
+func (c *Client) ExportAll(ctx context.Context, opts ExportOptions) (ExportResult, error) {
+	if opts.Format == "" {
+		opts.Format = ExportJSON
+	}
+	if opts.Format != ExportJSON && opts.Format != ExportZoneFile {
+		return ExportResult{}, fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 4
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return ExportResult{}, err
+	}
+
+	checkpointPath := filepath.Join(opts.Dir, ExportCheckpointFile)
+	checkpoint := exportCheckpoint{Completed: map[string]time.Time{}}
+	if data, err := os.ReadFile(checkpointPath); err == nil {
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return ExportResult{}, fmt.Errorf("reading checkpoint: %w", err)
+		}
+		if checkpoint.Completed == nil {
+			checkpoint.Completed = map[string]time.Time{}
+		}
+	}
+
+	domains, err := c.Domains().List(ctx)
+	if err != nil {
+		return ExportResult{}, err
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Name < domains[j].Name })
+
+	result := ExportResult{Failed: map[string]error{}}
+	var pending []int
+	byID := map[int]Domain{}
+	for _, domain := range domains {
+		if _, done := checkpoint.Completed[domain.Name]; done {
+			result.Skipped = append(result.Skipped, domain.Name)
+		} else {
+			pending = append(pending, domain.ID)
+			byID[domain.ID] = domain
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		stopped error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.eachDomain(ctx, pending, opts.Concurrency, func(domainID int) {
+		domain := byID[domainID]
+		err := c.exportDomain(ctx, domain, opts)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			result.Exported = append(result.Exported, domain.Name)
+			checkpoint.Completed[domain.Name] = time.Now()
+			if saveErr := writeJSONFile(checkpointPath, checkpoint); saveErr != nil && stopped == nil {
+				stopped = fmt.Errorf("saving checkpoint: %w", saveErr)
+				cancel()
+			}
+		case ctx.Err() != nil:
+			// cancelled; left for the next run
+		default:
+			result.Failed[domain.Name] = err
+			if isRateLimited(err) && stopped == nil {
+				stopped = fmt.Errorf("%w: export stopped, run again to resume", ErrRateLimited)
+				cancel()
+			}
+		}
+	})
+
+	sort.Strings(result.Exported)
+	if stopped != nil {
+		return result, stopped
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	if len(result.Failed) > 0 {
+		var errs []error
+		for name, err := range result.Failed {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return result, errors.Join(errs...)
+	}
+	os.Remove(checkpointPath)
+	return result, nil
+}
+
+func (c *Client) exportDomain(ctx context.Context, domain Domain, opts ExportOptions) error {
+	records, err := c.Records(domain.ID).List(ctx)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].Type < records[j].Type
+	})
+
+	path := filepath.Join(opts.Dir, domain.Name+"."+string(opts.Format))
+	if opts.Format == ExportJSON {
+		return writeJSONFile(path, ZoneExport{domain, records})
+	}
+	return writeFileAtomic(path, []byte(ZoneFile(domain.Name, records)))
+}
+
+// Renders records as an RFC 1035 zone file for zone. Records with no
+// standard equivalent are written as comments.
+func ZoneFile(zone string, records []Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s\n", AbsoluteName("", zone))
+	for _, record := range records {
+		rr, err := record.RR(zone)
+		if err != nil {
+			fmt.Fprintf(&b, "; %s\t%d\t%s\t%s\n", AbsoluteName(record.Name, zone), record.Ttl, record.Type, record.Value)
+			continue
+		}
+		fmt.Fprintln(&b, rr.String())
+	}
+	return b.String()
+}
+
+// Reports whether err is DNS Made Easy refusing a request for exceeding
+// the quota
+func isRateLimited(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return errors.Is(err, ErrRateLimited) || strings.Contains(strings.ToLower(fmt.Sprint(err)), "rate limit")
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Writes through a temporary file, so an interrupted write never
+// leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,74 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAll(t *testing.T) {
+	fake, client := newFakeDME(t)
+	a := fake.addDomain("a.com", Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	fake.addDomain("b.com", Record{Name: "", Type: "ANAME", Value: "lb.example.net.", Ttl: 300})
+	c := fake.addDomain("c.com")
+	dir := t.TempDir()
+
+	// quota runs out while exporting c.com
+	fake.fail = func(r *http.Request) bool {
+		return strings.Contains(r.URL.Path, "/"+itoa(c.ID)+"/records")
+	}
+	fake.failStatus = http.StatusTooManyRequests
+	fake.failMessage = "Rate limit exceeded"
+
+	opts := ExportOptions{Dir: dir, Concurrency: 1}
+	result, err := client.ExportAll(context.Background(), opts)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, []string{"a.com", "b.com"}, result.Exported)
+	assert.Contains(t, result.Failed, "c.com")
+	assert.FileExists(t, filepath.Join(dir, ExportCheckpointFile))
+
+	var export ZoneExport
+	data, err := os.ReadFile(filepath.Join(dir, "a.com.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &export))
+	assert.Equal(t, a.ID, export.Domain.ID)
+	assert.Len(t, export.Records, 1)
+
+	// resuming only exports what is left
+	fake.fail = nil
+	result, err = client.ExportAll(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c.com"}, result.Exported)
+	assert.Equal(t, []string{"a.com", "b.com"}, result.Skipped)
+	assert.FileExists(t, filepath.Join(dir, "c.com.json"))
+	assert.NoFileExists(t, filepath.Join(dir, ExportCheckpointFile))
+}
+
+func TestExportZoneFile(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "", Type: "ANAME", Value: "lb.example.net.", Ttl: 300},
+	)
+	dir := t.TempDir()
+
+	result, err := client.ExportAll(context.Background(), ExportOptions{Dir: dir, Format: ExportZoneFile})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com"}, result.Exported)
+
+	data, err := os.ReadFile(filepath.Join(dir, "example.com.zone"))
+	require.NoError(t, err)
+	assert.Equal(t, "$ORIGIN example.com.\n"+
+		"; example.com.\t300\tANAME\tlb.example.net.\n"+
+		"www.example.com.\t300\tIN\tA\t192.0.2.1\n", string(data))
+
+	_, err = client.ExportAll(context.Background(), ExportOptions{Dir: dir, Format: "xml"})
+	assert.EqualError(t, err, `unknown export format "xml"`)
+}
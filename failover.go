@@ -0,0 +1,85 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// The most failover addresses a monitor supports
+const MaxFailoverIPs = 5
+
+// Returns the failover addresses in order of preference, primary first
+func (m Monitor) FailoverIPs() []string {
+	var ips []string
+	for _, ip := range []string{m.IP1, m.IP2, m.IP3, m.IP4, m.IP5} {
+		if ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// Replaces the failover addresses with ips, in order of preference
+func (m *Monitor) SetFailoverIPs(ips []string) error {
+	if len(ips) > MaxFailoverIPs {
+		return fmt.Errorf("at most %d failover IPs are supported, got %d", MaxFailoverIPs, len(ips))
+	}
+	seen := map[string]bool{}
+	for _, ip := range ips {
+		if net.ParseIP(ip).To4() == nil {
+			return fmt.Errorf("%q is not an IPv4 address", ip)
+		}
+		if seen[ip] {
+			return fmt.Errorf("%s is listed more than once", ip)
+		}
+		seen[ip] = true
+	}
+
+	slots := []*string{&m.IP1, &m.IP2, &m.IP3, &m.IP4, &m.IP5}
+	for idx, slot := range slots {
+		*slot = ""
+		if idx < len(ips) {
+			*slot = ips[idx]
+		}
+	}
+	return nil
+}
+
+// Sets the failover chain of a monitored A record, keeping the rest of
+// its monitor configuration. The first address must be the record's
+// current value, since DNS Made Easy serves ip1 as the primary.
+func (s *MonitorsService) SetFailoverIPs(ctx context.Context, domainID int, recordID int, ips []string) (Monitor, error) {
+	records, err := s.client.Records(domainID).list(ctx)
+	if err != nil {
+		return Monitor{}, err
+	}
+	var record *Record
+	for idx := range records {
+		if records[idx].ID == recordID {
+			record = &records[idx]
+			break
+		}
+	}
+	if record == nil {
+		return Monitor{}, fmt.Errorf("record %d in domain %d: %w", recordID, domainID, ErrNotFound)
+	}
+	if record.Type != "A" {
+		return Monitor{}, fmt.Errorf("failover is only supported for A records, %s is %s", record.Name, record.Type)
+	}
+	if len(ips) == 0 || ips[0] != record.Value {
+		return Monitor{}, fmt.Errorf("the first failover IP must be the record's value %s", record.Value)
+	}
+
+	monitor, err := s.Get(ctx, recordID)
+	if err != nil {
+		return Monitor{}, err
+	}
+	if err := monitor.SetFailoverIPs(ips); err != nil {
+		return Monitor{}, err
+	}
+	if err := s.Update(ctx, recordID, monitor); err != nil {
+		return Monitor{}, err
+	}
+	return monitor, nil
+}
@@ -0,0 +1,56 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The monitoring state of one record
+type FailoverStatus struct {
+	DomainID int
+	Record   Record
+	Monitor  Monitor
+
+	// The record's primary IP is failing checks and DNS Made Easy is
+	// serving a failover address
+	FailedOver bool
+}
+
+// Returns the status of every monitored or failover-enabled record in
+// the supplied domains, ordered by domain ID then record name, for
+// status pages and alerting. Failed over records can be picked out with
+// the FailedOver field.
+func (c *Client) FailoverStatuses(ctx context.Context, domainIDs []int) ([]FailoverStatus, error) {
+	zones, fetchErr := c.FetchAllRecordsContext(ctx, domainIDs, 4)
+
+	var (
+		statuses []FailoverStatus
+		errs     []error
+	)
+	if fetchErr != nil {
+		errs = append(errs, fetchErr)
+	}
+	for domainID, records := range zones {
+		for _, record := range records {
+			if !record.Monitor && !record.Failover {
+				continue
+			}
+			monitor, err := c.Monitors().Get(ctx, record.ID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("monitor for %s (record %d): %w", record.Name, record.ID, err))
+				continue
+			}
+			statuses = append(statuses, FailoverStatus{domainID, record, monitor, record.Failed})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].DomainID != statuses[j].DomainID {
+			return statuses[i].DomainID < statuses[j].DomainID
+		}
+		return statuses[i].Record.Name < statuses[j].Record.Name
+	})
+	return statuses, errors.Join(errs...)
+}
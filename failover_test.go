@@ -0,0 +1,80 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorFailoverIPs(t *testing.T) {
+	var m Monitor
+	require.NoError(t, m.SetFailoverIPs([]string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}))
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}, m.FailoverIPs())
+
+	// reordering clears slots that are no longer used
+	require.NoError(t, m.SetFailoverIPs([]string{"192.0.2.3", "192.0.2.1"}))
+	assert.Equal(t, []string{"192.0.2.3", "192.0.2.1"}, m.FailoverIPs())
+	assert.Empty(t, m.IP3)
+
+	assert.Error(t, m.SetFailoverIPs([]string{"192.0.2.1", "192.0.2.1"}))
+	assert.Error(t, m.SetFailoverIPs([]string{"2001:db8::1"}))
+	assert.Error(t, m.SetFailoverIPs(make([]string, 6)))
+}
+
+func TestSetFailoverIPs(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1"},
+		Record{Name: "alias", Type: "CNAME", Value: "www"})
+	records := fake.recordList(domain.ID)
+	ctx := context.Background()
+
+	require.NoError(t, client.Monitors().Update(ctx, records[0].ID, Monitor{
+		Monitor: true, Failover: true, ProtocolID: ProtocolHTTP, Sensitivity: SensitivityLow,
+		IP1: "192.0.2.1", IP2: "192.0.2.2"}))
+
+	monitor, err := client.Monitors().SetFailoverIPs(ctx, domain.ID, records[0].ID,
+		[]string{"192.0.2.1", "192.0.2.3", "192.0.2.2"})
+	require.NoError(t, err)
+	assert.Equal(t, SensitivityLow, monitor.Sensitivity)
+
+	live, err := client.Monitors().Get(ctx, records[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.3", "192.0.2.2"}, live.FailoverIPs())
+
+	_, err = client.Monitors().SetFailoverIPs(ctx, domain.ID, records[0].ID, []string{"192.0.2.2", "192.0.2.1"})
+	assert.ErrorContains(t, err, "must be the record's value")
+	_, err = client.Monitors().SetFailoverIPs(ctx, domain.ID, records[1].ID, []string{"192.0.2.1"})
+	assert.ErrorContains(t, err, "only supported for A records")
+	_, err = client.Monitors().SetFailoverIPs(ctx, domain.ID, 1, []string{"192.0.2.1"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFailoverStatuses(t *testing.T) {
+	fake, client := newFakeDME(t)
+	a := fake.addDomain("a.example",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Monitor: true, Failover: true, Failed: true},
+		Record{Name: "static", Type: "A", Value: "192.0.2.9"})
+	b := fake.addDomain("b.example",
+		Record{Name: "api", Type: "A", Value: "192.0.2.5", Monitor: true})
+
+	statuses, err := client.FailoverStatuses(context.Background(), []int{b.ID, a.ID})
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "www", statuses[0].Record.Name)
+	assert.True(t, statuses[0].FailedOver)
+	assert.Equal(t, "api", statuses[1].Record.Name)
+	assert.False(t, statuses[1].FailedOver)
+	assert.Equal(t, statuses[1].Record.ID, statuses[1].Monitor.RecordID)
+
+	// a done context stops the fetch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	served := fake.served
+	statuses, err = client.FailoverStatuses(ctx, []int{b.ID, a.ID})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, statuses)
+	assert.Equal(t, served, fake.served)
+}
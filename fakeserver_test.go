@@ -0,0 +1,498 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// An in-memory stand-in for the DNS Made Easy API, implementing just
+// enough of the managed DNS endpoints for unit tests
+type fakeDME struct {
+	mu       sync.Mutex
+	nextID   int
+	domains  map[int]*Domain
+	records  map[int]map[int]Record
+	monitors map[int]Monitor
+	folders  []Folder
+
+	// query usage by "year/month"
+	usage map[string][]QueryUsage
+
+	// when set, requests for which fail returns true are rejected with
+	// a DME style error body
+	fail        func(r *http.Request) bool
+	failMessage string
+	failStatus  int
+
+	// merged into every record returned by listRecords, to simulate
+	// fields added to the API
+	extraRecordFields map[string]interface{}
+
+	// number of requests served, by "METHOD path"
+	calls  map[string]int
+	served int
+
+	// the API key that signed the most recent request, its headers and
+	// its body
+	apiKey  string
+	headers http.Header
+	body    []byte
+
+	// names of deleted domains that can't be created again until they
+	// have been rejected this many more times
+	pendingDeletes map[string]int
+
+	// when limit is set, responses carry rate limit headers and
+	// remaining counts down with each request
+	limit, remaining int
+}
+
+func newFakeDME(t testing.TB) (*fakeDME, *Client) {
+	f := &fakeDME{
+		nextID:   1000,
+		domains:  map[int]*Domain{},
+		records:  map[int]map[int]Record{},
+		monitors: map[int]Monitor{},
+		calls:    map[string]int{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dns/managed/{$}", f.listDomains)
+	mux.HandleFunc("POST /dns/managed/{$}", f.createDomain)
+	mux.HandleFunc("PUT /dns/managed/{$}", f.updateDomains)
+	mux.HandleFunc("GET /dns/managed/{domainId}", f.getDomain)
+	mux.HandleFunc("PUT /dns/managed/{domainId}", f.updateDomain)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}", f.deleteDomain)
+	mux.HandleFunc("GET /dns/managed/{domainId}/records", f.listRecords)
+	mux.HandleFunc("POST /dns/managed/{domainId}/records", f.createRecord)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records", f.deleteAllRecords)
+	mux.HandleFunc("POST /dns/managed/{domainId}/records/createMulti", f.createRecords)
+	mux.HandleFunc("PUT /dns/managed/{domainId}/records/updateMulti", f.updateRecords)
+	mux.HandleFunc("POST /dns/managed/{domainId}/records/updateMulti", f.updateRecords)
+	mux.HandleFunc("PUT /dns/managed/{domainId}/records/{recordId}", f.updateRecord)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records/{recordId}", f.deleteRecord)
+	mux.HandleFunc("DELETE /dns/managed/{domainId}/records/{$}", f.deleteRecords)
+	mux.HandleFunc("GET /security/folder", f.listFolders)
+	mux.HandleFunc("GET /monitor/{recordId}", f.getMonitor)
+	mux.HandleFunc("PUT /monitor/{recordId}", f.updateMonitor)
+	mux.HandleFunc("GET /usageApi/queriesApi/{year}/{month}", f.getUsage)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.calls[r.Method+" "+strings.TrimPrefix(r.URL.Path, "/"+string(DefaultAPIVersion))]++
+		f.served++
+		f.apiKey = r.Header.Get("X-Dnsme-Apikey")
+		f.headers = r.Header.Clone()
+		f.body, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(f.body))
+		w.Header().Set(RequestIDHeader, fmt.Sprint("req-", f.served))
+		if f.limit > 0 {
+			if f.remaining > 0 {
+				f.remaining--
+			}
+			w.Header().Set(RequestLimitHeader, fmt.Sprint(f.limit))
+			w.Header().Set(RequestsRemainingHeader, fmt.Sprint(f.remaining))
+		}
+		if f.fail != nil && f.fail(r) {
+			msg := f.failMessage
+			if msg == "" {
+				msg = "injected failure"
+			}
+			status := f.failStatus
+			if status == 0 {
+				status = http.StatusBadRequest
+			}
+			writeError(w, status, msg)
+			return
+		}
+		http.StripPrefix("/"+string(DefaultAPIVersion), mux).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return f, GetClient("key", "secret", BaseURL(server.URL+"/"+string(DefaultAPIVersion)+"/"))
+}
+
+// Adds a domain and its records directly to the fake's state
+func (f *fakeDME) addDomain(name string, records ...Record) Domain {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	domain := &Domain{ID: f.nextID, Name: name}
+	f.domains[domain.ID] = domain
+	f.records[domain.ID] = map[int]Record{}
+	for _, record := range records {
+		f.nextID++
+		record.ID = f.nextID
+		record.SourceId = domain.ID
+		f.records[domain.ID][record.ID] = record
+	}
+	return *domain
+}
+
+// Returns the records of a domain sorted by ID
+func (f *fakeDME) recordList(domainId int) []Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sortedRecords(domainId)
+}
+
+func (f *fakeDME) sortedRecords(domainId int) []Record {
+	records := []Record{}
+	for _, record := range f.records[domainId] {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+func itoa(id int) string {
+	return strconv.Itoa(id)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string][]string{"error": {msg}})
+}
+
+func (f *fakeDME) domainFor(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, _ := strconv.Atoi(r.PathValue("domainId"))
+	if _, ok := f.domains[id]; !ok {
+		writeError(w, http.StatusNotFound, "Domain not found")
+		return 0, false
+	}
+	return id, true
+}
+
+func (f *fakeDME) listDomains(w http.ResponseWriter, r *http.Request) {
+	var domains []Domain
+	for _, domain := range f.domains {
+		domains = append(domains, *domain)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].ID < domains[j].ID })
+	writeJSON(w, http.StatusOK, DomainsResp{
+		TotalRecords: len(domains), TotalPages: 1, Domains: domains, CurrentPage: 1})
+}
+
+func (f *fakeDME) createDomain(w http.ResponseWriter, r *http.Request) {
+	var domain Domain
+	json.NewDecoder(r.Body).Decode(&domain)
+	if f.pendingDeletes[domain.Name] > 0 {
+		f.pendingDeletes[domain.Name]--
+		writeError(w, http.StatusBadRequest, "Domain already exists")
+		return
+	}
+	for _, existing := range f.domains {
+		if existing.Name == domain.Name {
+			writeError(w, http.StatusBadRequest, "Domain already exists")
+			return
+		}
+	}
+	f.nextID++
+	domain.ID = f.nextID
+	f.domains[domain.ID] = &domain
+	f.records[domain.ID] = map[int]Record{}
+	writeJSON(w, http.StatusCreated, domain)
+}
+
+func (f *fakeDME) updateDomains(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		IDs []int `json:"ids"`
+	}
+	json.Unmarshal(f.body, &body)
+	for _, id := range body.IDs {
+		if _, ok := f.domains[id]; !ok {
+			writeError(w, http.StatusNotFound, "Domain not found")
+			return
+		}
+	}
+	for _, id := range body.IDs {
+		json.Unmarshal(f.body, f.domains[id])
+		f.domains[id].ID = id
+	}
+}
+
+func (f *fakeDME) getDomain(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		writeJSON(w, http.StatusOK, f.domains[id])
+	}
+}
+
+func (f *fakeDME) updateDomain(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	// decoding over the existing domain only changes the fields sent
+	json.NewDecoder(r.Body).Decode(f.domains[id])
+	f.domains[id].ID = id
+}
+
+func (f *fakeDME) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		delete(f.domains, id)
+		delete(f.records, id)
+	}
+}
+
+func (f *fakeDME) listRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	records, total, pages, page := queryRecords(f.sortedRecords(id), r.URL.Query())
+	if f.extraRecordFields != nil {
+		var data []map[string]interface{}
+		for _, record := range records {
+			var fields map[string]interface{}
+			b, _ := json.Marshal(record)
+			json.Unmarshal(b, &fields)
+			for k, v := range f.extraRecordFields {
+				fields[k] = v
+			}
+			data = append(data, fields)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"totalRecords": total, "totalPages": pages, "data": data, "page": page})
+		return
+	}
+	writeJSON(w, http.StatusOK, RecordsResp{
+		TotalRecords: total, TotalPages: pages, Records: records, CurrentPage: page})
+}
+
+// Applies the record listing's filter, sort and paging parameters
+func queryRecords(records []Record, query url.Values) (page []Record, total, pages, current int) {
+	filtered := []Record{}
+	for _, record := range records {
+		if t := query.Get("type"); t != "" && record.Type != t {
+			continue
+		}
+		if name := query.Get("recordName"); name != "" && record.Name != name {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+
+	if field := query.Get("sort"); field != "" {
+		key := func(r Record) string {
+			switch field {
+			case "name":
+				return r.Name
+			case "type":
+				return r.Type
+			case "value":
+				return r.Value
+			case "ttl":
+				return fmt.Sprintf("%010d", r.Ttl)
+			}
+			return fmt.Sprintf("%010d", r.ID)
+		}
+		desc := query.Get("direction") == "DESC"
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if desc {
+				return key(filtered[i]) > key(filtered[j])
+			}
+			return key(filtered[i]) < key(filtered[j])
+		})
+	}
+
+	total = len(filtered)
+	rows, _ := strconv.Atoi(query.Get("rows"))
+	if rows <= 0 {
+		return filtered, total, 1, 1
+	}
+	current, _ = strconv.Atoi(query.Get("page"))
+	current = max(current, 1)
+	pages = (total + rows - 1) / rows
+	start := min((current-1)*rows, total)
+	return filtered[start:min(start+rows, total)], total, pages, current
+}
+
+func (f *fakeDME) insert(domainId int, record Record) (Record, error) {
+	if record.Name == "invalid" {
+		return Record{}, fmt.Errorf("Record name invalid is not allowed")
+	}
+	f.nextID++
+	record.ID = f.nextID
+	record.SourceId = domainId
+	f.records[domainId][record.ID] = record
+	return record, nil
+}
+
+func (f *fakeDME) createRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	var record Record
+	json.NewDecoder(r.Body).Decode(&record)
+	created, err := f.insert(id, record)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (f *fakeDME) createRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	var records []Record
+	json.NewDecoder(r.Body).Decode(&records)
+	for _, record := range records {
+		if record.Name == "invalid" {
+			writeError(w, http.StatusBadRequest, "Record name invalid is not allowed")
+			return
+		}
+	}
+	var created []Record
+	for _, record := range records {
+		record, _ = f.insert(id, record)
+		created = append(created, record)
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (f *fakeDME) updateRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	recordId, _ := strconv.Atoi(r.PathValue("recordId"))
+	if _, ok := f.records[id][recordId]; !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	var record Record
+	json.NewDecoder(r.Body).Decode(&record)
+	if record.Name == "invalid" {
+		writeError(w, http.StatusBadRequest, "Record name invalid is not allowed")
+		return
+	}
+	record.ID = recordId
+	record.SourceId = id
+	f.records[id][recordId] = record
+}
+
+func (f *fakeDME) updateRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	var records []Record
+	json.NewDecoder(r.Body).Decode(&records)
+	for _, record := range records {
+		if _, ok := f.records[id][record.ID]; !ok {
+			writeError(w, http.StatusNotFound, "Record not found")
+			return
+		}
+	}
+	for _, record := range records {
+		record.SourceId = id
+		f.records[id][record.ID] = record
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (f *fakeDME) deleteRecord(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	recordId, _ := strconv.Atoi(r.PathValue("recordId"))
+	if _, ok := f.records[id][recordId]; !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	delete(f.records[id], recordId)
+}
+
+func (f *fakeDME) deleteRecords(w http.ResponseWriter, r *http.Request) {
+	id, ok := f.domainFor(w, r)
+	if !ok {
+		return
+	}
+	for _, value := range r.URL.Query()["ids"] {
+		recordId, _ := strconv.Atoi(value)
+		delete(f.records[id], recordId)
+	}
+}
+
+func (f *fakeDME) deleteAllRecords(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.domainFor(w, r); ok {
+		f.records[id] = map[int]Record{}
+	}
+}
+
+// Returns the domain containing the supplied record
+func (f *fakeDME) domainOfRecord(recordId int) (int, bool) {
+	for domainId, records := range f.records {
+		if _, ok := records[recordId]; ok {
+			return domainId, true
+		}
+	}
+	return 0, false
+}
+
+func (f *fakeDME) getMonitor(w http.ResponseWriter, r *http.Request) {
+	recordId, _ := strconv.Atoi(r.PathValue("recordId"))
+	domainId, ok := f.domainOfRecord(recordId)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	monitor, ok := f.monitors[recordId]
+	if !ok {
+		monitor = Monitor{RecordID: recordId, SourceID: domainId}
+	}
+	writeJSON(w, http.StatusOK, monitor)
+}
+
+func (f *fakeDME) updateMonitor(w http.ResponseWriter, r *http.Request) {
+	recordId, _ := strconv.Atoi(r.PathValue("recordId"))
+	domainId, ok := f.domainOfRecord(recordId)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Record not found")
+		return
+	}
+	var monitor Monitor
+	json.NewDecoder(r.Body).Decode(&monitor)
+	monitor.RecordID = recordId
+	monitor.SourceID = domainId
+	f.monitors[recordId] = monitor
+
+	record := f.records[domainId][recordId]
+	record.Monitor = monitor.Monitor
+	record.Failover = monitor.Failover
+	f.records[domainId][recordId] = record
+}
+
+func (f *fakeDME) listFolders(w http.ResponseWriter, r *http.Request) {
+	folders := f.folders
+	if folders == nil {
+		folders = []Folder{{ID: 1, Name: "Default"}}
+	}
+	writeJSON(w, http.StatusOK, folders)
+}
+
+func (f *fakeDME) getUsage(w http.ResponseWriter, r *http.Request) {
+	usage := f.usage[r.PathValue("year")+"/"+r.PathValue("month")]
+	writeJSON(w, http.StatusOK, QueryUsageResp{Usage: usage, TotalRecords: len(usage), TotalPages: 1, CurrentPage: 1})
+}
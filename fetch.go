@@ -0,0 +1,85 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Enumerates the records of many domains in parallel using at most
+// concurrency simultaneous requests, returning a map of domain ID to
+// records
+//
+// Workers slow down once the account's remaining request quota drops to
+// the number of workers. Domains that could not be fetched are missing
+// from the map and their errors are joined into the returned error.
+//
+// Equivalent to c.FetchAllRecordsContext(context.Background(), domainIds, concurrency)
+func (c *Client) FetchAllRecords(domainIds []int, concurrency int) (map[int][]Record, error) {
+	return c.FetchAllRecordsContext(context.Background(), domainIds, concurrency)
+}
+
+// Enumerates the records of many domains in parallel as FetchAllRecords
+// does. Once ctx is done no more domains are fetched and its error is
+// joined into the returned error.
+func (c *Client) FetchAllRecordsContext(ctx context.Context, domainIds []int, concurrency int) (map[int][]Record, error) {
+	var (
+		mu      sync.Mutex
+		results = make(map[int][]Record, len(domainIds))
+		errs    []error
+	)
+	c.eachDomain(ctx, domainIds, concurrency, func(domainId int) {
+		records, err := c.Records(domainId).List(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainId, err))
+		} else {
+			results[domainId] = records
+		}
+	})
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Calls fn for each of the domains from concurrency workers, which slow
+// down once the account's remaining request quota drops to their
+// number. No more domains are handed out once ctx is done. Calls to fn
+// run concurrently, so it must guard what it shares.
+func (c *Client) eachDomain(ctx context.Context, domainIds []int, concurrency int, fn func(domainId int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	ids := make(chan int)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for domainId := range ids {
+				c.throttle(concurrency)
+				fn(domainId)
+			}
+		}()
+	}
+
+dispatch:
+	for _, domainId := range domainIds {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case ids <- domainId:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(ids)
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchAllRecords(t *testing.T) {
+	fake, client := newFakeDME(t)
+	var ids []int
+	for _, name := range []string{"a.example", "b.example", "c.example"} {
+		domain := fake.addDomain(name,
+			Record{Name: "www", Type: "A", Value: "192.0.2.1"},
+			Record{Name: "mail", Type: "A", Value: "192.0.2.2"})
+		ids = append(ids, domain.ID)
+	}
+
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+	fake.limit, fake.remaining = 150, 3
+
+	results, err := client.FetchAllRecords(append(ids, 1), 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "domain 1:")
+	assert.Len(t, results, 3)
+	for _, id := range ids {
+		assert.Len(t, results[id], 2)
+	}
+
+	// the quota reaches the worker count after the second request
+	assert.Len(t, slept, 2)
+	assert.Equal(t, 2*time.Second, slept[0])
+	assert.Equal(t, RateLimit{150, 0, client.RateLimit().Updated}, client.RateLimit())
+}
@@ -0,0 +1,102 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Computes a stable hash over a set of records. The order of the
+// records does not affect the result.
+func RecordsFingerprint(records []Record) string {
+	encoded := make([]string, 0, len(records))
+	for _, record := range records {
+		// notes aren't part of the zone
+		record.Note = ""
+		// marshalling a struct is deterministic, so the JSON form
+		// doubles as a canonical representation
+		b, _ := json.Marshal(record)
+		encoded = append(encoded, string(b))
+	}
+	sort.Strings(encoded)
+
+	h := sha256.New()
+	for _, e := range encoded {
+		h.Write([]byte(e))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// The validators DNS Made Easy returned for a zone's records
+type zoneValidator struct {
+	etag         string
+	lastModified string
+	fingerprint  string
+}
+
+type validatorStore struct {
+	mu         sync.Mutex
+	validators map[int]zoneValidator
+}
+
+func (s *validatorStore) get(domainId int) (zoneValidator, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.validators[domainId]
+	return v, ok
+}
+
+func (s *validatorStore) set(domainId int, v zoneValidator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.validators == nil {
+		s.validators = map[int]zoneValidator{}
+	}
+	s.validators[domainId] = v
+}
+
+// Returns a stable hash of the records in the supplied domain, suitable
+// for cheaply detecting whether a zone changed between polls
+//
+// If the API returned an ETag or Last-Modified header for an earlier
+// call, the request is made conditional and an unchanged zone is not
+// downloaded again.
+func (c *Client) ZoneFingerprint(domainId int) (string, error) {
+	var respRecords RecordsResp
+	req := c.newRequest(context.Background()).
+		SetResult(&respRecords).
+		SetPathParam("domainId", fmt.Sprint(domainId))
+
+	previous, known := c.validators.get(domainId)
+	if known {
+		if previous.etag != "" {
+			req.SetHeader("If-None-Match", previous.etag)
+		}
+		if previous.lastModified != "" {
+			req.SetHeader("If-Modified-Since", previous.lastModified)
+		}
+	}
+
+	resp, err := req.Get(DNSManagedPath + DNSRecordsPath)
+	if err == nil && known && resp.StatusCode() == http.StatusNotModified {
+		return previous.fingerprint, nil
+	}
+	resp, err = checkRespForError(resp, err)
+	if err != nil {
+		return "", err
+	}
+
+	fingerprint := RecordsFingerprint(respRecords.Records)
+	c.validators.set(domainId, zoneValidator{
+		etag:         resp.Header().Get("ETag"),
+		lastModified: resp.Header().Get("Last-Modified"),
+		fingerprint:  fingerprint,
+	})
+	return fingerprint, nil
+}
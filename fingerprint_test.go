@@ -0,0 +1,36 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordsFingerprint(t *testing.T) {
+	a := Record{ID: 1, Name: "www", Type: "A", Value: "192.0.2.1"}
+	b := Record{ID: 2, Name: "mail", Type: "A", Value: "192.0.2.2"}
+
+	assert.Equal(t, RecordsFingerprint([]Record{a, b}), RecordsFingerprint([]Record{b, a}))
+
+	b.Ttl = 300
+	assert.NotEqual(t, RecordsFingerprint([]Record{a, b}), RecordsFingerprint([]Record{a}))
+	assert.NotEqual(t, RecordsFingerprint([]Record{a, b}), RecordsFingerprint([]Record{b, a, b}))
+}
+
+func TestZoneFingerprint(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+
+	first, err := client.ZoneFingerprint(domain.ID)
+	require.NoError(t, err)
+	again, err := client.ZoneFingerprint(domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	_, err = client.CreateRecord(domain.ID, Record{Name: "mail", Type: "A", Value: "192.0.2.2"})
+	require.NoError(t, err)
+	changed, err := client.ZoneFingerprint(domain.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, changed)
+}
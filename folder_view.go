@@ -0,0 +1,91 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+)
+
+// A view of the account limited to the domains in one folder, for
+// tooling that treats folders as tenants
+type FolderView struct {
+	client   *Client
+	folderID int
+}
+
+// Returns a view of the domains in the supplied folder
+func (c *Client) Folder(folderID int) *FolderView {
+	return &FolderView{c, folderID}
+}
+
+// The ID of the folder the view is limited to
+func (f *FolderView) ID() int {
+	return f.folderID
+}
+
+// Returns the domains in the folder
+func (f *FolderView) List(ctx context.Context) ([]Domain, error) {
+	domains, err := f.client.Domains().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var inFolder []Domain
+	for _, domain := range domains {
+		if domain.FolderID == f.folderID {
+			inFolder = append(inFolder, domain)
+		}
+	}
+	return inFolder, nil
+}
+
+// Returns a map of Name:ID for the domains in the folder
+func (f *FolderView) EnumerateDomains() (map[string]int, error) {
+	domains, err := f.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]int, len(domains))
+	for _, domain := range domains {
+		names[domain.Name] = domain.ID
+	}
+	return names, nil
+}
+
+// Finds the numerical ID for a given domain name in the folder
+func (f *FolderView) IdForDomain(domain string) (int, error) {
+	names, err := f.EnumerateDomains()
+	if err != nil {
+		return 0, err
+	}
+	id, ok := names[domain]
+	if !ok {
+		return 0, fmt.Errorf("domain %s is not in folder %d: %w", domain, f.folderID, ErrNotFound)
+	}
+	return id, nil
+}
+
+// Returns the records service for a domain, provided it is in the
+// folder
+func (f *FolderView) Records(ctx context.Context, domainID int) (*RecordsService, error) {
+	domain, err := f.client.Domains().Get(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.FolderID != f.folderID {
+		return nil, fmt.Errorf("domain %d is not in folder %d: %w", domainID, f.folderID, ErrNotFound)
+	}
+	return f.client.Records(domainID), nil
+}
+
+// Enumerates the records of every domain in the folder, as
+// Client.FetchAllRecords does
+func (f *FolderView) FetchAllRecords(concurrency int) (map[int][]Record, error) {
+	domains, err := f.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(domains))
+	for idx, domain := range domains {
+		ids[idx] = domain.ID
+	}
+	return f.client.FetchAllRecords(ids, concurrency)
+}
@@ -0,0 +1,43 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderView(t *testing.T) {
+	fake, client := newFakeDME(t)
+	a := fake.addDomain("a.com", Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	b := fake.addDomain("b.com")
+	c := fake.addDomain("c.com")
+	fake.domains[a.ID].FolderID = 5
+	fake.domains[b.ID].FolderID = 5
+	fake.domains[c.ID].FolderID = 6
+
+	folder := client.Folder(5)
+	names, err := folder.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a.com": a.ID, "b.com": b.ID}, names)
+
+	id, err := folder.IdForDomain("b.com")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, id)
+	_, err = folder.IdForDomain("c.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = folder.Records(context.Background(), c.ID)
+	assert.EqualError(t, err, "domain "+itoa(c.ID)+" is not in folder 5: not found")
+	records, err := folder.Records(context.Background(), a.ID)
+	require.NoError(t, err)
+	list, err := records.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	zones, err := folder.FetchAllRecords(2)
+	require.NoError(t, err)
+	assert.Len(t, zones, 2)
+	assert.NotContains(t, zones, c.ID)
+}
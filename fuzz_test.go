@@ -0,0 +1,87 @@
+package dnsmadeeasy
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func FuzzParseZoneFile(f *testing.F) {
+	f.Add("$ORIGIN example.com.\nwww 300 IN A 192.0.2.1\n")
+	f.Add("@ IN MX 10 mail\n_sip._tcp IN SRV 10 20 5060 sip.example.net.\n")
+	f.Add(`txt IN TXT "v=spf1 -all" "a \"quoted\" ; part" ` + "\n")
+	f.Add("www IN CNAME")
+	f.Add("$TTL 60\n$INCLUDE /etc/passwd\n")
+	f.Fuzz(func(t *testing.T, data string) {
+		records, err := ParseZoneFile(strings.NewReader(data), "example.com")
+		if err != nil {
+			return
+		}
+		// whatever parses converts back and forth unchanged
+		for _, record := range records {
+			rr, err := record.RR("example.com")
+			if err != nil {
+				t.Fatalf("%+v: %v", record, err)
+			}
+			again, err := RecordFromRR(rr, "example.com")
+			if err != nil {
+				t.Fatalf("%s: %v", rr, err)
+			}
+			if again != record {
+				t.Fatalf("round trip changed %+v to %+v", record, again)
+			}
+		}
+	})
+}
+
+func FuzzDecodeResponse(f *testing.F) {
+	f.Add(http.StatusOK, `{"totalRecords":1,"data":[{"id":1,"name":"www","type":"A","value":"192.0.2.1"}]}`)
+	f.Add(http.StatusOK, `{"data":[{"id":1,"surprise":{"nested":[1,2]}}]}`)
+	f.Add(http.StatusBadRequest, `{"error":["Record name invalid"]}`)
+	f.Add(http.StatusBadRequest, `{"error":"bare string"}`)
+	f.Add(http.StatusBadRequest, `{"error":[1,null]}`)
+	f.Add(http.StatusBadGateway, `<html>`)
+	f.Fuzz(func(t *testing.T, status int, body string) {
+		var resp RecordsResp
+		err := decodeResponse(status, "req", strings.NewReader(body), &resp)
+		if (status < 200 || status >= 300) && err == nil {
+			t.Fatalf("status %d decoded without error", status)
+		}
+		if err != nil {
+			_ = err.Error()
+		}
+	})
+}
+
+func FuzzParseAnnotation(f *testing.F) {
+	f.Add(`"dme-meta1 name=www type=A owner=Jane+Doe team=web ticket=OPS-12"`)
+	f.Add(`dme-meta1 type=%zz`)
+	f.Add(`"dme-meta1 name=www type=A label.env=prod label.team=web"`)
+	f.Add(`"v=spf1 -all"`)
+	f.Fuzz(func(t *testing.T, txt string) {
+		a, ok := ParseAnnotation(txt)
+		if !ok {
+			return
+		}
+		again, ok := ParseAnnotation(a.txt())
+		if !ok || !reflect.DeepEqual(again, a) {
+			t.Fatalf("round trip changed %+v to %+v", a, again)
+		}
+	})
+}
+
+func FuzzParseQuery(f *testing.F) {
+	f.Add(`type=A,AAAA name=web* value~^192\.0\.2\. ttl=60..300 gtd=DEFAULT`)
+	f.Add(`value~"a \"b\" c" ttl<=60`)
+	f.Fuzz(func(t *testing.T, s string) {
+		q, err := ParseQuery(s)
+		if err != nil {
+			return
+		}
+		again, err := ParseQuery(q.String())
+		if err != nil || again.String() != q.String() {
+			t.Fatalf("round trip changed %q to %q (%v)", q, again, err)
+		}
+	})
+}
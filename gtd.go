@@ -0,0 +1,159 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Global Traffic Director locations records can be served from
+const (
+	GtdDefault      = "DEFAULT"
+	GtdUSEast       = "US_EAST"
+	GtdUSWest       = "US_WEST"
+	GtdEurope       = "EUROPE"
+	GtdAsiaPac      = "ASIA_PAC"
+	GtdOceania      = "OCEANIA"
+	GtdSouthAmerica = "SOUTH_AMERICA"
+)
+
+// The regional locations, excluding DEFAULT
+var GtdRegions = []string{GtdUSEast, GtdUSWest, GtdEurope, GtdAsiaPac, GtdOceania, GtdSouthAmerica}
+
+// How one name and type is served across Global Traffic Director
+// locations
+type GTDNameReport struct {
+	Name string
+	Type string
+
+	// The locations with records, including DEFAULT
+	Locations []string
+
+	// The regions without records of their own, which are answered from
+	// DEFAULT
+	FallbackRegions []string
+
+	// The name has regional records but no DEFAULT ones, so queries
+	// from FallbackRegions get no answer
+	NoDefault bool
+
+	// The TTL used in each location, and whether they differ
+	TTLs            map[string]int
+	InconsistentTTL bool
+}
+
+// The Global Traffic Director layout of one domain
+type GTDReport struct {
+	DomainID int
+	Domain   string
+
+	// The names with region specific records, ordered by name and type
+	Names []GTDNameReport
+}
+
+// Reports how the names with region specific records are served across
+// Global Traffic Director locations. Names only served from DEFAULT
+// are left out.
+func AuditGTD(records []Record) []GTDNameReport {
+	type key struct{ name, rrtype string }
+	byName := map[key]map[string][]Record{}
+	for _, record := range records {
+		location := record.GtdLocation
+		if location == "" {
+			location = GtdDefault
+		}
+		k := key{record.Name, record.Type}
+		if byName[k] == nil {
+			byName[k] = map[string][]Record{}
+		}
+		byName[k][location] = append(byName[k][location], record)
+	}
+
+	var reports []GTDNameReport
+	for k, locations := range byName {
+		if len(locations) == 1 && locations[GtdDefault] != nil {
+			continue
+		}
+		report := GTDNameReport{Name: k.name, Type: k.rrtype, TTLs: map[string]int{}}
+		for location, records := range locations {
+			report.Locations = append(report.Locations, location)
+			report.TTLs[location] = records[0].Ttl
+			for _, record := range records {
+				if record.Ttl != records[0].Ttl {
+					report.InconsistentTTL = true
+				}
+			}
+		}
+		for _, region := range GtdRegions {
+			if locations[region] == nil {
+				report.FallbackRegions = append(report.FallbackRegions, region)
+			}
+		}
+		report.NoDefault = locations[GtdDefault] == nil
+		for _, ttl := range report.TTLs {
+			if ttl != report.TTLs[report.Locations[0]] {
+				report.InconsistentTTL = true
+			}
+		}
+		sort.Strings(report.Locations)
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Name != reports[j].Name {
+			return reports[i].Name < reports[j].Name
+		}
+		return reports[i].Type < reports[j].Type
+	})
+	return reports
+}
+
+// Audits the Global Traffic Director layout of each of the supplied
+// domains that has GTD enabled, ordered by domain ID
+func (c *Client) GTDAudit(ctx context.Context, domainIDs []int) ([]GTDReport, error) {
+	var (
+		reports []GTDReport
+		errs    []error
+	)
+	for _, domainID := range domainIDs {
+		domain, err := c.Domains().Get(ctx, domainID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainID, err))
+			continue
+		}
+		if !domain.GtdEnabled {
+			continue
+		}
+		records, err := c.Records(domainID).List(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("domain %d: %w", domainID, err))
+			continue
+		}
+		reports = append(reports, GTDReport{domain.ID, domain.Name, AuditGTD(records)})
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].DomainID < reports[j].DomainID })
+	return reports, errors.Join(errs...)
+}
+
+// Turns on Global Traffic Director for the supplied domains
+//
+// Equivalent to c.Domains().SetGTD(context.Background(), domainIds, true)
+func (c *Client) EnableGTD(domainIds []int) ([]int, error) {
+	return c.Domains().SetGTD(context.Background(), domainIds, true)
+}
+
+// Turns off Global Traffic Director for the supplied domains
+//
+// Equivalent to c.Domains().SetGTD(context.Background(), domainIds, false)
+func (c *Client) DisableGTD(domainIds []int) ([]int, error) {
+	return c.Domains().SetGTD(context.Background(), domainIds, false)
+}
+
+// Turns Global Traffic Director on or off for the supplied domains, in
+// batches paced by the account's request quota. Returns the IDs of the
+// domains updated; a *BatchError reports those that weren't.
+func (s *DomainsService) SetGTD(ctx context.Context, domainIds []int, enabled bool) ([]int, error) {
+	return s.UpdateSettingsMulti(ctx, domainIds, DomainPatch{GtdEnabled: Bool(enabled)})
+}
@@ -0,0 +1,76 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditGTD(t *testing.T) {
+	reports := AuditGTD([]Record{
+		{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdEurope},
+		{Name: "www", Type: "A", Value: "192.0.2.3", Ttl: 60, GtdLocation: GtdUSEast},
+		{Name: "api", Type: "A", Value: "192.0.2.4", Ttl: 300, GtdLocation: GtdAsiaPac},
+	})
+	require.Len(t, reports, 2)
+
+	api := reports[0]
+	assert.Equal(t, "api", api.Name)
+	assert.True(t, api.NoDefault)
+	assert.Equal(t, []string{GtdAsiaPac}, api.Locations)
+	assert.Len(t, api.FallbackRegions, 5)
+	assert.False(t, api.InconsistentTTL)
+
+	www := reports[1]
+	assert.Equal(t, []string{GtdDefault, GtdEurope, GtdUSEast}, www.Locations)
+	assert.Equal(t, []string{GtdUSWest, GtdAsiaPac, GtdOceania, GtdSouthAmerica}, www.FallbackRegions)
+	assert.False(t, www.NoDefault)
+	assert.True(t, www.InconsistentTTL)
+	assert.Equal(t, 60, www.TTLs[GtdUSEast])
+}
+
+func TestGTDAudit(t *testing.T) {
+	fake, client := newFakeDME(t)
+	plain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdEurope})
+	gtd := fake.addDomain("example.org",
+		Record{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdEurope})
+	fake.domains[gtd.ID].GtdEnabled = true
+
+	reports, err := client.GTDAudit(context.Background(), []int{plain.ID, gtd.ID, 1})
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "example.org", reports[0].Domain)
+	assert.Len(t, reports[0].Names, 1)
+}
+
+func TestEnableGTD(t *testing.T) {
+	fake, client := newFakeDME(t)
+	client = GetClient("key", "secret", client.BaseURL, WithBatchSize(2))
+	var ids []int
+	for _, name := range []string{"a.com", "b.com", "c.com"} {
+		ids = append(ids, fake.addDomain(name).ID)
+	}
+
+	updated, err := client.EnableGTD(append(ids, 1))
+	assert.Equal(t, ids[:2], updated)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{0, 1}, batchErr.Succeeded)
+	assert.Len(t, batchErr.Failed, 2)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 2, fake.calls["PUT /dns/managed/"])
+
+	assert.True(t, fake.domains[ids[0]].GtdEnabled)
+	assert.True(t, fake.domains[ids[1]].GtdEnabled)
+	assert.False(t, fake.domains[ids[2]].GtdEnabled)
+
+	updated, err = client.DisableGTD(ids)
+	require.NoError(t, err)
+	assert.Equal(t, ids, updated)
+	assert.False(t, fake.domains[ids[0]].GtdEnabled)
+}
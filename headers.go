@@ -0,0 +1,23 @@
+package dnsmadeeasy
+
+import "strings"
+
+// Identifies this package in the User-Agent of every request
+const UserAgent = "dnsmadeeasy-go"
+
+// Identifies the calling tool to DNS Made Easy and any gateways in
+// between, e.g. "terraform-provider-dme/1.2". The package's own
+// identifier is appended.
+func WithUserAgent(product string) Option {
+	return func(c *Client) {
+		c.resty.SetHeader("User-Agent", strings.TrimSpace(product+" "+UserAgent))
+	}
+}
+
+// Sends an extra header with every request. The authentication headers
+// can't be overridden this way.
+func WithHeader(name string, value string) Option {
+	return func(c *Client) {
+		c.resty.SetHeader(name, value)
+	}
+}
@@ -0,0 +1,25 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaders(t *testing.T) {
+	fake, client := newFakeDME(t)
+	_, err := client.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, UserAgent, fake.headers.Get("User-Agent"))
+
+	client = GetClient("key", "secret", client.BaseURL,
+		WithUserAgent("terraform-provider-dme/1.2"),
+		WithHeader("X-Team", "platform"),
+		WithHeader("X-Dnsme-Apikey", "spoofed"))
+	_, err = client.EnumerateDomains()
+	require.NoError(t, err)
+	assert.Equal(t, "terraform-provider-dme/1.2 "+UserAgent, fake.headers.Get("User-Agent"))
+	assert.Equal(t, "platform", fake.headers.Get("X-Team"))
+	assert.Equal(t, []string{"key"}, fake.headers.Values("X-Dnsme-Apikey"))
+}
@@ -0,0 +1,160 @@
+package dnsmadeeasy
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// A change in a zone's history, with an ID to refer to it by. DNS Made
+// Easy doesn't report who made a change, so history records what
+// changed and when it was detected.
+type HistoryEntry struct {
+	ID string `json:"id"`
+	ChangeEvent
+}
+
+// Returns the changes between successive backups of a zone, oldest
+// first, timed at the backup that detected them
+func BackupChanges(entries []BackupEntry) []ChangeEvent {
+	var changes []ChangeEvent
+	var full *BackupEntry
+	var previous []Record
+	baseline := false
+	for idx := range entries {
+		entry := &entries[idx]
+		if entry.Full {
+			full = entry
+		}
+		if full == nil {
+			continue
+		}
+		records := applyBackup(*full, *entry)
+		if baseline {
+			changes = append(changes, changeEvents(entry.DomainID, entry.Domain, entry.Time, previous, records)...)
+		}
+		previous, baseline = records, true
+	}
+	return changes
+}
+
+// Reads the change events written by a JSON lines ChangeWriter
+func ReadChangeJournal(r io.Reader) ([]ChangeEvent, error) {
+	var events []ChangeEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var event ChangeEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Merges change events from any number of sources into a timeline,
+// oldest first. Events seen by more than one source appear once.
+func History(sources ...[]ChangeEvent) []HistoryEntry {
+	seen := map[string]bool{}
+	var history []HistoryEntry
+	for _, events := range sources {
+		for _, event := range events {
+			id := changeID(event)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			history = append(history, HistoryEntry{ID: id, ChangeEvent: event})
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	return history
+}
+
+// Returns the entry whose ID is or starts with id, as long as only one
+// does
+func FindChange(history []HistoryEntry, id string) (HistoryEntry, error) {
+	var found []HistoryEntry
+	for _, entry := range history {
+		if strings.HasPrefix(entry.ID, id) {
+			found = append(found, entry)
+		}
+	}
+	switch {
+	case id == "" || len(found) == 0:
+		return HistoryEntry{}, fmt.Errorf("change %q: %w", id, ErrNotFound)
+	case len(found) > 1:
+		return HistoryEntry{}, fmt.Errorf("change %q is ambiguous: %d changes match", id, len(found))
+	}
+	return found[0], nil
+}
+
+// Identifies a change by its zone, kind, time and record, so the same
+// change gets the same ID whichever source reports it
+func changeID(event ChangeEvent) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d/%s/%d/%d", event.DomainID, event.Type, event.Time.UnixNano(), event.Record.ID)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Undoes a single change with ApplyContext: added records are deleted,
+// modified ones changed back and removed ones created again. Fails with
+// ErrConflict, changing nothing, if the record changed again since.
+func (c *Client) RevertChange(ctx context.Context, event ChangeEvent) (ApplyResult, error) {
+	op, err := c.revertOperation(ctx, event)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	return c.ApplyContext(ctx, []Operation{op})
+}
+
+// Returns the operation undoing event, checking the zone still holds
+// what the change left behind
+func (c *Client) revertOperation(ctx context.Context, event ChangeEvent) (Operation, error) {
+	records, err := c.Records(event.DomainID).list(ctx)
+	if err != nil {
+		return Operation{}, err
+	}
+	var current *Record
+	for idx := range records {
+		if records[idx].ID == event.Record.ID {
+			current = &records[idx]
+		}
+	}
+	conflict := fmt.Errorf("%s %s in %s: %w", event.Record.Type, event.Record.Name, event.Domain, ErrConflict)
+
+	switch event.Type {
+	case ChangeAdded, ChangeModified:
+		if current == nil || restorable(*current) != restorable(event.Record) {
+			return Operation{}, conflict
+		}
+		if event.Type == ChangeAdded {
+			return Operation{OpDelete, event.DomainID, *current}, nil
+		}
+		if event.Previous == nil {
+			return Operation{}, fmt.Errorf("change to %s %s has no previous record", event.Record.Type, event.Record.Name)
+		}
+		previous := *event.Previous
+		previous.ID = current.ID
+		return Operation{OpUpdate, event.DomainID, previous}, nil
+	case ChangeRemoved:
+		for _, record := range records {
+			if RecordKey(record) == RecordKey(event.Record) {
+				return Operation{}, conflict
+			}
+		}
+		record := event.Record
+		record.ID = 0
+		return Operation{OpCreate, event.DomainID, record}, nil
+	}
+	return Operation{}, fmt.Errorf("unknown change type %s", event.Type)
+}
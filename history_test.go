@@ -0,0 +1,88 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupHistory(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	store := FileBackups{Dir: t.TempDir()}
+	b := &BackupSchedule{Client: client, Store: store}
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, b.backup(ctx, domain, start))
+
+	records := fake.sortedRecords(domain.ID)
+	www := records[0]
+	www.Value = "192.0.2.10"
+	require.NoError(t, client.Records(domain.ID).Update(ctx, www))
+	require.NoError(t, b.backup(ctx, domain, start.Add(time.Hour)))
+	require.NoError(t, client.Records(domain.ID).Delete(ctx, records[1].ID))
+	require.NoError(t, b.backup(ctx, domain, start.Add(2*time.Hour)))
+
+	entries, err := store.LoadBackups(domain.ID)
+	require.NoError(t, err)
+	changes := BackupChanges(entries)
+	require.Len(t, changes, 2)
+	assert.Equal(t, ChangeModified, changes[0].Type)
+	assert.Equal(t, "192.0.2.1", changes[0].Previous.Value)
+	assert.Equal(t, start.Add(time.Hour), changes[0].Time)
+	assert.Equal(t, ChangeRemoved, changes[1].Type)
+	assert.Equal(t, "api", changes[1].Record.Name)
+
+	ids, err := store.Domains()
+	require.NoError(t, err)
+	assert.Equal(t, []int{domain.ID}, ids)
+
+	// the same change from a journal appears once
+	var journal bytes.Buffer
+	require.NoError(t, NewJSONLinesWriter(&journal).WriteChange(changes[1]))
+	fromJournal, err := ReadChangeJournal(&journal)
+	require.NoError(t, err)
+	history := History(changes, fromJournal)
+	require.Len(t, history, 2)
+
+	entry, err := FindChange(history, history[1].ID[:6])
+	require.NoError(t, err)
+	assert.Equal(t, ChangeRemoved, entry.Type)
+	_, err = FindChange(history, "zz")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.RevertChange(ctx, entry.ChangeEvent)
+	require.NoError(t, err)
+	_, err = client.RevertChange(ctx, history[0].ChangeEvent)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"www=192.0.2.1", "api=192.0.2.2", "mail=192.0.2.3"}, recordValues(fake.recordList(domain.ID)))
+
+	// reverting again conflicts with the zone as it is now
+	_, err = client.RevertChange(ctx, entry.ChangeEvent)
+	assert.ErrorIs(t, err, ErrConflict)
+	_, err = client.RevertChange(ctx, history[0].ChangeEvent)
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func TestRevertAddedRecord(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	ctx := context.Background()
+	created, err := client.Records(domain.ID).Create(ctx, Record{Name: "new", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: GtdDefault})
+	require.NoError(t, err)
+
+	event := ChangeEvent{Type: ChangeAdded, Time: time.Now(), DomainID: domain.ID, Domain: domain.Name, Record: created}
+	result, err := client.RevertChange(ctx, event)
+	require.NoError(t, err)
+	require.Len(t, result.Applied, 1)
+	assert.Equal(t, OpDelete, result.Applied[0].Type)
+	assert.Empty(t, fake.recordList(domain.ID))
+}
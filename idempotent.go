@@ -0,0 +1,36 @@
+package dnsmadeeasy
+
+import "context"
+
+// Makes Create check the zone for a record with the same name, type and
+// value before creating one, returning the existing record instead. A
+// Create retried after a timeout, whose first attempt may have been
+// applied, then can't produce a duplicate.
+//
+// NOTE: costs an extra listing of the domain's records per create
+func WithIdempotentCreates() Option {
+	return func(c *Client) {
+		c.idempotentCreates = true
+	}
+}
+
+// Returns the record in the domain matching record's name, type and
+// value, if there is one
+func (s *RecordsService) existing(ctx context.Context, record Record) (Record, bool, error) {
+	records, err := s.list(ctx)
+	if err != nil {
+		return Record{}, false, err
+	}
+	for _, r := range records {
+		if sameRecord(r, record) {
+			return r, true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// Reports whether a and b are the same resource record, ignoring their
+// IDs and settings
+func sameRecord(a, b Record) bool {
+	return RecordKey(a) == RecordKey(b)
+}
@@ -0,0 +1,31 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotentCreates(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client = GetClient("key", "secret", client.BaseURL, WithIdempotentCreates())
+
+	record := Record{Name: "www", Type: "CNAME", Value: "example.com.", Ttl: 300}
+	first, err := client.CreateRecord(domain.ID, record)
+	require.NoError(t, err)
+
+	// a retry, after a first attempt whose response was lost
+	record.Value = "EXAMPLE.com"
+	second, err := client.CreateRecord(domain.ID, record)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Len(t, fake.recordList(domain.ID), 1)
+	assert.Equal(t, 1, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records"])
+
+	record.Value = "other.example.com."
+	_, err = client.CreateRecord(domain.ID, record)
+	require.NoError(t, err)
+	assert.Len(t, fake.recordList(domain.ID), 2)
+}
@@ -0,0 +1,151 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The outcome of importing records into a zone
+type ImportResult struct {
+	// Records created by this run
+	Created []Record
+
+	// Records already in the zone, from an earlier interrupted run or
+	// otherwise
+	Skipped []Record
+
+	// Records that could not be created
+	Failed []Record
+}
+
+// Creates the records that aren't already in the domain, identifying
+// them by name, type and value. Re-running an interrupted or partly
+// failed import only creates what is still missing, so imports can be
+// retried freely. Creation is batched as by CreateMulti, so
+// WithBatchSize and WithCreateFallback apply.
+func (c *Client) ImportZone(ctx context.Context, domainID int, records []Record) (ImportResult, error) {
+	existing, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	present := map[string]bool{}
+	for _, record := range existing {
+		present[RecordKey(record)] = true
+	}
+
+	var result ImportResult
+	var missing []Record
+	for _, record := range records {
+		key := RecordKey(record)
+		if present[key] {
+			result.Skipped = append(result.Skipped, record)
+			continue
+		}
+		// duplicates in the input are only created once
+		present[key] = true
+		record.ID = 0
+		record.SourceId = 0
+		missing = append(missing, record)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	created, err := c.Records(domainID).CreateMulti(ctx, missing)
+	result.Created = created
+	if err != nil {
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			result.Failed = missing
+			return result, err
+		}
+		for _, item := range batchErr.Failed {
+			result.Failed = append(result.Failed, missing[item.Index])
+		}
+	}
+	return result, err
+}
+
+// Imports every zone exported by ExportAll in JSON format from dir,
+// creating domains that don't exist yet. Domains are matched by name,
+// so the export may come from another account. Results are keyed by
+// domain name; like ImportZone, it can be re-run until it succeeds.
+func (c *Client) ImportAll(ctx context.Context, dir string) (map[string]ImportResult, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*."+string(ExportJSON)))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	results := map[string]ImportResult{}
+	var errs []error
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		var export ZoneExport
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &export)
+		}
+		if err != nil || export.Domain.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: not a zone export: %v", filepath.Base(path), err))
+			continue
+		}
+		name := export.Domain.Name
+
+		domainID, err := c.Domains().IdFor(ctx, name)
+		if errors.Is(err, ErrDomainNotFound) {
+			var domain Domain
+			domain, err = c.Domains().Create(ctx, name)
+			if err == nil {
+				// records can't be added until creation completes
+				domain, err = c.WaitForDomain(ctx, domain.ID)
+			}
+			domainID = domain.ID
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		result, err := c.ImportZone(ctx, domainID, export.Records)
+		results[name] = result
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Identifies a record by name, type and value, as ImportZone matches
+// them. Names, types and the values of address and hostname records
+// ignore case and a trailing dot; other values, such as TXT, must match
+// exactly.
+func RecordKey(record Record) string {
+	value := record.Value
+	if hostValued(record.Type) {
+		value = strings.ToLower(strings.TrimSuffix(value, "."))
+	}
+	return strings.Join([]string{
+		strings.ToLower(record.Name),
+		strings.ToUpper(record.Type),
+		value,
+	}, "\x00")
+}
+
+// Reports whether records of the type hold an address or hostname,
+// which DNS compares ignoring case
+func hostValued(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA", "CNAME", "ANAME", "MX", "NS", "PTR", "SRV":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportZone(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+
+	records := []Record{
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		{Name: "mail", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		{Name: "mail", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		{Name: "invalid", Type: "A", Value: "192.0.2.3", Ttl: 300},
+	}
+	client = GetClient("key", "secret", client.BaseURL, WithCreateFallback())
+	result, err := client.ImportZone(context.Background(), domain.ID, records)
+	var batchErr *BatchError
+	assert.ErrorAs(t, err, &batchErr)
+	assert.Len(t, result.Created, 1)
+	assert.Len(t, result.Skipped, 2)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "invalid", result.Failed[0].Name)
+
+	// a re-run only retries what failed
+	result, err = client.ImportZone(context.Background(), domain.ID, records[:3])
+	require.NoError(t, err)
+	assert.Empty(t, result.Created)
+	assert.Len(t, result.Skipped, 3)
+	assert.Len(t, fake.recordList(domain.ID), 2)
+}
+
+func TestImportAll(t *testing.T) {
+	source, sourceClient := newFakeDME(t)
+	source.addDomain("a.com", Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	source.addDomain("b.com", Record{Name: "", Type: "MX", Value: "mail.b.com.", MxLevel: 10, Ttl: 300})
+	dir := t.TempDir()
+	_, err := sourceClient.ExportAll(context.Background(), ExportOptions{Dir: dir})
+	require.NoError(t, err)
+
+	target, client := newFakeDME(t)
+	existing := target.addDomain("a.com")
+	for range 2 {
+		results, err := client.ImportAll(context.Background(), dir)
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	}
+
+	assert.Len(t, target.recordList(existing.ID), 1)
+	id, err := client.IdForDomain("b.com")
+	require.NoError(t, err)
+	records := target.recordList(id)
+	require.Len(t, records, 1)
+	assert.Equal(t, 10, records[0].MxLevel)
+}
+
+func TestImportZoneKeepsValueCase(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "", Type: "TXT", Value: `"token=AbC"`, Ttl: 300},
+		Record{Name: "www", Type: "CNAME", Value: "Host.example.net.", Ttl: 300})
+
+	result, err := client.ImportZone(context.Background(), domain.ID, []Record{
+		{Name: "", Type: "TXT", Value: `"token=abc"`, Ttl: 300},
+		{Name: "", Type: "txt", Value: `"token=AbC"`, Ttl: 300},
+		{Name: "WWW", Type: "CNAME", Value: "host.example.net", Ttl: 300},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 1)
+	assert.Equal(t, `"token=abc"`, result.Created[0].Value)
+	assert.Len(t, result.Skipped, 2)
+}
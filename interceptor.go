@@ -0,0 +1,38 @@
+package dnsmadeeasy
+
+import (
+	"net/http"
+)
+
+// Runs around every HTTP request the client makes, retries included,
+// after authentication headers are set. It may change the request,
+// rewrite or replace the response, or answer without calling next.
+type Interceptor func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// Adapts a function to http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Adds interceptors to the client's chain. The first registered is the
+// outermost, seeing requests first and responses last.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+		c.resty.SetTransport(chain(c.transport, c.interceptors))
+	}
+}
+
+// Returns base wrapped by interceptors, the first outermost
+func chain(base http.RoundTripper, interceptors []Interceptor) http.RoundTripper {
+	next := base
+	for idx := len(interceptors) - 1; idx >= 0; idx-- {
+		interceptor, inner := interceptors[idx], next
+		next = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, inner)
+		})
+	}
+	return next
+}
@@ -0,0 +1,68 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			order = append(order, name+" request")
+			resp, err := next.RoundTrip(req)
+			order = append(order, name+" response")
+			return resp, err
+		}
+	}
+	injectHeader := func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		req.Header.Set("X-Tenant", "team-a")
+		return next.RoundTrip(req)
+	}
+	rewrite := func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || !strings.HasSuffix(req.URL.Path, "/records") {
+			return resp, err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		body = bytes.ReplaceAll(body, []byte("192.0.2.1"), []byte("192.0.2.99"))
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		return resp, nil
+	}
+
+	client = GetClient("key", "secret", client.BaseURL,
+		WithInterceptors(trace("outer"), injectHeader),
+		WithInterceptors(trace("inner"), rewrite))
+	records, err := client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.99", records[0].Value)
+	assert.Equal(t, "team-a", fake.headers.Get("X-Tenant"))
+	assert.Equal(t, []string{"outer request", "inner request", "inner response", "outer response"}, order)
+
+	// an interceptor can answer without reaching the API
+	served := fake.served
+	client = GetClient("key", "secret", client.BaseURL, WithInterceptors(
+		func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"error":["maintenance"]}`)),
+				Request:    req,
+			}, nil
+		}))
+	_, err = client.Domains().List(context.Background())
+	assert.EqualError(t, err, "maintenance")
+	assert.Equal(t, served, fake.served)
+}
@@ -0,0 +1,207 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A requirement on one label of a Selector
+type requirement struct {
+	Key   string
+	Value string
+
+	// Whether the label must have Value, or be present if Value is
+	// empty; otherwise it must not
+	Equal bool
+}
+
+// Selects records by their annotation's labels. Every requirement must
+// hold; see ParseSelector.
+type Selector []requirement
+
+// Parses a selector such as "env=prod,team=web". Requirements are
+// separated by commas: "key=value" and "key!=value" compare a label's
+// value, "key" requires the label and "!key" requires its absence. The
+// empty selector matches everything.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var req requirement
+		switch {
+		case strings.Contains(part, "!="):
+			req.Key, req.Value, _ = strings.Cut(part, "!=")
+		case strings.Contains(part, "="):
+			req.Key, req.Value, _ = strings.Cut(part, "=")
+			req.Equal = true
+		case strings.HasPrefix(part, "!"):
+			req.Key = part[1:]
+		default:
+			req.Key, req.Equal = part, true
+		}
+		req.Key, req.Value = strings.TrimSpace(req.Key), strings.TrimSpace(req.Value)
+		if err := checkLabel(req.Key, req.Value); err != nil {
+			return nil, fmt.Errorf("selector %q: %w", s, err)
+		}
+		if strings.Contains(part, "=") && req.Value == "" {
+			return nil, fmt.Errorf("selector %q: %q has no value", s, part)
+		}
+		sel = append(sel, req)
+	}
+	return sel, nil
+}
+
+// Reports whether labels satisfy every requirement
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch {
+		case req.Value == "" && ok != req.Equal:
+			return false
+		case req.Value != "" && (ok && value == req.Value) != req.Equal:
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for idx, req := range s {
+		switch {
+		case req.Value == "" && req.Equal:
+			parts[idx] = req.Key
+		case req.Value == "":
+			parts[idx] = "!" + req.Key
+		case req.Equal:
+			parts[idx] = req.Key + "=" + req.Value
+		default:
+			parts[idx] = req.Key + "!=" + req.Value
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Rejects label keys and values that selectors couldn't express
+func checkLabel(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("empty label key")
+	}
+	if strings.ContainsAny(key, ",=! \t") {
+		return fmt.Errorf("label key %q may not contain commas, =, ! or spaces", key)
+	}
+	if strings.ContainsAny(value, ", \t") {
+		return fmt.Errorf("label value %q may not contain commas or spaces", value)
+	}
+	return nil
+}
+
+// Replaces the labels of the records of one name and type, keeping the
+// rest of their annotation. Nil or empty labels remove them.
+func SetLabels(ctx context.Context, store AnnotationStore, domainID int, name, recordType string, labels map[string]string) error {
+	for key, value := range labels {
+		if err := checkLabel(key, value); err != nil {
+			return err
+		}
+	}
+	a, _, err := store.Get(ctx, domainID, name, recordType)
+	if err != nil {
+		return err
+	}
+	if sameLabels(a.Labels, labels) {
+		return nil
+	}
+	a.Labels = labels
+	if len(labels) == 0 {
+		a.Labels = nil
+	}
+	return store.Set(ctx, domainID, RecordAnnotation{name, recordType, a})
+}
+
+// Returns the domain's records whose labels in store match sel
+func (c *Client) SelectRecords(ctx context.Context, store AnnotationStore, domainID int, sel Selector) ([]Record, error) {
+	records, err := c.Records(domainID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := LabelsByRecord(ctx, store, domainID)
+	if err != nil {
+		return nil, err
+	}
+	var selected []Record
+	for _, record := range records {
+		if sel.Matches(labels(record)) {
+			selected = append(selected, record)
+		}
+	}
+	return selected, nil
+}
+
+// Changes every record matching sel in the domains, or the whole
+// account if domainIDs is empty, in a single ApplyContext so a failure
+// part way is rolled back. change edits each selected record; records
+// it leaves as they were aren't updated.
+func (c *Client) UpdateSelected(ctx context.Context, store AnnotationStore, domainIDs []int, sel Selector, change func(*Record)) (ApplyResult, error) {
+	domains, err := c.domainsOrAll(ctx, domainIDs)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	var ops []Operation
+	for _, domain := range domains {
+		records, err := c.SelectRecords(ctx, store, domain.ID, sel)
+		if err != nil {
+			return ApplyResult{}, fmt.Errorf("%s: %w", domain.Name, err)
+		}
+		for _, record := range records {
+			updated := record
+			change(&updated)
+			updated.ID = record.ID
+			if updated != record {
+				ops = append(ops, Operation{OpUpdate, domain.ID, updated})
+			}
+		}
+	}
+	return c.ApplyContext(ctx, ops)
+}
+
+// Loads the labels of a domain's records from store, returning a
+// lookup from record to labels
+func LabelsByRecord(ctx context.Context, store AnnotationStore, domainID int) (func(Record) map[string]string, error) {
+	all, err := store.List(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("loading labels: %w", err)
+	}
+	labels := map[string]map[string]string{}
+	for _, a := range all {
+		labels[noteKey(a.Name, a.Type)] = a.Labels
+	}
+	return func(record Record) map[string]string {
+		return labels[noteKey(record.Name, record.Type)]
+	}, nil
+}
+
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
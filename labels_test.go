@@ -0,0 +1,98 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelector(t *testing.T) {
+	sel, err := ParseSelector("env=prod, team!=ops,web,!legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "env=prod,team!=ops,web,!legacy", sel.String())
+
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"env": "prod", "web": "yes"}, true},
+		{map[string]string{"env": "prod", "web": "", "team": "web"}, true},
+		{map[string]string{"env": "dev", "web": "yes"}, false},
+		{map[string]string{"env": "prod", "web": "yes", "team": "ops"}, false},
+		{map[string]string{"env": "prod"}, false},
+		{map[string]string{"env": "prod", "web": "yes", "legacy": "true"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, sel.Matches(tt.labels), "%v", tt.labels)
+	}
+
+	empty, err := ParseSelector("")
+	require.NoError(t, err)
+	assert.True(t, empty.Matches(nil))
+
+	for _, bad := range []string{"=prod", "env=", "env=a b", "!"} {
+		_, err := ParseSelector(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestUpdateSelected(t *testing.T) {
+	fake, client := newFakeDME(t)
+	prod := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	other := fake.addDomain("example.org",
+		Record{Name: "www", Type: "A", Value: "198.51.100.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	store := &FileAnnotations{Path: filepath.Join(t.TempDir(), "labels.json")}
+	ctx := context.Background()
+	require.NoError(t, SetLabels(ctx, store, prod.ID, "www", "A", map[string]string{"env": "prod", "team": "web"}))
+	require.NoError(t, SetLabels(ctx, store, prod.ID, "api", "A", map[string]string{"env": "prod", "team": "api"}))
+	require.NoError(t, SetLabels(ctx, store, other.ID, "www", "A", map[string]string{"env": "prod", "team": "web"}))
+	assert.Error(t, SetLabels(ctx, store, prod.ID, "mail", "A", map[string]string{"bad key": "x"}))
+
+	sel, err := ParseSelector("env=prod,team=web")
+	require.NoError(t, err)
+	selected, err := client.SelectRecords(ctx, store, prod.ID, sel)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"www=192.0.2.1"}, recordValues(selected))
+
+	result, err := client.UpdateSelected(ctx, store, nil, sel, func(record *Record) { record.Ttl = 60 })
+	require.NoError(t, err)
+	assert.Len(t, result.Applied, 2)
+	ttls := map[string]int{}
+	for _, domain := range []Domain{prod, other} {
+		for _, record := range fake.recordList(domain.ID) {
+			ttls[domain.Name+"/"+record.Name] = record.Ttl
+		}
+	}
+	assert.Equal(t, map[string]int{"example.com/www": 60, "example.com/api": 300, "example.com/mail": 300, "example.org/www": 60}, ttls)
+
+	// records already as wanted aren't updated again
+	result, err = client.UpdateSelected(ctx, store, []int{prod.ID}, sel, func(record *Record) { record.Ttl = 60 })
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+
+	// clearing labels keeps the rest of the annotation
+	require.NoError(t, SetNote(ctx, store, prod.ID, "api", "A", "internal only"))
+	require.NoError(t, SetLabels(ctx, store, prod.ID, "api", "A", nil))
+	a, ok, err := store.Get(ctx, prod.ID, "api", "A")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Annotation{Note: "internal only"}, a)
+}
+
+func TestLabelsInCompanionTXT(t *testing.T) {
+	a := RecordAnnotation{Name: "www", Type: "A", Annotation: Annotation{Owner: "web", Labels: map[string]string{"env": "prod", "tier": "edge/1"}}}
+	txt := a.txt()
+	assert.Equal(t, `"dme-meta1 name=www type=A owner=web label.env=prod label.tier=edge%2F1"`, txt)
+	parsed, ok := ParseAnnotation(txt)
+	require.True(t, ok)
+	assert.Equal(t, a, parsed)
+}
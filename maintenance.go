@@ -0,0 +1,220 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Returned, wrapped, when a change to a domain is refused because none
+// of its maintenance windows is open
+var ErrOutsideMaintenanceWindow = errors.New("outside maintenance window")
+
+// A weekly period during which a domain may be changed
+type MaintenanceWindow struct {
+	// The days the window opens on; every day if empty
+	Weekdays []time.Weekday
+
+	// When the window opens, after midnight, and how long it stays open.
+	// Windows may run past midnight.
+	Start    time.Duration
+	Duration time.Duration
+
+	// UTC if nil
+	Location *time.Location
+}
+
+// Reports whether the window is open at t
+func (w MaintenanceWindow) open(t time.Time) bool {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	// a window may have opened on any of the past week's days
+	for offset := 0; offset >= -7; offset-- {
+		start := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, loc).Add(w.Start)
+		if !start.After(t) && t.Before(start.Add(w.Duration)) && w.opensOn(start.Weekday()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns when the window next opens after t
+func (w MaintenanceWindow) next(t time.Time) time.Time {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	for offset := 0; offset <= 7; offset++ {
+		start := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, loc).Add(w.Start)
+		if start.After(t) && w.opensOn(start.Weekday()) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+func (w MaintenanceWindow) opensOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range w.Weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Restricts when domains may be changed
+type MaintenancePolicy struct {
+	// Windows by domain ID. Domains not listed may be changed at any time.
+	Domains map[int][]MaintenanceWindow
+
+	// Wait for the next window to open instead of failing, for as long
+	// as the request's context allows
+	Queue bool
+
+	// time.Now if nil; for tests
+	now func() time.Time
+}
+
+// Reports whether the domain may be changed at t
+func (p MaintenancePolicy) Open(domainID int, t time.Time) bool {
+	windows, ok := p.Domains[domainID]
+	if !ok {
+		return true
+	}
+	for _, window := range windows {
+		if window.open(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns when the domain may next be changed, t itself if it may be
+// changed now, or the zero time if it never may
+func (p MaintenancePolicy) NextOpen(domainID int, t time.Time) time.Time {
+	if p.Open(domainID, t) {
+		return t
+	}
+	var next time.Time
+	for _, window := range p.Domains[domainID] {
+		if start := window.next(t); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Refuses, or with Queue holds, requests that change a domain outside
+// its maintenance windows. A request changing many domains, such as
+// those of UpdateSettingsMulti and SetGTD, is checked against the
+// windows of each. Requests whose context comes from
+// OverrideMaintenance are let through.
+func WithMaintenanceWindows(policy MaintenancePolicy) Option {
+	return func(c *Client) {
+		if policy.now == nil {
+			policy.now = time.Now
+		}
+		c.resty.OnBeforeRequest(func(client *resty.Client, req *resty.Request) error {
+			return c.checkMaintenance(client, req, policy)
+		})
+	}
+}
+
+type maintenanceOverrideKey struct{}
+
+// Returns a context whose requests may change domains outside their
+// maintenance windows, for emergencies
+func OverrideMaintenance(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maintenanceOverrideKey{}, true)
+}
+
+func (c *Client) checkMaintenance(client *resty.Client, req *resty.Request, policy MaintenancePolicy) error {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Context().Value(maintenanceOverrideKey{}) != nil {
+		return nil
+	}
+	domainIDs := requestDomainIDs(req)
+	if len(domainIDs) == 0 {
+		return nil
+	}
+
+	// a request changing several domains waits until all their windows
+	// are open at once, which with weekly windows happens within a week
+	// if ever
+	var waited time.Duration
+	for {
+		now := policy.now()
+		domainID, closed := policy.firstClosed(domainIDs, now)
+		if !closed {
+			if waited > 0 {
+				// the signature is only valid for a few minutes
+				return c.addAuthHeaders(client, req)
+			}
+			return nil
+		}
+		next := policy.NextOpen(domainID, now)
+		if next.IsZero() {
+			return fmt.Errorf("domain %d: %w", domainID, ErrOutsideMaintenanceWindow)
+		}
+		if !policy.Queue {
+			return fmt.Errorf("domain %d: %w; the next opens %s", domainID, ErrOutsideMaintenanceWindow, next.Format(time.RFC3339))
+		}
+		if waited > 7*24*time.Hour {
+			return fmt.Errorf("domains %v: %w; their windows never open together", domainIDs, ErrOutsideMaintenanceWindow)
+		}
+		if err := pause(req.Context(), next.Sub(now)); err != nil {
+			return fmt.Errorf("domain %d: waiting for maintenance window: %w", domainID, err)
+		}
+		waited += next.Sub(now)
+	}
+}
+
+// Returns the first of the domains that may not be changed at t
+func (p MaintenancePolicy) firstClosed(domainIDs []int, t time.Time) (int, bool) {
+	for _, domainID := range domainIDs {
+		if !p.Open(domainID, t) {
+			return domainID, true
+		}
+	}
+	return 0, false
+}
+
+// Returns the IDs of the domains a request acts on: the one its path
+// names or, for changes to many domains at once, those listed in the
+// ids of its body
+func requestDomainIDs(req *resty.Request) []int {
+	_, rest, ok := strings.Cut(req.URL, strings.TrimSuffix(DNSManagedPath, "/"))
+	if !ok {
+		return nil
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+	if segment == "" {
+		var bulk struct {
+			IDs []int `json:"ids"`
+		}
+		if data, err := json.Marshal(req.Body); err == nil {
+			json.Unmarshal(data, &bulk)
+		}
+		return bulk.IDs
+	}
+	if segment == "{domainId}" {
+		segment = req.PathParams["domainId"]
+	}
+	if id, err := strconv.Atoi(segment); err == nil {
+		return []int{id}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Weekdays from 22:00 to 02:00 UTC
+var nightly = MaintenanceWindow{
+	Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	Start:    22 * time.Hour,
+	Duration: 4 * time.Hour,
+}
+
+// 2024-01-05 is a Friday
+func jan2024(day, hour int) time.Time {
+	return time.Date(2024, 1, day, hour, 30, 0, 0, time.UTC)
+}
+
+func TestMaintenancePolicy(t *testing.T) {
+	policy := MaintenancePolicy{Domains: map[int][]MaintenanceWindow{7: {nightly}, 9: nil}}
+
+	assert.True(t, policy.Open(8, jan2024(5, 12)))
+	assert.False(t, policy.Open(7, jan2024(5, 12)))
+	assert.True(t, policy.Open(7, jan2024(5, 23)))
+	// Friday's window runs into Saturday, but none opens at the weekend
+	assert.True(t, policy.Open(7, jan2024(6, 1)))
+	assert.False(t, policy.Open(7, jan2024(6, 23)))
+
+	assert.Equal(t, jan2024(5, 23), policy.NextOpen(7, jan2024(5, 23)))
+	assert.Equal(t, time.Date(2024, 1, 8, 22, 0, 0, 0, time.UTC), policy.NextOpen(7, jan2024(6, 12)))
+	assert.True(t, policy.NextOpen(9, jan2024(6, 12)).IsZero())
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err == nil {
+		local := MaintenancePolicy{Domains: map[int][]MaintenanceWindow{7: {{Start: 22 * time.Hour, Duration: time.Hour, Location: berlin}}}}
+		assert.True(t, local.Open(7, time.Date(2024, 1, 5, 21, 30, 0, 0, time.UTC)))
+	}
+}
+
+func TestWithMaintenanceWindows(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	other := fake.addDomain("example.org")
+	policy := MaintenancePolicy{Domains: map[int][]MaintenanceWindow{domain.ID: {nightly}}}
+	now := jan2024(5, 12)
+	policy.now = func() time.Time { return now }
+	client = GetClient("key", "secret", client.BaseURL, WithMaintenanceWindows(policy))
+	ctx := context.Background()
+	record := Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault}
+
+	_, err := client.Records(domain.ID).Create(ctx, record)
+	assert.ErrorIs(t, err, ErrOutsideMaintenanceWindow)
+	assert.ErrorContains(t, err, "the next opens 2024-01-05T22:00:00Z")
+	assert.ErrorIs(t, client.Domains().Delete(ctx, domain.ID), ErrOutsideMaintenanceWindow)
+
+	// reads, other domains and overrides are let through
+	_, err = client.Records(domain.ID).List(ctx)
+	assert.NoError(t, err)
+	_, err = client.Records(other.ID).Create(ctx, record)
+	assert.NoError(t, err)
+	_, err = client.Records(domain.ID).Create(OverrideMaintenance(ctx), record)
+	assert.NoError(t, err)
+
+	// queued changes wait for the window
+	stubPause(t)
+	var waited []time.Duration
+	pause = func(ctx context.Context, d time.Duration) error {
+		waited = append(waited, d)
+		now = now.Add(d)
+		return nil
+	}
+	policy.Queue = true
+	client = GetClient("key", "secret", client.BaseURL, WithMaintenanceWindows(policy))
+	record.Name = "api"
+	_, err = client.Records(domain.ID).Create(ctx, record)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10*time.Hour - 30*time.Minute}, waited)
+	assert.Len(t, fake.recordList(domain.ID), 2)
+}
+
+func TestWithMaintenanceWindowsBulk(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	other := fake.addDomain("example.org")
+	policy := MaintenancePolicy{Domains: map[int][]MaintenanceWindow{domain.ID: {nightly}}}
+	now := jan2024(5, 12)
+	policy.now = func() time.Time { return now }
+	client = GetClient("key", "secret", client.BaseURL, WithMaintenanceWindows(policy))
+	ctx := context.Background()
+	ids := []int{other.ID, domain.ID}
+
+	_, err := client.Domains().UpdateSettingsMulti(ctx, ids, DomainPatch{GtdEnabled: Bool(true)})
+	assert.ErrorIs(t, err, ErrOutsideMaintenanceWindow)
+	assert.ErrorContains(t, err, "domain "+itoa(domain.ID))
+	_, err = client.Domains().SetGTD(ctx, ids, true)
+	assert.ErrorIs(t, err, ErrOutsideMaintenanceWindow)
+	assert.False(t, fake.domains[other.ID].GtdEnabled)
+	_, err = client.Domains().SetGTD(ctx, []int{other.ID}, true)
+	assert.NoError(t, err)
+
+	stubPause(t)
+	var waited []time.Duration
+	pause = func(ctx context.Context, d time.Duration) error {
+		waited = append(waited, d)
+		now = now.Add(d)
+		return nil
+	}
+	policy.Queue = true
+	client = GetClient("key", "secret", client.BaseURL, WithMaintenanceWindows(policy))
+	_, err = client.Domains().SetGTD(ctx, ids, true)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10*time.Hour - 30*time.Minute}, waited)
+	assert.True(t, fake.domains[domain.ID].GtdEnabled)
+
+	// windows that never overlap can't be waited for
+	policy.Domains[other.ID] = []MaintenanceWindow{{Weekdays: []time.Weekday{time.Sunday}, Start: 12 * time.Hour, Duration: time.Hour}}
+	client = GetClient("key", "secret", client.BaseURL, WithMaintenanceWindows(policy))
+	_, err = client.Domains().SetGTD(ctx, ids, false)
+	assert.ErrorIs(t, err, ErrOutsideMaintenanceWindow)
+	assert.ErrorContains(t, err, "never open together")
+}
@@ -0,0 +1,136 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A named set of credentials for one DNS Made Easy account
+type Account struct {
+	Name      string
+	APIKey    string
+	SecretKey string
+	BaseURL   BaseURL
+}
+
+// Holds clients for several accounts, for tooling that manages more than
+// one DNS Made Easy account
+type Manager struct {
+	opts []Option
+
+	mu       sync.Mutex
+	accounts map[string]Account
+	clients  map[string]*Client
+}
+
+// Creates an empty manager; opts are applied to every client it creates
+func NewManager(opts ...Option) *Manager {
+	return &Manager{
+		opts:     opts,
+		accounts: map[string]Account{},
+		clients:  map[string]*Client{},
+	}
+}
+
+// Adds or replaces an account
+func (m *Manager) Add(account Account) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[account.Name] = account
+	delete(m.clients, account.Name)
+}
+
+// Forgets an account
+func (m *Manager) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accounts, name)
+	delete(m.clients, name)
+}
+
+// Returns the names of all accounts in sorted order
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.accounts))
+	for name := range m.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the client for the named account, creating it on first use
+func (m *Manager) Client(name string) (*Client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if client, ok := m.clients[name]; ok {
+		return client, nil
+	}
+	account, ok := m.accounts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown account %q", name)
+	}
+	client := GetClient(account.APIKey, account.SecretKey, account.BaseURL, m.opts...)
+	m.clients[name] = client
+	return client, nil
+}
+
+// Runs fn against every account concurrently, returning the errors of
+// all failed invocations joined together
+func (m *Manager) ForEach(ctx context.Context, fn func(ctx context.Context, account string, c *Client) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, name := range m.Names() {
+		client, err := m.Client(name)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx, name, client); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("account %s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Returns the name of the account that manages the supplied domain, and
+// the domain's ID within it
+func (m *Manager) FindDomain(ctx context.Context, domain string) (string, int, error) {
+	var (
+		mu       sync.Mutex
+		owner    string
+		domainID int
+	)
+	err := m.ForEach(ctx, func(ctx context.Context, account string, c *Client) error {
+		names, err := c.Domains().names(ctx)
+		if err != nil {
+			return err
+		}
+		if id, ok := names[domain]; ok {
+			mu.Lock()
+			owner, domainID = account, id
+			mu.Unlock()
+		}
+		return nil
+	})
+	if owner != "" {
+		return owner, domainID, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return "", 0, fmt.Errorf("domain %s not found in any account: %w", domain, ErrDomainNotFound)
+}
@@ -0,0 +1,34 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager(t *testing.T) {
+	fakeA, clientA := newFakeDME(t)
+	fakeB, clientB := newFakeDME(t)
+	fakeA.addDomain("a.example")
+	b := fakeB.addDomain("b.example")
+
+	m := NewManager()
+	m.Add(Account{"prod", clientA.APIKey, clientA.SecretKey, clientA.BaseURL})
+	m.Add(Account{"customer", clientB.APIKey, clientB.SecretKey, clientB.BaseURL})
+	assert.Equal(t, []string{"customer", "prod"}, m.Names())
+
+	account, id, err := m.FindDomain(context.Background(), "b.example")
+	require.NoError(t, err)
+	assert.Equal(t, "customer", account)
+	assert.Equal(t, b.ID, id)
+
+	_, _, err = m.FindDomain(context.Background(), "c.example")
+	assert.ErrorIs(t, err, ErrDomainNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	m.Remove("customer")
+	_, err = m.Client("customer")
+	assert.Error(t, err)
+}
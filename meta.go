@@ -0,0 +1,101 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const RequestIDHeader string = "x-dnsme-requestId"
+
+// Details of an API response that aren't part of its body
+type ResponseMeta struct {
+	// Quote this when contacting DNS Made Easy support
+	RequestID string
+
+	StatusCode int
+
+	// The quota reported by this response, zero if it carried none
+	RateLimit RateLimit
+
+	Duration time.Duration
+
+	// Bytes of the response body after decompression
+	Size int64
+
+	// Bytes of the response body as received, -1 if unknown
+	TransferSize int64
+}
+
+type responseMetaKey struct{}
+
+// Returns a context that, when passed to a call, records the metadata of
+// the responses received on its behalf. A call that makes several
+// requests leaves the metadata of the last one. The context may be
+// shared by concurrent calls; read the metadata once they have returned.
+func CaptureResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	captured := &capturedMeta{meta: &ResponseMeta{}}
+	return context.WithValue(ctx, responseMetaKey{}, captured), captured.meta
+}
+
+// The destination of a CaptureResponseMeta context, written by every
+// request made with it
+type capturedMeta struct {
+	mu   sync.Mutex
+	meta *ResponseMeta
+}
+
+func (c *capturedMeta) set(meta ResponseMeta) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.meta = meta
+}
+
+type metaRecorder struct {
+	mu   sync.Mutex
+	last ResponseMeta
+}
+
+func responseMetaFrom(resp *resty.Response) ResponseMeta {
+	meta := ResponseMeta{
+		RequestID:  resp.Header().Get(RequestIDHeader),
+		StatusCode: resp.StatusCode(),
+		Duration:   resp.Time(),
+
+		Size:         resp.Size(),
+		TransferSize: transferSize(resp.RawResponse),
+	}
+	limit, err1 := strconv.Atoi(resp.Header().Get(RequestLimitHeader))
+	remaining, err2 := strconv.Atoi(resp.Header().Get(RequestsRemainingHeader))
+	if err1 == nil && err2 == nil {
+		meta.RateLimit = RateLimit{limit, remaining, resp.ReceivedAt()}
+	}
+	return meta
+}
+
+func (m *metaRecorder) observe(_ *resty.Client, resp *resty.Response) error {
+	m.record(resp.Request.Context(), responseMetaFrom(resp))
+	return nil
+}
+
+func (m *metaRecorder) record(ctx context.Context, meta ResponseMeta) {
+	m.mu.Lock()
+	m.last = meta
+	m.mu.Unlock()
+
+	if captured, ok := ctx.Value(responseMetaKey{}).(*capturedMeta); ok {
+		captured.set(meta)
+	}
+}
+
+// Returns the metadata of the most recent response received by the
+// client. Prefer CaptureResponseMeta when the client is shared between
+// goroutines.
+func (c *Client) LastResponseMeta() ResponseMeta {
+	c.meta.mu.Lock()
+	defer c.meta.mu.Unlock()
+	return c.meta.last
+}
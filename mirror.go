@@ -0,0 +1,325 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Serves zones over DNS from copies of their records refreshed every
+// Interval, as an emergency read-only mirror for internal resolvers
+// when the API or DNS Made Easy's nameservers are unreachable. Zone
+// transfers (AXFR and IXFR, always answered in full) let secondaries
+// follow the mirror.
+//
+// Only the DEFAULT Global Traffic Director region is served, and
+// records with no DNS equivalent, such as ANAME and HTTPRED, are left
+// out.
+type ZoneMirror struct {
+	Client *Client
+
+	// Every domain of the account if empty
+	DomainIDs []int
+
+	Interval time.Duration
+
+	// The primary nameserver named in the zones' SOA records; the first
+	// of DefaultNameservers if empty
+	Nameserver string
+
+	// Clients allowed zone transfers; none if empty
+	TransferFrom []netip.Prefix
+
+	// Receives errors from refreshing; they don't stop the mirror, which
+	// keeps serving the last copy of the zones that failed. Ignored if
+	// nil.
+	OnError func(error)
+
+	mu    sync.RWMutex
+	zones map[string]*mirroredZone
+}
+
+// A copy of a zone's records
+type mirroredZone struct {
+	soa *dns.SOA
+
+	// By canonical owner name
+	names map[string][]dns.RR
+
+	// Every record but the SOA, in a stable order, for comparing copies
+	// and transfers
+	records []dns.RR
+
+	synced time.Time
+}
+
+// Refreshes the zones, then again every Interval until ctx is done
+func (m *ZoneMirror) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Refresh(ctx); err != nil && m.OnError != nil {
+			m.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Fetches the zones, replacing the copies of those fetched. A zone's
+// SOA serial is bumped whenever its records change.
+func (m *ZoneMirror) Refresh(ctx context.Context) error {
+	domains, err := m.Client.domainsOrAll(ctx, m.DomainIDs)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, domain := range domains {
+		records, err := m.Client.Records(domain.ID).list(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, err))
+			continue
+		}
+		m.store(domain.Name, records, time.Now())
+	}
+	return errors.Join(errs...)
+}
+
+func (m *ZoneMirror) store(name string, records []Record, now time.Time) {
+	zone := dns.CanonicalName(name)
+	copied := &mirroredZone{names: map[string][]dns.RR{}, synced: now}
+	for _, record := range records {
+		if record.GtdLocation != "" && !strings.EqualFold(record.GtdLocation, GtdDefault) {
+			continue
+		}
+		rr, err := record.RR(zone)
+		if err != nil {
+			continue
+		}
+		owner := dns.CanonicalName(rr.Header().Name)
+		copied.names[owner] = append(copied.names[owner], rr)
+		copied.records = append(copied.records, rr)
+	}
+	sort.Slice(copied.records, func(i, j int) bool { return copied.records[i].String() < copied.records[j].String() })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.zones == nil {
+		m.zones = map[string]*mirroredZone{}
+	}
+	previous := m.zones[zone]
+	serial := uint32(now.Unix())
+	if previous != nil {
+		switch {
+		case sameRRs(previous.records, copied.records):
+			serial = previous.soa.Serial
+		case serial <= previous.soa.Serial:
+			serial = previous.soa.Serial + 1
+		}
+	}
+	copied.soa = m.soa(zone, serial)
+	m.zones[zone] = copied
+}
+
+func (m *ZoneMirror) soa(zone string, serial uint32) *dns.SOA {
+	ns := m.Nameserver
+	if ns == "" {
+		ns = DefaultNameservers[0]
+	}
+	refresh := uint32(m.Interval / time.Second)
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 300},
+		Ns:      dns.Fqdn(ns),
+		Mbox:    "hostmaster." + zone,
+		Serial:  serial,
+		Refresh: refresh,
+		Retry:   max(refresh/4, 60),
+		Expire:  1209600,
+		Minttl:  300,
+	}
+}
+
+func sameRRs(a, b []dns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx].String() != b[idx].String() {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns when each mirrored zone was last fetched, by zone name
+func (m *ZoneMirror) Synced() map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	synced := make(map[string]time.Time, len(m.zones))
+	for name, zone := range m.zones {
+		synced[strings.TrimSuffix(name, ".")] = zone.synced
+	}
+	return synced
+}
+
+// Answers queries for the mirrored zones, implementing dns.Handler.
+// Queries for other zones are refused.
+func (m *ZoneMirror) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	if req.Opcode != dns.OpcodeQuery || len(req.Question) != 1 {
+		resp.SetRcode(req, dns.RcodeNotImplemented)
+		w.WriteMsg(resp)
+		return
+	}
+	q := req.Question[0]
+	qname := dns.CanonicalName(q.Name)
+
+	m.mu.RLock()
+	zone, name := m.zoneFor(qname)
+	m.mu.RUnlock()
+	if zone == nil {
+		resp.SetRcode(req, dns.RcodeRefused)
+		w.WriteMsg(resp)
+		return
+	}
+
+	if q.Qtype == dns.TypeAXFR || q.Qtype == dns.TypeIXFR {
+		if qname != name || !m.transferAllowed(w) {
+			resp.SetRcode(req, dns.RcodeRefused)
+			w.WriteMsg(resp)
+			return
+		}
+		m.transfer(w, req, zone)
+		return
+	}
+
+	resp.Authoritative = true
+	resp.Answer, resp.Rcode = zone.lookup(name, qname, q.Qtype)
+	if len(resp.Answer) == 0 {
+		resp.Ns = []dns.RR{zone.soa}
+	}
+	w.WriteMsg(resp)
+}
+
+// Returns the most specific zone containing qname, and its name
+func (m *ZoneMirror) zoneFor(qname string) (*mirroredZone, string) {
+	for name := qname; ; {
+		if zone, ok := m.zones[name]; ok {
+			return zone, name
+		}
+		offset, end := dns.NextLabel(name, 0)
+		if end {
+			return nil, ""
+		}
+		name = name[offset:]
+	}
+}
+
+// Returns the answer to a query for qname in the zone named name, and
+// its rcode
+func (z *mirroredZone) lookup(name, qname string, qtype uint16) ([]dns.RR, int) {
+	if qname == name && qtype == dns.TypeSOA {
+		return []dns.RR{z.soa}, dns.RcodeSuccess
+	}
+	rrs, ok := z.names[qname]
+	if !ok {
+		if !z.exists(qname) {
+			rrs = z.wildcard(name, qname)
+		}
+		if rrs == nil {
+			if z.exists(qname) {
+				return nil, dns.RcodeSuccess
+			}
+			return nil, dns.RcodeNameError
+		}
+	}
+
+	var answer []dns.RR
+	for _, rr := range rrs {
+		if rr.Header().Rrtype == qtype || qtype == dns.TypeANY || (rr.Header().Rrtype == dns.TypeCNAME && qname != name) {
+			answer = append(answer, rr)
+		}
+	}
+	return answer, dns.RcodeSuccess
+}
+
+// Reports whether qname owns records or is an empty non-terminal, one
+// with records only below it
+func (z *mirroredZone) exists(qname string) bool {
+	if _, ok := z.names[qname]; ok {
+		return true
+	}
+	for owner := range z.names {
+		if dns.IsSubDomain(qname, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the records of the wildcard covering qname, renamed to qname,
+// or nil if none does
+func (z *mirroredZone) wildcard(name, qname string) []dns.RR {
+	for encloser := qname; encloser != name; {
+		offset, _ := dns.NextLabel(encloser, 0)
+		encloser = encloser[offset:]
+		if rrs, ok := z.names["*."+encloser]; ok {
+			renamed := make([]dns.RR, len(rrs))
+			for idx, rr := range rrs {
+				renamed[idx] = dns.Copy(rr)
+				renamed[idx].Header().Name = qname
+			}
+			return renamed
+		}
+		if z.exists(encloser) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *ZoneMirror) transferAllowed(w dns.ResponseWriter) bool {
+	addr, ok := w.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		// transfers need TCP
+		return false
+	}
+	ip, ok := netip.AddrFromSlice(addr.IP)
+	if !ok {
+		return false
+	}
+	for _, prefix := range m.TransferFrom {
+		if prefix.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sends the whole zone, framed by its SOA record, over as many messages
+// as it takes
+func (m *ZoneMirror) transfer(w dns.ResponseWriter, req *dns.Msg, zone *mirroredZone) {
+	const batch = 100
+	rrs := append(append([]dns.RR{zone.soa}, zone.records...), zone.soa)
+	for start := 0; start < len(rrs); start += batch {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+		resp.Answer = rrs[start:min(start+batch, len(rrs))]
+		if err := w.WriteMsg(resp); err != nil {
+			return
+		}
+	}
+}
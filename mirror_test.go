@@ -0,0 +1,128 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the mirror over UDP and TCP on the loopback address
+func serveMirror(t *testing.T, m *ZoneMirror) string {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	udp, err := net.ListenPacket("udp", tcp.Addr().String())
+	require.NoError(t, err)
+	for _, server := range []*dns.Server{{PacketConn: udp, Handler: m}, {Listener: tcp, Handler: m}} {
+		go server.ActivateAndServe()
+		t.Cleanup(func() { server.Shutdown() })
+	}
+	return tcp.Addr().String()
+}
+
+func queryMirror(t *testing.T, addr, name string, qtype uint16) *dns.Msg {
+	t.Helper()
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	resp, _, err := new(dns.Client).Exchange(msg, addr)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestZoneMirror(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "www", Type: "A", Value: "198.51.100.1", Ttl: 300, GtdLocation: "EUROPE"},
+		Record{Name: "app", Type: "CNAME", Value: "www", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "a.b", Type: "TXT", Value: "deep", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "*.dev", Type: "A", Value: "192.0.2.9", Ttl: 60, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "ANAME", Value: "lb.example.net.", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	m := &ZoneMirror{Client: client, Interval: 5 * time.Minute}
+	require.NoError(t, m.Refresh(context.Background()))
+	addr := serveMirror(t, m)
+
+	resp := queryMirror(t, addr, "WWW.example.com.", dns.TypeA)
+	assert.True(t, resp.Authoritative)
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, "192.0.2.1", resp.Answer[0].(*dns.A).A.String())
+
+	resp = queryMirror(t, addr, "app.example.com.", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, "www.example.com.", resp.Answer[0].(*dns.CNAME).Target)
+
+	resp = queryMirror(t, addr, "x.dev.example.com.", dns.TypeA)
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, "x.dev.example.com.", resp.Answer[0].Header().Name)
+
+	// an empty non-terminal exists, a name without records doesn't
+	resp = queryMirror(t, addr, "b.example.com.", dns.TypeA)
+	assert.Equal(t, dns.RcodeSuccess, resp.Rcode)
+	assert.Empty(t, resp.Answer)
+	require.Len(t, resp.Ns, 1)
+	resp = queryMirror(t, addr, "missing.example.com.", dns.TypeA)
+	assert.Equal(t, dns.RcodeNameError, resp.Rcode)
+
+	resp = queryMirror(t, addr, "example.org.", dns.TypeA)
+	assert.Equal(t, dns.RcodeRefused, resp.Rcode)
+
+	resp = queryMirror(t, addr, "example.com.", dns.TypeSOA)
+	require.Len(t, resp.Answer, 1)
+	serial := resp.Answer[0].(*dns.SOA).Serial
+	assert.Contains(t, m.Synced(), "example.com")
+
+	// unchanged zones keep their serial; a failed refresh keeps the copy
+	require.NoError(t, m.Refresh(context.Background()))
+	resp = queryMirror(t, addr, "example.com.", dns.TypeSOA)
+	assert.Equal(t, serial, resp.Answer[0].(*dns.SOA).Serial)
+	fake.fail = func(r *http.Request) bool { return true }
+	fake.failStatus = http.StatusServiceUnavailable
+	require.Error(t, m.Refresh(context.Background()))
+	resp = queryMirror(t, addr, "www.example.com.", dns.TypeA)
+	assert.Len(t, resp.Answer, 1)
+}
+
+func TestZoneMirrorTransfer(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "MX", Value: "mail", MxLevel: 10, Ttl: 300, GtdLocation: GtdDefault},
+	)
+	m := &ZoneMirror{Client: client, Interval: 5 * time.Minute}
+	require.NoError(t, m.Refresh(context.Background()))
+	addr := serveMirror(t, m)
+
+	transfer := func() ([]dns.RR, error) {
+		msg := new(dns.Msg)
+		msg.SetAxfr("example.com.")
+		envelopes, err := new(dns.Transfer).In(msg, addr)
+		if err != nil {
+			return nil, err
+		}
+		var rrs []dns.RR
+		for envelope := range envelopes {
+			if envelope.Error != nil {
+				return nil, envelope.Error
+			}
+			rrs = append(rrs, envelope.RR...)
+		}
+		return rrs, nil
+	}
+
+	_, err := transfer()
+	assert.Error(t, err, "transfers are refused unless allowed")
+
+	m.TransferFrom = []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}
+	rrs, err := transfer()
+	require.NoError(t, err)
+	require.Len(t, rrs, 4)
+	assert.Equal(t, dns.TypeSOA, rrs[0].Header().Rrtype)
+	assert.Equal(t, dns.TypeSOA, rrs[3].Header().Rrtype)
+}
@@ -0,0 +1,202 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const MonitorPath string = "/monitor/"
+
+// System monitoring and DNS failover configuration for an A record
+type Monitor struct {
+	RecordID int `json:"recordId,omitempty"`
+
+	// Indicates if System Monitoring is enabled
+	Monitor bool `json:"monitor"`
+
+	// Indicates if DNS Failover is enabled
+	Failover bool `json:"failover"`
+
+	// Return to the primary IP once it is back up
+	AutoFailover bool `json:"autoFailover"`
+
+	// The number of checks that must fail before failing over
+	Sensitivity Sensitivity `json:"sensitivity"`
+
+	// The protocol checked
+	ProtocolID Protocol `json:"protocolId"`
+
+	// The port checked
+	Port int `json:"port"`
+
+	// The failover IP addresses in order of preference. IP1 is the
+	// primary and matches the record's value.
+	IP1 string `json:"ip1,omitempty"`
+	IP2 string `json:"ip2,omitempty"`
+	IP3 string `json:"ip3,omitempty"`
+	IP4 string `json:"ip4,omitempty"`
+	IP5 string `json:"ip5,omitempty"`
+
+	// Notification settings
+	MaxEmails         int    `json:"maxEmails,omitempty"`
+	ContactListID     int    `json:"contactListId,omitempty"`
+	SystemDescription string `json:"systemDescription,omitempty"`
+
+	// For HTTP(S) checks
+	HttpFqdn        string `json:"httpFqdn,omitempty"`
+	HttpFile        string `json:"httpFile,omitempty"`
+	HttpQueryString string `json:"httpQueryString,omitempty"`
+
+	// The domain ID of the record
+	SourceID int `json:"sourceId,omitempty"`
+}
+
+// Operations on record monitoring and failover
+type MonitorsService struct {
+	client *Client
+}
+
+// Returns the service for managing monitors
+func (c *Client) Monitors() *MonitorsService {
+	return &MonitorsService{c}
+}
+
+// Returns the monitor configuration of the supplied record
+func (s *MonitorsService) Get(ctx context.Context, recordID int) (Monitor, error) {
+	var monitor Monitor
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetResult(&monitor).
+		Get(MonitorPath + fmt.Sprint(recordID)))
+	if err != nil {
+		return Monitor{}, err
+	}
+	return monitor, nil
+}
+
+// Replaces the monitor configuration of the supplied record. The
+// protocol's default port is filled in when Port is zero, and the
+// configuration is validated before it is sent.
+func (s *MonitorsService) Update(ctx context.Context, recordID int, monitor Monitor) error {
+	if monitor.Port == 0 {
+		monitor.Port = monitor.ProtocolID.DefaultPort()
+	}
+	if err := monitor.Validate(); err != nil {
+		return err
+	}
+
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetBody(&monitor).
+		Put(MonitorPath + fmt.Sprint(recordID)))
+	return err
+}
+
+// The protocol used to check a monitored record
+type Protocol int
+
+const (
+	ProtocolTCP   Protocol = 1
+	ProtocolUDP   Protocol = 2
+	ProtocolHTTP  Protocol = 3
+	ProtocolDNS   Protocol = 4
+	ProtocolSMTP  Protocol = 5
+	ProtocolHTTPS Protocol = 6
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolTCP:
+		return "TCP"
+	case ProtocolUDP:
+		return "UDP"
+	case ProtocolHTTP:
+		return "HTTP"
+	case ProtocolDNS:
+		return "DNS"
+	case ProtocolSMTP:
+		return "SMTP"
+	case ProtocolHTTPS:
+		return "HTTPS"
+	}
+	return fmt.Sprintf("Protocol(%d)", int(p))
+}
+
+// Returns the well known port for the protocol, or 0 for TCP and UDP
+// which have none
+func (p Protocol) DefaultPort() int {
+	switch p {
+	case ProtocolHTTP:
+		return 80
+	case ProtocolDNS:
+		return 53
+	case ProtocolSMTP:
+		return 25
+	case ProtocolHTTPS:
+		return 443
+	}
+	return 0
+}
+
+func (p Protocol) valid() bool {
+	return p >= ProtocolTCP && p <= ProtocolHTTPS
+}
+
+// How many consecutive checks must fail before a record fails over
+type Sensitivity int
+
+const (
+	SensitivityHigh   Sensitivity = 3
+	SensitivityMedium Sensitivity = 5
+	SensitivityLow    Sensitivity = 8
+)
+
+func (s Sensitivity) String() string {
+	switch s {
+	case SensitivityHigh:
+		return "high"
+	case SensitivityMedium:
+		return "medium"
+	case SensitivityLow:
+		return "low"
+	}
+	return fmt.Sprintf("Sensitivity(%d)", int(s))
+}
+
+// Checks the configuration locally so mistakes are caught before the
+// API rejects them
+func (m Monitor) Validate() error {
+	var errs []error
+	if !m.Monitor && !m.Failover {
+		// disabling needs nothing else
+		return nil
+	}
+
+	if !m.ProtocolID.valid() {
+		errs = append(errs, fmt.Errorf("unknown protocol %d", int(m.ProtocolID)))
+	}
+	switch m.Sensitivity {
+	case SensitivityHigh, SensitivityMedium, SensitivityLow:
+	default:
+		errs = append(errs, fmt.Errorf("sensitivity must be %d, %d or %d, got %d",
+			SensitivityHigh, SensitivityMedium, SensitivityLow, int(m.Sensitivity)))
+	}
+	if m.Port < 1 || m.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d out of range", m.Port))
+	}
+	if m.ProtocolID != ProtocolHTTP && m.ProtocolID != ProtocolHTTPS &&
+		(m.HttpFqdn != "" || m.HttpFile != "" || m.HttpQueryString != "") {
+		errs = append(errs, fmt.Errorf("HTTP settings require the HTTP or HTTPS protocol, not %s", m.ProtocolID))
+	}
+
+	for idx, ip := range []string{m.IP1, m.IP2, m.IP3, m.IP4, m.IP5} {
+		if ip != "" && net.ParseIP(ip).To4() == nil {
+			errs = append(errs, fmt.Errorf("ip%d %q is not an IPv4 address", idx+1, ip))
+		}
+	}
+	if m.Failover && (m.IP1 == "" || m.IP2 == "") {
+		errs = append(errs, errors.New("failover requires at least ip1 and ip2"))
+	}
+
+	return errors.Join(errs...)
+}
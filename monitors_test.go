@@ -0,0 +1,30 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorValidate(t *testing.T) {
+	valid := Monitor{Monitor: true, Failover: true, ProtocolID: ProtocolHTTPS, Port: 443,
+		Sensitivity: SensitivityHigh, IP1: "192.0.2.1", IP2: "192.0.2.2", HttpFqdn: "www.example.com"}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, Monitor{}.Validate())
+
+	for name, mutate := range map[string]func(m *Monitor){
+		"protocol":    func(m *Monitor) { m.ProtocolID = 9 },
+		"sensitivity": func(m *Monitor) { m.Sensitivity = 4 },
+		"port":        func(m *Monitor) { m.Port = 0 },
+		"http fields": func(m *Monitor) { m.ProtocolID = ProtocolTCP },
+		"ipv6":        func(m *Monitor) { m.IP3 = "2001:db8::1" },
+		"single ip":   func(m *Monitor) { m.IP2 = "" },
+	} {
+		m := valid
+		mutate(&m)
+		assert.Error(t, m.Validate(), name)
+	}
+
+	assert.Equal(t, 53, ProtocolDNS.DefaultPort())
+	assert.Equal(t, "SMTP", ProtocolSMTP.String())
+}
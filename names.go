@@ -0,0 +1,37 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Returns the hostname as a record name relative to zone, as DNS Made
+// Easy stores it: "www" for www.example.com in example.com, and "" for
+// the apex. Either name may be fully qualified with a trailing dot, and
+// case is ignored.
+func RelativeName(fqdn, zone string) (string, error) {
+	host := canonicalName(fqdn)
+	zone = canonicalName(zone)
+	if host == zone {
+		return "", nil
+	}
+	if name, ok := strings.CutSuffix(host, "."+zone); ok && name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("%s is not in zone %s", host, zone)
+}
+
+// Returns the fully qualified hostname, with a trailing dot, for a
+// record name relative to zone ("" being the apex)
+func AbsoluteName(name, zone string) string {
+	zone = canonicalName(zone) + "."
+	if name == "" || name == "@" {
+		return zone
+	}
+	return name + "." + zone
+}
+
+// Lowercases a hostname and drops its trailing dot
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,39 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelativeName(t *testing.T) {
+	for fqdn, want := range map[string]string{
+		"www.example.com":         "www",
+		"WWW.Example.COM.":        "www",
+		"a.b.example.com":         "a.b",
+		"example.com.":            "",
+		"*.example.com":           "*",
+		"_dmarc.example.com.":     "_dmarc",
+		"example.com.example.com": "example.com",
+	} {
+		name, err := RelativeName(fqdn, "example.com.")
+		require.NoError(t, err, fqdn)
+		assert.Equal(t, want, name, fqdn)
+	}
+
+	_, err := RelativeName("www.notexample.com", "example.com")
+	assert.EqualError(t, err, "www.notexample.com is not in zone example.com")
+	_, err = RelativeName("com", "example.com")
+	assert.Error(t, err)
+}
+
+func TestAbsoluteName(t *testing.T) {
+	assert.Equal(t, "www.example.com.", AbsoluteName("www", "example.com"))
+	assert.Equal(t, "example.com.", AbsoluteName("", "Example.com."))
+	assert.Equal(t, "example.com.", AbsoluteName("@", "example.com"))
+
+	name, err := RelativeName(AbsoluteName("a.b", "example.com"), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "a.b", name)
+}
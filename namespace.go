@@ -0,0 +1,179 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned by a NamespacedClient for records outside its prefix
+var ErrOutsideNamespace = errors.New("record is outside the namespace")
+
+// A view of the account's records confined to the names under a prefix,
+// so several teams can share a zone. Names passed to and returned from
+// it are relative to the prefix: "www" in the namespace "team-a" is the
+// record www.team-a, and "" is team-a itself.
+type NamespacedClient struct {
+	client *Client
+	prefix string
+}
+
+// Returns a client scoped to the record names under prefix
+func (c *Client) NamespacedClient(prefix string) *NamespacedClient {
+	return &NamespacedClient{c, strings.ToLower(strings.Trim(prefix, "."))}
+}
+
+// Returns the namespace's prefix
+func (n *NamespacedClient) Prefix() string {
+	return n.prefix
+}
+
+// Returns the full record name of name within the namespace
+func (n *NamespacedClient) Qualify(name string) string {
+	if name == "" || name == "@" {
+		return n.prefix
+	}
+	return strings.TrimSuffix(name, ".") + "." + n.prefix
+}
+
+// Returns name relative to the namespace, or false if it lies outside
+func (n *NamespacedClient) Relative(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if name == n.prefix {
+		return "", true
+	}
+	if rel, ok := strings.CutSuffix(name, "."+n.prefix); ok {
+		return rel, true
+	}
+	return "", false
+}
+
+// Record operations within the namespace for a single domain
+type NamespacedRecords struct {
+	ns      *NamespacedClient
+	records *RecordsService
+}
+
+// Returns the namespace's records in the domain
+func (n *NamespacedClient) Records(domainID int) *NamespacedRecords {
+	return &NamespacedRecords{n, n.client.Records(domainID)}
+}
+
+// Returns the records in the namespace, named relative to it
+func (s *NamespacedRecords) List(ctx context.Context) ([]Record, error) {
+	all, err := s.records.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for _, record := range all {
+		if rel, ok := s.ns.Relative(record.Name); ok {
+			record.Name = rel
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// Creates a record named relative to the namespace
+func (s *NamespacedRecords) Create(ctx context.Context, record Record) (Record, error) {
+	record.Name = s.ns.Qualify(record.Name)
+	created, err := s.records.Create(ctx, record)
+	if err != nil {
+		return Record{}, err
+	}
+	return s.relative(created), nil
+}
+
+// Creates records named relative to the namespace, as
+// RecordsService.CreateMulti
+func (s *NamespacedRecords) CreateMulti(ctx context.Context, records []Record) ([]Record, error) {
+	qualified := make([]Record, len(records))
+	for idx, record := range records {
+		record.Name = s.ns.Qualify(record.Name)
+		qualified[idx] = record
+	}
+	created, err := s.records.CreateMulti(ctx, qualified)
+	for idx := range created {
+		created[idx] = s.relative(created[idx])
+	}
+	return created, err
+}
+
+// Updates a record in the namespace, refusing records that currently
+// lie outside it
+func (s *NamespacedRecords) Update(ctx context.Context, record Record) error {
+	if err := s.check(ctx, record.ID); err != nil {
+		return err
+	}
+	record.Name = s.ns.Qualify(record.Name)
+	return s.records.Update(ctx, record)
+}
+
+// Updates records in the namespace, as RecordsService.UpdateMulti. No
+// record is updated if any lies outside the namespace.
+func (s *NamespacedRecords) UpdateMulti(ctx context.Context, records []Record) ([]Record, error) {
+	ids := make([]int, len(records))
+	qualified := make([]Record, len(records))
+	for idx, record := range records {
+		ids[idx] = record.ID
+		record.Name = s.ns.Qualify(record.Name)
+		qualified[idx] = record
+	}
+	if err := s.check(ctx, ids...); err != nil {
+		return nil, err
+	}
+	updated, err := s.records.UpdateMulti(ctx, qualified)
+	for idx := range updated {
+		updated[idx] = s.relative(updated[idx])
+	}
+	return updated, err
+}
+
+// Deletes a record in the namespace
+func (s *NamespacedRecords) Delete(ctx context.Context, recordID int) error {
+	if err := s.check(ctx, recordID); err != nil {
+		return err
+	}
+	return s.records.Delete(ctx, recordID)
+}
+
+// Deletes records in the namespace, as RecordsService.DeleteMulti. No
+// record is deleted if any lies outside the namespace.
+func (s *NamespacedRecords) DeleteMulti(ctx context.Context, recordIDs []int) ([]int, error) {
+	if err := s.check(ctx, recordIDs...); err != nil {
+		return nil, err
+	}
+	return s.records.DeleteMulti(ctx, recordIDs)
+}
+
+// Returns ErrOutsideNamespace unless every record ID names a record in
+// the namespace
+func (s *NamespacedRecords) check(ctx context.Context, recordIDs ...int) error {
+	all, err := s.records.List(ctx)
+	if err != nil {
+		return err
+	}
+	names := map[int]string{}
+	for _, record := range all {
+		names[record.ID] = record.Name
+	}
+	for _, id := range recordIDs {
+		name, ok := names[id]
+		if !ok {
+			return fmt.Errorf("record %d: %w", id, ErrNotFound)
+		}
+		if _, ok := s.ns.Relative(name); !ok {
+			return fmt.Errorf("record %d (%s): %w", id, name, ErrOutsideNamespace)
+		}
+	}
+	return nil
+}
+
+func (s *NamespacedRecords) relative(record Record) Record {
+	if rel, ok := s.ns.Relative(record.Name); ok {
+		record.Name = rel
+	}
+	return record
+}
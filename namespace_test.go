@@ -0,0 +1,52 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacedClient(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "team-a", Type: "A", Value: "192.0.2.1"},
+		Record{Name: "api.team-a", Type: "A", Value: "192.0.2.2"},
+		Record{Name: "api.team-b", Type: "A", Value: "192.0.2.3"},
+		Record{Name: "xteam-a", Type: "A", Value: "192.0.2.4"},
+	)
+	ctx := context.Background()
+	ns := client.NamespacedClient("Team-A.")
+	assert.Equal(t, "team-a", ns.Prefix())
+	records := ns.Records(domain.ID)
+
+	list, err := records.List(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, record := range list {
+		names = append(names, record.Name)
+	}
+	assert.ElementsMatch(t, []string{"", "api"}, names)
+
+	created, err := records.Create(ctx, Record{Name: "www", Type: "CNAME", Value: "api"})
+	require.NoError(t, err)
+	assert.Equal(t, "www", created.Name)
+
+	byName := map[string]Record{}
+	for _, record := range fake.recordList(domain.ID) {
+		byName[record.Name] = record
+	}
+	assert.Contains(t, byName, "www.team-a")
+
+	other := byName["api.team-b"]
+	assert.ErrorIs(t, records.Delete(ctx, other.ID), ErrOutsideNamespace)
+	other.Name = "api"
+	assert.ErrorIs(t, records.Update(ctx, other), ErrOutsideNamespace)
+	_, err = records.DeleteMulti(ctx, []int{created.ID, byName["xteam-a"].ID})
+	assert.ErrorIs(t, err, ErrOutsideNamespace)
+	assert.ErrorIs(t, records.Delete(ctx, 99999), ErrNotFound)
+
+	require.NoError(t, records.Delete(ctx, created.ID))
+	assert.Len(t, fake.recordList(domain.ID), 4)
+}
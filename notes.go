@@ -0,0 +1,76 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Keeps record notes in store, as the Note of each name and type's
+// annotation. Listed records carry their notes, and records created or
+// updated with a Note store it; clearing a note takes SetNote. DNS Made
+// Easy has no notes of its own, so TXTAnnotations or FileAnnotations
+// hold them.
+func WithNotes(store AnnotationStore) Option {
+	return func(c *Client) {
+		c.notes = store
+	}
+}
+
+// Sets the note of the records of one name and type, keeping the rest
+// of their annotation. An empty note removes it.
+func SetNote(ctx context.Context, store AnnotationStore, domainID int, name, recordType, note string) error {
+	a, _, err := store.Get(ctx, domainID, name, recordType)
+	if err != nil {
+		return err
+	}
+	if a.Note == note {
+		return nil
+	}
+	a.Note = note
+	return store.Set(ctx, domainID, RecordAnnotation{name, recordType, a})
+}
+
+// Fills in the notes of records when WithNotes is set
+func (c *Client) attachNotes(ctx context.Context, domainID int, records []Record) ([]Record, error) {
+	if c.notes == nil {
+		return records, nil
+	}
+	all, err := c.notes.List(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("loading notes: %w", err)
+	}
+	notes := map[string]string{}
+	for _, a := range all {
+		notes[noteKey(a.Name, a.Type)] = a.Note
+	}
+	for idx := range records {
+		records[idx].Note = notes[noteKey(records[idx].Name, records[idx].Type)]
+	}
+	return records, nil
+}
+
+// Stores the notes of records that have one when WithNotes is set
+func (c *Client) saveNotes(ctx context.Context, domainID int, records ...Record) error {
+	if c.notes == nil {
+		return nil
+	}
+	var errs []error
+	seen := map[string]bool{}
+	for _, record := range records {
+		key := noteKey(record.Name, record.Type)
+		if record.Note == "" || seen[key] || strings.HasPrefix(record.Name, AnnotationLabel) {
+			continue
+		}
+		seen[key] = true
+		if err := SetNote(ctx, c.notes, domainID, record.Name, record.Type, record.Note); err != nil {
+			errs = append(errs, fmt.Errorf("saving note of %s %s: %w", record.Type, record.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func noteKey(name, recordType string) string {
+	return name + "/" + strings.ToUpper(recordType)
+}
@@ -0,0 +1,85 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotesInCompanionTXT(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client.notes = TXTAnnotations{Client: client}
+	ctx := context.Background()
+
+	created, err := client.Records(domain.ID).Create(ctx, Record{
+		Name: "legacy", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault,
+		Note: "kept for the 2019 billing integration",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kept for the 2019 billing integration", created.Note)
+
+	stored := fake.sortedRecords(domain.ID)
+	require.Len(t, stored, 2)
+	for _, record := range stored {
+		assert.Empty(t, record.Note, "notes aren't sent to the API")
+	}
+	assert.Equal(t, "TXT", stored[1].Type)
+	assert.Equal(t, AnnotationName("legacy"), stored[1].Name)
+
+	records, err := client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	notes := map[string]string{}
+	for _, record := range records {
+		notes[record.Name] = record.Note
+	}
+	assert.Equal(t, map[string]string{"legacy": "kept for the 2019 billing integration", AnnotationName("legacy"): ""}, notes)
+
+	created.Note = "remove after March"
+	require.NoError(t, client.Records(domain.ID).Update(ctx, created))
+	a, ok, err := client.notes.Get(ctx, domain.ID, "legacy", "A")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "remove after March", a.Note)
+
+	require.NoError(t, SetNote(ctx, client.notes, domain.ID, "legacy", "A", ""))
+	assert.Len(t, fake.recordList(domain.ID), 1, "clearing the only annotation removes the companion record")
+}
+
+func TestNotesKeepAnnotation(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	store := &FileAnnotations{Path: filepath.Join(t.TempDir(), "annotations.json")}
+	client.notes = store
+	client.recordDefaults = RecordDefaults{Annotation: Annotation{Owner: "alice"}, Annotations: store}
+	ctx := context.Background()
+
+	_, err := client.Records(domain.ID).CreateMulti(ctx, []Record{
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault, Note: "marketing site"},
+		{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdDefault},
+	})
+	require.NoError(t, err)
+
+	a, ok, err := store.Get(ctx, domain.ID, "www", "A")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Annotation{Owner: "alice", Note: "marketing site"}, a)
+	a, _, err = store.Get(ctx, domain.ID, "api", "A")
+	require.NoError(t, err)
+	assert.Equal(t, Annotation{Owner: "alice"}, a)
+
+	// notes don't change a zone's fingerprint
+	records, err := client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, RecordsFingerprint(fake.recordList(domain.ID)), RecordsFingerprint(records))
+}
+
+func TestParseAnnotationNote(t *testing.T) {
+	a := RecordAnnotation{Name: "www", Type: "A", Annotation: Annotation{Team: "web", Note: "points at the old CDN; see INC-42"}}
+	parsed, ok := ParseAnnotation(a.txt())
+	require.True(t, ok)
+	assert.Equal(t, a, parsed)
+}
@@ -0,0 +1,76 @@
+package dnsmadeeasy
+
+import (
+	"errors"
+	"time"
+)
+
+// Makes deleting a domain or record that no longer exists succeed, so
+// retried and repeated deletes are harmless
+func WithIdempotentDeletes() Option {
+	return func(c *Client) {
+		c.idempotentDeletes = true
+	}
+}
+
+// Filters the error of a delete, discarding "not found" when deletes are
+// idempotent
+func (c *Client) deleted(err error) error {
+	if c.idempotentDeletes && errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Returned by IdForDomain when no domain has the name
+var ErrDomainNotFound error = notFoundError("Domain not found")
+
+// A not found error raised by the client rather than the API
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e)
+}
+
+func (e notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// Makes IdForDomain remember names it didn't find for ttl, answering
+// ErrDomainNotFound without enumerating the account's domains again
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.negativeTTL = ttl
+		c.misses = map[string]time.Time{}
+	}
+}
+
+// Reports whether domain was recently looked up and not found
+func (c *Client) recentMiss(domain string) bool {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	if c.misses == nil {
+		return false
+	}
+	missed, ok := c.misses[domain]
+	if ok && time.Since(missed) >= c.negativeTTL {
+		delete(c.misses, domain)
+		return false
+	}
+	return ok
+}
+
+func (c *Client) recordMiss(domain string) {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	if c.misses != nil {
+		c.misses[domain] = time.Now()
+	}
+}
+
+// Forgets that domain wasn't found, once it has been created
+func (c *Client) forgetMiss(domain string) {
+	c.domainsMu.Lock()
+	defer c.domainsMu.Unlock()
+	delete(c.misses, domain)
+}
@@ -0,0 +1,86 @@
+package dnsmadeeasy
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotFound(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.limit, fake.remaining = 150, 150
+	domain := fake.addDomain("example.com")
+
+	_, err := client.GetDomain(1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "Domain not found")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 404, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.RequestID)
+
+	assert.ErrorIs(t, client.DeleteRecord(domain.ID, 1), ErrNotFound)
+	assert.ErrorIs(t, client.DeleteDomain(1), ErrNotFound)
+
+	client = GetClient("key", "secret", client.BaseURL, WithIdempotentDeletes())
+	assert.NoError(t, client.DeleteRecord(domain.ID, 1))
+	assert.NoError(t, client.DeleteDomain(domain.ID))
+	assert.NoError(t, client.DeleteDomain(domain.ID))
+
+	// other failures still surface
+	fake.fail = func(*http.Request) bool { return true }
+	assert.Error(t, client.DeleteDomain(domain.ID))
+}
+
+func TestAPIErrorMessages(t *testing.T) {
+	assert.EqualError(t, &APIError{StatusCode: 500}, "request returned http error code 500")
+	assert.EqualError(t, &APIError{StatusCode: 400, Messages: []string{"a", "b"}}, "0: a\n1: b\n")
+	assert.NotErrorIs(t, &APIError{StatusCode: 400}, ErrNotFound)
+}
+
+func TestNegativeCache(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com")
+	client = GetClient("key", "secret", client.BaseURL, WithNegativeCache(time.Hour))
+	listPath := "GET /dns/managed/"
+
+	_, err := client.IdForDomain("missing.com")
+	assert.ErrorIs(t, err, ErrDomainNotFound)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "Domain not found")
+	assert.Equal(t, 1, fake.calls[listPath])
+
+	for range 3 {
+		_, err = client.IdForDomain("missing.com")
+		assert.ErrorIs(t, err, ErrDomainNotFound)
+	}
+	assert.Equal(t, 1, fake.calls[listPath])
+
+	// other names still refresh on a miss
+	fake.addDomain("example.org")
+	_, err = client.IdForDomain("example.org")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.calls[listPath])
+
+	client.misses["missing.com"] = time.Now().Add(-2 * time.Hour)
+	fake.addDomain("missing.com")
+	_, err = client.IdForDomain("missing.com")
+	require.NoError(t, err)
+}
+
+func TestNegativeCacheForgetsCreated(t *testing.T) {
+	_, client := newFakeDME(t)
+	client = GetClient("key", "secret", client.BaseURL, WithNegativeCache(time.Hour))
+
+	_, err := client.IdForDomain("example.com")
+	require.ErrorIs(t, err, ErrDomainNotFound)
+	domain, err := client.CreateDomain("example.com")
+	require.NoError(t, err)
+	id, err := client.IdForDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ID, id)
+}
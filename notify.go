@@ -0,0 +1,185 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// What an Event reports
+type EventKind string
+
+const (
+	// Changes were applied to bring a zone to its desired state
+	EventSyncApplied EventKind = "sync.applied"
+
+	// A zone could not be brought to its desired state
+	EventSyncFailed EventKind = "sync.failed"
+
+	// A zone changed outside of this program
+	EventDriftDetected EventKind = "drift.detected"
+)
+
+// A change to a zone, as delivered to notifiers
+type Event struct {
+	Kind     EventKind `json:"kind"`
+	Time     time.Time `json:"time"`
+	DomainID int       `json:"domainId"`
+	Domain   string    `json:"domain,omitempty"`
+
+	Created []Record `json:"created,omitempty"`
+	Updated []Record `json:"updated,omitempty"`
+	Deleted []Record `json:"deleted,omitempty"`
+
+	// Why a sync failed
+	Error string `json:"error,omitempty"`
+}
+
+// Returns a one line, human readable description of the event
+func (e Event) Summary() string {
+	zone := e.Domain
+	if zone == "" {
+		zone = fmt.Sprintf("domain %d", e.DomainID)
+	}
+	counts := fmt.Sprintf("%d created, %d updated, %d deleted", len(e.Created), len(e.Updated), len(e.Deleted))
+	switch e.Kind {
+	case EventSyncApplied:
+		return fmt.Sprintf("%s synced: %s", zone, counts)
+	case EventSyncFailed:
+		return fmt.Sprintf("%s sync failed: %s", zone, e.Error)
+	case EventDriftDetected:
+		return fmt.Sprintf("%s drifted: %s", zone, counts)
+	}
+	return fmt.Sprintf("%s %s: %s", zone, e.Kind, counts)
+}
+
+// Receives zone change events, from the reconciler or a drift watcher
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, event Event) error
+
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// Delivers events to every notifier, returning all of their errors
+type Notifiers []Notifier
+
+func (n Notifiers) Notify(ctx context.Context, event Event) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// POSTs each event as JSON to a URL
+type WebhookNotifier struct {
+	URL string
+
+	// Added to every request, for authentication
+	Header http.Header
+
+	// http.DefaultClient if nil
+	Client *http.Client
+}
+
+func (n WebhookNotifier) Notify(ctx context.Context, event Event) error {
+	return postJSON(ctx, n.Client, n.URL, n.Header, event)
+}
+
+// Posts a message for each event to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+
+	// Overrides the webhook's default channel when set
+	Channel string
+
+	// http.DefaultClient if nil
+	Client *http.Client
+}
+
+func (n SlackNotifier) Notify(ctx context.Context, event Event) error {
+	var text strings.Builder
+	text.WriteString(event.Summary())
+	for _, change := range []struct {
+		prefix  string
+		records []Record
+	}{{"+", event.Created}, {"~", event.Updated}, {"-", event.Deleted}} {
+		for _, record := range change.records {
+			fmt.Fprintf(&text, "\n%s %s %s %s", change.prefix, AbsoluteName(record.Name, event.Domain), record.Type, record.Value)
+		}
+	}
+	msg := map[string]string{"text": text.String()}
+	if n.Channel != "" {
+		msg["channel"] = n.Channel
+	}
+	return postJSON(ctx, n.Client, n.WebhookURL, nil, msg)
+}
+
+var sendMail = smtp.SendMail
+
+// Emails each event through an SMTP server
+type EmailNotifier struct {
+	// host:port of the SMTP server
+	Addr string
+
+	// nil to send unauthenticated
+	Auth smtp.Auth
+
+	From string
+	To   []string
+}
+
+func (n EmailNotifier) Notify(ctx context.Context, event Event) error {
+	body, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return err
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [dme] %s\r\n", event.Summary())
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.Write(body)
+	msg.WriteString("\r\n")
+	return sendMail(n.Addr, n.Auth, n.From, n.To, msg.Bytes())
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify %s: %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = Event{
+	Kind:     EventDriftDetected,
+	Time:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	DomainID: 7,
+	Domain:   "example.com",
+	Created:  []Record{{ID: 1, Name: "www", Type: "A", Value: "192.0.2.1"}},
+	Deleted:  []Record{{ID: 2, Name: "", Type: "MX", Value: "mail"}},
+}
+
+// Starts a server recording the body and headers of the last request
+func recordingServer(t *testing.T, status int) (*httptest.Server, *[]byte, *http.Header) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &body, &header
+}
+
+func TestEventSummary(t *testing.T) {
+	assert.Equal(t, "example.com drifted: 1 created, 0 updated, 1 deleted", testEvent.Summary())
+	assert.Equal(t, "domain 3 sync failed: boom", Event{Kind: EventSyncFailed, DomainID: 3, Error: "boom"}.Summary())
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, body, header := recordingServer(t, http.StatusNoContent)
+	n := WebhookNotifier{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	require.NoError(t, n.Notify(context.Background(), testEvent))
+
+	var got Event
+	require.NoError(t, json.Unmarshal(*body, &got))
+	assert.Equal(t, testEvent, got)
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	failing, _, _ := recordingServer(t, http.StatusInternalServerError)
+	assert.ErrorContains(t, WebhookNotifier{URL: failing.URL}.Notify(context.Background(), testEvent), "500")
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, body, _ := recordingServer(t, http.StatusOK)
+	n := SlackNotifier{WebhookURL: server.URL, Channel: "#dns"}
+	require.NoError(t, n.Notify(context.Background(), testEvent))
+	assert.JSONEq(t, `{"channel":"#dns","text":"example.com drifted: 1 created, 0 updated, 1 deleted\n+ www.example.com. A 192.0.2.1\n- example.com. MX mail"}`, string(*body))
+}
+
+func TestEmailNotifier(t *testing.T) {
+	var to []string
+	var msg string
+	sendMail = func(addr string, a smtp.Auth, from string, rcpt []string, m []byte) error {
+		to, msg = rcpt, string(m)
+		return nil
+	}
+	t.Cleanup(func() { sendMail = smtp.SendMail })
+
+	n := EmailNotifier{Addr: "localhost:25", From: "dme@example.com", To: []string{"ops@example.com"}}
+	require.NoError(t, n.Notify(context.Background(), testEvent))
+	assert.Equal(t, []string{"ops@example.com"}, to)
+	assert.Contains(t, msg, "Subject: [dme] example.com drifted: 1 created, 0 updated, 1 deleted\r\n")
+	assert.Contains(t, msg, `"kind": "drift.detected"`)
+}
+
+func TestNotifiers(t *testing.T) {
+	var got []EventKind
+	ok := NotifierFunc(func(ctx context.Context, e Event) error {
+		got = append(got, e.Kind)
+		return nil
+	})
+	failing := NotifierFunc(func(ctx context.Context, e Event) error { return errors.New("down") })
+
+	err := Notifiers{failing, ok}.Notify(context.Background(), testEvent)
+	assert.EqualError(t, err, "down")
+	assert.Equal(t, []EventKind{EventDriftDetected}, got)
+}
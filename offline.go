@@ -0,0 +1,200 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The account's domains as last read from the API
+type DomainsSnapshot struct {
+	Domains []Domain  `json:"domains"`
+	Taken   time.Time `json:"taken"`
+}
+
+// A domain's records as last read from the API
+type RecordsSnapshot struct {
+	DomainID int       `json:"domainId"`
+	Records  []Record  `json:"records"`
+	Taken    time.Time `json:"taken"`
+}
+
+// Persists the results of reads, so they can be served while the API is
+// down
+type SnapshotStore interface {
+	// Return a zero snapshot if there is none
+	LoadDomainsSnapshot() (DomainsSnapshot, error)
+	SaveDomainsSnapshot(DomainsSnapshot) error
+	LoadRecordsSnapshot(domainID int) (RecordsSnapshot, error)
+	SaveRecordsSnapshot(RecordsSnapshot) error
+}
+
+// Keeps snapshots as JSON files in Dir
+type FileSnapshots struct {
+	Dir string
+}
+
+func (f FileSnapshots) LoadDomainsSnapshot() (DomainsSnapshot, error) {
+	var snapshot DomainsSnapshot
+	err := f.load("domains.json", &snapshot)
+	return snapshot, err
+}
+
+func (f FileSnapshots) SaveDomainsSnapshot(snapshot DomainsSnapshot) error {
+	return f.save("domains.json", snapshot)
+}
+
+func (f FileSnapshots) LoadRecordsSnapshot(domainID int) (RecordsSnapshot, error) {
+	var snapshot RecordsSnapshot
+	err := f.load(fmt.Sprintf("records-%d.json", domainID), &snapshot)
+	return snapshot, err
+}
+
+func (f FileSnapshots) SaveRecordsSnapshot(snapshot RecordsSnapshot) error {
+	return f.save(fmt.Sprintf("records-%d.json", snapshot.DomainID), snapshot)
+}
+
+func (f FileSnapshots) load(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(f.Dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (f FileSnapshots) save(name string, v interface{}) error {
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	return writeJSONFile(filepath.Join(f.Dir, name), v)
+}
+
+// Saves every domain and record list read from the API to store, and
+// serves the saved copy when a later read fails because the API is
+// unreachable or erroring, so tooling keeps working through an outage.
+// Snapshots older than maxAge aren't served; zero serves any age.
+// TrackStaleness reports which reads were served from snapshots.
+//
+// Only Domains().List, Domains().Get and Records().List fall back;
+// reads made on the way to changing records always need the API.
+func WithOfflineSnapshots(store SnapshotStore, maxAge time.Duration) Option {
+	return func(c *Client) {
+		c.snapshots = store
+		c.snapshotMaxAge = maxAge
+	}
+}
+
+// Whether reads through a context were served from snapshots
+type Staleness struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+type stalenessKey struct{}
+
+// Returns a context whose reads served from snapshots are recorded in
+// the returned Staleness
+func TrackStaleness(ctx context.Context) (context.Context, *Staleness) {
+	s := &Staleness{}
+	return context.WithValue(ctx, stalenessKey{}, s), s
+}
+
+// Reports whether any read was served from a snapshot
+func (s *Staleness) Stale() bool {
+	return !s.Since().IsZero()
+}
+
+// Returns when the oldest snapshot served was taken, or the zero time if
+// none was
+func (s *Staleness) Since() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.oldest
+}
+
+func (s *Staleness) served(taken time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.oldest.IsZero() || taken.Before(s.oldest) {
+		s.oldest = taken
+	}
+}
+
+// Reports whether err means the API couldn't serve a request, as opposed
+// to refusing it
+func isOutage(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500
+	case errors.Is(err, ErrCircuitOpen), errors.As(err, &urlErr), errors.As(err, &netErr):
+		return true
+	}
+	return false
+}
+
+// Returns whether a snapshot taken at taken may be served in place of a
+// failed read, recording it with ctx's Staleness if so
+func (c *Client) serveSnapshot(ctx context.Context, taken time.Time) bool {
+	if taken.IsZero() || (c.snapshotMaxAge > 0 && time.Since(taken) > c.snapshotMaxAge) {
+		return false
+	}
+	if s, ok := ctx.Value(stalenessKey{}).(*Staleness); ok {
+		s.served(taken)
+	}
+	return true
+}
+
+// Saves the domains, ignoring errors as a failed save only costs
+// serving an older snapshot
+func (c *Client) saveDomainsSnapshot(domains []Domain) {
+	if c.snapshots != nil {
+		c.snapshots.SaveDomainsSnapshot(DomainsSnapshot{Domains: domains, Taken: time.Now()})
+	}
+}
+
+func (c *Client) saveRecordsSnapshot(domainID int, records []Record) {
+	if c.snapshots != nil {
+		c.snapshots.SaveRecordsSnapshot(RecordsSnapshot{DomainID: domainID, Records: records, Taken: time.Now()})
+	}
+}
+
+// Returns the saved domains in place of a read that failed with err, or
+// err if there are none to serve
+func (c *Client) offlineDomains(ctx context.Context, err error) ([]Domain, error) {
+	if c.snapshots == nil || !isOutage(ctx, err) {
+		return nil, err
+	}
+	snapshot, loadErr := c.snapshots.LoadDomainsSnapshot()
+	if loadErr != nil || !c.serveSnapshot(ctx, snapshot.Taken) {
+		return nil, err
+	}
+	return snapshot.Domains, nil
+}
+
+func (c *Client) offlineRecords(ctx context.Context, domainID int, err error) ([]Record, error) {
+	if c.snapshots == nil || !isOutage(ctx, err) {
+		return nil, err
+	}
+	snapshot, loadErr := c.snapshots.LoadRecordsSnapshot(domainID)
+	if loadErr != nil || !c.serveSnapshot(ctx, snapshot.Taken) {
+		return nil, err
+	}
+	return snapshot.Records, nil
+}
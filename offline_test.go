@@ -0,0 +1,73 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineSnapshots(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	client.snapshots = FileSnapshots{Dir: t.TempDir()}
+
+	// reads while the API is up are saved
+	ctx := context.Background()
+	_, err := client.Domains().List(ctx)
+	require.NoError(t, err)
+	_, err = client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+
+	fake.fail = func(r *http.Request) bool { return true }
+	fake.failStatus = http.StatusServiceUnavailable
+
+	tracked, staleness := TrackStaleness(ctx)
+	domains, err := client.Domains().List(tracked)
+	require.NoError(t, err)
+	require.Len(t, domains, 1)
+	got, err := client.Domains().Get(tracked, domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", got.Name)
+	records, err := client.Records(domain.ID).List(tracked)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "192.0.2.1", records[0].Value)
+	assert.True(t, staleness.Stale())
+	assert.WithinDuration(t, time.Now(), staleness.Since(), time.Minute)
+
+	// domains never read have no snapshot
+	_, err = client.Records(domain.ID + 1).List(ctx)
+	assert.Error(t, err)
+
+	// nor are refusals, as opposed to outages, covered up
+	fake.failStatus = http.StatusBadRequest
+	_, err = client.Records(domain.ID).List(ctx)
+	assert.Error(t, err)
+}
+
+func TestOfflineSnapshotsMaxAge(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	store := FileSnapshots{Dir: t.TempDir()}
+	require.NoError(t, store.SaveRecordsSnapshot(RecordsSnapshot{
+		DomainID: domain.ID,
+		Records:  []Record{{Name: "www", Type: "A", Value: "192.0.2.1"}},
+		Taken:    time.Now().Add(-2 * time.Hour),
+	}))
+	client.snapshots, client.snapshotMaxAge = store, time.Hour
+	fake.fail = func(r *http.Request) bool { return true }
+	fake.failStatus = http.StatusBadGateway
+
+	_, err := client.Records(domain.ID).List(context.Background())
+	assert.Error(t, err)
+	client.snapshotMaxAge = 3 * time.Hour
+	records, err := client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
@@ -0,0 +1,100 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// The direction of a sorted listing
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "ASC"
+	Descending SortDirection = "DESC"
+)
+
+// Query parameters for listing a page of records. Zero values are left
+// to the API's defaults.
+type ListOptions struct {
+	// The number of records per page
+	Rows int
+
+	// The page to return, starting at 1
+	Page int
+
+	// The record field to sort by, such as "name", "type" or "ttl"
+	Sort      string
+	Direction SortDirection
+
+	// Only return records of this type
+	Type string
+
+	// Only return records with this name
+	RecordName string
+}
+
+func (o ListOptions) params() map[string]string {
+	params := map[string]string{}
+	if o.Rows > 0 {
+		params["rows"] = fmt.Sprint(o.Rows)
+	}
+	if o.Page > 0 {
+		params["page"] = fmt.Sprint(o.Page)
+	}
+	if o.Sort != "" {
+		params["sort"] = o.Sort
+	}
+	if o.Direction != "" {
+		params["direction"] = strings.ToUpper(string(o.Direction))
+	}
+	if o.Type != "" {
+		params["type"] = strings.ToUpper(o.Type)
+	}
+	if o.RecordName != "" {
+		params["recordName"] = o.RecordName
+	}
+	return params
+}
+
+// Returns one page of the domain's records, sorted and filtered by the
+// API. The response's totals describe the whole listing.
+//
+// NOTE: bypasses the record cache
+func (s *RecordsService) ListPage(ctx context.Context, opts ListOptions) (RecordsResp, error) {
+	var respRecords RecordsResp
+	err := s.client.getStreamed(s.request(ctx).SetQueryParams(opts.params()),
+		DNSManagedPath+DNSRecordsPath, &respRecords)
+	if err != nil {
+		return RecordsResp{}, err
+	}
+	return respRecords, nil
+}
+
+// Returns one page of the supplied domain's records
+//
+// Equivalent to c.Records(domainId).ListPage(context.Background(), opts)
+func (c *Client) ListRecords(domainId int, opts ListOptions) (RecordsResp, error) {
+	return c.Records(domainId).ListPage(context.Background(), opts)
+}
+
+// Returns the number of records in the domain matching the type and
+// record name filters of opts, without downloading them. Its paging and
+// sort fields are ignored.
+func (s *RecordsService) Count(ctx context.Context, opts ListOptions) (int, error) {
+	opts.Rows, opts.Page = 1, 1
+	opts.Sort, opts.Direction = "", ""
+	page, err := s.ListPage(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+	return page.TotalRecords, nil
+}
+
+// Returns the number of records in the supplied domain matching the
+// filters of opts
+//
+// Equivalent to c.Records(domainId).Count(context.Background(), opts)
+func (c *Client) CountRecords(domainId int, opts ListOptions) (int, error) {
+	return c.Records(domainId).Count(context.Background(), opts)
+}
@@ -0,0 +1,61 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRecords(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "c", Type: "A", Value: "192.0.2.3", Ttl: 300},
+		Record{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "b", Type: "TXT", Value: "\"hello\"", Ttl: 300},
+		Record{Name: "d", Type: "A", Value: "192.0.2.4", Ttl: 300},
+	)
+
+	page, err := client.ListRecords(domain.ID, ListOptions{Rows: 2, Page: 2, Sort: "name", Direction: Ascending})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.TotalRecords)
+	assert.Equal(t, 2, page.TotalPages)
+	assert.Equal(t, 2, page.CurrentPage)
+	require.Len(t, page.Records, 2)
+	assert.Equal(t, "c", page.Records[0].Name)
+	assert.Equal(t, "d", page.Records[1].Name)
+
+	page, err = client.ListRecords(domain.ID, ListOptions{Type: "a", Sort: "name", Direction: "desc"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.TotalRecords)
+	assert.Equal(t, "d", page.Records[0].Name)
+
+	page, err = client.ListRecords(domain.ID, ListOptions{RecordName: "b"})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, "TXT", page.Records[0].Type)
+}
+
+func TestCountRecords(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "a", Type: "TXT", Value: "\"hello\"", Ttl: 300},
+		Record{Name: "b", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	)
+
+	count, err := client.CountRecords(domain.ID, ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = client.CountRecords(domain.ID, ListOptions{Type: "A"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = client.CountRecords(domain.ID, ListOptions{Type: "A", RecordName: "a", Rows: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = client.CountRecords(1, ListOptions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
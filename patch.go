@@ -0,0 +1,137 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// A partial update to a record. Only non-nil fields are changed, so a
+// field can be deliberately set to its zero value (an MX level of 0, for
+// example) without being confused with "leave as is".
+type RecordPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Value       *string `json:"value,omitempty"`
+	Ttl         *int    `json:"ttl,omitempty"`
+	GtdLocation *string `json:"gtdLocation,omitempty"`
+	Failover    *bool   `json:"failover,omitempty"`
+	Monitor     *bool   `json:"monitor,omitempty"`
+	HardLink    *bool   `json:"hardLink,omitempty"`
+	DynamicDns  *bool   `json:"dynamicDns,omitempty"`
+	MxLevel     *int    `json:"mxLevel,omitempty"`
+	Priority    *int    `json:"priority,omitempty"`
+	Weight      *int    `json:"weight,omitempty"`
+	Port        *int    `json:"port,omitempty"`
+}
+
+// Returns a pointer to s, for populating patches
+func String(s string) *string { return &s }
+
+// Returns a pointer to i, for populating patches
+func Int(i int) *int { return &i }
+
+// Returns a pointer to b, for populating patches
+func Bool(b bool) *bool { return &b }
+
+// Returns a copy of record with the patch's fields applied
+func (p RecordPatch) Apply(record Record) Record {
+	if p.Name != nil {
+		record.Name = *p.Name
+	}
+	if p.Value != nil {
+		record.Value = *p.Value
+	}
+	if p.Ttl != nil {
+		record.Ttl = *p.Ttl
+	}
+	if p.GtdLocation != nil {
+		record.GtdLocation = *p.GtdLocation
+	}
+	if p.Failover != nil {
+		record.Failover = *p.Failover
+	}
+	if p.Monitor != nil {
+		record.Monitor = *p.Monitor
+	}
+	if p.HardLink != nil {
+		record.HardLink = *p.HardLink
+	}
+	if p.DynamicDns != nil {
+		record.DynamicDns = *p.DynamicDns
+	}
+	if p.MxLevel != nil {
+		record.MxLevel = *p.MxLevel
+	}
+	if p.Priority != nil {
+		record.Priority = *p.Priority
+	}
+	if p.Weight != nil {
+		record.Weight = *p.Weight
+	}
+	if p.Port != nil {
+		record.Port = *p.Port
+	}
+	return record
+}
+
+// Builds the request body for updating record with the patch applied.
+// Record's omitempty tags would drop fields patched to zero, so patched
+// fields are written explicitly.
+func (p RecordPatch) body(record Record) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	b, err := json.Marshal(p.Apply(record))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, err
+	}
+
+	b, err = json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var patched map[string]interface{}
+	if err := json.Unmarshal(b, &patched); err != nil {
+		return nil, err
+	}
+	for k, v := range patched {
+		body[k] = v
+	}
+	return body, nil
+}
+
+// Changes only the fields set in patch on the supplied record, leaving
+// everything else as it currently is in DNS Made Easy
+func (s *RecordsService) Patch(ctx context.Context, recordID int, patch RecordPatch) (Record, error) {
+	// the API only accepts whole records, so start from the live one
+	records, err := s.list(ctx)
+	if err != nil {
+		return Record{}, err
+	}
+	var current *Record
+	for idx := range records {
+		if records[idx].ID == recordID {
+			current = &records[idx]
+			break
+		}
+	}
+	if current == nil {
+		return Record{}, fmt.Errorf("record %d in domain %d: %w", recordID, s.domainID, ErrNotFound)
+	}
+
+	body, err := patch.body(*current)
+	if err != nil {
+		return Record{}, err
+	}
+
+	defer s.client.InvalidateRecords(s.domainID)
+	_, err = checkRespForError(s.request(ctx).
+		SetBody(body).
+		SetPathParam("recordId", fmt.Sprint(recordID)).
+		Put(DNSManagedPath + DNSRecordPath))
+	if err != nil {
+		return Record{}, err
+	}
+	return patch.Apply(*current), nil
+}
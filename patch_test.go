@@ -0,0 +1,44 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordPatch(t *testing.T) {
+	mx := Record{ID: 1, Name: "", Type: "MX", Value: "mx.example.com.", Ttl: 1800, MxLevel: 10, GtdLocation: "DEFAULT"}
+
+	body, err := RecordPatch{MxLevel: Int(0)}.body(mx)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), body["mxLevel"])
+	assert.Equal(t, float64(1800), body["ttl"])
+	assert.Equal(t, "mx.example.com.", body["value"])
+
+	// unpatched zero values keep their usual encoding
+	body, err = RecordPatch{Ttl: Int(300)}.body(Record{Type: "A"})
+	require.NoError(t, err)
+	assert.NotContains(t, body, "mxLevel")
+	assert.Equal(t, float64(300), body["ttl"])
+}
+
+func TestRecordsPatch(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "US_EAST"})
+	www := fake.recordList(domain.ID)[0]
+
+	patched, err := client.Records(domain.ID).Patch(context.Background(), www.ID, RecordPatch{Ttl: Int(300)})
+	require.NoError(t, err)
+	assert.Equal(t, 300, patched.Ttl)
+
+	live := fake.recordList(domain.ID)[0]
+	assert.Equal(t, 300, live.Ttl)
+	assert.Equal(t, "192.0.2.1", live.Value)
+	assert.Equal(t, "US_EAST", live.GtdLocation)
+
+	_, err = client.Records(domain.ID).Patch(context.Background(), 1, RecordPatch{Ttl: Int(300)})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,78 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Creating a domain failed because a domain of the same name is still
+// being deleted
+var ErrDomainPendingDelete = errors.New("domain is pending deletion")
+
+// Makes CreateDomain retry, backing off up to the polling interval,
+// while a recently deleted domain of the same name is still being
+// removed. Gives up with ErrDomainPendingDelete when the polling
+// deadline (see WithPolling) passes.
+func WithPendingDeleteRetry() Option {
+	return func(c *Client) {
+		c.pendingDeleteRetry = true
+	}
+}
+
+// Reports whether err from creating the domain means a domain of that
+// name is being deleted. DNS Made Easy either says so, or claims the
+// domain exists while it is missing from the account's listing.
+func (s *DomainsService) pendingDelete(ctx context.Context, domainName string, err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Error())
+	if strings.Contains(msg, "pending") && strings.Contains(msg, "delet") {
+		return true
+	}
+	if !strings.Contains(msg, "already exists") {
+		return false
+	}
+	names, listErr := s.names(ctx)
+	if listErr != nil {
+		return false
+	}
+	_, listed := names[domainName]
+	return !listed
+}
+
+func (s *DomainsService) createAfterDelete(ctx context.Context, domainName string) (Domain, error) {
+	c := s.client
+	ctx, cancel := context.WithTimeout(ctx, c.pollDeadline)
+	defer cancel()
+
+	backoff := min(time.Second, c.pollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return Domain{}, fmt.Errorf("creating %s: %w: %w", domainName, ErrDomainPendingDelete, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.pollInterval)
+
+		domain, err := s.create(ctx, domainName)
+		if err != nil && ctx.Err() != nil {
+			// the deadline passed mid-request
+			continue
+		}
+		if err == nil {
+			return domain, nil
+		}
+		if !s.pendingDelete(ctx, domainName, err) {
+			if ctx.Err() != nil {
+				// the deadline passed while listing domains
+				continue
+			}
+			return domain, err
+		}
+	}
+}
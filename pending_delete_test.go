@@ -0,0 +1,36 @@
+package dnsmadeeasy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingDeleteRetry(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.pendingDeletes = map[string]int{"example.com": 2}
+
+	_, err := client.CreateDomain("example.com")
+	assert.EqualError(t, err, "Domain already exists")
+
+	client = GetClient("key", "secret", client.BaseURL,
+		WithPendingDeleteRetry(), WithPolling(time.Millisecond, time.Second))
+	domain, err := client.CreateDomain("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", domain.Name)
+	assert.Equal(t, 3, fake.calls["POST /dns/managed/"])
+
+	// a domain that really exists isn't waited for
+	_, err = client.CreateDomain("example.com")
+	assert.EqualError(t, err, "Domain already exists")
+	assert.False(t, errors.Is(err, ErrDomainPendingDelete))
+
+	fake.pendingDeletes["example.org"] = 1000
+	client = GetClient("key", "secret", client.BaseURL,
+		WithPendingDeleteRetry(), WithPolling(time.Millisecond, 20*time.Millisecond))
+	_, err = client.CreateDomain("example.org")
+	assert.ErrorIs(t, err, ErrDomainPendingDelete)
+}
@@ -0,0 +1,84 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+var (
+	// The API key is unknown or the HMAC didn't validate
+	ErrBadCredentials = errors.New("invalid API credentials")
+
+	// The request date differs too much from DNS Made Easy's clock
+	ErrClockSkew = errors.New("local clock out of sync with DNS Made Easy")
+
+	// The account's request quota is exhausted
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// The API could not be reached
+	ErrNetwork = errors.New("network failure")
+)
+
+// The outcome of a successful Ping
+type PingResult struct {
+	Latency time.Duration
+
+	// The quota reported by the API
+	RateLimit RateLimit
+
+	// The difference between DNS Made Easy's clock and ours, positive
+	// when ours is behind. Only accurate to about a second.
+	ClockSkew time.Duration
+}
+
+// Makes a minimal authenticated request to confirm the API is reachable
+// and the credentials are valid. Failures wrap one of ErrBadCredentials,
+// ErrClockSkew, ErrRateLimited or ErrNetwork where they can be told
+// apart.
+func (c *Client) Ping(ctx context.Context) (PingResult, error) {
+	start := time.Now()
+	resp, err := c.newRequest(ctx).
+		SetQueryParam("rows", "1").
+		Get(DNSManagedPath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return PingResult{}, err
+		}
+		return PingResult{}, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+
+	result := PingResult{
+		Latency:   time.Since(start),
+		RateLimit: responseMetaFrom(resp).RateLimit,
+	}
+	if date, err := http.ParseTime(resp.Header().Get("Date")); err == nil {
+		result.ClockSkew = date.Sub(start.Add(result.Latency / 2)).Truncate(time.Second)
+	}
+
+	if _, err = checkRespForError(resp, nil); err != nil {
+		return result, classifyAuthError(resp, err)
+	}
+	return result, nil
+}
+
+// Wraps err with the sentinel matching the failure DNS Made Easy
+// reported, if any
+func classifyAuthError(resp *resty.Response, err error) error {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "rate limit") || resp.StatusCode() == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	case strings.Contains(msg, "date") || strings.Contains(msg, "sync"):
+		return fmt.Errorf("%w: %w", ErrClockSkew, err)
+	case resp.StatusCode() == http.StatusForbidden || resp.StatusCode() == http.StatusUnauthorized ||
+		strings.Contains(msg, "api key") || strings.Contains(msg, "hmac"):
+		return fmt.Errorf("%w: %w", ErrBadCredentials, err)
+	}
+	return err
+}
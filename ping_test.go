@@ -0,0 +1,35 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.limit, fake.remaining = 150, 100
+
+	result, err := client.Ping(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 99, result.RateLimit.Remaining)
+	assert.Less(t, result.ClockSkew.Abs().Seconds(), 2.0)
+
+	for msg, sentinel := range map[string]error{
+		"Rate limit exceeded":                                ErrRateLimited,
+		"Request sent with date header too far out of sync.": ErrClockSkew,
+		"HMAC validation failed":                             ErrBadCredentials,
+	} {
+		fake.fail = func(r *http.Request) bool { return true }
+		fake.failMessage = msg
+		_, err = client.Ping(context.Background())
+		assert.ErrorIs(t, err, sentinel, msg)
+	}
+
+	client = GetClient("key", "secret", "http://127.0.0.1:1/")
+	_, err = client.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrNetwork)
+}
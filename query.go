@@ -0,0 +1,368 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// A search over records, parsed from text by ParseQuery. Every term
+// must match; the zero Query matches every record.
+type Query struct {
+	// Any of these types
+	Types []string
+
+	// A path.Match pattern of the name relative to the zone, "@" for
+	// the apex, ignoring case
+	Name string
+
+	// A regular expression the value must contain a match of
+	Value *regexp.Regexp
+
+	// The value exactly, ignoring case and a trailing dot
+	ExactValue string
+
+	// Inclusive TTL bounds; zero leaves a bound open
+	MinTTL, MaxTTL int
+
+	// Any of these GTD locations
+	GtdLocations []string
+}
+
+// Parses a query of whitespace separated terms, such as
+//
+//	type=A,AAAA name=web* value~^192\.0\.2\. ttl=60..300 gtd=DEFAULT
+//
+// type and gtd take a comma separated list, name a glob, value~ a
+// regular expression and value= an exact value. ttl takes =n, <n, <=n, >n, >=n or =min..max.
+// Values containing spaces can be double quoted.
+func ParseQuery(s string) (Query, error) {
+	terms, err := queryTerms(s)
+	if err != nil {
+		return Query{}, err
+	}
+	var q Query
+	for _, term := range terms {
+		idx := strings.IndexAny(term, "=~<>")
+		if idx <= 0 {
+			return Query{}, fmt.Errorf("query term %q: expected field, operator and value", term)
+		}
+		field, rest := strings.ToLower(term[:idx]), term[idx:]
+		op := rest[:1]
+		if len(rest) > 1 && rest[1] == '=' && op != "=" {
+			op += "="
+		}
+		value, err := queryValue(rest[len(op):])
+		if err != nil {
+			return Query{}, fmt.Errorf("query term %q: %w", term, err)
+		}
+
+		switch {
+		case field == "type" && op == "=":
+			for _, t := range strings.Split(value, ",") {
+				q.Types = append(q.Types, strings.ToUpper(strings.TrimSpace(t)))
+			}
+		case field == "gtd" && op == "=":
+			for _, location := range strings.Split(value, ",") {
+				q.GtdLocations = append(q.GtdLocations, strings.ToUpper(strings.TrimSpace(location)))
+			}
+		case field == "name" && op == "=":
+			if _, err := path.Match(value, ""); err != nil {
+				return Query{}, fmt.Errorf("query term %q: %w", term, err)
+			}
+			q.Name = strings.ToLower(value)
+		case field == "value" && op == "=":
+			q.ExactValue = value
+		case field == "value" && op == "~":
+			if q.Value, err = regexp.Compile(value); err != nil {
+				return Query{}, fmt.Errorf("query term %q: %w", term, err)
+			}
+		case field == "ttl":
+			if err := q.parseTTL(op, value); err != nil {
+				return Query{}, fmt.Errorf("query term %q: %w", term, err)
+			}
+		default:
+			return Query{}, fmt.Errorf("query term %q: unknown field or operator", term)
+		}
+	}
+	return q, nil
+}
+
+func (q *Query) parseTTL(op, value string) error {
+	if low, high, ok := strings.Cut(value, ".."); ok && op == "=" {
+		lowTTL, err1 := strconv.Atoi(low)
+		highTTL, err2 := strconv.Atoi(high)
+		if err := errors.Join(err1, err2); err != nil {
+			return err
+		}
+		if lowTTL > highTTL {
+			return fmt.Errorf("empty TTL range")
+		}
+		q.MinTTL, q.MaxTTL = lowTTL, highTTL
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case "=":
+		q.MinTTL, q.MaxTTL = n, n
+	case "<":
+		if n <= 1 {
+			return fmt.Errorf("no TTL is below %d", n)
+		}
+		q.MaxTTL = n - 1
+	case "<=":
+		q.MaxTTL = n
+	case ">":
+		q.MinTTL = n + 1
+	case ">=":
+		q.MinTTL = n
+	default:
+		return fmt.Errorf("unknown operator %s", op)
+	}
+	return nil
+}
+
+// Splits a query into terms at whitespace outside double quotes
+func queryTerms(s string) ([]string, error) {
+	var terms []string
+	var term strings.Builder
+	quoted, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+			continue
+		}
+		term.WriteRune(r)
+	}
+	if quoted {
+		return nil, fmt.Errorf("query %q: unterminated quote", s)
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, nil
+}
+
+// Returns a term's value, unquoted. Only \" is an escape within
+// quotes, so regular expressions keep their backslashes.
+func queryValue(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) {
+		if len(s) < 2 || !strings.HasSuffix(s, `"`) {
+			return "", fmt.Errorf("text after quoted value")
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], `\"`, `"`), nil
+	}
+	if s == "" {
+		return "", fmt.Errorf("missing value")
+	}
+	return s, nil
+}
+
+// Reports whether the record matches every term of the query. The
+// cheap comparisons are made first.
+func (q Query) Matches(record Record) bool {
+	if len(q.Types) > 0 && !containsFold(q.Types, record.Type) {
+		return false
+	}
+	if q.MinTTL > 0 && record.Ttl < q.MinTTL || q.MaxTTL > 0 && record.Ttl > q.MaxTTL {
+		return false
+	}
+	if len(q.GtdLocations) > 0 && !containsFold(q.GtdLocations, record.GtdLocation) {
+		return false
+	}
+	if q.Name != "" {
+		name := strings.ToLower(record.Name)
+		if name == "" {
+			name = "@"
+		}
+		if ok, _ := path.Match(q.Name, name); !ok {
+			return false
+		}
+	}
+	if q.ExactValue != "" && canonicalValue(q.ExactValue) != canonicalValue(record.Value) {
+		return false
+	}
+	return q.Value == nil || q.Value.MatchString(record.Value)
+}
+
+// Returns the records matching the query
+func (q Query) Filter(records []Record) []Record {
+	var matched []Record
+	for _, record := range records {
+		if q.Matches(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+// Renders the query in the syntax ParseQuery accepts
+func (q Query) String() string {
+	var terms []string
+	if len(q.Types) > 0 {
+		terms = append(terms, "type="+strings.Join(q.Types, ","))
+	}
+	if q.Name != "" {
+		terms = append(terms, "name="+quoteQueryValue(q.Name))
+	}
+	if q.ExactValue != "" {
+		terms = append(terms, "value="+quoteQueryValue(q.ExactValue))
+	}
+	if q.Value != nil {
+		terms = append(terms, "value~"+quoteQueryValue(q.Value.String()))
+	}
+	switch {
+	case q.MinTTL > 0 && q.MinTTL == q.MaxTTL:
+		terms = append(terms, fmt.Sprintf("ttl=%d", q.MinTTL))
+	case q.MinTTL > 0 && q.MaxTTL > 0:
+		terms = append(terms, fmt.Sprintf("ttl=%d..%d", q.MinTTL, q.MaxTTL))
+	case q.MinTTL > 0:
+		terms = append(terms, fmt.Sprintf("ttl>=%d", q.MinTTL))
+	case q.MaxTTL > 0:
+		terms = append(terms, fmt.Sprintf("ttl<=%d", q.MaxTTL))
+	}
+	if len(q.GtdLocations) > 0 {
+		terms = append(terms, "gtd="+strings.Join(q.GtdLocations, ","))
+	}
+	return strings.Join(terms, " ")
+}
+
+func quoteQueryValue(s string) string {
+	if strings.ContainsAny(s, "\" \t\n") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
+
+func (q Query) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Query) UnmarshalText(text []byte) error {
+	parsed, err := ParseQuery(string(text))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the domain's records matching q, served from the record cache
+// when one is configured
+func (c *Client) QueryRecords(ctx context.Context, domainID int, q Query) ([]Record, error) {
+	records, err := c.Records(domainID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.Filter(records), nil
+}
+
+// Named queries kept in a JSON file, for searches run often. Safe for
+// concurrent use within a process.
+type SavedSearches struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Returns the saved query of that name, or an error wrapping ErrNotFound
+func (s *SavedSearches) Get(name string) (Query, error) {
+	all, err := s.List()
+	if err != nil {
+		return Query{}, err
+	}
+	q, ok := all[name]
+	if !ok {
+		return Query{}, fmt.Errorf("saved search %q: %w", name, ErrNotFound)
+	}
+	return q, nil
+}
+
+// Returns every saved query by name
+func (s *SavedSearches) List() (map[string]Query, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Saves q under name, replacing any query of that name
+func (s *SavedSearches) Save(name string, q Query) error {
+	return s.update(func(all map[string]Query) { all[name] = q })
+}
+
+// Removes the query of that name, if any
+func (s *SavedSearches) Delete(name string) error {
+	return s.update(func(all map[string]Query) { delete(all, name) })
+}
+
+// Returns the names of the saved queries, sorted
+func (s *SavedSearches) Names() ([]string, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *SavedSearches) update(change func(map[string]Query)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	change(all)
+	return writeJSONFile(s.Path, all)
+}
+
+// Reads the file; a missing file holds no searches
+func (s *SavedSearches) load() (map[string]Query, error) {
+	all := map[string]Query{}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return all, nil
+}
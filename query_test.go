@@ -0,0 +1,89 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	records := []Record{
+		{Name: "web1", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "WEB2", Type: "AAAA", Value: "2001:db8::2", Ttl: 60, GtdLocation: "US_EAST"},
+		{Name: "", Type: "MX", Value: "mail.example.com.", Ttl: 3600, MxLevel: 10, GtdLocation: "DEFAULT"},
+		{Name: "txt", Type: "TXT", Value: `"v=spf1 include:mail.example.com -all"`, Ttl: 1800, GtdLocation: "DEFAULT"},
+		{Name: "api", Type: "A", Value: "198.51.100.7", Ttl: 86400, GtdLocation: "EUROPE"},
+	}
+	names := func(records []Record) []string {
+		var names []string
+		for _, record := range records {
+			names = append(names, record.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"web1", "WEB2", "", "txt", "api"}},
+		{"type=a,aaaa", []string{"web1", "WEB2", "api"}},
+		{"name=web*", []string{"web1", "WEB2"}},
+		{"name=@", []string{""}},
+		{`value~^192\.0\.2\.`, []string{"web1"}},
+		{"value=MAIL.example.com", []string{""}},
+		{"value=192.0.2", nil},
+		{`value~"include:mail\.example\.com -all"`, []string{"txt"}},
+		{"ttl=60..300", []string{"web1", "WEB2"}},
+		{"ttl<300", []string{"WEB2"}},
+		{"ttl>=3600", []string{"", "api"}},
+		{"ttl=1800", []string{"txt"}},
+		{"gtd=us_east,EUROPE", []string{"WEB2", "api"}},
+		{"type=A  ttl>300\tgtd=EUROPE", []string{"api"}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		require.NoError(t, err, tt.query)
+		assert.Equal(t, tt.want, names(q.Filter(records)), tt.query)
+
+		again, err := ParseQuery(q.String())
+		require.NoError(t, err, q.String())
+		assert.Equal(t, names(q.Filter(records)), names(again.Filter(records)), "round trip of %q", tt.query)
+	}
+
+	for _, bad := range []string{"type", "color=red", "name~x", "value<x", `value~"(`, "name=[", "ttl=abc", "ttl=300..60", "ttl<1", `name="web`, "type="} {
+		_, err := ParseQuery(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSavedSearches(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 60, GtdLocation: GtdDefault},
+	)
+	searches := &SavedSearches{Path: filepath.Join(t.TempDir(), "searches.json")}
+	q, err := ParseQuery(`type=A ttl<120 value~"^192\.0\.2\."`)
+	require.NoError(t, err)
+	require.NoError(t, searches.Save("low-ttl", q))
+	require.NoError(t, searches.Save("other", Query{}))
+
+	reloaded := &SavedSearches{Path: searches.Path}
+	saved, err := reloaded.Get("low-ttl")
+	require.NoError(t, err)
+	assert.Equal(t, q.String(), saved.String())
+	records, err := client.QueryRecords(context.Background(), domain.ID, saved)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api=192.0.2.2"}, recordValues(records))
+
+	require.NoError(t, reloaded.Delete("other"))
+	names, err := reloaded.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"low-ttl"}, names)
+	_, err = reloaded.Get("other")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
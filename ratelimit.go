@@ -0,0 +1,70 @@
+package dnsmadeeasy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	RequestLimitHeader      string = "x-dnsme-requestLimit"
+	RequestsRemainingHeader string = "x-dnsme-requestsRemaining"
+
+	// DNS Made Easy allows RequestLimit requests per rolling window
+	RateLimitWindow = 5 * time.Minute
+)
+
+// The most recently observed request quota for the account
+type RateLimit struct {
+	// Requests allowed per RateLimitWindow, 0 if not yet known
+	Limit int
+
+	// Requests remaining in the current window
+	Remaining int
+
+	// When the values were last read from a response
+	Updated time.Time
+}
+
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	state RateLimit
+}
+
+// stubbed out in tests
+var sleep = time.Sleep
+
+// Records the quota headers of every response
+func (t *rateLimitTracker) observe(_ *resty.Client, resp *resty.Response) error {
+	rl := responseMetaFrom(resp).RateLimit
+	if rl.Limit == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = rl
+	return nil
+}
+
+func (t *rateLimitTracker) get() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// Returns the request quota reported by the most recent response
+func (c *Client) RateLimit() RateLimit {
+	return c.rateLimit.get()
+}
+
+// Blocks while the remaining quota is at or below reserve, pacing
+// requests at the rate DNS Made Easy replenishes them
+func (c *Client) throttle(reserve int) {
+	rl := c.rateLimit.get()
+	if rl.Limit == 0 || rl.Remaining > reserve {
+		return
+	}
+	sleep(RateLimitWindow / time.Duration(rl.Limit))
+}
@@ -0,0 +1,154 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Returned by Reap for a plan that was neither approved nor forced, and
+// wrapped by Approvers refusing a plan
+var ErrNotApproved = errors.New("plan has not been approved")
+
+// Dangling records proposed for deletion. Nothing is deleted until the
+// plan is approved, or Reap is called with ReapOptions.Force.
+type ReapPlan struct {
+	Created time.Time
+	Records []DanglingRecord
+
+	approved bool
+}
+
+// Looks for dangling records in the supplied domains and returns a plan
+// to delete them
+func (c *Client) PlanReap(ctx context.Context, domainIDs []int, opts DanglingOptions) (*ReapPlan, error) {
+	dangling, err := c.FindDangling(ctx, domainIDs, opts)
+	return &ReapPlan{Created: time.Now(), Records: dangling}, err
+}
+
+// Marks the plan as confirmed for deletion
+func (p *ReapPlan) Approve() {
+	p.approved = true
+}
+
+// Reports whether Approve has been called
+func (p *ReapPlan) Approved() bool {
+	return p.approved
+}
+
+// Settings for Reap
+type ReapOptions struct {
+	// Directory the zones are snapshotted to before anything is deleted;
+	// required
+	SnapshotDir string
+
+	// Records deleted per request; 50 if zero
+	BatchSize int
+
+	// Batches wait while the remaining request quota is at or below
+	// Reserve
+	Reserve int
+
+	// Delete without an approved plan
+	Force bool
+}
+
+type ReapResult struct {
+	// Snapshot file written for each domain, by domain ID
+	Snapshots map[int]string
+
+	Deleted []DanglingRecord
+
+	// Planned records that were changed or removed since the plan was
+	// made, and so were left alone
+	Skipped []DanglingRecord
+}
+
+// Deletes the records of an approved plan. Each affected zone is first
+// written to opts.SnapshotDir as it would be by Export with ExportJSON,
+// and records that no longer match the plan are skipped. Deletions run
+// in batches, paced against the account's request quota.
+func (c *Client) Reap(ctx context.Context, plan *ReapPlan, opts ReapOptions) (ReapResult, error) {
+	result := ReapResult{Snapshots: map[int]string{}}
+	if !plan.approved && !opts.Force {
+		return result, ErrNotApproved
+	}
+	if opts.SnapshotDir == "" {
+		return result, errors.New("reap requires a snapshot directory")
+	}
+	if err := os.MkdirAll(opts.SnapshotDir, 0o755); err != nil {
+		return result, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	byDomain := map[int][]DanglingRecord{}
+	var domainIDs []int
+	for _, d := range plan.Records {
+		if _, ok := byDomain[d.DomainID]; !ok {
+			domainIDs = append(domainIDs, d.DomainID)
+		}
+		byDomain[d.DomainID] = append(byDomain[d.DomainID], d)
+	}
+	sort.Ints(domainIDs)
+
+	// snapshot every zone before touching any of them
+	current := map[int]map[int]Record{}
+	for _, domainID := range domainIDs {
+		domain, err := c.Domains().Get(ctx, domainID)
+		if err != nil {
+			return result, fmt.Errorf("snapshot of domain %d: %w", domainID, err)
+		}
+		records, err := c.Records(domainID).list(ctx)
+		if err != nil {
+			return result, fmt.Errorf("snapshot of %s: %w", domain.Name, err)
+		}
+		path := filepath.Join(opts.SnapshotDir,
+			fmt.Sprintf("%s-%s.json", domain.Name, plan.Created.UTC().Format("20060102T150405Z")))
+		if err := writeJSONFile(path, ZoneExport{domain, records}); err != nil {
+			return result, fmt.Errorf("snapshot of %s: %w", domain.Name, err)
+		}
+		result.Snapshots[domainID] = path
+
+		current[domainID] = map[int]Record{}
+		for _, record := range records {
+			current[domainID][record.ID] = record
+		}
+	}
+
+	for _, domainID := range domainIDs {
+		var reap []DanglingRecord
+		for _, d := range byDomain[domainID] {
+			if record, ok := current[domainID][d.Record.ID]; ok && record == d.Record {
+				reap = append(reap, d)
+			} else {
+				result.Skipped = append(result.Skipped, d)
+			}
+		}
+		err := chunkedBy(batchSize, len(reap), func(start, end int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			c.throttle(opts.Reserve)
+			ids := make([]int, 0, end-start)
+			for _, d := range reap[start:end] {
+				ids = append(ids, d.Record.ID)
+			}
+			if _, err := c.Records(domainID).DeleteMulti(ctx, ids); err != nil {
+				return err
+			}
+			result.Deleted = append(result.Deleted, reap[start:end]...)
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("domain %d: %w", domainID, err)
+		}
+	}
+	return result, nil
+}
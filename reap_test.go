@@ -0,0 +1,69 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReap(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1"},
+		Record{Name: "old1", Type: "CNAME", Value: "gone1.example.net."},
+		Record{Name: "old2", Type: "CNAME", Value: "gone2.example.net."},
+		Record{Name: "old3", Type: "CNAME", Value: "gone3.example.net."},
+	)
+	resolver := &fakeResolver{calls: map[string]int{}}
+
+	plan, err := client.PlanReap(context.Background(), []int{domain.ID}, DanglingOptions{Resolver: resolver})
+	require.NoError(t, err)
+	require.Len(t, plan.Records, 3)
+
+	dir := t.TempDir()
+	_, err = client.Reap(context.Background(), plan, ReapOptions{SnapshotDir: dir})
+	assert.ErrorIs(t, err, ErrNotApproved)
+	assert.Len(t, fake.recordList(domain.ID), 4)
+
+	// a record changed after planning is left alone
+	fake.mu.Lock()
+	changed := fake.records[domain.ID][plan.Records[2].Record.ID]
+	changed.Value = "new.example.net."
+	fake.records[domain.ID][changed.ID] = changed
+	fake.mu.Unlock()
+
+	plan.Approve()
+	result, err := client.Reap(context.Background(), plan, ReapOptions{SnapshotDir: dir, BatchSize: 1})
+	require.NoError(t, err)
+	assert.Len(t, result.Deleted, 2)
+	require.Len(t, result.Skipped, 1)
+	assert.Equal(t, "old3", result.Skipped[0].Record.Name)
+	assert.Equal(t, 2, fake.calls[fmt.Sprintf("DELETE /dns/managed/%d/records/", domain.ID)])
+
+	var names []string
+	for _, record := range fake.recordList(domain.ID) {
+		names = append(names, record.Name)
+	}
+	assert.ElementsMatch(t, []string{"www", "old3"}, names)
+
+	data, err := os.ReadFile(result.Snapshots[domain.ID])
+	require.NoError(t, err)
+	var snapshot ZoneExport
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+	assert.Equal(t, "example.com", snapshot.Domain.Name)
+	assert.Len(t, snapshot.Records, 4)
+}
+
+func TestReapForce(t *testing.T) {
+	_, client := newFakeDME(t)
+	plan := &ReapPlan{}
+	_, err := client.Reap(context.Background(), plan, ReapOptions{Force: true})
+	assert.EqualError(t, err, "reap requires a snapshot directory")
+	_, err = client.Reap(context.Background(), plan, ReapOptions{Force: true, SnapshotDir: t.TempDir()})
+	assert.NoError(t, err)
+}
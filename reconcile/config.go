@@ -0,0 +1,128 @@
+package reconcile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings for running a reconciler, read from YAML:
+//
+//	dir: zones/
+//	interval: 5m
+//	listen: ":9153"
+//	policy:
+//	  maxDeletes: 20
+//	  createDomains: true
+//	notify:
+//	  slack: https://hooks.slack.com/services/...
+//
+// Set git instead of dir to read specs from a repository, or inventory
+// to generate them from a host inventory file.
+type Config struct {
+	// A spec file or directory of them
+	Dir string `yaml:"dir"`
+
+	Git *GitSource `yaml:"git"`
+
+	// A host inventory file
+	Inventory string `yaml:"inventory"`
+
+	Interval time.Duration `yaml:"interval"`
+
+	// Address to serve metrics and health checks on, if any
+	Listen string `yaml:"listen"`
+
+	Policy Policy `yaml:"policy"`
+
+	// Where record labels are kept: "zone" for companion TXT records in
+	// each zone, otherwise a JSON file. Needed by policy.selector.
+	Labels string `yaml:"labels"`
+
+	Notify NotifyConfig `yaml:"notify"`
+}
+
+// Where events are sent
+type NotifyConfig struct {
+	Webhooks     []string `yaml:"webhooks"`
+	Slack        string   `yaml:"slack"`
+	SlackChannel string   `yaml:"slackChannel"`
+}
+
+// Reads a YAML config file. Relative paths in it are taken relative to
+// the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	sources := 0
+	for _, set := range []bool{cfg.Dir != "", cfg.Git != nil, cfg.Inventory != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return Config{}, fmt.Errorf("%s: exactly one of dir, git and inventory must be set", path)
+	}
+	if cfg.Git != nil && (cfg.Git.URL == "" || cfg.Git.Checkout == "") {
+		return Config{}, fmt.Errorf("%s: git needs url and checkout", path)
+	}
+	base := filepath.Dir(path)
+	if cfg.Dir != "" && !filepath.IsAbs(cfg.Dir) {
+		cfg.Dir = filepath.Join(base, cfg.Dir)
+	}
+	if cfg.Inventory != "" && !filepath.IsAbs(cfg.Inventory) {
+		cfg.Inventory = filepath.Join(base, cfg.Inventory)
+	}
+	if cfg.Labels != "" && cfg.Labels != "zone" && !filepath.IsAbs(cfg.Labels) {
+		cfg.Labels = filepath.Join(base, cfg.Labels)
+	}
+	if cfg.Git != nil && !filepath.IsAbs(cfg.Git.Checkout) {
+		cfg.Git.Checkout = filepath.Join(base, cfg.Git.Checkout)
+	}
+	return cfg, nil
+}
+
+// Builds the reconciler the config describes
+func (c Config) Reconciler(client *dme.Client) *Reconciler {
+	r := &Reconciler{
+		Client:   client,
+		Source:   DirSource{Path: c.Dir},
+		Interval: c.Interval,
+		Policy:   c.Policy,
+	}
+	if c.Git != nil {
+		r.Source = *c.Git
+	}
+	if c.Inventory != "" {
+		r.Source = InventorySource{Path: c.Inventory}
+	}
+	switch c.Labels {
+	case "":
+	case "zone":
+		r.Labels = dme.TXTAnnotations{Client: client}
+	default:
+		r.Labels = &dme.FileAnnotations{Path: c.Labels}
+	}
+
+	var notifiers dme.Notifiers
+	for _, url := range c.Notify.Webhooks {
+		notifiers = append(notifiers, dme.WebhookNotifier{URL: url})
+	}
+	if c.Notify.Slack != "" {
+		notifiers = append(notifiers, dme.SlackNotifier{WebhookURL: c.Notify.Slack, Channel: c.Notify.SlackChannel})
+	}
+	if len(notifiers) > 0 {
+		r.Notifier = notifiers
+	}
+	return r
+}
@@ -0,0 +1,75 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+)
+
+// Coordinates replicas of the reconciler, typically over a shared store
+// such as a database, Consul or a Kubernetes Lease, so only one applies
+// changes to a zone at a time
+type Locker interface {
+	// Takes the lock on zone for ttl, reporting false if another holder
+	// has it
+	Acquire(ctx context.Context, zone string, ttl time.Duration) (bool, error)
+
+	// Extends a held lock by ttl
+	Renew(ctx context.Context, zone string, ttl time.Duration) error
+
+	// Gives up a held lock
+	Release(ctx context.Context, zone string) error
+}
+
+// The default Locker, which always grants the lock
+type NopLocker struct{}
+
+func (NopLocker) Acquire(context.Context, string, time.Duration) (bool, error) { return true, nil }
+func (NopLocker) Renew(context.Context, string, time.Duration) error           { return nil }
+func (NopLocker) Release(context.Context, string) error                        { return nil }
+
+// How long locks are taken for when Reconciler.LockTTL is zero
+const DefaultLockTTL = time.Minute
+
+// Takes the lock on zone and keeps renewing it until the returned
+// function is called, which releases it
+func (r *Reconciler) lock(ctx context.Context, zone string) (bool, func(), error) {
+	locker := r.Locker
+	if locker == nil {
+		locker = NopLocker{}
+	}
+	ttl := r.LockTTL
+	if ttl == 0 {
+		ttl = DefaultLockTTL
+	}
+
+	ok, err := locker.Acquire(ctx, zone, ttl)
+	if err != nil || !ok {
+		return false, nil, err
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := locker.Renew(ctx, zone, ttl); err != nil {
+					r.logger().Printf("reconcile: %s: renewing lock: %v", zone, err)
+				}
+			}
+		}
+	}()
+	return true, func() {
+		close(done)
+		<-stopped
+		// release even if ctx was cancelled mid-run
+		if err := locker.Release(context.WithoutCancel(ctx), zone); err != nil {
+			r.logger().Printf("reconcile: %s: releasing lock: %v", zone, err)
+		}
+	}, nil
+}
@@ -0,0 +1,77 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/john-k/dnsmadeeasy/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// An in-memory lock table shared by replicas in the test
+type memoryLocker struct {
+	mu      sync.Mutex
+	holders map[string]string
+	log     []string
+
+	// the replica this view acts as
+	id string
+}
+
+func (l *memoryLocker) as(id string) *memoryLocker {
+	return &memoryLocker{holders: l.holders, id: id}
+}
+
+func (l *memoryLocker) Acquire(ctx context.Context, zone string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.log = append(l.log, "acquire "+zone)
+	if holder, ok := l.holders[zone]; ok && holder != l.id {
+		return false, nil
+	}
+	l.holders[zone] = l.id
+	return true, nil
+}
+
+func (l *memoryLocker) Renew(ctx context.Context, zone string, ttl time.Duration) error {
+	return nil
+}
+
+func (l *memoryLocker) Release(ctx context.Context, zone string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.log = append(l.log, "release "+zone)
+	delete(l.holders, zone)
+	return nil
+}
+
+func TestReconcilerLocker(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+	domain := account.AddDomain("example.com")
+	spec := staticSource{Domains: []seed.DomainSpec{
+		{Name: "example.com", Records: []seed.RecordSpec{{Name: "www", Type: "A", Value: "192.0.2.1"}}},
+	}}
+
+	shared := &memoryLocker{holders: map[string]string{"example.com": "other"}}
+	locker := shared.as("me")
+	r := &Reconciler{Client: client, Source: spec, Locker: locker, Logger: log.New(&bytes.Buffer{}, "", 0)}
+
+	results, err := r.Once(context.Background())
+	require.NoError(t, err)
+	assert.True(t, results[0].Locked)
+	assert.Empty(t, account.Records(domain.ID))
+
+	delete(shared.holders, "example.com")
+	results, err = r.Once(context.Background())
+	require.NoError(t, err)
+	assert.True(t, results[0].Applied)
+	assert.Len(t, account.Records(domain.ID), 1)
+	assert.Equal(t, []string{"acquire example.com", "acquire example.com", "release example.com"}, locker.log)
+	assert.Empty(t, shared.holders)
+}
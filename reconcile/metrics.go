@@ -0,0 +1,101 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// Counters describing the reconciler's work, for monitoring
+type Metrics struct {
+	mu   sync.Mutex
+	snap MetricsSnapshot
+}
+
+type MetricsSnapshot struct {
+	Runs           int
+	FailedRuns     int
+	Created        int
+	Updated        int
+	Deleted        int
+	PolicyBlocks   int
+	LastRun        time.Time
+	LastSuccess    time.Time
+	LastDuration   time.Duration
+	PendingChanges int
+}
+
+func (m *Metrics) run(start time.Time, results []Result, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &m.snap
+	s.Runs++
+	s.LastRun = start
+	s.LastDuration = time.Since(start)
+	s.PendingChanges = 0
+	for _, result := range results {
+		if result.Err != nil && errors.Is(result.Err, ErrPolicy) {
+			s.PolicyBlocks++
+		}
+		if !result.Applied {
+			s.PendingChanges += len(result.Plan)
+			continue
+		}
+		for _, op := range result.Plan {
+			switch op.Type {
+			case dme.OpCreate:
+				s.Created++
+			case dme.OpUpdate:
+				s.Updated++
+			case dme.OpDelete:
+				s.Deleted++
+			}
+		}
+	}
+	if err != nil {
+		s.FailedRuns++
+		return
+	}
+	s.LastSuccess = start
+}
+
+func (m *Metrics) snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snap
+}
+
+// Writes the snapshot in the Prometheus text exposition format
+func (s MetricsSnapshot) WritePrometheus(w io.Writer) error {
+	metrics := []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"dme_reconcile_runs_total", "counter", "Reconciliation runs.", float64(s.Runs)},
+		{"dme_reconcile_failed_runs_total", "counter", "Reconciliation runs that failed for any zone.", float64(s.FailedRuns)},
+		{"dme_reconcile_records_created_total", "counter", "Records created.", float64(s.Created)},
+		{"dme_reconcile_records_updated_total", "counter", "Records updated.", float64(s.Updated)},
+		{"dme_reconcile_records_deleted_total", "counter", "Records deleted.", float64(s.Deleted)},
+		{"dme_reconcile_policy_blocks_total", "counter", "Zone plans refused by policy.", float64(s.PolicyBlocks)},
+		{"dme_reconcile_pending_changes", "gauge", "Planned changes not applied by the last run.", float64(s.PendingChanges)},
+		{"dme_reconcile_last_run_duration_seconds", "gauge", "Duration of the last run.", s.LastDuration.Seconds()},
+		{"dme_reconcile_last_success_timestamp_seconds", "gauge", "Start of the last successful run.", unixSeconds(s.LastSuccess)},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", m.name, m.help, m.name, m.kind, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
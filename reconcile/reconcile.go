@@ -0,0 +1,435 @@
+// Package reconcile runs DNS Made Easy as a GitOps controller: desired
+// zone state is read periodically from spec files, in a directory or a
+// Git checkout, and the account is changed to match.
+//
+// Desired state uses the seed package's YAML spec. Every domain listed
+// is owned entirely by the spec, so records missing from it are deleted;
+// domains not listed are left alone.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/seed"
+)
+
+// The interval between runs when Reconciler.Interval is zero
+const DefaultInterval = 5 * time.Minute
+
+// Returned, wrapped, when a zone's plan is refused by the Policy
+var ErrPolicy = errors.New("refused by policy")
+
+// Safeguards checked before any change is made
+type Policy struct {
+	// The most records a single run may delete from a zone; zero means
+	// no limit
+	MaxDeletes int `yaml:"maxDeletes"`
+
+	// The largest share of a zone's existing records a single run may
+	// change, between 0 and 1; zero means no limit. Guards against a
+	// truncated or mistaken spec wiping out a zone.
+	MaxChangeFraction float64 `yaml:"maxChangeFraction"`
+
+	// Create domains in the spec that the account doesn't have
+	CreateDomains bool `yaml:"createDomains"`
+
+	// Record names, relative to their zone, that are never changed or
+	// deleted, such as "_acme-challenge" records managed elsewhere
+	Protected []string `yaml:"protected"`
+
+	// Only records whose labels match this selector, such as
+	// "managed-by=gitops", are managed; others are left alone. Needs
+	// Reconciler.Labels. See dme.ParseSelector.
+	Selector string `yaml:"selector"`
+
+	// Plan and report but don't change anything
+	DryRun bool `yaml:"dryRun"`
+}
+
+// Returns an error wrapping ErrPolicy if the plan for a zone of
+// existing records breaks the policy
+func (p Policy) Check(plan []dme.Operation, existing int) error {
+	deletes, changes := 0, 0
+	for _, op := range plan {
+		if op.Type == dme.OpDelete {
+			deletes++
+		}
+		if op.Type != dme.OpCreate {
+			changes++
+		}
+	}
+	if p.MaxDeletes > 0 && deletes > p.MaxDeletes {
+		return fmt.Errorf("%w: %d deletes exceed the limit of %d", ErrPolicy, deletes, p.MaxDeletes)
+	}
+	if p.MaxChangeFraction > 0 && existing > 0 && float64(changes)/float64(existing) > p.MaxChangeFraction {
+		return fmt.Errorf("%w: changing %d of %d records exceeds %.0f%%", ErrPolicy, changes, existing, p.MaxChangeFraction*100)
+	}
+	return nil
+}
+
+func (p Policy) protected(name string) bool {
+	for _, protected := range p.Protected {
+		if strings.EqualFold(protected, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the operations that change current into desired. Records are
+// matched by name, type and value; matches whose other settings differ
+// are updated. Creates come first and deletes last, so a failure part
+// way leaves a zone with extra records rather than missing ones.
+func Plan(domainID int, current, desired []dme.Record) []dme.Operation {
+	existing := map[string][]dme.Record{}
+	for _, record := range current {
+		existing[dme.RecordKey(record)] = append(existing[dme.RecordKey(record)], record)
+	}
+
+	var creates, updates, deletes []dme.Operation
+	for _, want := range desired {
+		k := dme.RecordKey(want)
+		matches := existing[k]
+		if len(matches) == 0 {
+			want.ID = 0
+			creates = append(creates, dme.Operation{Type: dme.OpCreate, DomainID: domainID, Record: want})
+			continue
+		}
+		have := matches[0]
+		existing[k] = matches[1:]
+		if !sameSettings(have, want) {
+			want.ID = have.ID
+			updates = append(updates, dme.Operation{Type: dme.OpUpdate, DomainID: domainID, Record: want})
+		}
+	}
+	for _, records := range existing {
+		for _, record := range records {
+			deletes = append(deletes, dme.Operation{Type: dme.OpDelete, DomainID: domainID, Record: record})
+		}
+	}
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Record.ID < deletes[j].Record.ID })
+
+	return append(append(creates, updates...), deletes...)
+}
+
+// Reports whether the settings a spec can express match
+func sameSettings(have, want dme.Record) bool {
+	return have.Ttl == want.Ttl &&
+		strings.EqualFold(have.GtdLocation, want.GtdLocation) &&
+		have.MxLevel == want.MxLevel &&
+		have.Priority == want.Priority &&
+		have.Weight == want.Weight &&
+		have.Port == want.Port
+}
+
+// Periodically brings the account in line with a Source
+type Reconciler struct {
+	Client *dme.Client
+	Source Source
+
+	// DefaultInterval if zero
+	Interval time.Duration
+
+	Policy Policy
+
+	// Told of every zone changed or failed; optional
+	Notifier dme.Notifier
+
+	// Consulted before each run, so only one of several replicas makes
+	// changes. Runs are skipped while it returns false. Optional.
+	Leader func(ctx context.Context) bool
+
+	// Taken per zone before it is read and changed, so replicas never
+	// apply changes to the same zone at once. NopLocker if nil.
+	Locker Locker
+
+	// How long zone locks are taken and renewed for; DefaultLockTTL if
+	// zero
+	LockTTL time.Duration
+
+	// Where record labels are kept. Spec records' labels are written
+	// here, and Policy.Selector is matched against it. Optional.
+	Labels dme.AnnotationStore
+
+	// log.Default() if nil
+	Logger *log.Logger
+
+	metrics Metrics
+}
+
+// The outcome of reconciling one zone
+type Result struct {
+	Domain   string
+	DomainID int
+	Plan     []dme.Operation
+
+	// The zone's managed records when Plan was made, the before side of
+	// its updates and deletes
+	Current []dme.Record
+
+	// Whether Plan was applied; false for dry runs and failures
+	Applied bool
+
+	// Whether the zone was skipped because another replica held its
+	// lock
+	Locked bool
+
+	Err error
+}
+
+// Reconciles every Interval until ctx is done. Failed runs are logged
+// and retried at the next interval.
+func (r *Reconciler) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	for {
+		if r.Leader == nil || r.Leader(ctx) {
+			if _, err := r.Once(ctx); err != nil {
+				r.logger().Printf("reconcile: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Loads the desired state and reconciles each of its zones once
+func (r *Reconciler) Once(ctx context.Context) ([]Result, error) {
+	start := time.Now()
+	spec, err := r.Source.Load(ctx)
+	if err != nil {
+		r.metrics.run(start, nil, err)
+		return nil, fmt.Errorf("loading desired state: %w", err)
+	}
+
+	results, err := r.reconcile(ctx, spec.Domains)
+	r.metrics.run(start, results, err)
+	return results, err
+}
+
+// Reconciles only the listed zones, for callers that know which specs
+// changed
+func (r *Reconciler) Zones(ctx context.Context, domains []seed.DomainSpec) ([]Result, error) {
+	start := time.Now()
+	results, err := r.reconcile(ctx, domains)
+	r.metrics.run(start, results, err)
+	return results, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, domains []seed.DomainSpec) ([]Result, error) {
+	var results []Result
+	var errs []error
+	for _, domain := range domains {
+		result := r.zone(ctx, domain)
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, result.Err))
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+func (r *Reconciler) zone(ctx context.Context, spec seed.DomainSpec) Result {
+	result := Result{Domain: spec.Name}
+	locked, unlock, err := r.lock(ctx, spec.Name)
+	if err != nil {
+		result.Err = fmt.Errorf("taking lock: %w", err)
+		r.notify(ctx, result)
+		return result
+	}
+	if !locked {
+		r.logger().Printf("reconcile: %s: locked by another replica, skipped", spec.Name)
+		result.Locked = true
+		return result
+	}
+	defer unlock()
+
+	domainID, err := r.domainID(ctx, spec.Name)
+	if err != nil {
+		result.Err = err
+		r.notify(ctx, result)
+		return result
+	}
+	result.DomainID = domainID
+
+	current, err := r.Client.Records(domainID).List(ctx)
+	if err != nil {
+		result.Err = err
+		r.notify(ctx, result)
+		return result
+	}
+	desired := make([]dme.Record, 0, len(spec.Records))
+	for _, record := range spec.Records {
+		desired = append(desired, normalize(record.Record()))
+	}
+
+	// protected names and records outside the selector are invisible
+	// to the plan
+	sel, inScope, err := r.scope(ctx, domainID)
+	if err != nil {
+		result.Err = err
+		r.notify(ctx, result)
+		return result
+	}
+	var managed []dme.Record
+	for _, record := range current {
+		if !r.Policy.protected(record.Name) && inScope(record) {
+			managed = append(managed, record)
+		}
+	}
+	for _, record := range desired {
+		if r.Policy.protected(record.Name) {
+			result.Err = fmt.Errorf("%w: %q is protected", ErrPolicy, record.Name)
+			r.notify(ctx, result)
+			return result
+		}
+	}
+	for _, record := range spec.Records {
+		if !sel.Matches(record.Labels) {
+			result.Err = fmt.Errorf("%w: %s %q doesn't match selector %s", ErrPolicy, record.Type, record.Name, sel)
+			r.notify(ctx, result)
+			return result
+		}
+	}
+
+	result.Current = managed
+	result.Plan = Plan(domainID, managed, desired)
+	if len(result.Plan) == 0 {
+		if !r.Policy.DryRun {
+			if result.Err = r.saveLabels(ctx, domainID, spec.Records); result.Err != nil {
+				r.notify(ctx, result)
+			}
+		}
+		return result
+	}
+	if err := r.Policy.Check(result.Plan, len(managed)); err != nil {
+		result.Err = err
+		r.notify(ctx, result)
+		return result
+	}
+	if r.Policy.DryRun {
+		r.logger().Printf("reconcile: %s: dry run, %d changes planned", spec.Name, len(result.Plan))
+		return result
+	}
+
+	if _, err := r.Client.ApplyContext(ctx, result.Plan); err != nil {
+		result.Err = err
+	} else {
+		result.Applied = true
+		result.Err = r.saveLabels(ctx, domainID, spec.Records)
+	}
+	r.notify(ctx, result)
+	return result
+}
+
+// Returns the policy's selector and whether a record falls within it.
+// Companion TXT records holding labels are never in scope.
+func (r *Reconciler) scope(ctx context.Context, domainID int) (dme.Selector, func(dme.Record) bool, error) {
+	sel, err := dme.ParseSelector(r.Policy.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrPolicy, err)
+	}
+	if r.Labels == nil {
+		if len(sel) > 0 {
+			return nil, nil, fmt.Errorf("%w: selector %s needs a label store", ErrPolicy, sel)
+		}
+		return sel, func(dme.Record) bool { return true }, nil
+	}
+	labels, err := dme.LabelsByRecord(ctx, r.Labels, domainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sel, func(record dme.Record) bool {
+		return !strings.HasPrefix(record.Name, dme.AnnotationLabel) && sel.Matches(labels(record))
+	}, nil
+}
+
+// Writes the labels of spec records that have them to the label store
+func (r *Reconciler) saveLabels(ctx context.Context, domainID int, records []seed.RecordSpec) error {
+	if r.Labels == nil {
+		return nil
+	}
+	var errs []error
+	for _, record := range records {
+		if record.Labels == nil {
+			continue
+		}
+		if err := dme.SetLabels(ctx, r.Labels, domainID, record.Name, record.Type, record.Labels); err != nil {
+			errs = append(errs, fmt.Errorf("labelling %s %q: %w", record.Type, record.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) domainID(ctx context.Context, name string) (int, error) {
+	id, err := r.Client.Domains().IdFor(ctx, name)
+	if !errors.Is(err, dme.ErrDomainNotFound) || !r.Policy.CreateDomains {
+		return id, err
+	}
+	if r.Policy.DryRun {
+		return 0, fmt.Errorf("domain does not exist; it would be created")
+	}
+	domain, err := r.Client.Domains().Create(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	domain, err = r.Client.WaitForDomain(ctx, domain.ID)
+	return domain.ID, err
+}
+
+func (r *Reconciler) notify(ctx context.Context, result Result) {
+	if r.Notifier == nil {
+		return
+	}
+	event := dme.Event{Kind: dme.EventSyncApplied, Time: time.Now(), DomainID: result.DomainID, Domain: result.Domain}
+	if result.Err != nil {
+		event.Kind = dme.EventSyncFailed
+		event.Error = result.Err.Error()
+	}
+	for _, op := range result.Plan {
+		switch op.Type {
+		case dme.OpCreate:
+			event.Created = append(event.Created, op.Record)
+		case dme.OpUpdate:
+			event.Updated = append(event.Updated, op.Record)
+		case dme.OpDelete:
+			event.Deleted = append(event.Deleted, op.Record)
+		}
+	}
+	if err := r.Notifier.Notify(ctx, event); err != nil {
+		r.logger().Printf("reconcile: notify: %v", err)
+	}
+}
+
+func (r *Reconciler) logger() *log.Logger {
+	if r.Logger == nil {
+		return log.Default()
+	}
+	return r.Logger
+}
+
+// Returns the counters of runs so far
+func (r *Reconciler) Metrics() MetricsSnapshot {
+	return r.metrics.snapshot()
+}
+
+// Spec files may write the apex as "@"
+func normalize(record dme.Record) dme.Record {
+	if record.Name == "@" {
+		record.Name = ""
+	}
+	record.Type = strings.ToUpper(record.Type)
+	return record
+}
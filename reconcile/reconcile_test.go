@@ -0,0 +1,236 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/john-k/dnsmadeeasy/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A Source returning a fixed spec
+type staticSource seed.Spec
+
+func (s staticSource) Load(ctx context.Context) (seed.Spec, error) {
+	return seed.Spec(s), nil
+}
+
+func TestPlan(t *testing.T) {
+	current := []dme.Record{
+		{ID: 1, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{ID: 2, Name: "old", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "DEFAULT"},
+		{ID: 3, Name: "", Type: "MX", Value: "mail.example.com.", MxLevel: 10, Ttl: 300, GtdLocation: "DEFAULT"},
+	}
+	desired := []dme.Record{
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "", Type: "MX", Value: "MAIL.example.com", MxLevel: 20, Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "new", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: "DEFAULT"},
+	}
+
+	plan := Plan(7, current, desired)
+	require.Len(t, plan, 3)
+	assert.Equal(t, dme.Operation{Type: dme.OpCreate, DomainID: 7, Record: desired[2]}, plan[0])
+	assert.Equal(t, dme.OpUpdate, plan[1].Type)
+	assert.Equal(t, 3, plan[1].Record.ID)
+	assert.Equal(t, 20, plan[1].Record.MxLevel)
+	assert.Equal(t, dme.Operation{Type: dme.OpDelete, DomainID: 7, Record: current[1]}, plan[2])
+
+	assert.Empty(t, Plan(7, current[:1], desired[:1]))
+}
+
+func TestPolicyCheck(t *testing.T) {
+	plan := []dme.Operation{{Type: dme.OpDelete}, {Type: dme.OpDelete}, {Type: dme.OpCreate}}
+	assert.NoError(t, Policy{}.Check(plan, 2))
+	assert.ErrorIs(t, Policy{MaxDeletes: 1}.Check(plan, 10), ErrPolicy)
+	assert.NoError(t, Policy{MaxChangeFraction: 0.5}.Check(plan, 4))
+	assert.ErrorIs(t, Policy{MaxChangeFraction: 0.5}.Check(plan, 3), ErrPolicy)
+}
+
+func TestReconcilerOnce(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+	domain := account.AddDomain("example.com",
+		dme.Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"},
+		dme.Record{Name: "stale", Type: "A", Value: "192.0.2.2", Ttl: 1800, GtdLocation: "DEFAULT"},
+		dme.Record{Name: "_acme-challenge", Type: "TXT", Value: `"token"`, Ttl: 60, GtdLocation: "DEFAULT"},
+	)
+	existing := account.Records(domain.ID)
+	spec := staticSource{Domains: []seed.DomainSpec{
+		{Name: "example.com", Records: []seed.RecordSpec{
+			{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+			{Name: "@", Type: "a", Value: "192.0.2.5"},
+		}},
+		{Name: "new.example", Records: []seed.RecordSpec{{Name: "www", Type: "A", Value: "192.0.2.9"}}},
+	}}
+
+	var events []dme.Event
+	r := &Reconciler{
+		Client: client,
+		Source: spec,
+		Policy: Policy{Protected: []string{"_acme-challenge"}, DryRun: true, CreateDomains: true},
+		Notifier: dme.NotifierFunc(func(ctx context.Context, e dme.Event) error {
+			events = append(events, e)
+			return nil
+		}),
+		Logger: log.New(&bytes.Buffer{}, "", 0),
+	}
+
+	// a dry run changes nothing and can't create domains
+	results, err := r.Once(context.Background())
+	assert.ErrorContains(t, err, "new.example: domain does not exist")
+	require.Len(t, results, 2)
+	assert.Len(t, results[0].Plan, 3)
+	assert.False(t, results[0].Applied)
+	assert.Len(t, account.Records(domain.ID), 3)
+	assert.Equal(t, 3, r.Metrics().PendingChanges)
+
+	r.Policy.DryRun = false
+	events = nil
+	results, err = r.Once(context.Background())
+	require.NoError(t, err)
+	assert.True(t, results[0].Applied)
+	records := account.Records(domain.ID)
+	require.Len(t, records, 3)
+	assert.Equal(t, []dme.Record{
+		{ID: existing[0].ID, SourceId: domain.ID, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{ID: existing[2].ID, SourceId: domain.ID, Name: "_acme-challenge", Type: "TXT", Value: `"token"`, Ttl: 60, GtdLocation: "DEFAULT"},
+		{ID: records[2].ID, SourceId: domain.ID, Name: "", Type: "A", Value: "192.0.2.5", Ttl: seed.DefaultTTL, GtdLocation: "DEFAULT"},
+	}, records)
+	domains := account.Domains()
+	require.Len(t, domains, 2)
+	assert.Equal(t, "new.example", domains[1].Name)
+	assert.Len(t, account.Records(domains[1].ID), 1)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, dme.EventSyncApplied, events[0].Kind)
+	assert.Equal(t, "example.com", events[0].Domain)
+	assert.Len(t, events[0].Created, 1)
+	assert.Len(t, events[0].Updated, 1)
+	assert.Len(t, events[0].Deleted, 1)
+
+	metrics := r.Metrics()
+	assert.Equal(t, 2, metrics.Runs)
+	assert.Equal(t, 1, metrics.FailedRuns)
+	assert.Equal(t, 2, metrics.Created)
+	assert.Equal(t, 0, metrics.PendingChanges)
+
+	// converged
+	events = nil
+	results, err = r.Once(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Plan)
+	assert.Empty(t, events)
+}
+
+func TestReconcilerPolicyBlocks(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+	var records []dme.Record
+	for idx := range 5 {
+		records = append(records, dme.Record{Name: "host" + strconv.Itoa(idx), Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"})
+	}
+	domain := account.AddDomain("example.com", records...)
+
+	var events []dme.Event
+	r := &Reconciler{
+		Client: client,
+		Source: staticSource{Domains: []seed.DomainSpec{{Name: "example.com"}}},
+		Policy: Policy{MaxDeletes: 3},
+		Notifier: dme.NotifierFunc(func(ctx context.Context, e dme.Event) error {
+			events = append(events, e)
+			return nil
+		}),
+	}
+	_, err := r.Once(context.Background())
+	assert.ErrorIs(t, err, ErrPolicy)
+	assert.Len(t, account.Records(domain.ID), 5)
+	require.Len(t, events, 1)
+	assert.Equal(t, dme.EventSyncFailed, events[0].Kind)
+	assert.Equal(t, 1, r.Metrics().PolicyBlocks)
+}
+
+func TestReconcilerSelector(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+	domain := account.AddDomain("example.com",
+		dme.Record{Name: "legacy", Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"},
+		dme.Record{Name: "web", Type: "A", Value: "192.0.2.2", Ttl: 1800, GtdLocation: "DEFAULT"},
+	)
+	labels := &dme.FileAnnotations{Path: filepath.Join(t.TempDir(), "labels.json")}
+	ctx := context.Background()
+	require.NoError(t, dme.SetLabels(ctx, labels, domain.ID, "web", "A", map[string]string{"managed-by": "gitops"}))
+
+	gitops := map[string]string{"managed-by": "gitops"}
+	spec := seed.DomainSpec{Name: "example.com", Records: []seed.RecordSpec{
+		{Name: "web", Type: "A", Value: "192.0.2.20", Labels: map[string]string{"managed-by": "gitops", "env": "prod"}},
+		{Name: "api", Type: "A", Value: "192.0.2.3", Labels: gitops},
+	}}
+	r := &Reconciler{
+		Client: client,
+		Source: staticSource{Domains: []seed.DomainSpec{spec}},
+		Policy: Policy{Selector: "managed-by=gitops"},
+		Labels: labels,
+	}
+	_, err := r.Once(ctx)
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, record := range account.Records(domain.ID) {
+		values[record.Name] = record.Value
+	}
+	assert.Equal(t, map[string]string{"legacy": "192.0.2.1", "web": "192.0.2.20", "api": "192.0.2.3"}, values, "unlabelled records are left alone")
+	a, _, err := labels.Get(ctx, domain.ID, "web", "A")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"managed-by": "gitops", "env": "prod"}, a.Labels)
+	a, _, err = labels.Get(ctx, domain.ID, "api", "A")
+	require.NoError(t, err)
+	assert.Equal(t, gitops, a.Labels)
+
+	results, err := r.Once(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, results[0].Plan)
+
+	spec.Records = append(spec.Records, seed.RecordSpec{Name: "mail", Type: "A", Value: "192.0.2.4"})
+	r.Source = staticSource{Domains: []seed.DomainSpec{spec}}
+	_, err = r.Once(ctx)
+	assert.ErrorIs(t, err, ErrPolicy, "spec records must match the selector")
+
+	r.Labels = nil
+	_, err = r.Once(ctx)
+	assert.ErrorIs(t, err, ErrPolicy, "selectors need a label store")
+}
+
+func TestRunSkipsWhenNotLeader(t *testing.T) {
+	loads := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Reconciler{
+		Source: sourceFunc(func(ctx context.Context) (seed.Spec, error) {
+			loads++
+			return seed.Spec{}, errors.New("unexpected load")
+		}),
+		Leader: func(ctx context.Context) bool {
+			cancel()
+			return false
+		},
+	}
+	assert.ErrorIs(t, r.Run(ctx), context.Canceled)
+	assert.Zero(t, loads)
+}
+
+type sourceFunc func(ctx context.Context) (seed.Spec, error)
+
+func (f sourceFunc) Load(ctx context.Context) (seed.Spec, error) {
+	return f(ctx)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, MetricsSnapshot{Runs: 3, Deleted: 2}.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), "# TYPE dme_reconcile_runs_total counter\ndme_reconcile_runs_total 3\n")
+	assert.Contains(t, buf.String(), "dme_reconcile_records_deleted_total 2\n")
+}
@@ -0,0 +1,189 @@
+package reconcile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+)
+
+// ANSI colours for terminal diffs
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// One record change of a plan, with the record before and after
+type Change struct {
+	Type dme.OperationType
+
+	// nil for creates
+	Before *dme.Record
+
+	// nil for deletes
+	After *dme.Record
+}
+
+// Returns the changes of a result's plan, finding the before side of
+// updates in its Current records
+func (r Result) Changes() []Change {
+	current := map[int]dme.Record{}
+	for _, record := range r.Current {
+		current[record.ID] = record
+	}
+	changes := make([]Change, 0, len(r.Plan))
+	for _, op := range r.Plan {
+		record := op.Record
+		change := Change{Type: op.Type}
+		switch op.Type {
+		case dme.OpCreate:
+			change.After = &record
+		case dme.OpUpdate:
+			if before, ok := current[record.ID]; ok {
+				change.Before = &before
+			}
+			change.After = &record
+		case dme.OpDelete:
+			change.Before = &record
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Writes the plans of results as a diff for a terminal, coloured with
+// ANSI escapes if color is set
+func RenderText(w io.Writer, results []Result, color bool) error {
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	var b strings.Builder
+	for _, result := range results {
+		fmt.Fprintf(&b, "%s: %s\n", result.Domain, summary(result))
+		for _, change := range result.Changes() {
+			switch change.Type {
+			case dme.OpCreate:
+				b.WriteString(paint(ansiGreen, "  + "+describe(*change.After)) + "\n")
+			case dme.OpDelete:
+				b.WriteString(paint(ansiRed, "  - "+describe(*change.Before)) + "\n")
+			case dme.OpUpdate:
+				b.WriteString(paint(ansiYellow, "  ~ "+owner(*change.After)) + "\n")
+				if change.Before != nil {
+					b.WriteString(paint(ansiRed, "      - "+settings(*change.Before)) + "\n")
+				}
+				b.WriteString(paint(ansiGreen, "      + "+settings(*change.After)) + "\n")
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Writes the plans of results as Markdown, such as for a pull request
+// comment
+func RenderMarkdown(w io.Writer, results []Result) error {
+	var b strings.Builder
+	for idx, result := range results {
+		if idx > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", escapeMarkdown(result.Domain))
+		if result.Err != nil {
+			fmt.Fprintf(&b, "**Error:** %s\n\n", escapeMarkdown(result.Err.Error()))
+		}
+		changes := result.Changes()
+		if len(changes) == 0 {
+			fmt.Fprintf(&b, "%s.\n", capitalize(summary(result)))
+			continue
+		}
+		b.WriteString("| | Name | Type | Before | After |\n|---|---|---|---|---|\n")
+		for _, change := range changes {
+			var symbol, before, after string
+			record := change.After
+			switch change.Type {
+			case dme.OpCreate:
+				symbol = "+"
+			case dme.OpDelete:
+				symbol, record = "-", change.Before
+			case dme.OpUpdate:
+				symbol = "~"
+			}
+			if change.Before != nil {
+				before = "`" + settings(*change.Before) + "`"
+			}
+			if change.After != nil {
+				after = "`" + settings(*change.After) + "`"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", symbol,
+				escapeMarkdown(displayName(record.Name)), record.Type, escapeMarkdown(before), escapeMarkdown(after))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func summary(result Result) string {
+	switch {
+	case result.Locked:
+		return "skipped, locked by another replica"
+	case len(result.Plan) == 0 && result.Err != nil:
+		return "failed"
+	case len(result.Plan) == 0:
+		return "no changes"
+	case len(result.Plan) == 1:
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", len(result.Plan))
+}
+
+func describe(record dme.Record) string {
+	return owner(record) + " " + settings(record)
+}
+
+func owner(record dme.Record) string {
+	return displayName(record.Name) + " " + record.Type
+}
+
+// Returns a record's value and the settings that differ from their
+// defaults
+func settings(record dme.Record) string {
+	parts := []string{record.Value, fmt.Sprintf("ttl=%d", record.Ttl)}
+	if record.GtdLocation != "" && !strings.EqualFold(record.GtdLocation, dme.GtdDefault) {
+		parts = append(parts, "gtd="+record.GtdLocation)
+	}
+	for _, setting := range []struct {
+		name  string
+		value int
+	}{{"mx", record.MxLevel}, {"priority", record.Priority}, {"weight", record.Weight}, {"port", record.Port}} {
+		if setting.value != 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", setting.name, setting.value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
+}
+
+// Escapes characters that would break a Markdown table cell
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
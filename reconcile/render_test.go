@@ -0,0 +1,58 @@
+package reconcile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderResults() []Result {
+	current := []dme.Record{
+		{ID: 1, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"},
+		{ID: 2, Name: "old", Type: "TXT", Value: `"a|b"`, Ttl: 300, GtdLocation: "DEFAULT"},
+	}
+	desired := []dme.Record{
+		{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "", Type: "MX", Value: "mail.example.com.", Ttl: 1800, MxLevel: 10, GtdLocation: "DEFAULT"},
+	}
+	return []Result{
+		{Domain: "example.com", DomainID: 7, Current: current, Plan: Plan(7, current, desired)},
+		{Domain: "example.org", DomainID: 8},
+		{Domain: "new.example", Err: errors.New("domain does not exist")},
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, RenderText(&b, renderResults(), false))
+	assert.Equal(t, `example.com: 3 changes
+  + @ MX mail.example.com. ttl=1800 mx=10
+  ~ www A
+      - 192.0.2.1 ttl=1800
+      + 192.0.2.1 ttl=300
+  - old TXT "a|b" ttl=300
+example.org: no changes
+new.example: failed
+`, b.String())
+
+	b.Reset()
+	require.NoError(t, RenderText(&b, renderResults()[:1], true))
+	assert.Contains(t, b.String(), "\x1b[32m  + @ MX mail.example.com. ttl=1800 mx=10\x1b[0m\n")
+	assert.Contains(t, b.String(), "\x1b[31m  - old TXT")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, RenderMarkdown(&b, renderResults()))
+	assert.Equal(t, "### example.com\n\n"+
+		"| | Name | Type | Before | After |\n|---|---|---|---|---|\n"+
+		"| + | @ | MX |  | `mail.example.com. ttl=1800 mx=10` |\n"+
+		"| ~ | www | A | `192.0.2.1 ttl=1800` | `192.0.2.1 ttl=300` |\n"+
+		"| - | old | TXT | `\"a\\|b\" ttl=300` |  |\n"+
+		"\n### example.org\n\nNo changes.\n"+
+		"\n### new.example\n\n**Error:** domain does not exist\n\nFailed.\n", b.String())
+}
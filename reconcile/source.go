@@ -0,0 +1,173 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/seed"
+)
+
+// Where desired state comes from
+type Source interface {
+	Load(ctx context.Context) (seed.Spec, error)
+}
+
+// Reads every spec in a directory, or a single spec file. YAML specs
+// (.yaml, .yml) may describe several domains; zone files (.zone, .db)
+// describe the domain they are named after, such as example.com.zone.
+// A domain may only be described by one file.
+type DirSource struct {
+	Path string `yaml:"path"`
+}
+
+func (s DirSource) Load(ctx context.Context) (seed.Spec, error) {
+	files, err := s.files()
+	if err != nil {
+		return seed.Spec{}, err
+	}
+
+	var spec seed.Spec
+	owners := map[string]string{}
+	for _, path := range files {
+		domains, err := loadFile(path)
+		if err != nil {
+			return seed.Spec{}, err
+		}
+		for _, domain := range domains {
+			if owner, ok := owners[domain.Name]; ok {
+				return seed.Spec{}, fmt.Errorf("%s: domain %s is already described by %s", path, domain.Name, owner)
+			}
+			owners[domain.Name] = path
+			spec.Domains = append(spec.Domains, domain)
+		}
+	}
+	return spec, nil
+}
+
+// Returns the spec files the source reads, sorted
+func (s DirSource) files() ([]string, error) {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{s.Path}, nil
+	}
+	entries, err := os.ReadDir(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isSpecFile(entry.Name()) {
+			paths = append(paths, filepath.Join(s.Path, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func isSpecFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".zone", ".db":
+		return true
+	}
+	return false
+}
+
+// Reads the domains described by one spec file
+func loadFile(path string) ([]seed.DomainSpec, error) {
+	switch filepath.Ext(path) {
+	case ".zone", ".db":
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		zone := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		records, err := dme.ParseZoneFile(f, zone)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		domain := seed.DomainSpec{Name: zone}
+		for _, r := range records {
+			domain.Records = append(domain.Records, seed.RecordSpec{
+				Name: r.Name, Type: r.Type, Value: r.Value, Ttl: r.Ttl, GtdLocation: r.GtdLocation,
+				MxLevel: r.MxLevel, Priority: r.Priority, Weight: r.Weight, Port: r.Port,
+			})
+		}
+		return []seed.DomainSpec{domain}, nil
+	}
+	spec, err := seed.Load(path)
+	return spec.Domains, err
+}
+
+// Reads specs from a Git repository, cloning it into Checkout on first
+// use and fetching the branch before every load. Requires the git
+// command.
+type GitSource struct {
+	URL    string `yaml:"url"`
+	Branch string `yaml:"branch"`
+
+	// Local directory the repository is checked out to
+	Checkout string `yaml:"checkout"`
+
+	// Directory or file within the repository holding the specs; the
+	// root if empty
+	Path string `yaml:"path"`
+}
+
+// Runs git; replaced in tests
+var runGit = func(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, out)
+	}
+	return nil
+}
+
+func (s GitSource) Load(ctx context.Context) (seed.Spec, error) {
+	branch := s.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	if _, err := os.Stat(filepath.Join(s.Checkout, ".git")); err != nil {
+		if err := runGit(ctx, "", "clone", "--depth", "1", "--branch", branch, s.URL, s.Checkout); err != nil {
+			return seed.Spec{}, err
+		}
+	} else {
+		if err := runGit(ctx, s.Checkout, "fetch", "--depth", "1", "origin", branch); err != nil {
+			return seed.Spec{}, err
+		}
+		if err := runGit(ctx, s.Checkout, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return seed.Spec{}, err
+		}
+	}
+	return DirSource{Path: filepath.Join(s.Checkout, s.Path)}.Load(ctx)
+}
+
+// Generates desired state from a host inventory file, with
+// seed.GenerateRecords. Every zone the inventory's hosts touch is owned
+// entirely by it.
+type InventorySource struct {
+	Path string `yaml:"path"`
+}
+
+func (s InventorySource) Load(ctx context.Context) (seed.Spec, error) {
+	inventory, err := seed.LoadInventory(s.Path)
+	if err != nil {
+		return seed.Spec{}, err
+	}
+	spec, err := seed.GenerateRecords(inventory.Hosts)
+	if err != nil {
+		return seed.Spec{}, fmt.Errorf("%s: %w", s.Path, err)
+	}
+	return spec, nil
+}
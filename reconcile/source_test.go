@@ -0,0 +1,118 @@
+package reconcile
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "domains:\n  - name: a.example\n")
+	writeFile(t, filepath.Join(dir, "b.yml"), "domains:\n  - name: b.example\n")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "ignored")
+
+	spec, err := DirSource{Path: dir}.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, spec.Domains, 2)
+	assert.Equal(t, "a.example", spec.Domains[0].Name)
+
+	spec, err = DirSource{Path: filepath.Join(dir, "b.yml")}.Load(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, spec.Domains, 1)
+
+	writeFile(t, filepath.Join(dir, "c.yaml"), "domains:\n  - name: a.example\n")
+	_, err = DirSource{Path: dir}.Load(context.Background())
+	assert.ErrorContains(t, err, "already described by")
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(repo, "zones", "zones.yaml"), "domains:\n  - name: a.example\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	source := GitSource{URL: "file://" + repo, Checkout: filepath.Join(t.TempDir(), "checkout"), Path: "zones"}
+	spec, err := source.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, spec.Domains, 1)
+
+	writeFile(t, filepath.Join(repo, "zones", "zones.yaml"), "domains:\n  - name: a.example\n  - name: b.example\n")
+	git("commit", "-q", "-am", "second")
+	spec, err = source.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, spec.Domains, 2)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dme.yaml")
+	writeFile(t, path, `
+dir: zones
+interval: 90s
+listen: ":9153"
+policy:
+  maxDeletes: 5
+  protected: [_acme-challenge]
+notify:
+  webhooks: [https://example.com/hook]
+`)
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "zones"), cfg.Dir)
+	assert.Equal(t, 90*time.Second, cfg.Interval)
+	assert.Equal(t, Policy{MaxDeletes: 5, Protected: []string{"_acme-challenge"}}, cfg.Policy)
+
+	r := cfg.Reconciler(nil)
+	assert.Equal(t, DirSource{Path: filepath.Join(dir, "zones")}, r.Source)
+	assert.NotNil(t, r.Notifier)
+
+	writeFile(t, path, "inventory: hosts.yaml\n")
+	cfg, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, InventorySource{Path: filepath.Join(dir, "hosts.yaml")}, cfg.Reconciler(nil).Source)
+
+	writeFile(t, path, "interval: 1m\n")
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, "exactly one of dir, git and inventory")
+}
+
+func TestInventorySource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.yaml")
+	writeFile(t, path, "hosts:\n  - {hostname: web1.example.com, ipv4: [192.0.2.10]}\n")
+
+	spec, err := InventorySource{Path: path}.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, spec.Domains, 2)
+	assert.Equal(t, "2.0.192.in-addr.arpa", spec.Domains[0].Name)
+	assert.Equal(t, "example.com", spec.Domains[1].Name)
+
+	writeFile(t, path, "hosts:\n  - {hostname: web1.example.com, ipv4: [not-an-ip]}\n")
+	_, err = InventorySource{Path: path}.Load(context.Background())
+	assert.ErrorContains(t, err, "hosts.yaml: web1.example.com")
+}
@@ -0,0 +1,180 @@
+package reconcile
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/john-k/dnsmadeeasy/seed"
+)
+
+// Defaults for Watcher
+const (
+	DefaultPollInterval = time.Second
+	DefaultDebounce     = 2 * time.Second
+	DefaultReserve      = 10
+)
+
+// Watches a directory of spec files and reconciles the zones described
+// by each file soon after it changes, for editing DNS in a local
+// checkout and seeing it applied straight away.
+//
+// Changes are debounced: syncing waits until files have been quiet for
+// Debounce, so an editor's burst of writes or a git checkout triggers
+// one sync. Syncs are also held back while the account's remaining
+// request quota is at or below Reserve. Removing a file leaves its
+// zones as they are.
+type Watcher struct {
+	Reconciler *Reconciler
+
+	// The directory (or single file) of specs; see DirSource
+	Dir string
+
+	// How often files are checked; DefaultPollInterval if zero
+	PollInterval time.Duration
+
+	// DefaultDebounce if zero
+	Debounce time.Duration
+
+	// DefaultReserve if zero
+	Reserve int
+
+	// Reconcile every zone when starting, not only changed ones
+	InitialSync bool
+
+	files      map[string]fileState
+	pending    map[string]bool
+	lastChange time.Time
+}
+
+// What's compared to detect a changed file
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Polls until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	if _, err := w.scan(); err != nil {
+		return err
+	}
+	w.pending = map[string]bool{}
+	if w.InitialSync {
+		files, err := DirSource{Path: w.Dir}.files()
+		if err != nil {
+			return err
+		}
+		for _, path := range files {
+			w.pending[path] = true
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.tick(ctx, time.Now()); err != nil {
+			w.Reconciler.logger().Printf("reconcile: watch: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Notes changed files and syncs their zones once they have settled,
+// returning the results of any sync
+func (w *Watcher) tick(ctx context.Context, now time.Time) ([]Result, error) {
+	changed, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	if w.pending == nil {
+		w.pending = map[string]bool{}
+	}
+	for _, path := range changed {
+		w.pending[path] = true
+		w.lastChange = now
+	}
+	if len(w.pending) == 0 || now.Sub(w.lastChange) < w.debounce() {
+		return nil, nil
+	}
+	if rl := w.Reconciler.Client.RateLimit(); rl.Limit > 0 && rl.Remaining <= w.reserve() {
+		// wait for the quota to replenish; the changes stay pending
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(w.pending))
+	for path := range w.pending {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var domains []seed.DomainSpec
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			// removed files leave their zones alone
+			continue
+		}
+		specs, err := loadFile(path)
+		if err != nil {
+			// leave it pending; it may be mid-edit
+			return nil, err
+		}
+		domains = append(domains, specs...)
+	}
+	w.pending = map[string]bool{}
+	if len(domains) == 0 {
+		return nil, nil
+	}
+	return w.Reconciler.Zones(ctx, domains)
+}
+
+// Returns the spec files that are new, changed or removed since the
+// last scan
+func (w *Watcher) scan() ([]string, error) {
+	paths, err := DirSource{Path: w.Dir}.files()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]fileState, len(paths))
+	var changed []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		state := fileState{info.ModTime(), info.Size()}
+		current[path] = state
+		if prev, ok := w.files[path]; w.files != nil && (!ok || prev != state) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	w.files = current
+	return changed, nil
+}
+
+func (w *Watcher) debounce() time.Duration {
+	if w.Debounce == 0 {
+		return DefaultDebounce
+	}
+	return w.Debounce
+}
+
+func (w *Watcher) reserve() int {
+	if w.Reserve == 0 {
+		return DefaultReserve
+	}
+	return w.Reserve
+}
@@ -0,0 +1,92 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherTick(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+	a := account.AddDomain("a.example")
+	b := account.AddDomain("b.example")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), "domains:\n  - name: a.example\n")
+	writeFile(t, filepath.Join(dir, "b.example.zone"), "")
+
+	w := &Watcher{
+		Reconciler: &Reconciler{Client: client, Logger: log.New(&bytes.Buffer{}, "", 0)},
+		Dir:        dir,
+		Debounce:   time.Second,
+	}
+	ctx := context.Background()
+	now := time.Now()
+	_, err := w.scan()
+	require.NoError(t, err)
+
+	results, err := w.tick(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	writeFile(t, filepath.Join(dir, "b.example.zone"), "www 300 IN A 192.0.2.1\n")
+	results, err = w.tick(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, results, "synced before the debounce period")
+
+	// a second write restarts the debounce period
+	writeFile(t, filepath.Join(dir, "b.example.zone"), "www 300 IN A 192.0.2.1\napi 300 IN A 192.0.2.2\n")
+	results, err = w.tick(ctx, now.Add(900*time.Millisecond))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = w.tick(ctx, now.Add(2*time.Second))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "b.example", results[0].Domain)
+	assert.Len(t, account.Records(b.ID), 2)
+
+	results, err = w.tick(ctx, now.Add(4*time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// held back while the quota is low
+	account.SetRemaining(5)
+	_, err = client.Domains().List(ctx)
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "a.yaml"), "domains:\n  - name: a.example\n    records:\n      - {name: www, type: A, value: 192.0.2.9}\n")
+	_, err = w.tick(ctx, now.Add(5*time.Second))
+	require.NoError(t, err)
+	results, err = w.tick(ctx, now.Add(10*time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	account.SetRemaining(100)
+	_, err = client.Domains().List(ctx)
+	require.NoError(t, err)
+	results, err = w.tick(ctx, now.Add(11*time.Second))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "a.example", results[0].Domain)
+	records := account.Records(a.ID)
+	require.Len(t, records, 1)
+	assert.Equal(t, dme.Record{ID: records[0].ID, SourceId: a.ID, Name: "www", Type: "A", Value: "192.0.2.9", Ttl: 1800, GtdLocation: "DEFAULT"}, records[0])
+
+	// removing a file leaves its zone alone
+	require.NoError(t, os.Remove(filepath.Join(dir, "a.yaml")))
+	_, err = w.tick(ctx, now.Add(12*time.Second))
+	require.NoError(t, err)
+	results, err = w.tick(ctx, now.Add(20*time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	assert.Len(t, account.Records(a.ID), 1)
+}
@@ -0,0 +1,93 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Returned, wrapped, when a record template uses a placeholder no value
+// was supplied for
+var ErrMissingVariable = errors.New("missing template variable")
+
+// Placeholders look like {{ip}} or {{ env }}
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// Records whose Name, Type, Value and GtdLocation may hold {{name}}
+// placeholders, instantiated client-side for many hosts or environments.
+// Unrelated to the server-side Templates domains are assigned to.
+type RecordTemplate []Record
+
+// Returns the names of the placeholders the template uses, sorted
+func (t RecordTemplate) Variables() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, record := range t {
+		for _, field := range []string{record.Name, record.Type, record.Value, record.GtdLocation} {
+			for _, match := range placeholder.FindAllStringSubmatch(field, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					names = append(names, match[1])
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the template's records with placeholders replaced by vars.
+// Every placeholder must have a value.
+func (t RecordTemplate) Instantiate(vars map[string]string) ([]Record, error) {
+	var missing []string
+	for _, name := range t.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrMissingVariable, missing)
+	}
+
+	expand := func(s string) string {
+		return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+			return vars[placeholder.FindStringSubmatch(m)[1]]
+		})
+	}
+	records := make([]Record, len(t))
+	for idx, record := range t {
+		record.ID = 0
+		record.Name = expand(record.Name)
+		record.Type = expand(record.Type)
+		record.Value = expand(record.Value)
+		record.GtdLocation = expand(record.GtdLocation)
+		records[idx] = record
+	}
+	return records, nil
+}
+
+// Instantiates the template once per set of variables, such as once per
+// host or environment, returning all the records together
+func (t RecordTemplate) InstantiateEach(varSets ...map[string]string) ([]Record, error) {
+	var records []Record
+	for idx, vars := range varSets {
+		instance, err := t.Instantiate(vars)
+		if err != nil {
+			return nil, fmt.Errorf("variable set %d: %w", idx, err)
+		}
+		records = append(records, instance...)
+	}
+	return records, nil
+}
+
+// Instantiates the template once per set of variables and creates all
+// the resulting records in one batch
+func (s *RecordsService) CreateFromTemplate(ctx context.Context, t RecordTemplate, varSets ...map[string]string) ([]Record, error) {
+	records, err := t.InstantiateEach(varSets...)
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateMulti(ctx, records)
+}
@@ -0,0 +1,46 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var webTemplate = RecordTemplate{
+	{Name: "{{host}}.{{env}}", Type: "A", Value: "{{ip}}", Ttl: 300, GtdLocation: GtdDefault},
+	{Name: "www.{{ env }}", Type: "CNAME", Value: "{{host}}.{{env}}", Ttl: 300, GtdLocation: GtdDefault},
+}
+
+func TestRecordTemplateInstantiate(t *testing.T) {
+	assert.Equal(t, []string{"env", "host", "ip"}, webTemplate.Variables())
+
+	records, err := webTemplate.Instantiate(map[string]string{"host": "web1", "env": "prod", "ip": "192.0.2.1"})
+	require.NoError(t, err)
+	assert.Equal(t, []Record{
+		{Name: "web1.prod", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		{Name: "www.prod", Type: "CNAME", Value: "web1.prod", Ttl: 300, GtdLocation: GtdDefault},
+	}, records)
+
+	_, err = webTemplate.Instantiate(map[string]string{"host": "web1"})
+	assert.ErrorIs(t, err, ErrMissingVariable)
+	assert.ErrorContains(t, err, "[env ip]")
+
+	_, err = webTemplate.InstantiateEach(map[string]string{"host": "web1", "env": "prod", "ip": "192.0.2.1"}, map[string]string{})
+	assert.ErrorContains(t, err, "variable set 1")
+}
+
+func TestCreateFromTemplate(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+
+	created, err := client.Records(domain.ID).CreateFromTemplate(context.Background(), webTemplate[:1],
+		map[string]string{"host": "web1", "env": "prod", "ip": "192.0.2.1"},
+		map[string]string{"host": "web1", "env": "staging", "ip": "198.51.100.1"},
+	)
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "web1.staging", created[1].Name)
+	assert.Len(t, fake.recordList(domain.ID), 2)
+}
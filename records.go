@@ -0,0 +1,414 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type Record struct {
+	// A unique name per record Type
+	Name string `json:"name"`
+
+	// A unique identifier for this record
+	ID int `json:"id,omitempty"`
+
+	// Can be one of: A, AAAA, ANAME, CNAME, HTTPRED, MX
+	//                NS, PTR, SRV, TXT, SPF, or SOA
+	Type string `json:"type"`
+
+	// Differs per record type
+	Value string `json:"value"`
+
+	// 1 if the record is the record is domain specific
+	// 0 if the record is part of a template
+	Source int `json:"source,omitempty"`
+
+	// The time to live of the record
+	Ttl int `json:"ttl"`
+
+	// Global Traffic Director location.
+	// Values: DEFAULT, US_EAST, US_WEST, EUROPE,
+	//         ASIA_PAC, OCREANIA, SOUTH_AMERICA
+	GtdLocation string `json:"gtdLocation"`
+
+	// The domain ID of this record
+	SourceId int `json:"sourceId,omitempty"`
+
+	// Indicates if DNS Failover is enabled for an A record
+	Failover bool `json:"failover,omitempty"`
+
+	// Indicates if System Monitoring is enabled for an A record
+	Monitor bool `json:"monitor,omitempty"`
+
+	// For HTTP Redirection Records
+	HardLink bool `json:"hardLink,omitempty"`
+
+	// Indicates if the record has dynamic DNS enabled
+	DynamicDns bool `json:"dynamicDns,omitempty"`
+
+	// Indicates if an A record is in failed status
+	Failed bool `json:"failed,omitempty"`
+
+	// The priority for an MX record
+	MxLevel int `json:"mxLevel,omitempty"`
+
+	// The priority for an SRV record
+	Priority int `json:"priority,omitempty"`
+
+	// The weight for an SRV record
+	Weight int `json:"weight,omitempty"`
+
+	// The port for an SRV record
+	Port int `json:"port,omitempty"`
+
+	// Why the record exists. DNS Made Easy has no such field: notes are
+	// kept by the store given to WithNotes and never sent to the API.
+	Note string `json:"note,omitempty"`
+}
+
+type RecordsResp struct {
+	TotalRecords int      `json:"totalRecords"`
+	TotalPages   int      `json:"totalPages"`
+	Records      []Record `json:"data"`
+	CurrentPage  int      `json:"page"`
+}
+
+// Operations on the records of a single domain
+type RecordsService struct {
+	client   *Client
+	domainID int
+}
+
+// Returns the service for managing the records of the supplied domain
+func (c *Client) Records(domainID int) *RecordsService {
+	return &RecordsService{c, domainID}
+}
+
+func (s *RecordsService) request(ctx context.Context) *resty.Request {
+	return s.client.newRequest(ctx).
+		SetPathParam("domainId", fmt.Sprint(s.domainID))
+}
+
+// Returns all records in the domain, served from the record cache when
+// one is configured, or from a snapshot when the API is down and
+// WithOfflineSnapshots is set. With WithNotes, records carry their notes.
+func (s *RecordsService) List(ctx context.Context) ([]Record, error) {
+	records, err := s.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.attachNotes(ctx, s.domainID, records)
+}
+
+// Returns all records in the domain like List, without notes
+func (s *RecordsService) cached(ctx context.Context) ([]Record, error) {
+	var records []Record
+	var err error
+	if s.client.recordCache != nil {
+		records, err = s.client.recordCache.get(s.domainID, func() ([]Record, error) {
+			return s.list(ctx)
+		})
+	} else {
+		records, err = s.list(ctx)
+	}
+	if err != nil {
+		return s.client.offlineRecords(ctx, s.domainID, err)
+	}
+	return records, nil
+}
+
+func (s *RecordsService) list(ctx context.Context) ([]Record, error) {
+	v, err := s.client.coalesce(ctx, recordsReadKey(s.domainID), func(ctx context.Context) (interface{}, error) {
+		var records []Record
+		if s.client.listPageSize > 0 {
+			var err error
+			if records, err = s.listPaged(ctx, s.client.listPageSize); err != nil {
+				return nil, err
+			}
+		} else {
+			var respRecords RecordsResp
+			if err := s.client.getStreamed(s.request(ctx), DNSManagedPath+DNSRecordsPath, &respRecords); err != nil {
+				return nil, err
+			}
+			records = respRecords.Records
+		}
+		s.client.saveRecordsSnapshot(s.domainID, records)
+		if s.client.valueIndex != nil {
+			s.client.valueIndex.refresh(s.domainID, records)
+		}
+		return records, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyRecords(v.([]Record)), nil
+}
+
+// Creates a single record in the domain
+//
+// NOTE: with WithIdempotentCreates, an identical existing record is
+// returned rather than created again
+func (s *RecordsService) Create(ctx context.Context, record Record) (Record, error) {
+	defer s.client.InvalidateRecords(s.domainID)
+	noted := record
+	record = s.client.withDefaults(record)[0]
+
+	if s.client.idempotentCreates {
+		existing, ok, err := s.existing(ctx, record)
+		if err != nil {
+			return Record{}, err
+		}
+		if ok {
+			existing.Note = noted.Note
+			return existing, s.client.saveNotes(ctx, s.domainID, noted)
+		}
+	}
+
+	var newRecord Record
+
+	req := s.request(ctx).
+		SetResult(&newRecord).
+		SetBody(&record)
+
+	_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath))
+	if err != nil {
+		return Record{}, err
+	}
+
+	// the default annotation goes first, so a note adds to it
+	err = s.client.annotateCreated(ctx, s.domainID, []Record{newRecord})
+	newRecord.Note = noted.Note
+	return newRecord, errors.Join(err, s.client.saveNotes(ctx, s.domainID, noted))
+}
+
+// Create many records at once in the domain
+//
+// NOTE: is transactional; an error in creating any record causes none to be created.
+// With WithBatchSize, each chunk is transactional on its own and a
+// *BatchError reports which records were created. With
+// WithCreateFallback, the records of rejected chunks are created one by
+// one.
+func (s *RecordsService) CreateMulti(ctx context.Context, records []Record) ([]Record, error) {
+	defer s.client.InvalidateRecords(s.domainID)
+	noted := records
+	records = s.client.withDefaults(records...)
+
+	newRecords := []Record{}
+	err := s.client.chunked(len(records), func(start, end int) error {
+		var created []Record
+		chunk := records[start:end]
+		req := s.request(ctx).
+			SetResult(&created).
+			SetBody(&chunk)
+
+		_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath + "/createMulti"))
+		newRecords = append(newRecords, created...)
+		return err
+	})
+
+	if s.client.createFallback && err != nil {
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			batchErr = failedBatch(len(records), err)
+		}
+		newRecords, err = s.createEach(ctx, records, newRecords, batchErr)
+	}
+	if annotateErr := s.client.annotateCreated(ctx, s.domainID, newRecords); annotateErr != nil {
+		err = errors.Join(err, annotateErr)
+	}
+	if err == nil {
+		err = s.client.saveNotes(ctx, s.domainID, noted...)
+	}
+	return newRecords, err
+}
+
+// Retries the records of rejected createMulti chunks one at a time, so
+// only the records DNS Made Easy objects to are left out
+func (s *RecordsService) createEach(ctx context.Context, records, created []Record, batchErr *BatchError) ([]Record, error) {
+	var apiErr *APIError
+	failed := batchErr.Failed
+	batchErr.Failed = nil
+	for _, item := range failed {
+		// anything but a rejection may have been applied, so don't
+		// risk creating the records twice
+		if !errors.As(item.Err, &apiErr) {
+			batchErr.Failed = append(batchErr.Failed, item)
+			continue
+		}
+		record, err := s.Create(ctx, records[item.Index])
+		if err != nil {
+			batchErr.Failed = append(batchErr.Failed, &BatchItemError{Index: item.Index, Err: err})
+			continue
+		}
+		created = append(created, record)
+		batchErr.Succeeded = append(batchErr.Succeeded, item.Index)
+	}
+	sort.Ints(batchErr.Succeeded)
+
+	if len(batchErr.Failed) > 0 {
+		return created, batchErr
+	}
+	return created, nil
+}
+
+// Updates a single record in the domain
+func (s *RecordsService) Update(ctx context.Context, record Record) error {
+	defer s.client.InvalidateRecords(s.domainID)
+	noted := record
+	record = s.client.withDefaults(record)[0]
+
+	req := s.request(ctx).
+		SetBody(&record).
+		SetPathParam("recordId", fmt.Sprint(record.ID))
+
+	if _, err := checkRespForError(req.Put(DNSManagedPath + DNSRecordPath)); err != nil {
+		return err
+	}
+	return s.client.saveNotes(ctx, s.domainID, noted)
+}
+
+// Updates many records at once in the domain
+//
+// NOTE: with WithBatchSize, a *BatchError reports which records were
+// updated
+func (s *RecordsService) UpdateMulti(ctx context.Context, records []Record) ([]Record, error) {
+	defer s.client.InvalidateRecords(s.domainID)
+	noted := records
+	records = s.client.withDefaults(records...)
+
+	updatedRecords := []Record{}
+	err := s.client.chunked(len(records), func(start, end int) error {
+		var updated []Record
+		chunk := records[start:end]
+		req := s.request(ctx).
+			SetResult(&updated).
+			SetBody(&chunk)
+
+		_, err := checkRespForError(req.Post(DNSManagedPath + DNSRecordsPath + "/updateMulti"))
+		updatedRecords = append(updatedRecords, updated...)
+		return err
+	})
+	if err == nil {
+		err = s.client.saveNotes(ctx, s.domainID, noted...)
+	}
+	return updatedRecords, err
+}
+
+// Deletes a single record from the domain
+func (s *RecordsService) Delete(ctx context.Context, recordId int) error {
+	defer s.client.InvalidateRecords(s.domainID)
+
+	req := s.request(ctx).
+		SetPathParam("recordId", fmt.Sprint(recordId))
+
+	_, err := checkRespForError(req.Delete(DNSManagedPath + DNSRecordPath))
+	return s.client.deleted(err)
+}
+
+// Deletes records with numerical IDs from the domain
+//
+// NOTE: will silently continue if a recordId that doesn't belong to the
+// domain is passed. With WithBatchSize, the IDs of the chunks that were
+// deleted are returned alongside a *BatchError.
+func (s *RecordsService) DeleteMulti(ctx context.Context, recordIds []int) ([]int, error) {
+	defer s.client.InvalidateRecords(s.domainID)
+
+	deleted := []int{}
+	err := s.client.chunked(len(recordIds), func(start, end int) error {
+		var queryString string
+
+		// build query string of ids=X&ids=Y&ids=Z
+		// we can't use other convenience methods since they use
+		// map[string] and only the last id would made it
+		for idx, id := range recordIds[start:end] {
+			if idx > 0 {
+				queryString += "&"
+			}
+			queryString += fmt.Sprintf("ids=%d", id)
+		}
+
+		req := s.request(ctx).
+			SetPathParam("recordId", "").
+			SetQueryString(queryString)
+
+		_, err := checkRespForError(req.Delete(DNSManagedPath + DNSRecordPath))
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, recordIds[start:end]...)
+		return nil
+	})
+	if err != nil && len(deleted) == 0 {
+		return nil, err
+	}
+	return deleted, err
+}
+
+// Deletes all records in the domain
+func (s *RecordsService) DeleteAll(ctx context.Context) error {
+	defer s.client.InvalidateRecords(s.domainID)
+	_, err := checkRespForError(s.request(ctx).
+		Delete(DNSManagedPath + DNSRecordsPath))
+	return err
+}
+
+// Returns all records in the supplied domain, served from the record
+// cache when one is configured
+//
+// Equivalent to c.Records(domainId).List(context.Background())
+func (c *Client) EnumerateRecords(domainId int) ([]Record, error) {
+	return c.Records(domainId).List(context.Background())
+}
+
+// Deletes all records for the supplied domain
+//
+// Equivalent to c.Records(domainID).DeleteAll(context.Background())
+func (c *Client) DeleteAllRecords(domainID int) error {
+	return c.Records(domainID).DeleteAll(context.Background())
+}
+
+// Deletes records with numerical IDs for the supplied domain
+//
+// Equivalent to c.Records(domainId).DeleteMulti(context.Background(), recordIds)
+func (c *Client) DeleteRecords(domainId int, recordIds []int) ([]int, error) {
+	return c.Records(domainId).DeleteMulti(context.Background(), recordIds)
+}
+
+// Creates a single record in the supplied domain
+//
+// Equivalent to c.Records(domainId).Create(context.Background(), record)
+func (c *Client) CreateRecord(domainId int, record Record) (Record, error) {
+	return c.Records(domainId).Create(context.Background(), record)
+}
+
+// Create many records at once in the supplied domain
+//
+// Equivalent to c.Records(domainId).CreateMulti(context.Background(), records)
+func (c *Client) CreateRecords(domainId int, records []Record) ([]Record, error) {
+	return c.Records(domainId).CreateMulti(context.Background(), records)
+}
+
+// Updates many records at once in the supplied domain
+//
+// Equivalent to c.Records(domainId).UpdateMulti(context.Background(), records)
+func (c *Client) UpdateRecords(domainId int, records []Record) ([]Record, error) {
+	return c.Records(domainId).UpdateMulti(context.Background(), records)
+}
+
+// Updates a single record in the supplied domain
+//
+// Equivalent to c.Records(domainId).Update(context.Background(), record)
+func (c *Client) UpdateRecord(domainId int, record Record) error {
+	return c.Records(domainId).Update(context.Background(), record)
+}
+
+// Deletes a single record from the supplied domain
+//
+// Equivalent to c.Records(domainId).Delete(context.Background(), recordId)
+func (c *Client) DeleteRecord(domainId int, recordId int) error {
+	return c.Records(domainId).Delete(context.Background(), recordId)
+}
@@ -0,0 +1,107 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned by compare-and-swap updates when the live records no longer
+// match the fingerprint they were read with
+var ErrConflict = errors.New("records changed since they were read")
+
+// The records of one name and type, as they were when read
+type RecordSet struct {
+	DomainID int
+	Name     string
+	Type     string
+	Records  []Record
+
+	// RecordsFingerprint of Records, checked by ReplaceSet
+	Fingerprint string
+}
+
+// Reads the records named name of recordType, bypassing the record
+// cache, for a later ReplaceSet
+func (s *RecordsService) GetSet(ctx context.Context, name, recordType string) (RecordSet, error) {
+	live, err := s.list(ctx)
+	if err != nil {
+		return RecordSet{}, err
+	}
+	records := filterSet(live, name, recordType)
+	return RecordSet{s.domainID, name, recordType, records, RecordsFingerprint(records)}, nil
+}
+
+// Replaces the records of set with desired, failing with ErrConflict if
+// the set changed since it was read. Desired records carrying the ID of
+// a record in the set update it, those without an ID are created, and
+// records of the set left out are deleted. The change is applied as by
+// Apply, so a failure part way is rolled back.
+//
+// NOTE: the API has no conditional writes, so the check narrows the
+// window for lost updates rather than closing it
+func (s *RecordsService) ReplaceSet(ctx context.Context, set RecordSet, desired []Record) (ApplyResult, error) {
+	live, err := s.list(ctx)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	if RecordsFingerprint(filterSet(live, set.Name, set.Type)) != set.Fingerprint {
+		return ApplyResult{}, fmt.Errorf("%s %s: %w", set.Type, set.Name, ErrConflict)
+	}
+
+	existing := map[int]bool{}
+	for _, record := range set.Records {
+		existing[record.ID] = true
+	}
+	var ops []Operation
+	kept := map[int]bool{}
+	for _, record := range desired {
+		record.Name, record.Type = set.Name, set.Type
+		switch {
+		case record.ID == 0:
+			ops = append(ops, Operation{OpCreate, s.domainID, record})
+		case existing[record.ID]:
+			kept[record.ID] = true
+			ops = append(ops, Operation{OpUpdate, s.domainID, record})
+		default:
+			return ApplyResult{}, fmt.Errorf("record %d is not part of %s %s", record.ID, set.Type, set.Name)
+		}
+	}
+	for _, record := range set.Records {
+		if !kept[record.ID] {
+			ops = append(ops, Operation{OpDelete, s.domainID, record})
+		}
+	}
+	return s.client.ApplyContext(ctx, ops)
+}
+
+// Updates a record only if it still has the fingerprint it was read
+// with, failing with ErrConflict otherwise. Use
+// RecordsFingerprint([]Record{record}) to take the fingerprint.
+func (s *RecordsService) UpdateIfUnchanged(ctx context.Context, record Record, fingerprint string) error {
+	live, err := s.list(ctx)
+	if err != nil {
+		return err
+	}
+	for _, current := range live {
+		if current.ID != record.ID {
+			continue
+		}
+		if RecordsFingerprint([]Record{current}) != fingerprint {
+			return fmt.Errorf("record %d: %w", record.ID, ErrConflict)
+		}
+		return s.Update(ctx, record)
+	}
+	return fmt.Errorf("record %d: %w", record.ID, ErrConflict)
+}
+
+func filterSet(records []Record, name, recordType string) []Record {
+	set := []Record{}
+	for _, record := range records {
+		if record.Name == name && strings.EqualFold(record.Type, recordType) {
+			set = append(set, record)
+		}
+	}
+	return set
+}
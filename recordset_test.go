@@ -0,0 +1,62 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceSet(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300},
+		Record{Name: "www", Type: "AAAA", Value: "2001:db8::1", Ttl: 300},
+	)
+	ctx := context.Background()
+	records := client.Records(domain.ID)
+
+	set, err := records.GetSet(ctx, "www", "A")
+	require.NoError(t, err)
+	require.Len(t, set.Records, 2)
+
+	first := set.Records[0]
+	first.Ttl = 600
+	result, err := records.ReplaceSet(ctx, set, []Record{first, {Value: "192.0.2.3", Ttl: 600}})
+	require.NoError(t, err)
+	assert.Equal(t, ApplyCommitted, result.State)
+
+	updated, err := records.GetSet(ctx, "www", "A")
+	require.NoError(t, err)
+	var values []string
+	for _, record := range updated.Records {
+		values = append(values, record.Value)
+		assert.Equal(t, 600, record.Ttl)
+	}
+	assert.ElementsMatch(t, []string{"192.0.2.1", "192.0.2.3"}, values)
+
+	// the stale set is refused
+	_, err = records.ReplaceSet(ctx, set, nil)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Len(t, fake.recordList(domain.ID), 3)
+}
+
+func TestUpdateIfUnchanged(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+	ctx := context.Background()
+	records := client.Records(domain.ID)
+
+	read := fake.recordList(domain.ID)[0]
+	fingerprint := RecordsFingerprint([]Record{read})
+
+	mine, theirs := read, read
+	theirs.Value = "192.0.2.9"
+	require.NoError(t, records.UpdateIfUnchanged(ctx, theirs, fingerprint))
+
+	mine.Value = "192.0.2.5"
+	assert.ErrorIs(t, records.UpdateIfUnchanged(ctx, mine, fingerprint), ErrConflict)
+	assert.Equal(t, "192.0.2.9", fake.recordList(domain.ID)[0].Value)
+}
@@ -0,0 +1,88 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const redacted = "[REDACTED]"
+
+// Headers that carry credentials or values derived from them
+var sensitiveHeaders = []string{"X-Dnsme-Apikey", "X-Dnsme-Hmac"}
+
+// Masks credential headers in place
+func redactHeaders(h http.Header) {
+	for _, name := range sensitiveHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+}
+
+func redactRequestLog(rl *resty.RequestLog) error {
+	redactHeaders(rl.Header)
+	return nil
+}
+
+// Masks a secret, keeping only enough of it to tell keys apart
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return redacted
+	}
+	return secret[:4] + "..." + redacted
+}
+
+// Implements fmt.Stringer so credentials aren't leaked when a client
+// is logged
+func (c *Client) String() string {
+	return fmt.Sprintf("dnsmadeeasy.Client{APIKey: %s, BaseURL: %s}", maskSecret(c.APIKey), c.BaseURL)
+}
+
+// Implements fmt.GoStringer for the same reason as String
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+func (c Credentials) String() string {
+	return fmt.Sprintf("Credentials{APIKey: %s, SecretKey: %s}", maskSecret(c.APIKey), redacted)
+}
+
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+func (s StaticCredentials) String() string {
+	return Credentials(s).String()
+}
+
+func (s StaticCredentials) GoString() string {
+	return Credentials(s).String()
+}
+
+// Writes every request and response to w, with credential headers
+// masked
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) {
+		c.resty.SetLogger(debugLogger{w}).SetDebug(true)
+	}
+}
+
+// Adapts an io.Writer to resty's logger interface
+type debugLogger struct {
+	w io.Writer
+}
+
+func (l debugLogger) Errorf(format string, v ...interface{}) {
+	fmt.Fprintf(l.w, "ERROR "+format+"\n", v...)
+}
+
+func (l debugLogger) Warnf(format string, v ...interface{}) {
+	fmt.Fprintf(l.w, "WARN "+format+"\n", v...)
+}
+
+func (l debugLogger) Debugf(format string, v ...interface{}) {
+	fmt.Fprintf(l.w, "DEBUG "+format+"\n", v...)
+}
@@ -0,0 +1,36 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugRedaction(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.addDomain("example.com")
+
+	var log bytes.Buffer
+	client = GetClient("my-api-key-1234", "my-secret-5678", client.BaseURL, WithDebug(&log))
+	_, err := client.EnumerateDomains()
+	require.NoError(t, err)
+
+	assert.Contains(t, log.String(), "example.com")
+	assert.Contains(t, log.String(), "X-Dnsme-Apikey: "+redacted)
+	assert.Contains(t, log.String(), "X-Dnsme-Hmac: "+redacted)
+	assert.NotContains(t, log.String(), "my-api-key-1234")
+	assert.Equal(t, "my-api-key-1234", fake.apiKey)
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(format, client)
+		assert.NotContains(t, out, "my-api-key-1234")
+		assert.NotContains(t, out, "my-secret-5678")
+
+		out = fmt.Sprintf(format, StaticCredentials{"my-api-key-1234", "my-secret-5678"})
+		assert.NotContains(t, out, "my-api-key-1234")
+		assert.NotContains(t, out, "my-secret-5678")
+	}
+}
@@ -0,0 +1,139 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The changes that return a zone to its state at an earlier time.
+// Nothing is changed until the plan is approved and passed to
+// RestoreZoneTo.
+type RestorePlan struct {
+	DomainID int
+
+	// The time restored to, and when the backup entry the zone is
+	// restored from was taken
+	To       time.Time
+	BackupAt time.Time
+
+	Operations []Operation
+
+	// the zone when the plan was made
+	current  []Record
+	approved bool
+}
+
+// Marks the plan as confirmed
+func (p *RestorePlan) Approve() {
+	p.approved = true
+}
+
+// Reports whether Approve has been called
+func (p *RestorePlan) Approved() bool {
+	return p.approved
+}
+
+// Plans returning the domain's records to their state at t, as recorded
+// in store by a BackupSchedule. Records are matched by ID, then by name,
+// type and value, so unchanged records are left alone and changed ones
+// updated in place.
+func (c *Client) PlanRestore(ctx context.Context, store BackupStore, domainID int, t time.Time) (*RestorePlan, error) {
+	entries, err := store.LoadBackups(domainID)
+	if err != nil {
+		return nil, fmt.Errorf("loading backups: %w", err)
+	}
+	desired, taken, ok := BackupState(entries, t)
+	if !ok {
+		return nil, fmt.Errorf("%w: no backup of domain %d at or before %s", ErrNotFound, domainID, t.Format(time.RFC3339))
+	}
+	current, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &RestorePlan{
+		DomainID:   domainID,
+		To:         t,
+		BackupAt:   taken,
+		Operations: planZone(domainID, current, desired),
+		current:    current,
+	}, nil
+}
+
+// Applies an approved restore plan with ApplyContext, so a failure part
+// way is rolled back. Fails with ErrConflict, changing nothing, if the
+// zone changed since the plan was made.
+func (c *Client) RestoreZoneTo(ctx context.Context, plan *RestorePlan) (ApplyResult, error) {
+	if !plan.approved {
+		return ApplyResult{}, ErrNotApproved
+	}
+	records, err := c.Records(plan.DomainID).list(ctx)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	if created, updated, deleted := diffByID(plan.current, records); len(created)+len(updated)+len(deleted) > 0 {
+		return ApplyResult{}, fmt.Errorf("domain %d: %w; plan the restore again", plan.DomainID, ErrConflict)
+	}
+	return c.ApplyContext(ctx, plan.Operations)
+}
+
+// Returns the operations that change current into desired: creates,
+// then updates, then deletes, so a failure part way leaves extra
+// records rather than missing ones
+func planZone(domainID int, current, desired []Record) []Operation {
+	unmatched := make(map[int]Record, len(current))
+	for _, record := range current {
+		unmatched[record.ID] = record
+	}
+
+	var creates, updates []Operation
+	var unplaced []Record
+	match := func(have, want Record) {
+		delete(unmatched, have.ID)
+		want.ID = have.ID
+		if restorable(have) != restorable(want) {
+			updates = append(updates, Operation{OpUpdate, domainID, want})
+		}
+	}
+	for _, want := range desired {
+		if have, ok := unmatched[want.ID]; ok && want.ID != 0 {
+			match(have, want)
+		} else {
+			unplaced = append(unplaced, want)
+		}
+	}
+	// records deleted and recreated since the backup have new IDs
+	byKey := map[string][]Record{}
+	for _, record := range current {
+		if _, ok := unmatched[record.ID]; ok {
+			byKey[RecordKey(record)] = append(byKey[RecordKey(record)], record)
+		}
+	}
+	for _, want := range unplaced {
+		candidates := byKey[RecordKey(want)]
+		if len(candidates) > 0 {
+			byKey[RecordKey(want)] = candidates[1:]
+			match(candidates[0], want)
+			continue
+		}
+		want.ID = 0
+		creates = append(creates, Operation{OpCreate, domainID, want})
+	}
+
+	var deletes []Operation
+	for _, record := range unmatched {
+		deletes = append(deletes, Operation{OpDelete, domainID, record})
+	}
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Record.ID < deletes[j].Record.ID })
+	return append(append(creates, updates...), deletes...)
+}
+
+// Returns the record without the fields the API sets itself
+func restorable(record Record) Record {
+	record.ID = 0
+	record.Source = 0
+	record.SourceId = 0
+	record.Failed = false
+	return record
+}
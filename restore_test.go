@@ -0,0 +1,87 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreZoneTo(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	store := FileBackups{Dir: t.TempDir()}
+	b := &BackupSchedule{Client: client, Store: store}
+	ctx := context.Background()
+	backedUp := time.Now().Add(-time.Hour)
+	require.NoError(t, b.backup(ctx, domain, backedUp))
+
+	records := fake.sortedRecords(domain.ID)
+	www := records[0]
+	www.Value = "192.0.2.10"
+	require.NoError(t, client.Records(domain.ID).Update(ctx, www))
+	require.NoError(t, client.Records(domain.ID).Delete(ctx, records[1].ID))
+	_, err := client.Records(domain.ID).Create(ctx, Record{Name: "new", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: GtdDefault})
+	require.NoError(t, err)
+
+	_, err = client.PlanRestore(ctx, store, domain.ID, backedUp.Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	plan, err := client.PlanRestore(ctx, store, domain.ID, backedUp.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, backedUp.Equal(plan.BackupAt))
+	var kinds []string
+	for _, op := range plan.Operations {
+		kinds = append(kinds, op.Type.String()+" "+op.Record.Name)
+	}
+	assert.Equal(t, []string{"create api", "update www", "delete new"}, kinds)
+
+	_, err = client.RestoreZoneTo(ctx, plan)
+	assert.ErrorIs(t, err, ErrNotApproved)
+	plan.Approve()
+	result, err := client.RestoreZoneTo(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, ApplyCommitted, result.State)
+	assert.ElementsMatch(t, []string{"www=192.0.2.1", "api=192.0.2.2"}, recordValues(fake.recordList(domain.ID)))
+}
+
+func TestRestoreZoneToConflict(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	store := FileBackups{Dir: t.TempDir()}
+	ctx := context.Background()
+	require.NoError(t, (&BackupSchedule{Client: client, Store: store}).backup(ctx, domain, time.Now().Add(-time.Hour)))
+
+	www := fake.sortedRecords(domain.ID)[0]
+	www.Ttl = 60
+	require.NoError(t, client.Records(domain.ID).Update(ctx, www))
+	plan, err := client.PlanRestore(ctx, store, domain.ID, time.Now())
+	require.NoError(t, err)
+	require.Len(t, plan.Operations, 1)
+	plan.Approve()
+
+	www.Ttl = 120
+	require.NoError(t, client.Records(domain.ID).Update(ctx, www))
+	_, err = client.RestoreZoneTo(ctx, plan)
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Equal(t, 120, fake.sortedRecords(domain.ID)[0].Ttl)
+}
+
+func TestPlanZoneMatchesRecreatedRecords(t *testing.T) {
+	current := []Record{{ID: 7, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300}}
+	desired := []Record{{ID: 3, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300}}
+	assert.Empty(t, planZone(1, current, desired))
+
+	desired[0].Ttl = 60
+	ops := planZone(1, current, desired)
+	require.Len(t, ops, 1)
+	assert.Equal(t, OpUpdate, ops[0].Type)
+	assert.Equal(t, 7, ops[0].Record.ID)
+}
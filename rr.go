@@ -0,0 +1,170 @@
+package dnsmadeeasy
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// Converts the record to a miekg/dns resource record, qualifying its
+// name and any relative targets with zone. DNS Made Easy specific types
+// such as ANAME and HTTPRED have no RR equivalent and return an error.
+func (r Record) RR(zone string) (dns.RR, error) {
+	zone = dns.Fqdn(zone)
+	owner := AbsoluteName(r.Name, zone)
+
+	var rdata string
+	switch strings.ToUpper(r.Type) {
+	case "A", "AAAA":
+		rdata = r.Value
+	case "CNAME", "NS", "PTR":
+		rdata = qualify(r.Value, zone)
+	case "MX":
+		rdata = fmt.Sprintf("%d %s", r.MxLevel, qualify(r.Value, zone))
+	case "SRV":
+		rdata = fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, qualify(r.Value, zone))
+	case "TXT", "SPF":
+		rdata = quoteTXT(r.Value)
+	default:
+		return nil, fmt.Errorf("%s records have no standard DNS equivalent", r.Type)
+	}
+
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", owner, r.Ttl, strings.ToUpper(r.Type), rdata))
+	if err != nil {
+		return nil, fmt.Errorf("record %q: %w", r.Name, err)
+	}
+	return rr, nil
+}
+
+// Converts a miekg/dns resource record in zone to a Record. Targets
+// keep their trailing dot, which DNS Made Easy treats as fully
+// qualified.
+func RecordFromRR(rr dns.RR, zone string) (Record, error) {
+	hdr := rr.Header()
+	name, err := RelativeName(hdr.Name, zone)
+	if err != nil {
+		return Record{}, err
+	}
+	record := Record{
+		Name:        name,
+		Type:        dns.TypeToString[hdr.Rrtype],
+		Ttl:         int(hdr.Ttl),
+		GtdLocation: "DEFAULT",
+	}
+
+	switch v := rr.(type) {
+	case *dns.A:
+		record.Value = v.A.String()
+	case *dns.AAAA:
+		record.Value = v.AAAA.String()
+	case *dns.CNAME:
+		record.Value = v.Target
+	case *dns.NS:
+		record.Value = v.Ns
+	case *dns.PTR:
+		record.Value = v.Ptr
+	case *dns.MX:
+		record.Value = v.Mx
+		record.MxLevel = int(v.Preference)
+	case *dns.SRV:
+		record.Value = v.Target
+		record.Priority = int(v.Priority)
+		record.Weight = int(v.Weight)
+		record.Port = int(v.Port)
+	case *dns.TXT:
+		record.Value = joinTXT(v.Txt)
+	case *dns.SPF:
+		record.Value = joinTXT(v.Txt)
+	default:
+		return Record{}, fmt.Errorf("%s records are not supported", record.Type)
+	}
+	// zone files may leave the data out, as in dynamic updates
+	if record.Value == "" || record.Value == "<nil>" {
+		return Record{}, fmt.Errorf("%s record %s has no data", record.Type, hdr.Name)
+	}
+	return record, nil
+}
+
+// Converts records to resource records, stopping at the first that
+// can't be converted
+func RecordsToRRs(records []Record, zone string) ([]dns.RR, error) {
+	rrs := make([]dns.RR, 0, len(records))
+	for _, record := range records {
+		rr, err := record.RR(zone)
+		if err != nil {
+			return nil, err
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs, nil
+}
+
+// Converts resource records to records, stopping at the first that
+// can't be converted
+func RRsToRecords(rrs []dns.RR, zone string) ([]Record, error) {
+	records := make([]Record, 0, len(rrs))
+	for _, rr := range rrs {
+		record, err := RecordFromRR(rr, zone)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Parses an RFC 1035 zone file for zone into records. The SOA and apex
+// NS records are skipped, as DNS Made Easy manages them itself.
+func ParseZoneFile(r io.Reader, zone string) ([]Record, error) {
+	zone = dns.Fqdn(zone)
+	parser := dns.NewZoneParser(r, zone, "")
+	var records []Record
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		hdr := rr.Header()
+		if hdr.Rrtype == dns.TypeSOA || (hdr.Rrtype == dns.TypeNS && canonicalName(hdr.Name) == canonicalName(zone)) {
+			continue
+		}
+		record, err := RecordFromRR(rr, zone)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := parser.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Qualifies a target DNS Made Easy treats as relative to the zone
+func qualify(target, zone string) string {
+	if target == "" {
+		return zone
+	}
+	if dns.IsFqdn(target) {
+		return target
+	}
+	return target + "." + zone
+}
+
+// DNS Made Easy stores TXT values with their quotes, but tolerates
+// unquoted ones
+func quoteTXT(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// miekg/dns keeps TXT strings in presentation format, escapes and all,
+// so they only need quoting
+func joinTXT(txt []string) string {
+	quoted := make([]string, len(txt))
+	for idx, s := range txt {
+		quoted[idx] = `"` + s + `"`
+	}
+	return strings.Join(quoted, " ")
+}
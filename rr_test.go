@@ -0,0 +1,90 @@
+package dnsmadeeasy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRR(t *testing.T) {
+	tests := []struct {
+		record Record
+		rr     string
+	}{
+		{Record{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 300}, "example.com.\t300\tIN\tA\t192.0.2.1"},
+		{Record{Name: "v6", Type: "AAAA", Value: "2001:db8::1", Ttl: 300}, "v6.example.com.\t300\tIN\tAAAA\t2001:db8::1"},
+		{Record{Name: "www", Type: "CNAME", Value: "web", Ttl: 60}, "www.example.com.\t60\tIN\tCNAME\tweb.example.com."},
+		{Record{Name: "", Type: "MX", Value: "mx.example.net.", MxLevel: 10, Ttl: 3600}, "example.com.\t3600\tIN\tMX\t10 mx.example.net."},
+		{Record{Name: "_sip._tcp", Type: "SRV", Value: "sip", Priority: 1, Weight: 2, Port: 5060, Ttl: 300},
+			"_sip._tcp.example.com.\t300\tIN\tSRV\t1 2 5060 sip.example.com."},
+		{Record{Name: "", Type: "TXT", Value: `"v=spf1 -all"`, Ttl: 300}, "example.com.\t300\tIN\tTXT\t\"v=spf1 -all\""},
+		{Record{Name: "txt", Type: "TXT", Value: `unquoted text`, Ttl: 300}, "txt.example.com.\t300\tIN\tTXT\t\"unquoted text\""},
+	}
+	for _, test := range tests {
+		rr, err := test.record.RR("example.com")
+		require.NoError(t, err)
+		assert.Equal(t, test.rr, rr.String())
+	}
+
+	_, err := Record{Type: "ANAME", Value: "lb.example.net."}.RR("example.com")
+	assert.EqualError(t, err, "ANAME records have no standard DNS equivalent")
+}
+
+func TestRecordFromRR(t *testing.T) {
+	for _, text := range []string{
+		"example.com. 300 IN A 192.0.2.1",
+		"www.example.com. 60 IN CNAME web.example.com.",
+		"example.com. 3600 IN MX 10 mx.example.net.",
+		"_sip._tcp.example.com. 300 IN SRV 1 2 5060 sip.example.com.",
+		`example.com. 300 IN TXT "v=spf1" "-all"`,
+	} {
+		rr, err := dns.NewRR(text)
+		require.NoError(t, err)
+		record, err := RecordFromRR(rr, "example.com.")
+		require.NoError(t, err)
+
+		// round trip
+		back, err := record.RR("example.com")
+		require.NoError(t, err)
+		assert.Equal(t, rr.String(), back.String())
+	}
+
+	rr, _ := dns.NewRR("_sip._tcp.example.com. 300 IN SRV 1 2 5060 sip.example.com.")
+	record, _ := RecordFromRR(rr, "example.com")
+	assert.Equal(t, Record{Name: "_sip._tcp", Type: "SRV", Value: "sip.example.com.", Ttl: 300,
+		GtdLocation: "DEFAULT", Priority: 1, Weight: 2, Port: 5060}, record)
+
+	rr, _ = dns.NewRR("www.example.org. 300 IN A 192.0.2.1")
+	_, err := RecordFromRR(rr, "example.com")
+	assert.EqualError(t, err, "www.example.org is not in zone example.com")
+
+	records, err := RRsToRecords([]dns.RR{}, "example.com")
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestParseZoneFile(t *testing.T) {
+	zone := `$ORIGIN example.com.
+$TTL 600
+@	IN	SOA	ns0.dnsmadeeasy.com. dns.dnsmadeeasy.com. 1 43200 3600 1209600 180
+@	IN	NS	ns0.dnsmadeeasy.com.
+@	IN	A	192.0.2.1
+www	300	IN	CNAME	@
+sub	IN	NS	ns1.example.net.
+@	IN	MX	10 mail
+`
+	records, err := ParseZoneFile(strings.NewReader(zone), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []Record{
+		{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 600, GtdLocation: "DEFAULT"},
+		{Name: "www", Type: "CNAME", Value: "example.com.", Ttl: 300, GtdLocation: "DEFAULT"},
+		{Name: "sub", Type: "NS", Value: "ns1.example.net.", Ttl: 600, GtdLocation: "DEFAULT"},
+		{Name: "", Type: "MX", Value: "mail.example.com.", MxLevel: 10, Ttl: 600, GtdLocation: "DEFAULT"},
+	}, records)
+
+	_, err = ParseZoneFile(strings.NewReader("www IN A not-an-address\n"), "example.com")
+	assert.Error(t, err)
+}
@@ -1,60 +1,17 @@
-package dnsmadeeasy
+package dnsmadeeasy_test
 
 import (
 	"fmt"
 	"log"
 	"os"
 	"testing"
-	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
-	"github.com/tjarratt/babble"
-)
-
-func createTestDomain(t *testing.T, client *Client) (Domain, error) {
-	babbler := babble.NewBabbler()
-	testDomainName := fmt.Sprintf("%s.testing", babbler.Babble())
-
-	// create a domain for testing within this run
-	domain, err := client.CreateDomain(testDomainName)
-	if err != nil {
-		return Domain{}, err
-	}
-	t.Logf("Created domain %s\n", testDomainName)
-
-	return domain, nil
-}
-
-// This test might take up to 10 minutes and
-func deleteTestDomain(t *testing.T, client *Client, domainID int) {
-	maxRetries := 10
-	waitSeconds, _ := time.ParseDuration("30s")
-	tries := 0
 
-	// Since domains might still be in the "Pending Creaton" state
-	// due to the short lifetime of our tests, we need to poll the
-	// PendingActionID to see
-	for {
-		domain, err := client.GetDomain(domainID)
-		if err != nil {
-			t.Error(err)
-		}
-		if domain.PendingActionID == 0 {
-			break
-		}
-		tries += 1
-		if tries >= maxRetries {
-			t.Errorf("exceeded max retries deleting domain %s", domain.Name)
-		}
-		time.Sleep(waitSeconds)
-	}
-
-	err := client.DeleteDomain(domainID)
-	if err != nil {
-		t.Error(err)
-	}
-}
+	. "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+)
 
 func TestSandboxIntegration(t *testing.T) {
 	if testing.Short() {
@@ -71,12 +28,15 @@ func TestSandboxIntegration(t *testing.T) {
 	apiSecret := os.Getenv("DME_API_SECRET")
 
 	// set global client for use in testing
-	client := GetClient(apiToken, apiSecret, Sandbox)
+	client := GetClient(apiToken, apiSecret, Sandbox, WithPendingDeleteRetry())
 	toCreate := 50
 
+	// test domains are removed when the whole test completes
+	root := t
+
 	var testDomains []Domain
 	t.Run("create test domain", func(t *testing.T) {
-		domain, err := createTestDomain(t, client)
+		domain, err := dmetest.CreateDomain(root, client)
 		if err != nil {
 			t.Errorf("Failed: %s", err)
 		}
@@ -126,14 +86,11 @@ func TestSandboxIntegration(t *testing.T) {
 		assert.Equal(t, testDomains[0].ID, id)
 
 		// create new domain
-		newDomain, err := createTestDomain(t, client)
+		newDomain, err := dmetest.CreateDomain(root, client)
 		if err != nil {
 			t.Error(err)
 		}
 
-		// add newDomain to the list to be deleted later
-		testDomains = append(testDomains, newDomain)
-
 		// get the ID for the newly created domain name
 		newId, err := client.IdForDomain(newDomain.Name)
 		if err != nil {
@@ -141,11 +98,4 @@ func TestSandboxIntegration(t *testing.T) {
 		}
 		assert.Equal(t, newDomain.ID, newId)
 	})
-	t.Run("Cleanup test domains", func(t *testing.T) {
-		for _, domain := range testDomains {
-			t.Run(fmt.Sprint("Deleting ", domain.Name), func(t *testing.T) {
-				deleteTestDomain(t, client, domain.ID)
-			})
-		}
-	})
 }
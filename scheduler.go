@@ -0,0 +1,232 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	// Reads are dispatched before any queued writes
+	PriorityRead Priority = iota
+	PriorityWrite
+)
+
+var ErrSchedulerClosed = errors.New("scheduler closed")
+
+type SchedulerOptions struct {
+	// Requests allowed per Window. Defaults to the limit last reported by
+	// the API, or 150 if none has been seen yet.
+	Limit int
+
+	// Defaults to RateLimitWindow
+	Window time.Duration
+
+	// Requests that may be dispatched back to back before pacing kicks
+	// in. Defaults to 10.
+	Burst int
+}
+
+// Observability counters for a Scheduler
+type SchedulerStats struct {
+	QueuedReads  int
+	QueuedWrites int
+	Dispatched   int
+
+	// Time spent queued by the most recently dispatched job, and the
+	// longest and total queue time over all dispatched jobs
+	LastWait  time.Duration
+	MaxWait   time.Duration
+	TotalWait time.Duration
+}
+
+type scheduledJob struct {
+	fn       func() error
+	done     chan error
+	queuedAt time.Time
+}
+
+// Queues API calls and dispatches them no faster than the account's
+// request quota allows, preferring reads over writes
+//
+// Each job is expected to make a single API request.
+type Scheduler struct {
+	client   *Client
+	interval time.Duration
+	burst    int
+
+	mu     sync.Mutex
+	queues [2][]*scheduledJob
+	stats  SchedulerStats
+	closed bool
+	wake   chan struct{}
+	stop   chan struct{}
+}
+
+// Creates a scheduler for the supplied client and starts dispatching
+func NewScheduler(c *Client, opts SchedulerOptions) *Scheduler {
+	if opts.Limit <= 0 {
+		opts.Limit = c.RateLimit().Limit
+		if opts.Limit == 0 {
+			opts.Limit = 150
+		}
+	}
+	if opts.Window <= 0 {
+		opts.Window = RateLimitWindow
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 10
+	}
+
+	s := &Scheduler{
+		client:   c,
+		interval: opts.Window / time.Duration(opts.Limit),
+		burst:    opts.Burst,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Queues fn and blocks until it has run, returning its error. If ctx is
+// done before fn is dispatched, fn is dropped from the queue.
+func (s *Scheduler) Do(ctx context.Context, p Priority, fn func() error) error {
+	if p != PriorityRead {
+		p = PriorityWrite
+	}
+	job := &scheduledJob{fn, make(chan error, 1), time.Now()}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrSchedulerClosed
+	}
+	s.queues[p] = append(s.queues[p], job)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case err := <-job.done:
+		return err
+	case <-ctx.Done():
+		if s.remove(p, job) {
+			return ctx.Err()
+		}
+		// already dispatched; wait for the result
+		return <-job.done
+	}
+}
+
+func (s *Scheduler) remove(p Priority, job *scheduledJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for idx, queued := range s.queues[p] {
+		if queued == job {
+			s.queues[p] = append(s.queues[p][:idx], s.queues[p][idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a snapshot of queue depth and wait times
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.QueuedReads = len(s.queues[PriorityRead])
+	stats.QueuedWrites = len(s.queues[PriorityWrite])
+	return stats
+}
+
+// Stops dispatching. Queued jobs fail with ErrSchedulerClosed.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.stop)
+	for p := range s.queues {
+		for _, job := range s.queues[p] {
+			job.done <- ErrSchedulerClosed
+		}
+		s.queues[p] = nil
+	}
+}
+
+func (s *Scheduler) next() *scheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for p := range s.queues {
+		if len(s.queues[p]) > 0 {
+			job := s.queues[p][0]
+			s.queues[p] = s.queues[p][1:]
+
+			wait := time.Since(job.queuedAt)
+			s.stats.Dispatched++
+			s.stats.LastWait = wait
+			s.stats.TotalWait += wait
+			if wait > s.stats.MaxWait {
+				s.stats.MaxWait = wait
+			}
+			return job
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) run() {
+	tokens := float64(s.burst)
+	last := time.Now()
+
+	for {
+		// refill, never exceeding what the API says is left (allowing
+		// for the quota replenished since it said so)
+		now := time.Now()
+		tokens += float64(now.Sub(last)) / float64(s.interval)
+		last = now
+		if tokens > float64(s.burst) {
+			tokens = float64(s.burst)
+		}
+		if rl := s.client.RateLimit(); rl.Limit > 0 {
+			remaining := float64(rl.Remaining) + float64(now.Sub(rl.Updated))/float64(s.interval)
+			if tokens > remaining {
+				tokens = remaining
+			}
+		}
+
+		if tokens < 1 {
+			select {
+			case <-time.After(time.Duration((1 - tokens) * float64(s.interval))):
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+
+		job := s.next()
+		if job == nil {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+
+		tokens--
+		go func() {
+			job.done <- job.fn()
+		}()
+	}
+}
@@ -0,0 +1,77 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	_, client := newFakeDME(t)
+	s := NewScheduler(client, SchedulerOptions{Limit: 100, Window: time.Second, Burst: 1})
+	defer s.Close()
+
+	// hold the dispatcher on a first job so the rest queue up
+	release := make(chan struct{})
+	go s.Do(context.Background(), PriorityWrite, func() error {
+		<-release
+		return nil
+	})
+	require.Eventually(t, func() bool { return s.Stats().Dispatched == 1 }, time.Second, time.Millisecond)
+
+	var (
+		mu    sync.Mutex
+		order []Priority
+		wg    sync.WaitGroup
+	)
+	submit := func(p Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.Do(context.Background(), p, func() error {
+				mu.Lock()
+				order = append(order, p)
+				mu.Unlock()
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	submit(PriorityWrite)
+	require.Eventually(t, func() bool { return s.Stats().QueuedWrites == 1 }, time.Second, time.Millisecond)
+	submit(PriorityRead)
+	submit(PriorityRead)
+	require.Eventually(t, func() bool { return s.Stats().QueuedReads == 2 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []Priority{PriorityRead, PriorityRead, PriorityWrite}, order)
+	// the burst of one is exhausted, so jobs are spaced 10ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	stats := s.Stats()
+	assert.Equal(t, 4, stats.Dispatched)
+	assert.Positive(t, stats.MaxWait)
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	_, client := newFakeDME(t)
+	s := NewScheduler(client, SchedulerOptions{Limit: 1, Window: time.Hour, Burst: 1})
+
+	require.NoError(t, s.Do(context.Background(), PriorityRead, func() error { return nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.Do(ctx, PriorityRead, func() error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, s.Stats().QueuedReads)
+
+	s.Close()
+	assert.ErrorIs(t, s.Do(context.Background(), PriorityRead, func() error { return nil }), ErrSchedulerClosed)
+}
@@ -0,0 +1,101 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// A record found by SearchAllZones, with the zone it belongs to
+type SearchMatch struct {
+	DomainID int    `json:"domainId"`
+	Domain   string `json:"domain"`
+
+	// The record's fully qualified name
+	FQDN   string `json:"fqdn"`
+	Record Record `json:"record"`
+}
+
+type SearchOptions struct {
+	// Every domain of the account if empty
+	DomainIDs []int
+
+	// Domains searched at once. Defaults to 8.
+	Concurrency int
+}
+
+// Finds the records matching q across many zones at once, answering
+// questions like "where is 203.0.113.7 referenced?" with the query
+// value=203.0.113.7. Records are served from the record cache when one
+// is configured. Matches are sorted by domain, name and type. Domains
+// that couldn't be read are left out and their errors joined into the
+// returned error.
+func (c *Client) SearchAllZones(ctx context.Context, q Query, opts SearchOptions) ([]SearchMatch, error) {
+	domains, records, err := c.fetchZones(ctx, opts)
+	var matches []SearchMatch
+	for _, domain := range domains {
+		for _, record := range q.Filter(records[domain.ID]) {
+			matches = append(matches, SearchMatch{domain.ID, domain.Name, AbsoluteName(record.Name, domain.Name), record})
+		}
+	}
+	sortMatches(matches)
+	return matches, err
+}
+
+// Returns the domains in opts and the records of those that could be
+// read, fetched several domains at a time. The errors of the others are
+// joined, naming their domains.
+func (c *Client) fetchZones(ctx context.Context, opts SearchOptions) ([]Domain, map[int][]Record, error) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 8
+	}
+	domains, err := c.domainsOrAll(ctx, opts.DomainIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]int, len(domains))
+	names := make(map[int]string, len(domains))
+	for idx, domain := range domains {
+		ids[idx] = domain.ID
+		names[domain.ID] = domain.Name
+	}
+
+	var (
+		mu      sync.Mutex
+		records = make(map[int][]Record, len(domains))
+		errs    []error
+	)
+	c.eachDomain(ctx, ids, opts.Concurrency, func(domainID int) {
+		zone, err := c.Records(domainID).List(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", names[domainID], err))
+		} else {
+			records[domainID] = zone
+		}
+	})
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	return domains, records, errors.Join(errs...)
+}
+
+// Sorts matches by domain, name, type and ID
+func sortMatches(matches []SearchMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		switch {
+		case a.Domain != b.Domain:
+			return a.Domain < b.Domain
+		case a.Record.Name != b.Record.Name:
+			return a.Record.Name < b.Record.Name
+		case a.Record.Type != b.Record.Type:
+			return a.Record.Type < b.Record.Type
+		}
+		return a.Record.ID < b.Record.ID
+	})
+}
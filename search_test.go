@@ -0,0 +1,53 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAllZones(t *testing.T) {
+	fake, client := newFakeDME(t)
+	com := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "www2", Type: "A", Value: "203.0.113.70", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	org := fake.addDomain("example.org",
+		Record{Name: "legacy", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "mail", Type: "A", Value: "198.51.100.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	broken := fake.addDomain("example.net",
+		Record{Name: "www", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	fake.fail = func(r *http.Request) bool {
+		return strings.Contains(r.URL.Path, fmt.Sprintf("/managed/%d/records", broken.ID))
+	}
+
+	q, err := ParseQuery("value=203.0.113.7")
+	require.NoError(t, err)
+	matches, err := client.SearchAllZones(context.Background(), q, SearchOptions{Concurrency: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "example.net")
+
+	var found []string
+	for _, match := range matches {
+		found = append(found, fmt.Sprintf("%d %s", match.DomainID, match.FQDN))
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("%d example.com.", com.ID),
+		fmt.Sprintf("%d www.example.com.", com.ID),
+		fmt.Sprintf("%d legacy.example.org.", org.ID),
+	}, found)
+
+	matches, err = client.SearchAllZones(context.Background(), q, SearchOptions{DomainIDs: []int{org.ID}})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "example.org", matches[0].Domain)
+	assert.Equal(t, "legacy", matches[0].Record.Name)
+}
@@ -0,0 +1,227 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// How serious a security finding is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Names of the checks AuditZoneRecords performs
+const (
+	CheckTakeover    = "subdomain-takeover"
+	CheckMissingCAA  = "missing-caa"
+	CheckWildcard    = "open-wildcard"
+	CheckSPFAll      = "spf-all"
+	CheckSPFMultiple = "spf-multiple"
+	CheckTXTSecret   = "txt-secret"
+	CheckTXTExposure = "txt-exposure"
+)
+
+// Hosting services whose hostnames can be claimed by anyone once the
+// owner releases them, so a CNAME left pointing at one can be taken
+// over. Matched as suffixes of the target.
+var TakeoverSuffixes = []string{
+	"amazonaws.com",
+	"azurewebsites.net",
+	"cloudapp.net",
+	"cloudapp.azure.com",
+	"trafficmanager.net",
+	"blob.core.windows.net",
+	"azureedge.net",
+	"herokuapp.com",
+	"herokudns.com",
+	"github.io",
+	"gitlab.io",
+	"netlify.app",
+	"vercel.app",
+	"pantheonsite.io",
+	"myshopify.com",
+	"ghost.io",
+	"surge.sh",
+	"bitbucket.io",
+	"wpengine.com",
+	"zendesk.com",
+	"readthedocs.io",
+	"fly.dev",
+}
+
+// TXT records at one name beyond which the zone is flagged for
+// exposing too much about the services it uses
+const MaxTXTPerName = 10
+
+// One problem found by a security audit
+type SecurityFinding struct {
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+
+	// The record the finding is about; zero for zone-wide findings
+	RecordID int    `json:"recordId,omitempty"`
+	Name     string `json:"name"`
+
+	Message string `json:"message"`
+}
+
+// The security audit of one domain
+type SecurityReport struct {
+	DomainID int
+	Domain   string
+
+	// Most severe first
+	Findings []SecurityFinding
+}
+
+// Returns the severity of the worst finding, or SeverityInfo if there
+// are none
+func (r SecurityReport) MaxSeverity() Severity {
+	if len(r.Findings) == 0 {
+		return SeverityInfo
+	}
+	return r.Findings[0].Severity
+}
+
+var secretPattern = regexp.MustCompile(`(?i)\b(password|passwd|secret|api[_-]?key|access[_-]?key|private[_-]?key|token)\s*[=:]`)
+
+// Checks a zone's records for common DNS security problems, returning
+// findings most severe first:
+//
+//   - CNAME and ANAME targets on services prone to subdomain takeover
+//   - no CAA record at the apex, so any CA may issue certificates
+//   - wildcard records, which answer for every name in the zone
+//   - SPF records allowing any sender with +all, or several SPF records
+//     at one name
+//   - TXT values that look like credentials, or an unusually large
+//     number of TXT records at one name
+//
+// Takeover findings flag risk only; the target isn't resolved.
+func AuditZoneRecords(zone string, records []Record) []SecurityFinding {
+	var findings []SecurityFinding
+	add := func(severity Severity, check string, record *Record, format string, args ...interface{}) {
+		f := SecurityFinding{Severity: severity, Check: check, Message: fmt.Sprintf(format, args...)}
+		if record != nil {
+			f.RecordID = record.ID
+			f.Name = strings.TrimSuffix(AbsoluteName(record.Name, zone), ".")
+		} else {
+			f.Name = canonicalName(zone)
+		}
+		findings = append(findings, f)
+	}
+
+	hasCAA := false
+	spfs := map[string]int{}
+	txts := map[string]int{}
+	for idx := range records {
+		record := &records[idx]
+		recordType := strings.ToUpper(record.Type)
+		value := strings.Trim(record.Value, `"`)
+
+		switch recordType {
+		case "CAA":
+			if record.Name == "" {
+				hasCAA = true
+			}
+		case "CNAME", "ANAME":
+			target := canonicalName(qualify(record.Value, AbsoluteName("", zone)))
+			for _, suffix := range TakeoverSuffixes {
+				if target == suffix || strings.HasSuffix(target, "."+suffix) {
+					add(SeverityHigh, CheckTakeover, record, "%s points at %s on %s; if that resource is released anyone can claim it and serve content for this name", recordType, target, suffix)
+					break
+				}
+			}
+		case "TXT", "SPF":
+			txts[strings.ToLower(record.Name)]++
+			if secretPattern.MatchString(value) {
+				add(SeverityHigh, CheckTXTSecret, record, "TXT value looks like it contains a credential")
+			}
+			if strings.HasPrefix(strings.ToLower(value), "v=spf1") {
+				spfs[strings.ToLower(record.Name)]++
+				for _, term := range strings.Fields(strings.ToLower(value)) {
+					switch term {
+					case "+all":
+						add(SeverityCritical, CheckSPFAll, record, "SPF ends in +all, authorizing every host on the internet to send mail for this name")
+					case "?all":
+						add(SeverityLow, CheckSPFAll, record, "SPF ends in ?all, which gives receivers no guidance on unauthorized senders")
+					}
+				}
+			}
+		}
+
+		if IsWildcard(record.Name) {
+			severity := SeverityMedium
+			if recordType == "CNAME" || recordType == "ANAME" {
+				severity = SeverityHigh
+			}
+			add(severity, CheckWildcard, record, "wildcard %s answers for every otherwise undefined name under %s", recordType, strings.TrimSuffix(strings.TrimPrefix(AbsoluteName(record.Name, zone), "*."), "."))
+		}
+	}
+
+	if !hasCAA {
+		add(SeverityMedium, CheckMissingCAA, nil, "no CAA record at the apex, so any certificate authority may issue for this zone")
+	}
+	for name, count := range spfs {
+		if count > 1 {
+			add(SeverityMedium, CheckSPFMultiple, &Record{Name: name}, "%d SPF records; receivers treat multiple SPF records as a permanent error", count)
+		}
+	}
+	for name, count := range txts {
+		if count > MaxTXTPerName {
+			add(SeverityLow, CheckTXTExposure, &Record{Name: name}, "%d TXT records; stale verification tokens reveal which services the organization uses", count)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		if findings[i].Name != findings[j].Name {
+			return findings[i].Name < findings[j].Name
+		}
+		return findings[i].Check < findings[j].Check
+	})
+	return findings
+}
+
+// Audits the records of a domain with AuditZoneRecords
+func (c *Client) AuditZone(ctx context.Context, domainID int) (SecurityReport, error) {
+	domain, err := c.Domains().Get(ctx, domainID)
+	if err != nil {
+		return SecurityReport{}, err
+	}
+	records, err := c.Records(domainID).List(ctx)
+	if err != nil {
+		return SecurityReport{}, err
+	}
+	return SecurityReport{domain.ID, domain.Name, AuditZoneRecords(domain.Name, records)}, nil
+}
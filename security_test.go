@@ -0,0 +1,61 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditZoneRecords(t *testing.T) {
+	records := []Record{
+		{ID: 1, Name: "", Type: "TXT", Value: `"v=spf1 include:_spf.example.net +all"`},
+		{ID: 2, Name: "", Type: "TXT", Value: `"v=spf1 -all"`},
+		{ID: 3, Name: "assets", Type: "CNAME", Value: "example-assets.s3-website-us-east-1.amazonaws.com."},
+		{ID: 4, Name: "docs", Type: "CNAME", Value: "www"},
+		{ID: 5, Name: "*", Type: "A", Value: "192.0.2.1"},
+		{ID: 6, Name: "*.dev", Type: "CNAME", Value: "example.herokuapp.com."},
+		{ID: 7, Name: "ci", Type: "TXT", Value: `"api_key=abc123"`},
+	}
+	findings := AuditZoneRecords("example.com", records)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%s %s %s %d", f.Severity, f.Check, f.Name, f.RecordID))
+	}
+	assert.Equal(t, []string{
+		"critical spf-all example.com 1",
+		"high open-wildcard *.dev.example.com 6",
+		"high subdomain-takeover *.dev.example.com 6",
+		"high subdomain-takeover assets.example.com 3",
+		"high txt-secret ci.example.com 7",
+		"medium open-wildcard *.example.com 5",
+		"medium missing-caa example.com 0",
+		"medium spf-multiple example.com 0",
+	}, got)
+}
+
+func TestAuditZoneRecordsClean(t *testing.T) {
+	var records []Record
+	records = append(records, Record{Name: "", Type: "CAA", Value: `0 issue "letsencrypt.org"`})
+	for idx := range MaxTXTPerName + 1 {
+		records = append(records, Record{Name: "", Type: "TXT", Value: fmt.Sprintf(`"verification-%d"`, idx)})
+	}
+	findings := AuditZoneRecords("example.com", records)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SecurityFinding{Severity: SeverityLow, Check: CheckTXTExposure, Name: "example.com",
+		Message: "11 TXT records; stale verification tokens reveal which services the organization uses"}, findings[0])
+}
+
+func TestAuditZone(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+
+	report, err := client.AuditZone(context.Background(), domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", report.Domain)
+	assert.Equal(t, SeverityMedium, report.MaxSeverity())
+	assert.Equal(t, CheckMissingCAA, report.Findings[0].Check)
+}
@@ -0,0 +1,171 @@
+package seed
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"gopkg.in/yaml.v3"
+)
+
+// A host from an inventory such as a CMDB export:
+//
+//	hosts:
+//	  - {hostname: web1.example.com, ipv4: [192.0.2.10], aliases: [www.example.com]}
+type HostSpec struct {
+	// Fully qualified
+	Hostname string `yaml:"hostname" json:"hostname"`
+
+	// The forward zone the host belongs to; the hostname without its
+	// first label if empty
+	Zone string `yaml:"zone" json:"zone"`
+
+	IPv4 []string `yaml:"ipv4" json:"ipv4"`
+	IPv6 []string `yaml:"ipv6" json:"ipv6"`
+
+	// Fully qualified names in the host's zone pointing at it by CNAME
+	Aliases []string `yaml:"aliases" json:"aliases"`
+
+	// DefaultTTL if zero
+	Ttl int `yaml:"ttl" json:"ttl"`
+}
+
+type Inventory struct {
+	Hosts []HostSpec `yaml:"hosts" json:"hosts"`
+}
+
+// Reads a YAML, or JSON, inventory from a file
+func LoadInventory(path string) (Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Inventory{}, err
+	}
+	var inventory Inventory
+	if err := yaml.Unmarshal(data, &inventory); err != nil {
+		return Inventory{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return inventory, nil
+}
+
+// Returns the spec of every record the hosts need: A and AAAA records
+// for their addresses, CNAMEs for their aliases and PTRs in reverse
+// zones. IPv4 reverse zones are /24s, such as 2.0.192.in-addr.arpa, and
+// IPv6 ones /64s.
+//
+// The spec describes each zone completely, so zones it names should be
+// given over to the inventory before it is reconciled.
+func GenerateRecords(hosts []HostSpec) (Spec, error) {
+	g := generator{domains: map[string]*DomainSpec{}, owners: map[string]string{}}
+	for _, host := range hosts {
+		if err := g.host(host); err != nil {
+			return Spec{}, fmt.Errorf("%s: %w", host.Hostname, err)
+		}
+	}
+
+	var spec Spec
+	for _, domain := range g.domains {
+		spec.Domains = append(spec.Domains, *domain)
+	}
+	sort.Slice(spec.Domains, func(i, j int) bool { return spec.Domains[i].Name < spec.Domains[j].Name })
+	return spec, nil
+}
+
+type generator struct {
+	domains map[string]*DomainSpec
+
+	// the host each name was generated for, to catch clashes
+	owners map[string]string
+}
+
+func (g *generator) host(host HostSpec) error {
+	hostname := strings.ToLower(strings.TrimSuffix(host.Hostname, "."))
+	zone := host.Zone
+	if zone == "" {
+		_, zone, _ = strings.Cut(hostname, ".")
+	}
+	if hostname == "" || zone == "" {
+		return fmt.Errorf("needs a fully qualified hostname")
+	}
+	name, err := dme.RelativeName(hostname, zone)
+	if err != nil {
+		return err
+	}
+	target := hostname + "."
+
+	for _, value := range append(append([]string{}, host.IPv4...), host.IPv6...) {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return err
+		}
+		addr = addr.Unmap()
+		recordType := "A"
+		if addr.Is6() {
+			recordType = "AAAA"
+		}
+		if err := g.add(zone, hostname, RecordSpec{Name: name, Type: recordType, Value: addr.String(), Ttl: host.Ttl}, false); err != nil {
+			return err
+		}
+		reverseZone, reverseName := reverse(addr)
+		if err := g.add(reverseZone, hostname, RecordSpec{Name: reverseName, Type: "PTR", Value: target, Ttl: host.Ttl}, true); err != nil {
+			return err
+		}
+	}
+	for _, alias := range host.Aliases {
+		aliasName, err := dme.RelativeName(alias, zone)
+		if err != nil {
+			return err
+		}
+		if err := g.add(zone, hostname, RecordSpec{Name: aliasName, Type: "CNAME", Value: target, Ttl: host.Ttl}, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adds a record to zone. Exclusive records, CNAMEs and PTRs, may not
+// share their name with records of any other host.
+func (g *generator) add(zone, hostname string, record RecordSpec, exclusive bool) error {
+	key := dme.AbsoluteName(record.Name, zone)
+	if owner, ok := g.owners[key]; ok && (exclusive || owner != hostname) {
+		return fmt.Errorf("%s %s clashes with records for %s", record.Type, key, owner)
+	}
+	if exclusive {
+		g.owners[key] = hostname + " (" + record.Type + ")"
+	} else {
+		g.owners[key] = hostname
+	}
+
+	domain, ok := g.domains[zone]
+	if !ok {
+		domain = &DomainSpec{Name: zone}
+		g.domains[zone] = domain
+	}
+	domain.Records = append(domain.Records, record)
+	return nil
+}
+
+// Returns the reverse zone an address belongs in and its name there
+func reverse(addr netip.Addr) (zone, name string) {
+	var labels []string
+	if addr.Is4() {
+		for _, b := range addr.As4() {
+			labels = append(labels, fmt.Sprint(b))
+		}
+		return strings.Join(reversed(labels[:3]), ".") + ".in-addr.arpa", labels[3]
+	}
+	for _, b := range addr.As16() {
+		labels = append(labels, fmt.Sprintf("%x", b>>4), fmt.Sprintf("%x", b&0xf))
+	}
+	return strings.Join(reversed(labels[:16]), ".") + ".ip6.arpa", strings.Join(reversed(labels[16:]), ".")
+}
+
+func reversed(labels []string) []string {
+	out := make([]string, len(labels))
+	for idx, label := range labels {
+		out[len(labels)-1-idx] = label
+	}
+	return out
+}
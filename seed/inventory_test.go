@@ -0,0 +1,63 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRecords(t *testing.T) {
+	spec, err := GenerateRecords([]HostSpec{
+		{Hostname: "web1.example.com.", IPv4: []string{"192.0.2.10"}, IPv6: []string{"2001:db8::10"}, Aliases: []string{"www.example.com"}, Ttl: 300},
+		{Hostname: "db.internal.example.com", Zone: "example.com", IPv4: []string{"192.0.2.20"}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, spec.Domains, 3)
+	assert.Equal(t, DomainSpec{Name: "0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", Records: []RecordSpec{
+		{Name: "0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0", Type: "PTR", Value: "web1.example.com.", Ttl: 300},
+	}}, spec.Domains[0])
+	assert.Equal(t, DomainSpec{Name: "2.0.192.in-addr.arpa", Records: []RecordSpec{
+		{Name: "10", Type: "PTR", Value: "web1.example.com.", Ttl: 300},
+		{Name: "20", Type: "PTR", Value: "db.internal.example.com."},
+	}}, spec.Domains[1])
+	assert.Equal(t, DomainSpec{Name: "example.com", Records: []RecordSpec{
+		{Name: "web1", Type: "A", Value: "192.0.2.10", Ttl: 300},
+		{Name: "web1", Type: "AAAA", Value: "2001:db8::10", Ttl: 300},
+		{Name: "www", Type: "CNAME", Value: "web1.example.com.", Ttl: 300},
+		{Name: "db.internal", Type: "A", Value: "192.0.2.20"},
+	}}, spec.Domains[2])
+}
+
+func TestGenerateRecordsClashes(t *testing.T) {
+	_, err := GenerateRecords([]HostSpec{
+		{Hostname: "web1.example.com", IPv4: []string{"192.0.2.10"}},
+		{Hostname: "web2.example.com", IPv4: []string{"192.0.2.10"}},
+	})
+	assert.ErrorContains(t, err, "web2.example.com: PTR 10.2.0.192.in-addr.arpa. clashes with records for web1.example.com")
+
+	_, err = GenerateRecords([]HostSpec{
+		{Hostname: "web1.example.com", IPv4: []string{"192.0.2.10"}},
+		{Hostname: "web2.example.com", Aliases: []string{"web1.example.com"}},
+	})
+	assert.ErrorContains(t, err, "CNAME web1.example.com. clashes")
+
+	_, err = GenerateRecords([]HostSpec{{Hostname: "web1.example.com", Aliases: []string{"www.example.org"}}})
+	assert.ErrorContains(t, err, "not in zone example.com")
+
+	_, err = GenerateRecords([]HostSpec{{Hostname: "web1.example.com", IPv4: []string{"192.0.2"}}})
+	assert.Error(t, err)
+}
+
+func TestLoadInventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`hosts:
+  - {hostname: web1.example.com, ipv4: [192.0.2.10], aliases: [www.example.com]}
+`), 0o600))
+	inventory, err := LoadInventory(path)
+	require.NoError(t, err)
+	assert.Equal(t, []HostSpec{{Hostname: "web1.example.com", IPv4: []string{"192.0.2.10"}, Aliases: []string{"www.example.com"}}}, inventory.Hosts)
+}
@@ -0,0 +1,156 @@
+// Package seed populates a DNS Made Easy account, usually the sandbox,
+// with domains and records described in a YAML spec, and removes them
+// again, so integration environments and demos can be rebuilt exactly.
+//
+// A spec looks like:
+//
+//	domains:
+//	  - name: example.testing
+//	    records:
+//	      - {name: www, type: A, value: 192.0.2.1, ttl: 300}
+//	      - {name: "", type: MX, value: mail.example.testing., mxLevel: 10}
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"gopkg.in/yaml.v3"
+)
+
+// The TTL of records that don't set one
+const DefaultTTL = 1800
+
+type Spec struct {
+	Domains []DomainSpec `yaml:"domains"`
+}
+
+type DomainSpec struct {
+	Name    string       `yaml:"name"`
+	Records []RecordSpec `yaml:"records"`
+}
+
+type RecordSpec struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Value       string `yaml:"value"`
+	Ttl         int    `yaml:"ttl"`
+	GtdLocation string `yaml:"gtdLocation"`
+	MxLevel     int    `yaml:"mxLevel"`
+	Priority    int    `yaml:"priority"`
+	Weight      int    `yaml:"weight"`
+	Port        int    `yaml:"port"`
+
+	// Set on the records' annotation by a reconciler with a label store
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// Returns the record the spec describes
+func (r RecordSpec) Record() dme.Record {
+	record := dme.Record{
+		Name:        r.Name,
+		Type:        r.Type,
+		Value:       r.Value,
+		Ttl:         r.Ttl,
+		GtdLocation: r.GtdLocation,
+		MxLevel:     r.MxLevel,
+		Priority:    r.Priority,
+		Weight:      r.Weight,
+		Port:        r.Port,
+	}
+	if record.Ttl == 0 {
+		record.Ttl = DefaultTTL
+	}
+	if record.GtdLocation == "" {
+		record.GtdLocation = dme.GtdDefault
+	}
+	return record
+}
+
+// Parses a YAML spec
+func Parse(data []byte) (Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return Spec{}, err
+	}
+	for idx, domain := range spec.Domains {
+		if domain.Name == "" {
+			return Spec{}, fmt.Errorf("domain %d has no name", idx)
+		}
+	}
+	return spec, nil
+}
+
+// Reads a YAML spec from a file
+func Load(path string) (Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Spec{}, err
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		return Spec{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Creates the spec's domains and records, skipping any that already
+// exist, so seeding twice leaves the account as seeding once does.
+// Returns the IDs of the spec's domains by name.
+func Seed(client *dme.Client, spec Spec) (map[string]int, error) {
+	ctx := context.Background()
+	ids := map[string]int{}
+	var errs []error
+	for _, domain := range spec.Domains {
+		id, err := client.Domains().IdFor(ctx, domain.Name)
+		if errors.Is(err, dme.ErrDomainNotFound) {
+			var created dme.Domain
+			created, err = client.Domains().Create(ctx, domain.Name)
+			if err == nil {
+				created, err = client.WaitForDomain(ctx, created.ID)
+			}
+			id = created.ID
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, err))
+			continue
+		}
+		ids[domain.Name] = id
+
+		records := make([]dme.Record, len(domain.Records))
+		for idx, record := range domain.Records {
+			records[idx] = record.Record()
+		}
+		if _, err := client.ImportZone(ctx, id, records); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, err))
+		}
+	}
+	return ids, errors.Join(errs...)
+}
+
+// Deletes the spec's domains, along with all of their records. Domains
+// that don't exist are skipped.
+func Teardown(client *dme.Client, spec Spec) error {
+	ctx := context.Background()
+	var errs []error
+	for _, domain := range spec.Domains {
+		id, err := client.Domains().IdFor(ctx, domain.Name)
+		if errors.Is(err, dme.ErrDomainNotFound) {
+			continue
+		}
+		if err == nil {
+			// domains can't be deleted while still being created
+			_, err = client.WaitForDomain(ctx, id)
+		}
+		if err == nil {
+			err = client.DeleteDomain(id)
+		}
+		if err != nil && !errors.Is(err, dme.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("%s: %w", domain.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
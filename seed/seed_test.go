@@ -0,0 +1,53 @@
+package seed
+
+import (
+	"testing"
+
+	dme "github.com/john-k/dnsmadeeasy"
+	"github.com/john-k/dnsmadeeasy/dmetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+domains:
+  - name: example.testing
+    records:
+      - {name: www, type: A, value: 192.0.2.1, ttl: 300}
+      - {name: "", type: MX, value: mail.example.testing., mxLevel: 10}
+  - name: empty.testing
+`
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse([]byte(spec))
+	require.NoError(t, err)
+	require.Len(t, parsed.Domains, 2)
+	assert.Equal(t, dme.Record{Name: "", Type: "MX", Value: "mail.example.testing.", Ttl: DefaultTTL,
+		GtdLocation: "DEFAULT", MxLevel: 10}, parsed.Domains[0].Records[1].Record())
+
+	_, err = Parse([]byte("domains:\n  - records: []\n"))
+	assert.EqualError(t, err, "domain 0 has no name")
+}
+
+func TestSeedAndTeardown(t *testing.T) {
+	account, client := dmetest.NewFakeAccount(t)
+
+	parsed, err := Parse([]byte(spec))
+	require.NoError(t, err)
+	for range 2 {
+		ids, err := Seed(client, parsed)
+		require.NoError(t, err)
+		assert.Len(t, ids, 2)
+		assert.Len(t, account.Records(ids["example.testing"]), 2)
+	}
+
+	require.NoError(t, Teardown(client, parsed))
+	assert.Empty(t, account.Domains())
+	require.NoError(t, Teardown(client, parsed))
+
+	// seeding again on the same client uses the new domains
+	ids, err := Seed(client, parsed)
+	require.NoError(t, err)
+	require.Len(t, account.Domains(), 2)
+	assert.Len(t, account.Records(ids["example.testing"]), 2)
+}
@@ -0,0 +1,80 @@
+package dnsmadeeasy
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Makes a GET and decodes a successful response into result straight
+// from the body, so a 100k-record listing is never held in memory as
+// raw JSON as well as decoded records. Falls back to buffering when
+// unknown fields are being checked or responses are logged, as both
+// need the raw JSON.
+func (c *Client) getStreamed(req *resty.Request, path string, result interface{}) error {
+	if c.strictDecoding || c.unknownFields != nil || c.resty.Debug {
+		_, err := checkRespForError(req.SetResult(result).Get(path))
+		return err
+	}
+
+	resp, err := req.SetDoNotParseResponse(true).Get(path)
+	if err != nil {
+		return err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	// resty skips response middleware for unparsed responses
+	c.rateLimit.observe(nil, resp)
+	meta := responseMetaFrom(resp)
+	defer func() { c.meta.record(resp.Request.Context(), meta) }()
+
+	var decoded io.Reader = body
+	if strings.EqualFold(resp.Header().Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		decoded = gz
+	}
+	counted := &countingReader{r: decoded}
+	defer func() { meta.Size = counted.n }()
+
+	return decodeResponse(resp.StatusCode(), resp.Header().Get(RequestIDHeader), counted, result)
+}
+
+// Decodes a successful response body into result, or returns the error
+// an unsuccessful one reports
+func decodeResponse(status int, requestID string, body io.Reader, result interface{}) error {
+	if status < 200 || status >= 300 {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		return apiError(status, requestID, data)
+	}
+	return json.NewDecoder(body).Decode(result)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// Closes the body of an attempt that is about to be retried, which
+// resty leaves open for unparsed responses
+func closeRetriedBody(resp *resty.Response, _ error) {
+	if resp != nil && resp.RawResponse != nil {
+		resp.RawResponse.Body.Close()
+	}
+}
@@ -0,0 +1,55 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamedListing(t *testing.T) {
+	fake, client := newFakeDME(t)
+	fake.limit, fake.remaining = 150, 100
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+
+	ctx, meta := CaptureResponseMeta(context.Background())
+	records, err := client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	// middleware resty skips for streamed responses still sees them
+	assert.Equal(t, 99, client.RateLimit().Remaining)
+	assert.Equal(t, "req-1", meta.RequestID)
+	assert.Positive(t, meta.Size)
+
+	_, err = client.Records(404).List(context.Background())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	fake.fail = func(r *http.Request) bool { return strings.HasSuffix(r.URL.Path, "/records") }
+	fake.failMessage = "Rate limit exceeded"
+	_, err = client.Records(domain.ID).List(context.Background())
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, []string{"Rate limit exceeded"}, apiErr.Messages)
+	assert.Equal(t, "req-3", apiErr.RequestID)
+}
+
+func TestStreamedListingRetries(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	failures := 2
+	fake.failStatus = http.StatusBadGateway
+	fake.fail = func(r *http.Request) bool {
+		failures--
+		return failures >= 0
+	}
+	client = GetClient("key", "secret", client.BaseURL, WithRetries(3, time.Minute))
+	client.resty.SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+
+	records, err := client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
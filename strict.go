@@ -0,0 +1,126 @@
+package dnsmadeeasy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Makes decoding fail when a response contains fields the target type
+// doesn't model, so changes to the API are noticed rather than silently
+// dropped
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strictDecoding = true
+		c.resty.SetJSONUnmarshaler(c.unmarshalJSON)
+	}
+}
+
+// Calls fn with the type being decoded and the dotted paths of any
+// response fields it doesn't model. Decoding otherwise proceeds as
+// usual.
+func WithUnknownFieldHandler(fn func(target string, fields []string)) Option {
+	return func(c *Client) {
+		c.unknownFields = fn
+		c.resty.SetJSONUnmarshaler(c.unmarshalJSON)
+	}
+}
+
+func (c *Client) unmarshalJSON(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	unknown := unknownFields(raw, reflect.TypeOf(v), "")
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+
+	target := strings.TrimPrefix(fmt.Sprintf("%T", v), "*")
+	if c.unknownFields != nil {
+		c.unknownFields(target, unknown)
+	}
+	if c.strictDecoding {
+		return fmt.Errorf("decoding %s: unknown fields %s", target, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Walks decoded JSON alongside the type it was decoded into and returns
+// the paths of object keys no struct field matches
+func unknownFields(raw interface{}, t reflect.Type, path string) []string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+
+	var unknown []string
+	switch value := raw.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, child := range value {
+				field, ok := fields[strings.ToLower(key)]
+				if !ok {
+					unknown = append(unknown, path+key)
+					continue
+				}
+				unknown = append(unknown, unknownFields(child, field, path+key+".")...)
+			}
+		case reflect.Map:
+			for key, child := range value {
+				unknown = append(unknown, unknownFields(child, t.Elem(), path+key+".")...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for idx, child := range value {
+				unknown = append(unknown, unknownFields(child, t.Elem(), fmt.Sprintf("%s%d.", path, idx))...)
+			}
+		}
+	}
+	return unknown
+}
+
+// Returns the types of a struct's fields keyed by their lowercased JSON
+// names, matching encoding/json's case-insensitive field lookup
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for idx := range t.NumField() {
+		field := t.Field(idx)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range jsonFields(embedded) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,47 @@
+package dnsmadeeasy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownFields(t *testing.T) {
+	var raw interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"totalRecords": 1,
+		"Page": 1,
+		"data": [{"name": "www", "ttl": 300, "comment": "new"}],
+		"nextCursor": "abc"
+	}`), &raw))
+
+	unknown := unknownFields(raw, reflect.TypeOf(&RecordsResp{}), "")
+	assert.ElementsMatch(t, []string{"data.0.comment", "nextCursor"}, unknown)
+}
+
+func TestStrictDecoding(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	fake.extraRecordFields = map[string]interface{}{"description": "added by DME"}
+
+	var reported []string
+	lenient := GetClient("key", "secret", client.BaseURL,
+		WithUnknownFieldHandler(func(target string, fields []string) {
+			assert.Equal(t, "dnsmadeeasy.RecordsResp", target)
+			reported = fields
+		}))
+	records, err := lenient.EnumerateRecords(domain.ID)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, []string{"data.0.description"}, reported)
+
+	strict := GetClient("key", "secret", client.BaseURL, WithStrictDecoding())
+	_, err = strict.EnumerateRecords(domain.ID)
+	assert.ErrorContains(t, err, "unknown fields data.0.description")
+
+	_, err = strict.GetDomain(domain.ID)
+	assert.NoError(t, err)
+}
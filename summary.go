@@ -0,0 +1,98 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"time"
+)
+
+// Statistics about the records of a zone, for inventory and audits
+type ZoneSummary struct {
+	DomainID int
+	Name     string
+
+	// The total number of records
+	Records int
+
+	// The number of records of each type
+	ByType map[string]int
+
+	// The number of records with each TTL
+	TTLs map[int]int
+
+	// The lowest and highest TTLs in the zone
+	MinTTL, MaxTTL int
+
+	// The number of records served from each Global Traffic Director
+	// location other than DEFAULT
+	GtdLocations map[string]int
+
+	// The number of records with monitoring or failover enabled
+	Monitored int
+	Failover  int
+
+	// The number of records currently serving a failover address
+	FailedOver int
+
+	// When the domain was created and last changed, and any action
+	// still pending on it (see Domain.PendingActionID)
+	Created         time.Time
+	Updated         time.Time
+	PendingActionID int
+}
+
+// Returns statistics about the records of the supplied domain
+func (c *Client) ZoneSummary(ctx context.Context, domainID int) (ZoneSummary, error) {
+	domain, err := c.Domains().Get(ctx, domainID)
+	if err != nil {
+		return ZoneSummary{}, err
+	}
+	records, err := c.Records(domainID).List(ctx)
+	if err != nil {
+		return ZoneSummary{}, err
+	}
+
+	summary := summarizeRecords(records)
+	summary.DomainID = domain.ID
+	summary.Name = domain.Name
+	summary.PendingActionID = domain.PendingActionID
+	// DNS Made Easy timestamps are in milliseconds
+	if domain.CreatedAt > 0 {
+		summary.Created = time.UnixMilli(int64(domain.CreatedAt))
+	}
+	if domain.UpdatedAt > 0 {
+		summary.Updated = time.UnixMilli(int64(domain.UpdatedAt))
+	}
+	return summary, nil
+}
+
+func summarizeRecords(records []Record) ZoneSummary {
+	summary := ZoneSummary{
+		Records:      len(records),
+		ByType:       map[string]int{},
+		TTLs:         map[int]int{},
+		GtdLocations: map[string]int{},
+	}
+	for idx, record := range records {
+		summary.ByType[record.Type]++
+		summary.TTLs[record.Ttl]++
+		if idx == 0 || record.Ttl < summary.MinTTL {
+			summary.MinTTL = record.Ttl
+		}
+		if record.Ttl > summary.MaxTTL {
+			summary.MaxTTL = record.Ttl
+		}
+		if record.GtdLocation != "" && record.GtdLocation != "DEFAULT" {
+			summary.GtdLocations[record.GtdLocation]++
+		}
+		if record.Monitor {
+			summary.Monitored++
+		}
+		if record.Failover {
+			summary.Failover++
+		}
+		if record.Failed {
+			summary.FailedOver++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,39 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneSummary(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		Record{Name: "", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "EUROPE"},
+		Record{Name: "www", Type: "A", Value: "192.0.2.3", Ttl: 3600, Monitor: true, Failover: true, Failed: true},
+		Record{Name: "", Type: "MX", Value: "mail", Ttl: 86400, MxLevel: 10},
+	)
+	fake.domains[domain.ID].UpdatedAt = 1700000000000
+
+	summary, err := client.ZoneSummary(context.Background(), domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", summary.Name)
+	assert.Equal(t, 4, summary.Records)
+	assert.Equal(t, map[string]int{"A": 3, "MX": 1}, summary.ByType)
+	assert.Equal(t, map[int]int{300: 2, 3600: 1, 86400: 1}, summary.TTLs)
+	assert.Equal(t, 300, summary.MinTTL)
+	assert.Equal(t, 86400, summary.MaxTTL)
+	assert.Equal(t, map[string]int{"EUROPE": 1}, summary.GtdLocations)
+	assert.Equal(t, 1, summary.Monitored)
+	assert.Equal(t, 1, summary.Failover)
+	assert.Equal(t, 1, summary.FailedOver)
+	assert.True(t, summary.Created.IsZero())
+	assert.Equal(t, time.UnixMilli(1700000000000), summary.Updated)
+
+	_, err = client.ZoneSummary(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,57 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+)
+
+const TemplatePath string = "/dns/template/"
+
+// A server-side record template that domains can be assigned to
+type Template struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	DomainIDs      []int  `json:"domainIds"`
+	PublicTemplate bool   `json:"publicTemplate"`
+}
+
+type TemplatesResp struct {
+	TotalRecords int        `json:"totalRecords"`
+	TotalPages   int        `json:"totalPages"`
+	Templates    []Template `json:"data"`
+	CurrentPage  int        `json:"page"`
+}
+
+// Operations on record templates
+type TemplatesService struct {
+	client *Client
+}
+
+// Returns the service for managing templates
+func (c *Client) Templates() *TemplatesService {
+	return &TemplatesService{c}
+}
+
+// Returns all templates available to the account
+func (s *TemplatesService) List(ctx context.Context) ([]Template, error) {
+	var respTemplates TemplatesResp
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetResult(&respTemplates).
+		Get(TemplatePath))
+	if err != nil {
+		return nil, err
+	}
+	return respTemplates.Templates, nil
+}
+
+// Returns the template with the supplied ID
+func (s *TemplatesService) Get(ctx context.Context, templateID int) (Template, error) {
+	var template Template
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetResult(&template).
+		Get(TemplatePath + fmt.Sprint(templateID)))
+	if err != nil {
+		return Template{}, err
+	}
+	return template, nil
+}
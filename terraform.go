@@ -0,0 +1,148 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Resource types generated Terraform uses by default, those of the
+// DNSMadeEasy/dme provider
+const (
+	TerraformDomainResource = "dme_domain"
+	TerraformRecordResource = "dme_dns_record"
+)
+
+type TerraformOptions struct {
+	// TerraformDomainResource and TerraformRecordResource if empty
+	DomainResource string
+	RecordResource string
+
+	// Write only import blocks, for resources already written by hand
+	ImportsOnly bool
+}
+
+// Runs of characters replaced by one underscore in resource names
+var terraformUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Writes Terraform import blocks, and unless ImportsOnly resource stubs
+// to go with them, bringing existing domains and records under
+// Terraform's management. Domains are imported by ID, records as
+// "<domain ID>:<record ID>". Running terraform plan afterwards shows any
+// attribute the stubs get wrong.
+func WriteTerraform(w io.Writer, zones []ZoneExport, opts TerraformOptions) error {
+	if opts.DomainResource == "" {
+		opts.DomainResource = TerraformDomainResource
+	}
+	if opts.RecordResource == "" {
+		opts.RecordResource = TerraformRecordResource
+	}
+
+	var b strings.Builder
+	names := map[string]bool{}
+	unique := func(name string) string {
+		name = strings.Trim(terraformUnsafe.ReplaceAllString(strings.ToLower(name), "_"), "_")
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			name = "r_" + name
+		}
+		candidate := name
+		for n := 2; names[candidate]; n++ {
+			candidate = fmt.Sprintf("%s_%d", name, n)
+		}
+		names[candidate] = true
+		return candidate
+	}
+
+	for _, zone := range zones {
+		domain := unique(zone.Domain.Name)
+		domainAddr := opts.DomainResource + "." + domain
+		fmt.Fprintf(&b, "import {\n  to = %s\n  id = %s\n}\n\n", domainAddr, hclString(fmt.Sprint(zone.Domain.ID)))
+		if !opts.ImportsOnly {
+			fmt.Fprintf(&b, "resource %s %s {\n  name = %s\n}\n\n", hclString(opts.DomainResource), hclString(domain), hclString(zone.Domain.Name))
+		}
+
+		for _, record := range zone.Records {
+			name := unique(domain + "_" + displayRecordName(record.Name) + "_" + record.Type)
+			fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %s\n}\n\n", opts.RecordResource, name,
+				hclString(fmt.Sprintf("%d:%d", zone.Domain.ID, record.ID)))
+			if opts.ImportsOnly {
+				continue
+			}
+			fmt.Fprintf(&b, "resource %s %s {\n", hclString(opts.RecordResource), hclString(name))
+			fmt.Fprintf(&b, "  domain_id    = %s.id\n", domainAddr)
+			fmt.Fprintf(&b, "  name         = %s\n", hclString(record.Name))
+			fmt.Fprintf(&b, "  type         = %s\n", hclString(record.Type))
+			fmt.Fprintf(&b, "  value        = %s\n", hclString(record.Value))
+			fmt.Fprintf(&b, "  ttl          = %d\n", record.Ttl)
+			if record.GtdLocation != "" {
+				fmt.Fprintf(&b, "  gtd_location = %s\n", hclString(record.GtdLocation))
+			}
+			for _, setting := range []struct {
+				name  string
+				value int
+			}{{"mx_level", record.MxLevel}, {"priority", record.Priority}, {"weight", record.Weight}, {"port", record.Port}} {
+				if setting.value != 0 {
+					fmt.Fprintf(&b, "  %-12s = %d\n", setting.name, setting.value)
+				}
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+	_, err := io.WriteString(w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+// Writes Terraform for the supplied domains as WriteTerraform does
+func (c *Client) ExportTerraform(ctx context.Context, w io.Writer, domainIDs []int, opts TerraformOptions) error {
+	zones := make([]ZoneExport, 0, len(domainIDs))
+	for _, id := range domainIDs {
+		domain, err := c.Domains().Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		records, err := c.Records(id).List(ctx)
+		if err != nil {
+			return err
+		}
+		zones = append(zones, ZoneExport{Domain: domain, Records: records})
+	}
+	return WriteTerraform(w, zones, opts)
+}
+
+// Quotes s as an HCL string, escaping template sequences
+func hclString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for idx, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[idx+1:], "{"):
+			// ${ and %{ start interpolations
+			b.WriteRune(r)
+			b.WriteRune(r)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func displayRecordName(name string) string {
+	if name == "" {
+		return "apex"
+	}
+	return name
+}
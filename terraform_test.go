@@ -0,0 +1,69 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTerraform(t *testing.T) {
+	zones := []ZoneExport{{
+		Domain: Domain{ID: 7, Name: "example.com"},
+		Records: []Record{
+			{ID: 10, Name: "", Type: "MX", Value: "mail", Ttl: 1800, MxLevel: 10, GtdLocation: "DEFAULT"},
+			{ID: 11, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+			{ID: 12, Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "DEFAULT"},
+			{ID: 13, Name: "_dmarc", Type: "TXT", Value: `"v=DMARC1; p=none; ${x}"`, Ttl: 300},
+		},
+	}}
+
+	var b strings.Builder
+	require.NoError(t, WriteTerraform(&b, zones, TerraformOptions{}))
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, `import {
+  to = dme_domain.example_com
+  id = "7"
+}
+
+resource "dme_domain" "example_com" {
+  name = "example.com"
+}
+
+import {
+  to = dme_dns_record.example_com_apex_mx
+  id = "7:10"
+}
+
+resource "dme_dns_record" "example_com_apex_mx" {
+  domain_id    = dme_domain.example_com.id
+  name         = ""
+  type         = "MX"
+  value        = "mail"
+  ttl          = 1800
+  gtd_location = "DEFAULT"
+  mx_level     = 10
+}
+`), out)
+	assert.Contains(t, out, "to = dme_dns_record.example_com_www_a_2\n  id = \"7:12\"")
+	assert.Contains(t, out, `resource "dme_dns_record" "example_com_dmarc_txt" {`)
+	assert.Contains(t, out, `value        = "\"v=DMARC1; p=none; $${x}\""`)
+	assert.True(t, strings.HasSuffix(out, "}\n"))
+
+	b.Reset()
+	require.NoError(t, WriteTerraform(&b, zones, TerraformOptions{ImportsOnly: true, RecordResource: "dme_record"}))
+	assert.NotContains(t, b.String(), "resource ")
+	assert.Equal(t, 5, strings.Count(b.String(), "import {"))
+	assert.Contains(t, b.String(), "to = dme_record.example_com_www_a\n")
+}
+
+func TestExportTerraform(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com", Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300})
+
+	var b strings.Builder
+	require.NoError(t, client.ExportTerraform(context.Background(), &b, []int{domain.ID}, TerraformOptions{ImportsOnly: true}))
+	assert.Contains(t, b.String(), "to = dme_dns_record.example_com_www_a\n")
+}
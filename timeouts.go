@@ -0,0 +1,106 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// Default limit on a single HTTP attempt
+	DefaultRequestTimeout = 30 * time.Second
+
+	// Defaults for WaitFor helpers; newly created domains can take
+	// several minutes to leave the pending state in the sandbox
+	DefaultPollInterval = 30 * time.Second
+	DefaultPollDeadline = 10 * time.Minute
+)
+
+// Limits how long a single HTTP attempt may take. Defaults to
+// DefaultRequestTimeout; zero disables the limit.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.resty.SetTimeout(d)
+	}
+}
+
+// Retries idempotent requests (GET, PUT, DELETE) that fail with a
+// network error, a 5xx or a 429 up to count times with exponential
+// backoff, giving up early once budget has elapsed since the first
+// attempt. Requests are not retried by default.
+func WithRetries(count int, budget time.Duration) Option {
+	return func(c *Client) {
+		c.retryBudget = budget
+		c.resty.
+			SetRetryCount(count).
+			SetRetryWaitTime(time.Second).
+			SetRetryMaxWaitTime(30 * time.Second).
+			AddRetryCondition(c.shouldRetry)
+	}
+}
+
+// Sets how often and for how long WaitFor helpers poll. Defaults to
+// DefaultPollInterval and DefaultPollDeadline.
+func WithPolling(interval time.Duration, deadline time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+		c.pollDeadline = deadline
+	}
+}
+
+type firstAttemptKey struct{}
+
+// Notes when a request was first attempted, for the retry budget
+func markFirstAttempt(_ *resty.Client, req *resty.Request) error {
+	if req.Context().Value(firstAttemptKey{}) == nil {
+		req.SetContext(context.WithValue(req.Context(), firstAttemptKey{}, time.Now()))
+	}
+	return nil
+}
+
+func (c *Client) shouldRetry(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+	switch resp.Request.Method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+	default:
+		// a retried create might succeed twice
+		return false
+	}
+	if start, ok := resp.Request.Context().Value(firstAttemptKey{}).(time.Time); ok &&
+		c.retryBudget > 0 && time.Since(start) >= c.retryBudget {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	status := resp.StatusCode()
+	return status >= 500 || status == http.StatusTooManyRequests
+}
+
+// Polls until the domain has no pending action (such as creation)
+// or the polling deadline passes, returning the settled domain
+func (c *Client) WaitForDomain(ctx context.Context, domainID int) (Domain, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.pollDeadline)
+	defer cancel()
+
+	for {
+		domain, err := c.Domains().Get(ctx, domainID)
+		if err != nil {
+			return Domain{}, err
+		}
+		if domain.PendingActionID == 0 {
+			return domain, nil
+		}
+		select {
+		case <-ctx.Done():
+			return domain, fmt.Errorf("domain %s still pending action %d: %w",
+				domain.Name, domain.PendingActionID, ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
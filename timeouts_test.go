@@ -0,0 +1,75 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetries(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	client = GetClient("key", "secret", client.BaseURL, WithRetries(3, time.Minute))
+	client.resty.SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+
+	fake.failStatus = http.StatusServiceUnavailable
+	fake.fail = func(r *http.Request) bool { return fake.served <= 2 }
+	got, err := client.GetDomain(domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Name, got.Name)
+	assert.Equal(t, 3, fake.calls["GET /dns/managed/"+itoa(domain.ID)])
+
+	// creates aren't retried
+	fake.fail = func(r *http.Request) bool { return true }
+	_, err = client.CreateRecord(domain.ID, Record{Name: "www", Type: "A", Value: "192.0.2.1"})
+	assert.Error(t, err)
+	assert.Equal(t, 1, fake.calls["POST /dns/managed/"+itoa(domain.ID)+"/records"])
+
+	// nor is anything once the budget is spent
+	client.retryBudget = time.Nanosecond
+	_, err = client.GetDomain(domain.ID)
+	assert.Error(t, err)
+	assert.Equal(t, 4, fake.calls["GET /dns/managed/"+itoa(domain.ID)])
+}
+
+func TestRequestTimeout(t *testing.T) {
+	fake, client := newFakeDME(t)
+	block := make(chan struct{})
+	defer close(block)
+	fake.fail = func(r *http.Request) bool {
+		<-block
+		return false
+	}
+
+	client = GetClient("key", "secret", client.BaseURL, WithRequestTimeout(10*time.Millisecond))
+	_, err := client.EnumerateDomains()
+	assert.ErrorContains(t, err, "Timeout")
+}
+
+func TestWaitForDomain(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com")
+	fake.domains[domain.ID].PendingActionID = 1
+	client = GetClient("key", "secret", client.BaseURL, WithPolling(time.Millisecond, time.Second))
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		fake.mu.Lock()
+		fake.domains[domain.ID].PendingActionID = 0
+		fake.mu.Unlock()
+	}()
+	settled, err := client.WaitForDomain(context.Background(), domain.ID)
+	require.NoError(t, err)
+	assert.Zero(t, settled.PendingActionID)
+
+	fake.mu.Lock()
+	fake.domains[domain.ID].PendingActionID = 3
+	fake.mu.Unlock()
+	client = GetClient("key", "secret", client.BaseURL, WithPolling(time.Millisecond, 10*time.Millisecond))
+	_, err = client.WaitForDomain(context.Background(), domain.ID)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
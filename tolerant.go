@@ -0,0 +1,108 @@
+package dnsmadeeasy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decodes a JSON object into the struct pointed to by v, coercing values
+// whose JSON type doesn't match the field: numbers sent as strings,
+// booleans sent as strings or 0/1, and strings sent as bare numbers.
+// Older zones and some endpoints return these inconsistently.
+func unmarshalTolerant(data []byte, v interface{}) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return json.Unmarshal(data, v)
+	}
+
+	fields := jsonFields(reflect.TypeOf(v).Elem())
+	for key, raw := range object {
+		t, ok := fields[strings.ToLower(key)]
+		if !ok {
+			continue
+		}
+		coerced, err := coerceJSON(raw, t)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", key, err)
+		}
+		object[key] = coerced
+	}
+
+	normalized, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, v)
+}
+
+func coerceJSON(raw json.RawMessage, t reflect.Type) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return raw, nil
+	}
+	quoted := trimmed[0] == '"'
+
+	var text string
+	if quoted {
+		if err := json.Unmarshal(trimmed, &text); err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(text)
+	} else {
+		text = string(trimmed)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !quoted && !strings.ContainsAny(text, ".eE") {
+			return raw, nil
+		}
+		if text == "" {
+			return json.RawMessage("0"), nil
+		}
+		if text == "true" || text == "false" {
+			return json.RawMessage(map[string]string{"true": "1", "false": "0"}[text]), nil
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		return json.RawMessage(strconv.FormatInt(int64(f), 10)), nil
+	case reflect.Bool:
+		if !quoted && (text == "true" || text == "false") {
+			return raw, nil
+		}
+		switch strings.ToLower(text) {
+		case "true", "1", "yes", "y", "on":
+			return json.RawMessage("true"), nil
+		case "false", "0", "no", "n", "off", "":
+			return json.RawMessage("false"), nil
+		}
+		return nil, fmt.Errorf("%q is not a boolean", text)
+	case reflect.String:
+		if quoted {
+			return raw, nil
+		}
+		return json.Marshal(text)
+	}
+	return raw, nil
+}
+
+func (r *Record) UnmarshalJSON(data []byte) error {
+	type plain Record
+	return unmarshalTolerant(data, (*plain)(r))
+}
+
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	type plain Domain
+	return unmarshalTolerant(data, (*plain)(d))
+}
+
+func (m *Monitor) UnmarshalJSON(data []byte) error {
+	type plain Monitor
+	return unmarshalTolerant(data, (*plain)(m))
+}
@@ -0,0 +1,48 @@
+package dnsmadeeasy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTolerantRecord(t *testing.T) {
+	var records []Record
+	err := json.Unmarshal([]byte(`[
+		{"id": "123", "name": "www", "type": "A", "value": "192.0.2.1", "ttl": "300",
+		 "failover": "true", "monitor": 0, "dynamicDns": "1", "gtdLocation": "DEFAULT"},
+		{"id": 124, "name": "mail", "type": "MX", "value": "mx.example.com.", "ttl": 3600.0,
+		 "mxLevel": " 10 ", "failed": false, "source": null},
+		{"id": 125, "name": "txt", "type": "TXT", "value": 12345, "ttl": 60}
+	]`), &records)
+	require.NoError(t, err)
+
+	assert.Equal(t, Record{ID: 123, Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300,
+		Failover: true, DynamicDns: true, GtdLocation: "DEFAULT"}, records[0])
+	assert.Equal(t, Record{ID: 124, Name: "mail", Type: "MX", Value: "mx.example.com.", Ttl: 3600,
+		MxLevel: 10}, records[1])
+	assert.Equal(t, "12345", records[2].Value)
+
+	var record Record
+	assert.Error(t, json.Unmarshal([]byte(`{"ttl": "soon"}`), &record))
+	assert.Error(t, json.Unmarshal([]byte(`{"failover": "maybe"}`), &record))
+}
+
+func TestTolerantDomain(t *testing.T) {
+	var domain Domain
+	err := json.Unmarshal([]byte(`{"id": "42", "name": "example.com", "gtdEnabled": "false",
+		"pendingActionId": "1", "activeThirdParties": []}`), &domain)
+	require.NoError(t, err)
+	assert.Equal(t, 42, domain.ID)
+	assert.Equal(t, 1, domain.PendingActionID)
+	assert.False(t, domain.GtdEnabled)
+
+	// round trips through the normal encoding
+	b, err := json.Marshal(domain)
+	require.NoError(t, err)
+	var again Domain
+	require.NoError(t, json.Unmarshal(b, &again))
+	assert.Equal(t, domain, again)
+}
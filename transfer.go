@@ -0,0 +1,56 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+)
+
+// Sets whether responses are requested gzip-compressed, which shrinks
+// large record listings several-fold. Enabled by default.
+//
+// NOTE: the DNS Made Easy API has no field selection, so compression
+// and page size are the only levers on transfer size
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		if enabled {
+			c.resty.SetHeader("Accept-Encoding", "gzip")
+		} else {
+			// otherwise net/http asks for gzip on its own
+			c.resty.SetHeader("Accept-Encoding", "identity")
+		}
+	}
+}
+
+// Makes full record listings fetch rows records per request rather than
+// the whole zone at once, so each response of a very large zone stays
+// well within the request timeout. Zero, the default, fetches zones in
+// a single request.
+func WithListPageSize(rows int) Option {
+	return func(c *Client) {
+		c.listPageSize = rows
+	}
+}
+
+// Fetches every page of the domain's records
+func (s *RecordsService) listPaged(ctx context.Context, rows int) ([]Record, error) {
+	var records []Record
+	for page := 1; ; page++ {
+		resp, err := s.ListPage(ctx, ListOptions{Rows: rows, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, resp.Records...)
+		if page >= resp.TotalPages || len(resp.Records) == 0 {
+			return records, nil
+		}
+	}
+}
+
+// The size of a response body as received, before decompression; -1
+// when the server didn't say
+func transferSize(resp *http.Response) int64 {
+	if resp == nil {
+		return -1
+	}
+	return resp.ContentLength
+}
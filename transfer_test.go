@@ -0,0 +1,69 @@
+package dnsmadeeasy
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	var records []Record
+	for idx := range 500 {
+		records = append(records, Record{ID: idx, Name: fmt.Sprint("host-", idx), Type: "A", Value: "192.0.2.1", Ttl: 1800, GtdLocation: "DEFAULT"})
+	}
+	var encodings []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			json.NewEncoder(w).Encode(RecordsResp{Records: records})
+			return
+		}
+		// buffered so the response carries a Content-Length
+		var body strings.Builder
+		gz := gzip.NewWriter(&body)
+		json.NewEncoder(gz).Encode(RecordsResp{Records: records})
+		gz.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", fmt.Sprint(body.Len()))
+		fmt.Fprint(w, body.String())
+	}))
+	defer server.Close()
+
+	client := GetClient("key", "secret", BaseURL(server.URL+"/V2.0/"))
+	ctx, meta := CaptureResponseMeta(context.Background())
+	got, err := client.Records(1).List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, records, got)
+	assert.Equal(t, "gzip", encodings[0])
+	assert.Less(t, meta.TransferSize*5, meta.Size)
+
+	client = GetClient("key", "secret", BaseURL(server.URL+"/V2.0/"), WithCompression(false))
+	got, err = client.Records(1).List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, got, 500)
+	assert.Equal(t, "identity", encodings[1])
+}
+
+func TestListPageSize(t *testing.T) {
+	fake, client := newFakeDME(t)
+	var records []Record
+	for idx := range 25 {
+		records = append(records, Record{Name: fmt.Sprint("host-", idx), Type: "A", Value: "192.0.2.1"})
+	}
+	domain := fake.addDomain("example.com", records...)
+
+	client = GetClient("key", "secret", client.BaseURL, WithListPageSize(10))
+	got, err := client.Records(domain.ID).List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, got, 25)
+	assert.Equal(t, 3, fake.calls[fmt.Sprintf("GET /dns/managed/%d/records", domain.ID)])
+}
@@ -0,0 +1,61 @@
+package dnsmadeeasy
+
+import (
+	"net/http"
+	"time"
+)
+
+// Connection pool settings for the client's HTTP transport. Zero fields
+// keep the client's defaults.
+type TransportOptions struct {
+	// Idle connections kept across all hosts
+	MaxIdleConns int
+
+	// Idle connections kept to the API host. net/http keeps only 2,
+	// which makes parallel workloads reconnect constantly.
+	MaxIdleConnsPerHost int
+
+	// Connections to the API host, idle or in use; unlimited if zero
+	MaxConnsPerHost int
+
+	// How long an idle connection is kept open
+	IdleConnTimeout time.Duration
+}
+
+// The pool the client uses unless WithTransportOptions says otherwise,
+// sized for the parallel requests of FetchAllRecords, Export and the
+// batch helpers
+var DefaultTransportOptions = TransportOptions{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 32,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// Tunes the client's connection pool
+func WithTransportOptions(opts TransportOptions) Option {
+	return func(c *Client) {
+		opts.apply(c.transport)
+	}
+}
+
+func (o TransportOptions) apply(transport *http.Transport) {
+	if o.MaxIdleConns != 0 {
+		transport.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost != 0 {
+		transport.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.MaxConnsPerHost != 0 {
+		transport.MaxConnsPerHost = o.MaxConnsPerHost
+	}
+	if o.IdleConnTimeout != 0 {
+		transport.IdleConnTimeout = o.IdleConnTimeout
+	}
+}
+
+// Returns a transport configured with the default pool settings
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	DefaultTransportOptions.apply(transport)
+	return transport
+}
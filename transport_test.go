@@ -0,0 +1,33 @@
+package dnsmadeeasy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportOptions(t *testing.T) {
+	client := GetClient("key", "secret", Sandbox)
+	transport, ok := client.resty.GetClient().Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 32, transport.MaxIdleConnsPerHost)
+	assert.NotSame(t, http.DefaultTransport, transport)
+
+	client = GetClient("key", "secret", Sandbox, WithTransportOptions(TransportOptions{
+		MaxIdleConnsPerHost: 8,
+		MaxConnsPerHost:     16,
+		IdleConnTimeout:     time.Minute,
+	}))
+	transport = client.resty.GetClient().Transport.(*http.Transport)
+	assert.Equal(t, 100, transport.MaxIdleConns)
+	assert.Equal(t, 8, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 16, transport.MaxConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+
+	// other clients are unaffected
+	other := GetClient("key", "secret", Sandbox).resty.GetClient().Transport.(*http.Transport)
+	assert.Equal(t, 32, other.MaxIdleConnsPerHost)
+}
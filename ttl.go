@@ -0,0 +1,122 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// TTLs suitable for most zones: 5 minutes, 30 minutes, an hour and a day
+var StandardTTLs = []int{300, 1800, 3600, 86400}
+
+// The TTLs a zone's records are expected to use. Min and Max bound the
+// range, and when Allowed is set records must use one of its values.
+// Zero Min or Max leave that side unbounded.
+type TTLPolicy struct {
+	Min, Max int
+	Allowed  []int
+}
+
+// A record whose TTL breaks a policy
+type TTLViolation struct {
+	Record Record
+	Reason string
+
+	// The TTL the policy would snap the record to
+	Suggested int
+}
+
+// Returns the records whose TTLs break the policy, in the order given
+func (p TTLPolicy) Audit(records []Record) []TTLViolation {
+	var violations []TTLViolation
+	for _, record := range records {
+		if reason := p.check(record.Ttl); reason != "" {
+			violations = append(violations, TTLViolation{record, reason, p.Snap(record.Ttl)})
+		}
+	}
+	return violations
+}
+
+func (p TTLPolicy) check(ttl int) string {
+	switch {
+	case p.Min > 0 && ttl < p.Min:
+		return fmt.Sprintf("TTL %d is below the minimum of %d", ttl, p.Min)
+	case p.Max > 0 && ttl > p.Max:
+		return fmt.Sprintf("TTL %d is above the maximum of %d", ttl, p.Max)
+	case len(p.Allowed) > 0 && !p.allowed(ttl):
+		return fmt.Sprintf("TTL %d is not one of the allowed values %v", ttl, p.Allowed)
+	}
+	return ""
+}
+
+func (p TTLPolicy) allowed(ttl int) bool {
+	for _, a := range p.Allowed {
+		if a == ttl {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the TTL closest to ttl that satisfies the policy. Ties go to
+// the longer TTL.
+func (p TTLPolicy) Snap(ttl int) int {
+	var candidates []int
+	for _, a := range p.Allowed {
+		if (p.Min == 0 || a >= p.Min) && (p.Max == 0 || a <= p.Max) {
+			candidates = append(candidates, a)
+		}
+	}
+	if len(candidates) == 0 {
+		if p.Min > 0 && ttl < p.Min {
+			return p.Min
+		}
+		if p.Max > 0 && ttl > p.Max {
+			return p.Max
+		}
+		return ttl
+	}
+
+	sort.Ints(candidates)
+	best := candidates[0]
+	for _, a := range candidates[1:] {
+		if abs(a-ttl) <= abs(best-ttl) {
+			best = a
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Snaps the TTL of every record in the domain that breaks the policy,
+// updating them in bulk. Returns the updated records.
+func (c *Client) NormalizeTTLs(ctx context.Context, domainID int, policy TTLPolicy) ([]Record, error) {
+	records, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []Record
+	for _, violation := range policy.Audit(records) {
+		record := violation.Record
+		if record.Ttl == violation.Suggested {
+			continue
+		}
+		record.Ttl = violation.Suggested
+		changed = append(changed, record)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	if _, err := c.Records(domainID).UpdateMulti(ctx, changed); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,172 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The TTLs LowerTTLs changed in a domain, kept until RestoreTTLs
+type LoweredTTLs struct {
+	DomainID int `json:"domainId"`
+
+	// The temporary TTL
+	TTL int `json:"ttl"`
+
+	// Original TTLs by record ID
+	Originals map[int]int `json:"originals"`
+
+	// When resolvers no longer hold any record with its original TTL,
+	// so the migration can safely begin
+	SafeAfter time.Time `json:"safeAfter"`
+}
+
+// Persists lowered TTLs, so a restore survives process restarts
+type TTLStateStore interface {
+	// Returns the saved state, or a zero LoweredTTLs if there is none
+	LoadTTLState(domainID int) (LoweredTTLs, error)
+	SaveTTLState(LoweredTTLs) error
+	ClearTTLState(domainID int) error
+}
+
+// Keeps lowered TTLs in a JSON file per domain in Dir
+type FileTTLState struct {
+	Dir string
+}
+
+func (f FileTTLState) path(domainID int) string {
+	return filepath.Join(f.Dir, fmt.Sprintf("ttl-%d.json", domainID))
+}
+
+func (f FileTTLState) LoadTTLState(domainID int) (LoweredTTLs, error) {
+	var state LoweredTTLs
+	data, err := os.ReadFile(f.path(domainID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+func (f FileTTLState) SaveTTLState(state LoweredTTLs) error {
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	return writeJSONFile(f.path(state.DomainID), state)
+}
+
+func (f FileTTLState) ClearTTLState(domainID int) error {
+	err := os.Remove(f.path(domainID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Sets where LowerTTLs keeps original TTLs for RestoreTTLs
+func WithTTLStateStore(store TTLStateStore) Option {
+	return func(c *Client) {
+		c.ttlState = store
+	}
+}
+
+// Lowers the TTL of the named records to tempTTL ahead of a planned
+// migration, saving their original TTLs to the TTL state store first.
+// Records already at or below tempTTL are left alone. Lowering again
+// before a restore keeps the first originals.
+func (c *Client) LowerTTLs(ctx context.Context, domainID int, names []string, tempTTL int) (LoweredTTLs, error) {
+	if c.ttlState == nil {
+		return LoweredTTLs{}, fmt.Errorf("lowering TTLs needs a TTL state store")
+	}
+	state, err := c.ttlState.LoadTTLState(domainID)
+	if err != nil {
+		return LoweredTTLs{}, fmt.Errorf("loading TTL state: %w", err)
+	}
+	if state.Originals == nil {
+		state = LoweredTTLs{DomainID: domainID, Originals: map[int]int{}}
+	}
+	state.TTL = tempTTL
+
+	records, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return LoweredTTLs{}, err
+	}
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[strings.ToLower(name)] = true
+	}
+	var changed []Record
+	longest := 0
+	for _, record := range records {
+		if !wanted[strings.ToLower(record.Name)] || record.Ttl <= tempTTL {
+			continue
+		}
+		if _, ok := state.Originals[record.ID]; !ok {
+			state.Originals[record.ID] = record.Ttl
+		}
+		longest = max(longest, record.Ttl)
+		record.Ttl = tempTTL
+		changed = append(changed, record)
+	}
+	if safe := time.Now().Add(time.Duration(longest) * time.Second); safe.After(state.SafeAfter) {
+		state.SafeAfter = safe
+	}
+	if len(changed) == 0 {
+		return state, nil
+	}
+
+	// saved before changing anything, so a crash part way can still be
+	// restored
+	if err := c.ttlState.SaveTTLState(state); err != nil {
+		return LoweredTTLs{}, fmt.Errorf("saving TTL state: %w", err)
+	}
+	if _, err := c.Records(domainID).UpdateMulti(ctx, changed); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// Restores the TTLs lowered by LowerTTLs, then clears the saved state.
+// Records whose TTL was changed from the temporary one since, or that
+// were deleted, are left alone. Returns the restored records.
+func (c *Client) RestoreTTLs(ctx context.Context, domainID int) ([]Record, error) {
+	if c.ttlState == nil {
+		return nil, fmt.Errorf("restoring TTLs needs a TTL state store")
+	}
+	state, err := c.ttlState.LoadTTLState(domainID)
+	if err != nil {
+		return nil, fmt.Errorf("loading TTL state: %w", err)
+	}
+	if len(state.Originals) == 0 {
+		return nil, nil
+	}
+
+	records, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var restored []Record
+	for _, record := range records {
+		original, ok := state.Originals[record.ID]
+		if !ok || record.Ttl != state.TTL {
+			continue
+		}
+		record.Ttl = original
+		restored = append(restored, record)
+	}
+	if len(restored) > 0 {
+		if _, err := c.Records(domainID).UpdateMulti(ctx, restored); err != nil {
+			return nil, err
+		}
+	}
+	return restored, c.ttlState.ClearTTLState(domainID)
+}
@@ -0,0 +1,75 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLowerAndRestoreTTLs(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 3600, GtdLocation: GtdDefault},
+		Record{Name: "WWW", Type: "AAAA", Value: "2001:db8::1", Ttl: 1800, GtdLocation: GtdDefault},
+		Record{Name: "api", Type: "A", Value: "192.0.2.2", Ttl: 30, GtdLocation: GtdDefault},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.3", Ttl: 3600, GtdLocation: GtdDefault},
+	)
+	dir := t.TempDir()
+	client.ttlState = FileTTLState{Dir: dir}
+	ctx := context.Background()
+
+	state, err := client.LowerTTLs(ctx, domain.ID, []string{"www", "api"}, 60)
+	require.NoError(t, err)
+	assert.Len(t, state.Originals, 2)
+	ttls := map[string]int{}
+	for _, record := range fake.recordList(domain.ID) {
+		ttls[record.Type+" "+record.Name] = record.Ttl
+	}
+	assert.Equal(t, map[string]int{"A www": 60, "AAAA WWW": 60, "A api": 30, "A mail": 3600}, ttls)
+
+	// lowering further keeps the first originals
+	_, err = client.LowerTTLs(ctx, domain.ID, []string{"www"}, 30)
+	require.NoError(t, err)
+
+	// a new process restores from the saved state
+	restarted := GetClient("key", "secret", client.BaseURL, WithTTLStateStore(FileTTLState{Dir: dir}))
+	restored, err := restarted.RestoreTTLs(ctx, domain.ID)
+	require.NoError(t, err)
+	assert.Len(t, restored, 2)
+	for _, record := range fake.recordList(domain.ID) {
+		ttls[record.Type+" "+record.Name] = record.Ttl
+	}
+	assert.Equal(t, map[string]int{"A www": 3600, "AAAA WWW": 1800, "A api": 30, "A mail": 3600}, ttls)
+
+	state, err = FileTTLState{Dir: dir}.LoadTTLState(domain.ID)
+	require.NoError(t, err)
+	assert.Empty(t, state.Originals)
+	restored, err = restarted.RestoreTTLs(ctx, domain.ID)
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+}
+
+func TestRestoreTTLsSkipsChangedRecords(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 3600, GtdLocation: GtdDefault},
+	)
+	client.ttlState = FileTTLState{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	_, err := client.LowerTTLs(ctx, domain.ID, []string{"www"}, 60)
+	require.NoError(t, err)
+	record := fake.recordList(domain.ID)[0]
+	record.Ttl = 900
+	require.NoError(t, client.Records(domain.ID).Update(ctx, record))
+
+	restored, err := client.RestoreTTLs(ctx, domain.ID)
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+	assert.Equal(t, 900, fake.recordList(domain.ID)[0].Ttl)
+
+	_, err = GetClient("key", "secret", client.BaseURL).LowerTTLs(ctx, domain.ID, []string{"www"}, 60)
+	assert.ErrorContains(t, err, "needs a TTL state store")
+}
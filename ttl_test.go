@@ -0,0 +1,59 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLPolicy(t *testing.T) {
+	policy := TTLPolicy{Min: 300, Max: 86400, Allowed: StandardTTLs}
+
+	violations := policy.Audit([]Record{
+		{Name: "a", Ttl: 60},
+		{Name: "b", Ttl: 300},
+		{Name: "c", Ttl: 600},
+		{Name: "d", Ttl: 604800},
+	})
+	require.Len(t, violations, 3)
+	assert.Equal(t, "a", violations[0].Record.Name)
+	assert.Equal(t, "TTL 60 is below the minimum of 300", violations[0].Reason)
+	assert.Equal(t, 300, violations[0].Suggested)
+	assert.Equal(t, "TTL 600 is not one of the allowed values [300 1800 3600 86400]", violations[1].Reason)
+	assert.Equal(t, 300, violations[1].Suggested)
+	assert.Equal(t, 86400, violations[2].Suggested)
+
+	assert.Equal(t, 1800, policy.Snap(1050))
+	assert.Equal(t, 3600, policy.Snap(3000))
+
+	ranged := TTLPolicy{Min: 120, Max: 3600}
+	assert.Equal(t, 120, ranged.Snap(30))
+	assert.Equal(t, 600, ranged.Snap(600))
+	assert.Equal(t, 3600, ranged.Snap(7200))
+	assert.Empty(t, ranged.Audit([]Record{{Ttl: 600}}))
+}
+
+func TestNormalizeTTLs(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "a", Type: "A", Value: "192.0.2.1", Ttl: 60},
+		Record{Name: "b", Type: "A", Value: "192.0.2.2", Ttl: 1800},
+		Record{Name: "c", Type: "A", Value: "192.0.2.3", Ttl: 7200},
+	)
+
+	changed, err := client.NormalizeTTLs(context.Background(), domain.ID, TTLPolicy{Allowed: StandardTTLs})
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+
+	var ttls []int
+	for _, record := range fake.recordList(domain.ID) {
+		ttls = append(ttls, record.Ttl)
+	}
+	assert.Equal(t, []int{300, 1800, 3600}, ttls)
+
+	changed, err = client.NormalizeTTLs(context.Background(), domain.ID, TTLPolicy{Allowed: StandardTTLs})
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
@@ -0,0 +1,88 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const UsagePath string = "/usageApi/queriesApi/"
+
+// The number of DNS queries answered for an entity in a month
+type QueryUsage struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Total int64 `json:"total"`
+
+	// What was counted: the account, or a domain within it
+	PrimaryEntity     string `json:"primaryEntity,omitempty"`
+	PrimaryEntityID   int    `json:"primaryEntityId,omitempty"`
+	SecondaryEntity   string `json:"secondaryEntity,omitempty"`
+	SecondaryEntityID int    `json:"secondaryEntityId,omitempty"`
+}
+
+type QueryUsageResp struct {
+	TotalRecords int          `json:"totalRecords"`
+	TotalPages   int          `json:"totalPages"`
+	Usage        []QueryUsage `json:"data"`
+	CurrentPage  int          `json:"page"`
+}
+
+// Returns the ID of the domain counted, or 0 if the usage is for the
+// whole account
+func (u QueryUsage) DomainID() int {
+	switch {
+	case u.SecondaryEntity == "Domain":
+		return u.SecondaryEntityID
+	case u.PrimaryEntity == "Domain":
+		return u.PrimaryEntityID
+	}
+	return 0
+}
+
+// Query statistics, as billed
+type UsageService struct {
+	client *Client
+}
+
+// Returns the service for reading query usage
+func (c *Client) Usage() *UsageService {
+	return &UsageService{c}
+}
+
+// Returns the account's total queries for every month on record
+func (s *UsageService) Months(ctx context.Context) ([]QueryUsage, error) {
+	return s.get(ctx, UsagePath)
+}
+
+// Returns the queries answered for each domain in a month
+func (s *UsageService) Month(ctx context.Context, year int, month time.Month) ([]QueryUsage, error) {
+	return s.get(ctx, fmt.Sprintf("%s%d/%d", UsagePath, year, month))
+}
+
+// Returns the per domain usage for every month from since through
+// until, inclusive, oldest first
+func (s *UsageService) Range(ctx context.Context, since, until time.Time) ([]QueryUsage, error) {
+	var usage []QueryUsage
+	month := time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(until.Year(), until.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; !month.After(last); month = month.AddDate(0, 1, 0) {
+		monthly, err := s.Month(ctx, month.Year(), month.Month())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", month.Format("2006-01"), err)
+		}
+		usage = append(usage, monthly...)
+	}
+	return usage, nil
+}
+
+func (s *UsageService) get(ctx context.Context, path string) ([]QueryUsage, error) {
+	var resp QueryUsageResp
+	_, err := checkRespForError(s.client.newRequest(ctx).
+		SetResult(&resp).
+		Get(path))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Usage, nil
+}
@@ -0,0 +1,28 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageRange(t *testing.T) {
+	f, client := newFakeDME(t)
+	f.usage = map[string][]QueryUsage{
+		"2023/12": {{Year: 2023, Month: 12, Total: 10, PrimaryEntity: "Account", SecondaryEntity: "Domain", SecondaryEntityID: 1}},
+		"2024/2":  {{Year: 2024, Month: 2, Total: 20, PrimaryEntity: "Domain", PrimaryEntityID: 2}},
+	}
+
+	since := time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	usage, err := client.Usage().Range(context.Background(), since, until)
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	assert.Equal(t, 1, usage[0].DomainID())
+	assert.Equal(t, 2, usage[1].DomainID())
+	assert.Equal(t, 3, f.calls["GET /usageApi/queriesApi/2024/1"]+f.calls["GET /usageApi/queriesApi/2023/12"]+f.calls["GET /usageApi/queriesApi/2024/2"])
+	assert.Equal(t, 0, QueryUsage{PrimaryEntity: "Account", PrimaryEntityID: 7}.DomainID())
+}
@@ -0,0 +1,231 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"sync"
+)
+
+// An in-memory index from the addresses and hostnames records point at
+// to the records referencing them, for seeing what depends on an IP or
+// host before decommissioning it. Build it with IndexValues; lookups
+// don't touch the API. Safe for concurrent use.
+type ValueIndex struct {
+	mu    sync.RWMutex
+	zones map[int]indexedZone
+	refs  map[string][]SearchMatch
+}
+
+type indexedZone struct {
+	name string
+
+	// The values the zone's records reference
+	keys []string
+}
+
+func NewValueIndex() *ValueIndex {
+	return &ValueIndex{zones: map[int]indexedZone{}, refs: map[string][]SearchMatch{}}
+}
+
+// Keeps index current with the zones it holds: whenever the client
+// fetches the records of one of them from DNS Made Easy, the zone is
+// re-indexed. With a record cache that is after every change made
+// through the client, which invalidates the zone's cached records.
+func WithValueIndex(index *ValueIndex) Option {
+	return func(c *Client) {
+		c.valueIndex = index
+	}
+}
+
+// Indexes the records of the domains in opts, replacing what the index
+// held for them. Records are served from the record cache when one is
+// configured. Indexing every domain also drops domains that no longer
+// exist. Domains that couldn't be read keep their previous entries and
+// their errors are joined into the returned error.
+func (c *Client) IndexValues(ctx context.Context, index *ValueIndex, opts SearchOptions) error {
+	domains, records, err := c.fetchZones(ctx, opts)
+	for _, domain := range domains {
+		if zone, ok := records[domain.ID]; ok {
+			index.Update(domain.ID, domain.Name, zone)
+		}
+	}
+	if len(opts.DomainIDs) == 0 && err == nil {
+		for _, domainID := range index.Domains() {
+			if _, ok := records[domainID]; !ok {
+				index.Remove(domainID)
+			}
+		}
+	}
+	return err
+}
+
+// Replaces the entries of a zone with its records
+func (x *ValueIndex) Update(domainID int, domain string, records []Record) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.update(domainID, domain, records)
+}
+
+// Re-indexes a zone the index already holds
+func (x *ValueIndex) refresh(domainID int, records []Record) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if zone, ok := x.zones[domainID]; ok {
+		x.update(domainID, zone.name, records)
+	}
+}
+
+func (x *ValueIndex) update(domainID int, domain string, records []Record) {
+	x.remove(domainID)
+	zone := indexedZone{name: domain}
+	for _, record := range records {
+		match := SearchMatch{domainID, domain, AbsoluteName(record.Name, domain), record}
+		for _, key := range recordTargets(record, domain) {
+			if n := len(x.refs[key]); n == 0 || x.refs[key][n-1].DomainID != domainID {
+				zone.keys = append(zone.keys, key)
+			}
+			x.refs[key] = append(x.refs[key], match)
+		}
+	}
+	x.zones[domainID] = zone
+}
+
+// Drops a zone from the index
+func (x *ValueIndex) Remove(domainID int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(domainID)
+}
+
+func (x *ValueIndex) remove(domainID int) {
+	zone, ok := x.zones[domainID]
+	if !ok {
+		return
+	}
+	for _, key := range zone.keys {
+		var kept []SearchMatch
+		for _, match := range x.refs[key] {
+			if match.DomainID != domainID {
+				kept = append(kept, match)
+			}
+		}
+		if len(kept) == 0 {
+			delete(x.refs, key)
+		} else {
+			x.refs[key] = kept
+		}
+	}
+	delete(x.zones, domainID)
+}
+
+// Returns the IDs of the indexed domains
+func (x *ValueIndex) Domains() []int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	ids := make([]int, 0, len(x.zones))
+	for id := range x.zones {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Returns the records referencing an address or hostname directly: A
+// and AAAA records holding the address, records whose target is the
+// host and SPF records naming either. Hostnames are compared fully
+// qualified and ignoring case, addresses in their canonical form.
+// Matches are sorted by domain, name and type.
+func (x *ValueIndex) Lookup(value string) []SearchMatch {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	matches := append([]SearchMatch(nil), x.refs[valueKey(value)]...)
+	sortMatches(matches)
+	return matches
+}
+
+// Returns the records that would stop working without an address or
+// host: those referencing it and, in turn, those referencing the names
+// of the A, AAAA, CNAME and ANAME records among them, such as an MX
+// pointing at a host whose address is being retired.
+func (x *ValueIndex) Dependents(value string) []SearchMatch {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	var matches []SearchMatch
+	seen := map[string]bool{}
+	type recordRef struct{ domainID, recordID int }
+	found := map[recordRef]bool{}
+	queue := []string{valueKey(value)}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for _, match := range x.refs[key] {
+			ref := recordRef{match.DomainID, match.Record.ID}
+			if found[ref] {
+				continue
+			}
+			found[ref] = true
+			matches = append(matches, match)
+			switch strings.ToUpper(match.Record.Type) {
+			case "A", "AAAA", "CNAME", "ANAME":
+				// only names resolving through the record depend on it
+				queue = append(queue, canonicalName(match.FQDN))
+			}
+		}
+	}
+	sortMatches(matches)
+	return matches
+}
+
+// Returns the index key of an address or hostname
+func valueKey(value string) string {
+	value = strings.TrimSpace(value)
+	if addr, err := netip.ParseAddr(value); err == nil {
+		return addr.String()
+	}
+	return canonicalName(value)
+}
+
+// Returns the keys of the addresses and hosts a record references
+func recordTargets(record Record, zone string) []string {
+	switch strings.ToUpper(record.Type) {
+	case "A", "AAAA":
+		return []string{valueKey(record.Value)}
+	case "CNAME", "ANAME", "MX", "NS", "PTR", "SRV":
+		return []string{canonicalName(qualify(record.Value, AbsoluteName("", zone)))}
+	case "TXT", "SPF":
+		return spfTargets(strings.Trim(record.Value, `"`))
+	}
+	return nil
+}
+
+// Returns the keys of the addresses and hosts named by the ip4, ip6, a,
+// mx and include mechanisms of an SPF policy
+func spfTargets(value string) []string {
+	if !strings.HasPrefix(strings.ToLower(value), "v=spf1") {
+		return nil
+	}
+	var keys []string
+	for _, term := range strings.Fields(value)[1:] {
+		term = strings.TrimLeft(term, "+-~?")
+		mechanism, target, ok := strings.Cut(term, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(mechanism) {
+		case "ip4", "ip6":
+			prefix, _, _ := strings.Cut(target, "/")
+			if addr, err := netip.ParseAddr(prefix); err == nil {
+				keys = append(keys, addr.String())
+			}
+		case "a", "mx", "include":
+			if host, _, _ := strings.Cut(target, "/"); host != "" {
+				keys = append(keys, canonicalName(host))
+			}
+		}
+	}
+	return keys
+}
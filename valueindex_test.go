@@ -0,0 +1,84 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueIndex(t *testing.T) {
+	fake, client := newFakeDME(t)
+	com := fake.addDomain("example.com",
+		Record{Name: "old", Type: "A", Value: "203.0.113.7", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "www", Type: "CNAME", Value: "old", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "MX", Value: "OLD.example.com.", MxLevel: 10, Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "TXT", Value: `"v=spf1 ip4:203.0.113.7/32 -all"`, Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "v6", Type: "AAAA", Value: "2001:DB8:0:0::1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	org := fake.addDomain("example.org",
+		Record{Name: "legacy", Type: "CNAME", Value: "www.example.com.", Ttl: 300, GtdLocation: GtdDefault},
+		Record{Name: "", Type: "TXT", Value: `"v=spf1 include:example.com ~all"`, Ttl: 300, GtdLocation: GtdDefault},
+	)
+	ctx := context.Background()
+	index := NewValueIndex()
+	require.NoError(t, client.IndexValues(ctx, index, SearchOptions{}))
+
+	refs := func(matches []SearchMatch) []string {
+		var refs []string
+		for _, match := range matches {
+			refs = append(refs, fmt.Sprintf("%s %s", match.FQDN, match.Record.Type))
+		}
+		return refs
+	}
+	assert.Equal(t, []string{"example.com. TXT", "old.example.com. A"}, refs(index.Lookup("203.0.113.7")))
+	assert.Equal(t, []string{"example.com. MX", "www.example.com. CNAME"}, refs(index.Lookup("old.example.com.")))
+	assert.Equal(t, []string{"v6.example.com. AAAA"}, refs(index.Lookup("2001:db8::1")))
+	assert.Equal(t, []string{"example.org. TXT"}, refs(index.Lookup("Example.com")))
+	assert.Empty(t, index.Lookup("192.0.2.1"))
+
+	assert.Equal(t, []string{
+		"example.com. MX",
+		"example.com. TXT",
+		"old.example.com. A",
+		"www.example.com. CNAME",
+		"legacy.example.org. CNAME",
+	}, refs(index.Dependents("203.0.113.7")))
+
+	// reindexing replaces a zone's entries and drops deleted zones
+	index.Update(com.ID, com.Name, []Record{{Name: "new", Type: "A", Value: "203.0.113.7"}})
+	assert.Equal(t, []string{"new.example.com. A"}, refs(index.Lookup("203.0.113.7")))
+	assert.Equal(t, []string{"legacy.example.org. CNAME"}, refs(index.Lookup("www.example.com")))
+	delete(fake.domains, org.ID)
+	require.NoError(t, client.IndexValues(ctx, index, SearchOptions{}))
+	assert.Equal(t, []int{com.ID}, index.Domains())
+	assert.Empty(t, index.Lookup("www.example.com"))
+}
+
+func TestWithValueIndex(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	other := fake.addDomain("example.org",
+		Record{Name: "www", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault},
+	)
+	index := NewValueIndex()
+	WithValueIndex(index)(client)
+	ctx := context.Background()
+	require.NoError(t, client.IndexValues(ctx, index, SearchOptions{DomainIDs: []int{domain.ID}}))
+	require.Len(t, index.Lookup("192.0.2.1"), 1)
+
+	_, err := client.Records(domain.ID).Create(ctx, Record{Name: "api", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: GtdDefault})
+	require.NoError(t, err)
+	_, err = client.Records(domain.ID).List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, index.Lookup("192.0.2.1"), 2)
+
+	// zones not in the index aren't added by reading them
+	_, err = client.Records(other.ID).List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{domain.ID}, index.Domains())
+}
@@ -0,0 +1,291 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DNS Made Easy's default authoritative nameservers
+var DefaultNameservers = []string{
+	"ns0.dnsmadeeasy.com",
+	"ns1.dnsmadeeasy.com",
+	"ns2.dnsmadeeasy.com",
+	"ns3.dnsmadeeasy.com",
+	"ns4.dnsmadeeasy.com",
+}
+
+// Sets the nameservers VerifyZone queries, as host or host:port, for
+// accounts using vanity nameservers
+func WithNameservers(nameservers ...string) Option {
+	return func(c *Client) {
+		c.nameservers = nameservers
+	}
+}
+
+// How a published record differs from DNS Made Easy's configuration
+type MismatchKind int
+
+const (
+	// A configured value isn't being served
+	MismatchMissing MismatchKind = iota
+
+	// A value is being served that isn't configured
+	MismatchUnexpected
+
+	// A value is served with a different TTL
+	MismatchTTL
+)
+
+func (k MismatchKind) String() string {
+	switch k {
+	case MismatchMissing:
+		return "missing"
+	case MismatchUnexpected:
+		return "unexpected"
+	case MismatchTTL:
+		return "ttl"
+	}
+	return fmt.Sprintf("MismatchKind(%d)", int(k))
+}
+
+// A difference between a nameserver's answer and the managed records
+type ZoneMismatch struct {
+	Nameserver string
+	Name       string
+	Type       string
+	Kind       MismatchKind
+
+	// The configured and served data, in zone file format, and their
+	// TTLs. Want is empty for unexpected values, Got for missing ones.
+	Want, Got       string
+	WantTTL, GotTTL int
+}
+
+func (m ZoneMismatch) String() string {
+	switch m.Kind {
+	case MismatchMissing:
+		return fmt.Sprintf("%s: %s %s %s is missing", m.Nameserver, m.Name, m.Type, m.Want)
+	case MismatchUnexpected:
+		return fmt.Sprintf("%s: %s %s %s is not configured", m.Nameserver, m.Name, m.Type, m.Got)
+	}
+	return fmt.Sprintf("%s: %s %s %s has TTL %d, want %d", m.Nameserver, m.Name, m.Type, m.Want, m.GotTTL, m.WantTTL)
+}
+
+// Queries the account's nameservers for every record of the supplied
+// domain and reports where their answers differ from the configured
+// records, catching records that haven't published. Answers matching
+// any Global Traffic Director region's records are accepted, since the
+// region served depends on where the query comes from.
+//
+// NOTE: ANAME and HTTPRED records are resolved by DNS Made Easy and
+// aren't checked
+func (c *Client) VerifyZone(ctx context.Context, domainID int) ([]ZoneMismatch, error) {
+	domain, err := c.Domains().Get(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.Records(domainID).list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zone := dns.Fqdn(domain.Name)
+
+	// expected data by owner and type, then by GTD region
+	type rrset struct {
+		name    string
+		rrtype  uint16
+		regions map[string][]dns.RR
+	}
+	sets := map[string]*rrset{}
+	var keys []string
+	for _, record := range records {
+		rr, err := record.RR(zone)
+		if err != nil {
+			continue
+		}
+		key := rr.Header().Name + " " + dns.TypeToString[rr.Header().Rrtype]
+		set, ok := sets[key]
+		if !ok {
+			set = &rrset{rr.Header().Name, rr.Header().Rrtype, map[string][]dns.RR{}}
+			sets[key] = set
+			keys = append(keys, key)
+		}
+		region := record.GtdLocation
+		if region == "" {
+			region = "DEFAULT"
+		}
+		set.regions[region] = append(set.regions[region], rr)
+	}
+	sort.Strings(keys)
+
+	nameservers := c.nameservers
+	if nameservers == nil {
+		nameservers = DefaultNameservers
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		mismatches []ZoneMismatch
+		errs       []error
+	)
+	for _, ns := range nameservers {
+		addr := ns
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			addr = net.JoinHostPort(ns, "53")
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, key := range keys {
+				set := sets[key]
+				answer, err := queryAuthoritative(ctx, addr, set.name, set.rrtype)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("%s: %s: %w", ns, key, err))
+					mu.Unlock()
+					if ctx.Err() != nil {
+						return
+					}
+					continue
+				}
+				found := compareRRs(ns, set.regions, answer)
+				mu.Lock()
+				mismatches = append(mismatches, found...)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(mismatches, func(i, j int) bool {
+		if mismatches[i].Name != mismatches[j].Name {
+			return mismatches[i].Name < mismatches[j].Name
+		}
+		if mismatches[i].Type != mismatches[j].Type {
+			return mismatches[i].Type < mismatches[j].Type
+		}
+		return mismatches[i].Nameserver < mismatches[j].Nameserver
+	})
+	return mismatches, errors.Join(errs...)
+}
+
+func queryAuthoritative(ctx context.Context, addr, name string, rrtype uint16) ([]dns.RR, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, rrtype)
+	msg.RecursionDesired = false
+
+	client := &dns.Client{Timeout: 5 * time.Second}
+	resp, _, err := client.ExchangeContext(ctx, msg, addr)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("nameserver answered %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	var answer []dns.RR
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == rrtype {
+			answer = append(answer, rr)
+		}
+	}
+	return answer, nil
+}
+
+// Compares a nameserver's answer with the configured records of each
+// region, reporting differences from the best matching region
+func compareRRs(ns string, regions map[string][]dns.RR, answer []dns.RR) []ZoneMismatch {
+	var best []ZoneMismatch
+	for _, region := range sortedRegions(regions) {
+		found := diffRRs(ns, regions[region], answer)
+		if len(found) == 0 {
+			return nil
+		}
+		if best == nil || region == "DEFAULT" {
+			best = found
+		}
+	}
+	return best
+}
+
+func sortedRegions(regions map[string][]dns.RR) []string {
+	var names []string
+	for region := range regions {
+		names = append(names, region)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func diffRRs(ns string, want, got []dns.RR) []ZoneMismatch {
+	served := map[string]dns.RR{}
+	for _, rr := range got {
+		served[rdata(rr)] = rr
+	}
+
+	var mismatches []ZoneMismatch
+	expected := map[string]bool{}
+	for _, rr := range want {
+		data := rdata(rr)
+		expected[data] = true
+		mismatch := ZoneMismatch{
+			Nameserver: ns,
+			Name:       rr.Header().Name,
+			Type:       dns.TypeToString[rr.Header().Rrtype],
+			Want:       data,
+			WantTTL:    int(rr.Header().Ttl),
+		}
+		match, ok := served[data]
+		switch {
+		case !ok:
+			mismatch.Kind = MismatchMissing
+		case match.Header().Ttl != rr.Header().Ttl:
+			mismatch.Kind = MismatchTTL
+			mismatch.Got = data
+			mismatch.GotTTL = int(match.Header().Ttl)
+		default:
+			continue
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	for _, rr := range got {
+		data := rdata(rr)
+		if expected[data] {
+			continue
+		}
+		mismatches = append(mismatches, ZoneMismatch{
+			Nameserver: ns,
+			Name:       rr.Header().Name,
+			Type:       dns.TypeToString[rr.Header().Rrtype],
+			Kind:       MismatchUnexpected,
+			Got:        data,
+			GotTTL:     int(rr.Header().Ttl),
+		})
+	}
+	return mismatches
+}
+
+// Returns the data of a resource record in zone file format, without
+// its owner, TTL, class and type. The data of records holding hostnames
+// is lowercased, as DNS compares them ignoring case; TXT and the like
+// are kept as they are.
+func rdata(rr dns.RR) string {
+	data := strings.TrimPrefix(rr.String(), rr.Header().String())
+	if hostValued(dns.TypeToString[rr.Header().Rrtype]) {
+		return strings.ToLower(data)
+	}
+	return data
+}
@@ -0,0 +1,100 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Serves the supplied zone file lines over UDP, returning the address
+func serveZone(t *testing.T, lines ...string) string {
+	var rrs []dns.RR
+	for _, line := range lines {
+		rr, err := dns.NewRR(line)
+		require.NoError(t, err)
+		rrs = append(rrs, rr)
+	}
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetReply(req)
+		resp.Authoritative = true
+		q := req.Question[0]
+		for _, rr := range rrs {
+			if dns.CanonicalName(rr.Header().Name) == dns.CanonicalName(q.Name) && rr.Header().Rrtype == q.Qtype {
+				resp.Answer = append(resp.Answer, rr)
+			}
+		}
+		w.WriteMsg(resp)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+	return conn.LocalAddr().String()
+}
+
+func TestVerifyZone(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "", Type: "A", Value: "192.0.2.1", Ttl: 300, GtdLocation: "DEFAULT"},
+		Record{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300, GtdLocation: "DEFAULT"},
+		Record{Name: "www", Type: "A", Value: "192.0.2.3", Ttl: 300, GtdLocation: "EUROPE"},
+		Record{Name: "mail", Type: "A", Value: "192.0.2.4", Ttl: 3600, GtdLocation: "DEFAULT"},
+		Record{Name: "new", Type: "TXT", Value: `"fresh"`, Ttl: 300, GtdLocation: "DEFAULT"},
+		Record{Name: "alias", Type: "ANAME", Value: "lb.example.net.", Ttl: 300, GtdLocation: "DEFAULT"},
+	)
+	addr := serveZone(t,
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN A 192.0.2.99",
+		"www.example.com. 300 IN A 192.0.2.3",
+		"mail.example.com. 600 IN A 192.0.2.4",
+	)
+	client = GetClient("key", "secret", client.BaseURL, WithNameservers(addr))
+
+	mismatches, err := client.VerifyZone(context.Background(), domain.ID)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 3)
+
+	assert.Equal(t, MismatchUnexpected, mismatches[0].Kind)
+	assert.Equal(t, "example.com.", mismatches[0].Name)
+	assert.Equal(t, "192.0.2.99", mismatches[0].Got)
+
+	assert.Equal(t, MismatchTTL, mismatches[1].Kind)
+	assert.Equal(t, "mail.example.com.", mismatches[1].Name)
+	assert.Equal(t, 3600, mismatches[1].WantTTL)
+	assert.Equal(t, 600, mismatches[1].GotTTL)
+	assert.Equal(t, addr+": mail.example.com. A 192.0.2.4 has TTL 600, want 3600", mismatches[1].String())
+
+	assert.Equal(t, MismatchMissing, mismatches[2].Kind)
+	assert.Equal(t, "new.example.com.", mismatches[2].Name)
+	assert.Equal(t, `"fresh"`, mismatches[2].Want)
+}
+
+func TestDiffRRsCase(t *testing.T) {
+	rrs := func(lines ...string) []dns.RR {
+		var rrs []dns.RR
+		for _, line := range lines {
+			rr, err := dns.NewRR(line)
+			require.NoError(t, err)
+			rrs = append(rrs, rr)
+		}
+		return rrs
+	}
+
+	// hostnames compare ignoring case
+	assert.Empty(t, diffRRs("ns", rrs("www.example.com. 300 IN CNAME LB.Example.net."), rrs("www.example.com. 300 IN CNAME lb.example.net.")))
+	assert.Empty(t, diffRRs("ns", rrs("example.com. 300 IN MX 10 Mail.example.com."), rrs("example.com. 300 IN MX 10 mail.example.com.")))
+
+	// TXT doesn't
+	mismatches := diffRRs("ns", rrs(`example.com. 300 IN TXT "Token=AbC"`), rrs(`example.com. 300 IN TXT "token=abc"`))
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, MismatchMissing, mismatches[0].Kind)
+	assert.Equal(t, `"Token=AbC"`, mismatches[0].Want)
+	assert.Equal(t, MismatchUnexpected, mismatches[1].Kind)
+	assert.Equal(t, `"token=abc"`, mismatches[1].Got)
+}
@@ -0,0 +1,79 @@
+package dnsmadeeasy
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// A revision of the DNS Made Easy REST API, as it appears in request
+// paths
+type APIVersion string
+
+const (
+	V2_0 APIVersion = "V2.0"
+
+	DefaultAPIVersion = V2_0
+)
+
+// matches a trailing version segment such as /V2.0/
+var versionSuffix = regexp.MustCompile(`^(.*?/)(V\d+(?:\.\d+)*)/?$`)
+
+// Splits a base URL into the API root and the version it names, if any
+func splitBaseURL(url BaseURL) (string, APIVersion) {
+	if m := versionSuffix.FindStringSubmatch(string(url)); m != nil {
+		return m[1], APIVersion(m[2])
+	}
+	root := string(url)
+	if !strings.HasSuffix(root, "/") {
+		root += "/"
+	}
+	return root, ""
+}
+
+// Selects the API version used for every request, overriding any version
+// in the base URL
+func WithAPIVersion(v APIVersion) Option {
+	return func(c *Client) {
+		c.version = v
+	}
+}
+
+// Selects the API version for requests whose path starts with prefix
+// (for example "/monitor/"), for endpoints that move to a new version
+// ahead of the rest of the API. The longest matching prefix wins.
+func WithEndpointVersion(prefix string, v APIVersion) Option {
+	return func(c *Client) {
+		if c.endpointVersions == nil {
+			c.endpointVersions = map[string]APIVersion{}
+		}
+		c.endpointVersions[prefix] = v
+	}
+}
+
+// Returns the API version used for requests without an endpoint override
+func (c *Client) APIVersion() APIVersion {
+	return c.version
+}
+
+// Returns the API version used for requests to path
+func (c *Client) versionFor(path string) APIVersion {
+	version, longest := c.version, -1
+	for prefix, v := range c.endpointVersions {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			version, longest = v, len(prefix)
+		}
+	}
+	return version
+}
+
+// Prefixes each request path with its API version
+func (c *Client) applyVersion(_ *resty.Client, req *resty.Request) error {
+	if strings.Contains(req.URL, "://") {
+		return nil
+	}
+	path := "/" + strings.TrimPrefix(req.URL, "/")
+	req.URL = "/" + string(c.versionFor(path)) + path
+	return nil
+}
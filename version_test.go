@@ -0,0 +1,48 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBaseURL(t *testing.T) {
+	for url, want := range map[BaseURL][2]string{
+		Prod:                                {"https://api.dnsmadeeasy.com/", "V2.0"},
+		Sandbox:                             {"https://api.sandbox.dnsmadeeasy.com/", "V2.0"},
+		"https://gw.example/dme/V3":         {"https://gw.example/dme/", "V3"},
+		"https://gw.example/dme":            {"https://gw.example/dme/", ""},
+		"http://localhost:8080/V2.0/extra/": {"http://localhost:8080/V2.0/extra/", ""},
+	} {
+		root, version := splitBaseURL(url)
+		assert.Equal(t, want, [2]string{root, string(version)}, string(url))
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	}))
+	defer server.Close()
+
+	client := GetClient("key", "secret", BaseURL(server.URL+"/V2.0/"))
+	assert.Equal(t, V2_0, client.APIVersion())
+
+	client = GetClient("key", "secret", BaseURL(server.URL),
+		WithAPIVersion("V3.0"),
+		WithEndpointVersion("/monitor/", "V2.0"),
+		WithEndpointVersion("/monitor/beta/", "V4.0"))
+	_, err := client.GetDomain(1)
+	require.NoError(t, err)
+	_, err = client.Monitors().Get(context.Background(), 2)
+	require.NoError(t, err)
+	require.NoError(t, client.Do(context.Background(), http.MethodGet, "monitor/beta/3", nil, nil))
+
+	assert.Equal(t, []string{"/V3.0/dns/managed/1", "/V2.0/monitor/2", "/V4.0/monitor/beta/3"}, paths)
+}
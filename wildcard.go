@@ -0,0 +1,98 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Reports whether a record name is a wildcard, such as "*" or "*.sub"
+func IsWildcard(name string) bool {
+	return name == "*" || strings.HasPrefix(name, "*.")
+}
+
+// Checks a record's use of wildcards: "*" may only appear as the whole
+// leftmost label, and NS and SOA records can't be wildcards
+func ValidateWildcard(record Record) error {
+	if !strings.Contains(record.Name, "*") {
+		return nil
+	}
+	if !IsWildcard(record.Name) || strings.Contains(record.Name[1:], "*") {
+		return fmt.Errorf("%q: a wildcard must be the whole leftmost label", record.Name)
+	}
+	switch strings.ToUpper(record.Type) {
+	case "NS", "SOA":
+		return fmt.Errorf("%q: %s records can't be wildcards", record.Name, strings.ToUpper(record.Type))
+	}
+	return nil
+}
+
+// Returns the wildcard records that would answer a query for name and
+// recordType in a zone holding records, or nil if the name exists in the
+// zone or no wildcard covers it. Names are relative to the zone, with ""
+// for the apex. CNAME wildcards answer queries of any type.
+//
+// Follows RFC 4592: only the wildcard directly below the closest
+// existing ancestor of name is consulted, so "*.example" does not cover
+// "a.sub.example" when "sub.example" exists.
+func CoveringWildcard(records []Record, name, recordType string) []Record {
+	name = strings.ToLower(name)
+	if nodeExists(records, name) {
+		return nil
+	}
+
+	encloser := name
+	for encloser != "" {
+		if _, parent, ok := strings.Cut(encloser, "."); ok {
+			encloser = parent
+		} else {
+			encloser = ""
+		}
+		if nodeExists(records, encloser) {
+			break
+		}
+	}
+
+	source := "*"
+	if encloser != "" {
+		source = "*." + encloser
+	}
+	var covering []Record
+	for _, record := range records {
+		if strings.EqualFold(record.Name, source) &&
+			(strings.EqualFold(record.Type, recordType) || strings.EqualFold(record.Type, "CNAME")) {
+			covering = append(covering, record)
+		}
+	}
+	return covering
+}
+
+// Reports whether name is owned by a record or is an ancestor of one
+func nodeExists(records []Record, name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, record := range records {
+		owner := strings.ToLower(record.Name)
+		if owner == name || strings.HasSuffix(owner, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns which of names in the supplied domain are answered by a
+// wildcard for recordType, mapped to the covering records
+func (c *Client) WildcardCovered(ctx context.Context, domainID int, recordType string, names []string) (map[string][]Record, error) {
+	records, err := c.Records(domainID).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	covered := map[string][]Record{}
+	for _, name := range names {
+		if wildcards := CoveringWildcard(records, name, recordType); len(wildcards) > 0 {
+			covered[name] = wildcards
+		}
+	}
+	return covered, nil
+}
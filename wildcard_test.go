@@ -0,0 +1,57 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWildcard(t *testing.T) {
+	assert.True(t, IsWildcard("*"))
+	assert.True(t, IsWildcard("*.sub"))
+	assert.False(t, IsWildcard("www"))
+
+	assert.NoError(t, ValidateWildcard(Record{Name: "www", Type: "NS"}))
+	assert.NoError(t, ValidateWildcard(Record{Name: "*.sub", Type: "A"}))
+	assert.NoError(t, ValidateWildcard(Record{Name: "*", Type: "MX"}))
+	assert.EqualError(t, ValidateWildcard(Record{Name: "w*w", Type: "A"}),
+		`"w*w": a wildcard must be the whole leftmost label`)
+	assert.Error(t, ValidateWildcard(Record{Name: "sub.*", Type: "A"}))
+	assert.Error(t, ValidateWildcard(Record{Name: "*.*", Type: "A"}))
+	assert.EqualError(t, ValidateWildcard(Record{Name: "*", Type: "ns"}),
+		`"*": NS records can't be wildcards`)
+}
+
+func TestCoveringWildcard(t *testing.T) {
+	records := []Record{
+		{Name: "*", Type: "A", Value: "192.0.2.1"},
+		{Name: "www", Type: "A", Value: "192.0.2.2"},
+		{Name: "*.sub", Type: "CNAME", Value: "www"},
+		{Name: "host.dev", Type: "A", Value: "192.0.2.3"},
+	}
+
+	assert.Nil(t, CoveringWildcard(records, "www", "A"))
+	assert.Nil(t, CoveringWildcard(records, "", "A"))
+	assert.Equal(t, "*", CoveringWildcard(records, "anything", "A")[0].Name)
+	assert.Nil(t, CoveringWildcard(records, "anything", "TXT"))
+	assert.Equal(t, "*.sub", CoveringWildcard(records, "a.b.sub", "TXT")[0].Name)
+
+	// dev exists as an empty non-terminal, so "*" doesn't reach below it
+	assert.Nil(t, CoveringWildcard(records, "dev", "A"))
+	assert.Nil(t, CoveringWildcard(records, "other.dev", "A"))
+}
+
+func TestWildcardCovered(t *testing.T) {
+	fake, client := newFakeDME(t)
+	domain := fake.addDomain("example.com",
+		Record{Name: "*", Type: "A", Value: "192.0.2.1", Ttl: 300},
+		Record{Name: "www", Type: "A", Value: "192.0.2.2", Ttl: 300},
+	)
+
+	covered, err := client.WildcardCovered(context.Background(), domain.ID, "A", []string{"www", "api", "mail"})
+	require.NoError(t, err)
+	assert.Len(t, covered, 2)
+	assert.Equal(t, "192.0.2.1", covered["api"][0].Value)
+}
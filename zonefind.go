@@ -0,0 +1,58 @@
+package dnsmadeeasy
+
+import (
+	"context"
+	"strings"
+)
+
+// Finds the most specific managed zone containing the hostname, so
+// app.sub.example.com resolves to sub.example.com when both it and
+// example.com are managed. Returns the zone, its ID and the hostname
+// relative to it ("" for the apex).
+func (s *DomainsService) FindZoneForHost(ctx context.Context, fqdn string) (zone string, domainID int, name string, err error) {
+	host := canonicalName(fqdn)
+	c := s.client
+	names := c.domainNames()
+	refreshed := false
+	if names == nil {
+		if names, err = s.names(ctx); err != nil {
+			return "", 0, "", err
+		}
+		c.setDomainNames(names)
+		refreshed = true
+	}
+
+	for {
+		if zone, domainID, name, ok := longestZoneMatch(names, host); ok {
+			return zone, domainID, name, nil
+		}
+		// the zone may have been added since the cache was filled
+		if refreshed {
+			return "", 0, "", ErrDomainNotFound
+		}
+		if names, err = s.names(ctx); err != nil {
+			return "", 0, "", err
+		}
+		c.setDomainNames(names)
+		refreshed = true
+	}
+}
+
+func longestZoneMatch(names map[string]int, host string) (zone string, domainID int, name string, ok bool) {
+	labels := strings.Split(host, ".")
+	for idx := range labels {
+		candidate := strings.Join(labels[idx:], ".")
+		if id, found := names[candidate]; found {
+			name, _ := RelativeName(host, candidate)
+			return candidate, id, name, true
+		}
+	}
+	return "", 0, "", false
+}
+
+// Finds the most specific managed zone containing the hostname
+//
+// Equivalent to c.Domains().FindZoneForHost(context.Background(), fqdn)
+func (c *Client) FindZoneForHost(fqdn string) (zone string, domainID int, name string, err error) {
+	return c.Domains().FindZoneForHost(context.Background(), fqdn)
+}
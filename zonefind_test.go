@@ -0,0 +1,41 @@
+package dnsmadeeasy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindZoneForHost(t *testing.T) {
+	fake, client := newFakeDME(t)
+	parent := fake.addDomain("example.com")
+	child := fake.addDomain("sub.example.com")
+
+	zone, id, name, err := client.FindZoneForHost("app.sub.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "sub.example.com", zone)
+	assert.Equal(t, child.ID, id)
+	assert.Equal(t, "app", name)
+
+	zone, id, name, err = client.FindZoneForHost("_acme-challenge.www.Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", zone)
+	assert.Equal(t, parent.ID, id)
+	assert.Equal(t, "_acme-challenge.www", name)
+
+	_, _, name, err = client.FindZoneForHost("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "", name)
+	assert.Equal(t, 1, fake.calls["GET /dns/managed/"])
+
+	// unknown zones refresh the cache once
+	_, _, _, err = client.FindZoneForHost("www.example.org")
+	assert.ErrorIs(t, err, ErrDomainNotFound)
+	assert.Equal(t, 2, fake.calls["GET /dns/managed/"])
+
+	added := fake.addDomain("example.org")
+	_, id, _, err = client.FindZoneForHost("www.example.org")
+	require.NoError(t, err)
+	assert.Equal(t, added.ID, id)
+}